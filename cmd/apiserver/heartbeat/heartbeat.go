@@ -69,7 +69,7 @@ func handle(conn *net.Conn) {
 	}
 	connMap.Store(node.UID, nodeConn)
 	log.Printf("Updating Node UID=%s, ready=%v into etcd\n", node.UID, node.Status.Condition.Ready)
-	err = etcdrw.PutObj("/apis/node/"+node.UID, nodeStr)
+	err = putNodeStatus(&node)
 	if err != nil {
 		log.Printf("Fail to put Node UID=%s into etcd, err: %v\n", node.UID, err)
 		return
@@ -77,13 +77,8 @@ func handle(conn *net.Conn) {
 
 	defer func() {
 		node.Status.Condition.Ready = false
-		buf, err = json.Marshal(node)
-		if err != nil {
-			log.Println("Fail to marshal Node, err: ", err)
-			return
-		}
 		log.Printf("Updating Node UID=%s, ready=%v into etcd\n", node.UID, false)
-		err = etcdrw.PutObj("/apis/node/"+node.UID, string(buf))
+		err = putNodeStatus(&node)
 		if err != nil {
 			log.Printf("Fail to put Node UID=%s into etcd, err: %v\n", node.UID, err)
 		}
@@ -108,7 +103,7 @@ func handle(conn *net.Conn) {
 			}
 
 			log.Printf("Updating Node UID=%s, ready=%v into etcd\n", newNode.UID, newNode.Status.Condition.Ready)
-			err = etcdrw.PutObj("/apis/node/"+newNode.UID, newNodeStr)
+			err = putNodeStatus(&newNode)
 			if err != nil {
 				log.Printf("Fail to put Node UID=%s into etcd, err: %v\n", newNode.UID, err)
 				return
@@ -120,6 +115,32 @@ func handle(conn *net.Conn) {
 	}
 }
 
+// putNodeStatus only takes status from the node message,
+// metadata and spec (labels, taints, cordon...) may be changed by users
+// after registration, so they are kept as stored in etcd
+func putNodeStatus(node *object.Node) error {
+	oldBuf, err := etcdrw.GetObj(object.NodeEtcdPrefix + node.UID)
+	if err != nil {
+		return err
+	}
+
+	newNode := *node
+	if oldBuf != nil {
+		var oldNode object.Node
+		if err = json.Unmarshal(oldBuf, &oldNode); err == nil {
+			newNode.ObjectMeta = oldNode.ObjectMeta
+			newNode.Spec = oldNode.Spec
+		}
+	}
+
+	buf, err := json.Marshal(newNode)
+	if err != nil {
+		log.Println("Fail to marshal Node, err: ", err)
+		return err
+	}
+	return etcdrw.PutObj(object.NodeEtcdPrefix+node.UID, string(buf))
+}
+
 func checkHealth() {
 	hb := []byte(heartbeat.MSG_HEARTBEAT)
 	hb = append(hb, heartbeat.MSG_DELIM)
@@ -0,0 +1,134 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func GetConfigMap(ctx *gin.Context) {
+	getObj(ctx, object.ConfigMapEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetConfigMaps(ctx *gin.Context) {
+	getObjs(ctx, object.ConfigMapEtcdPrefix)
+}
+
+func PostConfigMap(ctx *gin.Context) {
+	configMap := object.ConfigMap{}
+	err := ctx.BindJSON(&configMap)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if !checkConfigMap(&configMap) {
+		utils.BadRequest(ctx)
+		return
+	}
+	configMap.UID = uuid.New().String()
+	buf, _ := json.Marshal(configMap)
+	err = etcdrw.PutObj(object.ConfigMapEtcdPrefix+configMap.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, configMap)
+}
+
+func PutConfigMap(ctx *gin.Context) {
+	newConfigMap := object.ConfigMap{}
+	err := ctx.BindJSON(&newConfigMap)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newConfigMap.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.ConfigMapEtcdPrefix + newConfigMap.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if !checkConfigMap(&newConfigMap) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newConfigMap)
+	err = etcdrw.PutObj(object.ConfigMapEtcdPrefix+newConfigMap.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelConfigMap(ctx *gin.Context) {
+	delObj(ctx, object.ConfigMapEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectConfigMaps(ctx *gin.Context) {
+	var selectors map[string]string
+	err := ctx.BindJSON(&selectors)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if len(selectors) == 0 {
+		getObjs(ctx, object.ConfigMapEtcdPrefix)
+		return
+	}
+
+	selectObjs(ctx, object.ConfigMapEtcdPrefix, func(str []byte) bool {
+		var configMap object.ConfigMap
+		err = json.Unmarshal(str, &configMap)
+		if err != nil {
+			return false
+		}
+
+		for key, val := range selectors {
+			v := configMap.Labels[key]
+			if v != val {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// checkConfigMap makes sure config map names are unique,
+// since pods refer to them by name
+func checkConfigMap(configMap *object.ConfigMap) bool {
+	if configMap.Name == "" {
+		return false
+	}
+	bufs, err := etcdrw.GetObjs(object.ConfigMapEtcdPrefix)
+	if err != nil {
+		return false
+	}
+	for _, buf := range bufs {
+		var cm object.ConfigMap
+		if err = json.Unmarshal(buf, &cm); err != nil {
+			continue
+		}
+		if cm.UID != configMap.UID && cm.Name == configMap.Name {
+			return false
+		}
+	}
+	return true
+}
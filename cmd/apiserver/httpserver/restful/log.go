@@ -0,0 +1,219 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"Cubernetes/pkg/utils/localstorage"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GetPodLog streams logs of a pod container from the cubelet of its node
+func GetPodLog(ctx *gin.Context) {
+	if _, err := object.ParseLogOptions(ctx.Request.URL.Query()); err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	pod, query, ok := getPodContainerTarget(ctx)
+	if !ok {
+		return
+	}
+
+	proxyContainerLogs(ctx, pod.Status.NodeUID, "/containerLogs/pod/"+pod.UID+"?"+query.Encode())
+}
+
+func GetActorLog(ctx *gin.Context) {
+	buf, ok := getLogTarget(ctx, object.ActorEtcdPrefix+ctx.Param("uid"))
+	if !ok {
+		return
+	}
+	var actor object.Actor
+	if err := json.Unmarshal(buf, &actor); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if actor.Status == nil || actor.Status.NodeUID == "" {
+		ctx.String(http.StatusBadRequest, "actor %s is not scheduled yet", actor.Name)
+		return
+	}
+
+	proxyContainerLogs(ctx, actor.Status.NodeUID, "/containerLogs/actor/"+actor.UID+"?"+ctx.Request.URL.RawQuery)
+}
+
+func GetGpuJobLog(ctx *gin.Context) {
+	buf, ok := getLogTarget(ctx, object.GpuJobEtcdPrefix+ctx.Param("uid"))
+	if !ok {
+		return
+	}
+	var job object.GpuJob
+	if err := json.Unmarshal(buf, &job); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if job.Status.NodeUID == "" {
+		ctx.String(http.StatusBadRequest, "gpu job %s is not scheduled yet", job.Name)
+		return
+	}
+
+	proxyContainerLogs(ctx, job.Status.NodeUID, "/containerLogs/gpuJob/"+job.UID+"?"+ctx.Request.URL.RawQuery)
+}
+
+func getLogTarget(ctx *gin.Context, path string) ([]byte, bool) {
+	if _, err := object.ParseLogOptions(ctx.Request.URL.Query()); err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return getTarget(ctx, path)
+}
+
+func getTarget(ctx *gin.Context, path string) ([]byte, bool) {
+	buf, err := etcdrw.GetObj(path)
+	if err != nil {
+		utils.ServerError(ctx)
+		return nil, false
+	}
+	if buf == nil {
+		utils.NotFound(ctx)
+		return nil, false
+	}
+	return buf, true
+}
+
+// getPodContainerTarget returns the scheduled pod, and the query with its container filled,
+// the only container is chosen by default
+func getPodContainerTarget(ctx *gin.Context) (*object.Pod, url.Values, bool) {
+	buf, ok := getTarget(ctx, object.PodEtcdPrefix+ctx.Param("uid"))
+	if !ok {
+		return nil, nil, false
+	}
+	var pod object.Pod
+	if err := json.Unmarshal(buf, &pod); err != nil {
+		utils.ServerError(ctx)
+		return nil, nil, false
+	}
+
+	query := ctx.Request.URL.Query()
+	container := query.Get(object.LogQueryContainer)
+	if container == "" {
+		if len(pod.Spec.Containers) != 1 || len(pod.Spec.InitContainers) != 0 {
+			ctx.String(http.StatusBadRequest, "a container name must be specified for pod %s, choose one of: %s",
+				pod.Name, strings.Join(podContainerNames(&pod), " "))
+			return nil, nil, false
+		}
+		query.Set(object.LogQueryContainer, pod.Spec.Containers[0].Name)
+	} else if !hasContainer(&pod, container) {
+		ctx.String(http.StatusBadRequest, "container %s is not valid for pod %s", container, pod.Name)
+		return nil, nil, false
+	}
+	if pod.Status == nil || pod.Status.NodeUID == "" {
+		ctx.String(http.StatusBadRequest, "pod %s is not scheduled yet", pod.Name)
+		return nil, nil, false
+	}
+	return &pod, query, true
+}
+
+func podContainerNames(pod *object.Pod) []string {
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func hasContainer(pod *object.Pod, name string) bool {
+	for _, n := range podContainerNames(pod) {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getCubeletURL returns url of path on the cubelet of the node
+func getCubeletURL(ctx *gin.Context, nodeUID string, path string) (*object.Node, string, bool) {
+	buf, err := etcdrw.GetObj(object.NodeEtcdPrefix + nodeUID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return nil, "", false
+	}
+	if buf == nil {
+		ctx.String(http.StatusNotFound, "node %s not found", nodeUID)
+		return nil, "", false
+	}
+	var node object.Node
+	if err = json.Unmarshal(buf, &node); err != nil {
+		utils.ServerError(ctx)
+		return nil, "", false
+	}
+
+	return &node, "http://" + node.Status.Addresses.InternalIP + ":" + strconv.Itoa(cubeconfig.CubeletPort) + path, true
+}
+
+// getCubeletToken returns the cluster token, requests are never proxied to cubelets without it
+func getCubeletToken(ctx *gin.Context) (string, bool) {
+	token, err := localstorage.LoadCubeletToken()
+	if err != nil {
+		log.Println("[Error]: no cubelet token configured,", err)
+		ctx.String(http.StatusServiceUnavailable, "cubelet token is not configured on apiserver")
+		return "", false
+	}
+	return token, true
+}
+
+// proxyContainerLogs forwards the request to cubelet on the node, and streams the response back
+func proxyContainerLogs(ctx *gin.Context, nodeUID string, path string) {
+	node, cubeletURL, ok := getCubeletURL(ctx, nodeUID, path)
+	if !ok {
+		return
+	}
+	token, ok := getCubeletToken(ctx)
+	if !ok {
+		return
+	}
+
+	// request is cancelled when the client disconnects, which stops followed logs on the node
+	req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, cubeletURL, nil)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	req.Header.Set(cubeconfig.CubeletTokenHeader, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("[Error]: fail to reach cubelet of node %s: %v\n", node.Name, err)
+		ctx.String(http.StatusBadGateway, "fail to reach cubelet of node %s", node.Name)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	ctx.Header("Content-Type", resp.Header.Get("Content-Type"))
+	ctx.Status(resp.StatusCode)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(chunk)
+		if n > 0 {
+			if _, werr := ctx.Writer.Write(chunk[:n]); werr != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+		if err != nil {
+			if err != io.EOF && ctx.Request.Context().Err() == nil {
+				log.Printf("[Error]: log stream from node %s broken: %v\n", node.Name, err)
+			}
+			return
+		}
+	}
+}
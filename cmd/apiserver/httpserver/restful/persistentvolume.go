@@ -0,0 +1,277 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"path/filepath"
+)
+
+func GetPersistentVolume(ctx *gin.Context) {
+	getObj(ctx, object.PersistentVolumeEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetPersistentVolumes(ctx *gin.Context) {
+	getObjs(ctx, object.PersistentVolumeEtcdPrefix)
+}
+
+func PostPersistentVolume(ctx *gin.Context) {
+	pv := object.PersistentVolume{}
+	err := ctx.BindJSON(&pv)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if !checkPersistentVolume(&pv) {
+		utils.BadRequest(ctx)
+		return
+	}
+	if pv.Spec.ReclaimPolicy == "" {
+		pv.Spec.ReclaimPolicy = object.PersistentVolumeReclaimRetain
+	}
+	pv.UID = uuid.New().String()
+	pv.Status = &object.PersistentVolumeStatus{Phase: object.VolumeAvailable}
+	buf, _ := json.Marshal(pv)
+	err = etcdrw.PutObj(object.PersistentVolumeEtcdPrefix+pv.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, pv)
+}
+
+func PutPersistentVolume(ctx *gin.Context) {
+	newPV := object.PersistentVolume{}
+	err := ctx.BindJSON(&newPV)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newPV.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.PersistentVolumeEtcdPrefix + newPV.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if !checkPersistentVolume(&newPV) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newPV)
+	err = etcdrw.PutObj(object.PersistentVolumeEtcdPrefix+newPV.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelPersistentVolume(ctx *gin.Context) {
+	delObj(ctx, object.PersistentVolumeEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectPersistentVolumes(ctx *gin.Context) {
+	selectByLabels(ctx, object.PersistentVolumeEtcdPrefix)
+}
+
+func GetPersistentVolumeClaim(ctx *gin.Context) {
+	getObj(ctx, object.PersistentVolumeClaimEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetPersistentVolumeClaims(ctx *gin.Context) {
+	getObjs(ctx, object.PersistentVolumeClaimEtcdPrefix)
+}
+
+func PostPersistentVolumeClaim(ctx *gin.Context) {
+	pvc := object.PersistentVolumeClaim{}
+	err := ctx.BindJSON(&pvc)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if !checkPersistentVolumeClaim(&pvc) {
+		utils.BadRequest(ctx)
+		return
+	}
+	pvc.UID = uuid.New().String()
+	pvc.Status = &object.PersistentVolumeClaimStatus{Phase: object.ClaimPending}
+	buf, _ := json.Marshal(pvc)
+	err = etcdrw.PutObj(object.PersistentVolumeClaimEtcdPrefix+pvc.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, pvc)
+}
+
+func PutPersistentVolumeClaim(ctx *gin.Context) {
+	newPVC := object.PersistentVolumeClaim{}
+	err := ctx.BindJSON(&newPVC)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newPVC.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.PersistentVolumeClaimEtcdPrefix + newPVC.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	var oldPVC object.PersistentVolumeClaim
+	if err = json.Unmarshal(oldBuf, &oldPVC); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	// a bound claim keeps its volume
+	if oldPVC.Spec.VolumeName != "" && newPVC.Spec.VolumeName != oldPVC.Spec.VolumeName {
+		utils.BadRequest(ctx)
+		return
+	}
+	if !checkPersistentVolumeClaim(&newPVC) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newPVC)
+	err = etcdrw.PutObj(object.PersistentVolumeClaimEtcdPrefix+newPVC.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelPersistentVolumeClaim(ctx *gin.Context) {
+	delObj(ctx, object.PersistentVolumeClaimEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectPersistentVolumeClaims(ctx *gin.Context) {
+	selectByLabels(ctx, object.PersistentVolumeClaimEtcdPrefix)
+}
+
+// checkPersistentVolume checks the volume has exactly one source, and a unique name,
+// since claims refer to it by name
+func checkPersistentVolume(pv *object.PersistentVolume) bool {
+	spec := &pv.Spec
+	if pv.Name == "" || spec.Capacity <= 0 || !checkAccessModes(spec.AccessModes) {
+		return false
+	}
+	switch spec.ReclaimPolicy {
+	case "", object.PersistentVolumeReclaimRetain, object.PersistentVolumeReclaimDelete:
+	default:
+		return false
+	}
+
+	switch {
+	case spec.Local != nil && spec.NFS == nil:
+		// a local volume is only reachable on its node
+		if !filepath.IsAbs(spec.Local.Path) || spec.NodeAffinity == nil ||
+			(len(spec.NodeAffinity.NodeNames) == 0 && len(spec.NodeAffinity.Selector) == 0) {
+			return false
+		}
+	case spec.NFS != nil && spec.Local == nil:
+		if spec.NFS.Server == "" || !filepath.IsAbs(spec.NFS.Path) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if spec.ClaimRef != nil && spec.ClaimRef.Name == "" {
+		return false
+	}
+	return checkUniqueName(object.PersistentVolumeEtcdPrefix, pv.UID, pv.Name)
+}
+
+func checkPersistentVolumeClaim(pvc *object.PersistentVolumeClaim) bool {
+	if pvc.Name == "" || pvc.Spec.Storage <= 0 || !checkAccessModes(pvc.Spec.AccessModes) {
+		return false
+	}
+	return checkUniqueName(object.PersistentVolumeClaimEtcdPrefix, pvc.UID, pvc.Name)
+}
+
+func checkAccessModes(modes []object.PersistentVolumeAccessMode) bool {
+	if len(modes) == 0 {
+		return false
+	}
+	for _, mode := range modes {
+		switch mode {
+		case object.ReadWriteOnce, object.ReadOnlyMany, object.ReadWriteMany:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkUniqueName tells if no other object under prefix has the name
+func checkUniqueName(prefix string, UID string, name string) bool {
+	bufs, err := etcdrw.GetObjs(prefix)
+	if err != nil {
+		return false
+	}
+	for _, buf := range bufs {
+		var obj struct {
+			object.ObjectMeta `json:"metadata"`
+		}
+		if err = json.Unmarshal(buf, &obj); err != nil {
+			continue
+		}
+		if obj.UID != UID && obj.Name == name {
+			return false
+		}
+	}
+	return true
+}
+
+// selectByLabels returns objects under prefix whose labels match the selectors in body
+func selectByLabels(ctx *gin.Context, prefix string) {
+	var selectors map[string]string
+	err := ctx.BindJSON(&selectors)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if len(selectors) == 0 {
+		getObjs(ctx, prefix)
+		return
+	}
+
+	selectObjs(ctx, prefix, func(str []byte) bool {
+		var obj struct {
+			object.ObjectMeta `json:"metadata"`
+		}
+		if err = json.Unmarshal(str, &obj); err != nil {
+			return false
+		}
+		return object.MatchLabelSelector(selectors, obj.Labels)
+	})
+}
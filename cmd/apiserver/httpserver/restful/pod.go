@@ -7,7 +7,12 @@ import (
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"net"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func GetPod(ctx *gin.Context) {
@@ -25,7 +30,21 @@ func PostPod(ctx *gin.Context) {
 		utils.ParseFail(ctx)
 		return
 	}
-	if pod.Name == "" {
+	if pod.Name == "" || !checkPodSpec(&pod.Spec) {
+		utils.BadRequest(ctx)
+		return
+	}
+	// mirror pods are created by cubelet for static pods on its node
+	if object.IsMirrorPod(&pod) && (pod.Status == nil || pod.Status.NodeUID == "") {
+		utils.BadRequest(ctx)
+		return
+	}
+	ok, err := resolvePodPriority(&pod)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if !ok {
 		utils.BadRequest(ctx)
 		return
 	}
@@ -47,7 +66,8 @@ func PutPod(ctx *gin.Context) {
 		return
 	}
 
-	if newPod.UID != ctx.Param("uid") {
+	// the spec is checked as a new one, cubelet syncs updated pods, e.g. their volumes
+	if newPod.UID != ctx.Param("uid") || newPod.Name == "" || !checkPodSpec(&newPod.Spec) {
 		utils.BadRequest(ctx)
 		return
 	}
@@ -62,6 +82,18 @@ func PutPod(ctx *gin.Context) {
 		return
 	}
 
+	// deletion can't be undone by a stale copy of the pod
+	var oldPod object.Pod
+	if err = json.Unmarshal(oldBuf, &oldPod); err == nil && object.IsPodTerminating(&oldPod) {
+		newPod.DeletionTimestamp = oldPod.DeletionTimestamp
+		newPod.DeletionGracePeriodSeconds = oldPod.DeletionGracePeriodSeconds
+	}
+	// mirror pods are read-only except status, static pods are changed by their manifests on the node
+	if err == nil && object.IsMirrorPod(&oldPod) && !isMirrorPodUnchanged(&newPod, &oldPod) {
+		utils.BadRequest(ctx)
+		return
+	}
+
 	newBuf, _ := json.Marshal(newPod)
 	err = etcdrw.PutObj(object.PodEtcdPrefix+newPod.UID, string(newBuf))
 	if err != nil {
@@ -73,8 +105,57 @@ func PutPod(ctx *gin.Context) {
 	ctx.String(http.StatusOK, string(newBuf))
 }
 
+// DelPod marks a pod bound to a node as terminating, and cubelet removes it
+// after its containers stop. Pods not bound, or with gracePeriod=0 in query, are removed at once
 func DelPod(ctx *gin.Context) {
-	delObj(ctx, object.PodEtcdPrefix+ctx.Param("uid"))
+	path := object.PodEtcdPrefix + ctx.Param("uid")
+	buf, err := etcdrw.GetObj(path)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if buf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+
+	var pod object.Pod
+	if err = json.Unmarshal(buf, &pod); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	gracePeriod := object.DefaultTerminationGracePeriodSeconds
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	if query := ctx.Query("gracePeriod"); query != "" {
+		if gracePeriod, err = strconv.ParseInt(query, 10, 64); err != nil || gracePeriod < 0 {
+			utils.BadRequest(ctx)
+			return
+		}
+	}
+
+	if gracePeriod == 0 || pod.Status == nil || pod.Status.NodeUID == "" {
+		delObj(ctx, path)
+		return
+	}
+
+	deletionTimestamp := time.Now().Add(time.Duration(gracePeriod) * time.Second)
+	if object.IsPodTerminating(&pod) && !deletionTimestamp.Before(*pod.DeletionTimestamp) {
+		// already terminating, grace period can only be shortened
+		ctx.String(http.StatusOK, "terminating")
+		return
+	}
+	pod.DeletionTimestamp = &deletionTimestamp
+	pod.DeletionGracePeriodSeconds = &gracePeriod
+
+	newBuf, _ := json.Marshal(pod)
+	if err = etcdrw.PutObj(path, string(newBuf)); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.String(http.StatusOK, "terminating")
 }
 
 func SelectPods(ctx *gin.Context) {
@@ -142,3 +223,204 @@ func UpdatePodStatus(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/json")
 	ctx.String(http.StatusOK, string(newBuf))
 }
+
+func isMirrorPodUnchanged(newPod, oldPod *object.Pod) bool {
+	return newPod.Name == oldPod.Name && newPod.Namespace == oldPod.Namespace &&
+		reflect.DeepEqual(newPod.Labels, oldPod.Labels) && reflect.DeepEqual(newPod.Annotations, oldPod.Annotations) &&
+		reflect.DeepEqual(newPod.Spec, oldPod.Spec)
+}
+
+// checkPodSpec validates spec of a new pod, and sets default values
+func checkPodSpec(spec *object.PodSpec) bool {
+	switch spec.RestartPolicy {
+	case "":
+		spec.RestartPolicy = object.RestartPolicyAlways
+	case object.RestartPolicyAlways, object.RestartPolicyOnFailure, object.RestartPolicyNever:
+	default:
+		return false
+	}
+	if spec.TerminationGracePeriodSeconds != nil && *spec.TerminationGracePeriodSeconds < 0 {
+		return false
+	}
+	if spec.SecurityContext != nil {
+		for _, group := range spec.SecurityContext.SupplementalGroups {
+			if group < 0 {
+				return false
+			}
+		}
+	}
+
+	// containers are named uniquely in the pod, and only init containers can be sidecars
+	names := make(map[string]bool)
+	for idx := range spec.InitContainers {
+		container := &spec.InitContainers[idx]
+		if names[container.Name] || (container.RestartPolicy != "" && !object.IsSidecarContainer(container)) ||
+			!checkImagePullPolicy(container) || !checkSecurityContext(spec, container) || !checkResources(container) {
+			return false
+		}
+		names[container.Name] = true
+	}
+	for idx := range spec.Containers {
+		container := &spec.Containers[idx]
+		if names[container.Name] || container.RestartPolicy != "" || !checkImagePullPolicy(container) ||
+			!checkSecurityContext(spec, container) || !checkResources(container) {
+			return false
+		}
+		names[container.Name] = true
+	}
+	return checkPorts(spec) && checkDNS(spec) && checkVolumes(spec)
+}
+
+// checkDNS checks the DNS policy, default to ClusterFirst, and that dnsConfig fits in resolv.conf.
+// Pods with None policy must have nameservers in dnsConfig
+func checkDNS(spec *object.PodSpec) bool {
+	switch spec.DNSPolicy {
+	case "":
+		spec.DNSPolicy = object.DNSClusterFirst
+	case object.DNSClusterFirst, object.DNSDefault, object.DNSNone:
+	default:
+		return false
+	}
+
+	config := spec.DNSConfig
+	if config == nil {
+		return spec.DNSPolicy != object.DNSNone
+	}
+	if (spec.DNSPolicy == object.DNSNone && len(config.Nameservers) == 0) ||
+		len(config.Nameservers) > object.MaxDNSNameservers || len(config.Searches) > object.MaxDNSSearches {
+		return false
+	}
+	for _, server := range config.Nameservers {
+		if net.ParseIP(server) == nil {
+			return false
+		}
+	}
+	for _, search := range config.Searches {
+		if search == "" || strings.ContainsAny(search, " \t") {
+			return false
+		}
+	}
+	for _, option := range config.Options {
+		if option.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPorts checks ports of containers, and that no host port is exposed twice by the pod.
+// Protocol defaults to TCP, and host ports of hostNetwork pods default to their container ports
+func checkPorts(spec *object.PodSpec) bool {
+	containers := make([]*object.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	for idx := range spec.InitContainers {
+		containers = append(containers, &spec.InitContainers[idx])
+	}
+	for idx := range spec.Containers {
+		containers = append(containers, &spec.Containers[idx])
+	}
+
+	hostPorts := make(map[string]bool)
+	for _, container := range containers {
+		for idx := range container.Ports {
+			port := &container.Ports[idx]
+			port.Protocol = strings.ToUpper(port.Protocol)
+			switch object.Protocol(port.Protocol) {
+			case "":
+				port.Protocol = string(object.ProtocolTCP)
+			case object.ProtocolTCP, object.ProtocolUDP, object.ProtocolSCTP:
+			default:
+				return false
+			}
+			if spec.HostNetwork && port.HostPort == 0 {
+				port.HostPort = port.ContainerPort
+			}
+			if port.ContainerPort <= 0 || port.ContainerPort > 65535 || port.HostPort < 0 || port.HostPort > 65535 ||
+				(spec.HostNetwork && port.HostPort != port.ContainerPort) ||
+				(port.HostIP != "" && net.ParseIP(port.HostIP) == nil) {
+				return false
+			}
+			if port.HostPort == 0 {
+				continue
+			}
+			key := port.Protocol + "/" + net.JoinHostPort(port.HostIP, strconv.Itoa(int(port.HostPort)))
+			if hostPorts[key] {
+				return false
+			}
+			hostPorts[key] = true
+		}
+	}
+	return true
+}
+
+// checkVolumes checks that volumes are named uniquely with exactly one source,
+// and every volume mount refers to a volume of the pod
+func checkVolumes(spec *object.PodSpec) bool {
+	volumes := make(map[string]bool)
+	for idx := range spec.Volumes {
+		volume := &spec.Volumes[idx]
+		if volumes[volume.Name] || object.CheckVolume(volume) != nil {
+			return false
+		}
+		volumes[volume.Name] = true
+	}
+
+	containers := append(append([]object.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, mount := range container.VolumeMounts {
+			if !volumes[mount.Name] || mount.MountPath == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkSecurityContext checks the security context effective for the container
+func checkSecurityContext(spec *object.PodSpec, container *object.Container) bool {
+	sc := object.EffectiveSecurityContext(spec, container)
+	if sc == nil {
+		return true
+	}
+	if (sc.RunAsUser != nil && *sc.RunAsUser < 0) || (sc.RunAsGroup != nil && *sc.RunAsGroup < 0) {
+		return false
+	}
+	// docker can't run as a group without a user
+	if sc.RunAsGroup != nil && sc.RunAsUser == nil {
+		return false
+	}
+	// privileged containers can always escalate
+	if sc.Privileged != nil && *sc.Privileged && sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation {
+		return false
+	}
+	return true
+}
+
+// checkResources checks that requests don't exceed limits of the container
+func checkResources(container *object.Container) bool {
+	resources := container.Resources
+	if resources == nil {
+		return true
+	}
+	if resources.Cpus < 0 || resources.Memory < 0 {
+		return false
+	}
+	if requests := resources.Requests; requests != nil {
+		if requests.Cpus < 0 || requests.Memory < 0 ||
+			(resources.Cpus > 0 && requests.Cpus > resources.Cpus) ||
+			(resources.Memory > 0 && requests.Memory > resources.Memory) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkImagePullPolicy(container *object.Container) bool {
+	switch container.ImagePullPolicy {
+	case "":
+		container.ImagePullPolicy = object.DefaultImagePullPolicy(container.Image)
+	case object.PullAlways, object.PullIfNotPresent, object.PullNever:
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+func GetPodGroup(ctx *gin.Context) {
+	getObj(ctx, object.PodGroupEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetPodGroups(ctx *gin.Context) {
+	getObjs(ctx, object.PodGroupEtcdPrefix)
+}
+
+func PostPodGroup(ctx *gin.Context) {
+	podGroup := object.PodGroup{}
+	err := ctx.BindJSON(&podGroup)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if podGroup.Name == "" || podGroup.Spec.MinMember < 1 {
+		utils.BadRequest(ctx)
+		return
+	}
+	if !checkPodGroupName(&podGroup) {
+		utils.BadRequest(ctx)
+		return
+	}
+	podGroup.UID = uuid.New().String()
+	podGroup.Status = &object.PodGroupStatus{
+		Phase:          object.PodGroupPending,
+		LastUpdateTime: time.Now(),
+	}
+	buf, _ := json.Marshal(podGroup)
+	err = etcdrw.PutObj(object.PodGroupEtcdPrefix+podGroup.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, podGroup)
+}
+
+func PutPodGroup(ctx *gin.Context) {
+	newPodGroup := object.PodGroup{}
+	err := ctx.BindJSON(&newPodGroup)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newPodGroup.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.PodGroupEtcdPrefix + newPodGroup.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if newPodGroup.Spec.MinMember < 1 || !checkPodGroupName(&newPodGroup) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newPodGroup)
+	err = etcdrw.PutObj(object.PodGroupEtcdPrefix+newPodGroup.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelPodGroup(ctx *gin.Context) {
+	delObj(ctx, object.PodGroupEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectPodGroups(ctx *gin.Context) {
+	var selectors map[string]string
+	err := ctx.BindJSON(&selectors)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if len(selectors) == 0 {
+		getObjs(ctx, object.PodGroupEtcdPrefix)
+		return
+	}
+
+	selectObjs(ctx, object.PodGroupEtcdPrefix, func(str []byte) bool {
+		var podGroup object.PodGroup
+		err = json.Unmarshal(str, &podGroup)
+		if err != nil {
+			return false
+		}
+
+		for key, val := range selectors {
+			v := podGroup.Labels[key]
+			if v != val {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// checkPodGroupName makes sure group names are unique,
+// since pods refer to their group by name
+func checkPodGroupName(podGroup *object.PodGroup) bool {
+	bufs, err := etcdrw.GetObjs(object.PodGroupEtcdPrefix)
+	if err != nil {
+		return false
+	}
+	for _, buf := range bufs {
+		var pg object.PodGroup
+		if err = json.Unmarshal(buf, &pg); err != nil {
+			continue
+		}
+		if pg.UID != podGroup.UID && pg.Name == podGroup.Name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func GetPriorityClass(ctx *gin.Context) {
+	getObj(ctx, object.PriorityClassEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetPriorityClasses(ctx *gin.Context) {
+	getObjs(ctx, object.PriorityClassEtcdPrefix)
+}
+
+func PostPriorityClass(ctx *gin.Context) {
+	priorityClass := object.PriorityClass{}
+	err := ctx.BindJSON(&priorityClass)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if priorityClass.Name == "" {
+		utils.BadRequest(ctx)
+		return
+	}
+	if !checkPriorityClass(&priorityClass) {
+		utils.BadRequest(ctx)
+		return
+	}
+	if priorityClass.PreemptionPolicy == "" {
+		priorityClass.PreemptionPolicy = object.PreemptLowerPriority
+	}
+	priorityClass.UID = uuid.New().String()
+	buf, _ := json.Marshal(priorityClass)
+	err = etcdrw.PutObj(object.PriorityClassEtcdPrefix+priorityClass.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, priorityClass)
+}
+
+func PutPriorityClass(ctx *gin.Context) {
+	newPriorityClass := object.PriorityClass{}
+	err := ctx.BindJSON(&newPriorityClass)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newPriorityClass.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.PriorityClassEtcdPrefix + newPriorityClass.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if !checkPriorityClass(&newPriorityClass) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newPriorityClass)
+	err = etcdrw.PutObj(object.PriorityClassEtcdPrefix+newPriorityClass.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelPriorityClass(ctx *gin.Context) {
+	delObj(ctx, object.PriorityClassEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectPriorityClasses(ctx *gin.Context) {
+	var selectors map[string]string
+	err := ctx.BindJSON(&selectors)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if len(selectors) == 0 {
+		getObjs(ctx, object.PriorityClassEtcdPrefix)
+		return
+	}
+
+	selectObjs(ctx, object.PriorityClassEtcdPrefix, func(str []byte) bool {
+		var priorityClass object.PriorityClass
+		err = json.Unmarshal(str, &priorityClass)
+		if err != nil {
+			return false
+		}
+
+		for key, val := range selectors {
+			v := priorityClass.Labels[key]
+			if v != val {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func getPriorityClasses() ([]object.PriorityClass, error) {
+	bufs, err := etcdrw.GetObjs(object.PriorityClassEtcdPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityClasses := make([]object.PriorityClass, 0, len(bufs))
+	for _, buf := range bufs {
+		var priorityClass object.PriorityClass
+		if err = json.Unmarshal(buf, &priorityClass); err != nil {
+			continue
+		}
+		priorityClasses = append(priorityClasses, priorityClass)
+	}
+	return priorityClasses, nil
+}
+
+// checkPriorityClass makes sure class names are unique,
+// and there is at most one global default class
+func checkPriorityClass(priorityClass *object.PriorityClass) bool {
+	switch priorityClass.PreemptionPolicy {
+	case "", object.PreemptLowerPriority, object.PreemptNever:
+	default:
+		return false
+	}
+
+	priorityClasses, err := getPriorityClasses()
+	if err != nil {
+		return false
+	}
+	for _, pc := range priorityClasses {
+		if pc.UID == priorityClass.UID {
+			continue
+		}
+		if pc.Name == priorityClass.Name {
+			return false
+		}
+		if pc.GlobalDefault && priorityClass.GlobalDefault {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePodPriority sets priority of a new pod from its priority class,
+// or from the global default class if priorityClassName is not set.
+// It returns false if the class named by the pod does not exist
+func resolvePodPriority(pod *object.Pod) (bool, error) {
+	priorityClasses, err := getPriorityClasses()
+	if err != nil {
+		return false, err
+	}
+
+	var found *object.PriorityClass
+	for i, pc := range priorityClasses {
+		if pod.Spec.PriorityClassName == "" && pc.GlobalDefault {
+			found = &priorityClasses[i]
+			break
+		}
+		if pod.Spec.PriorityClassName != "" && pc.Name == pod.Spec.PriorityClassName {
+			found = &priorityClasses[i]
+			break
+		}
+	}
+
+	if found == nil {
+		if pod.Spec.PriorityClassName != "" {
+			return false, nil
+		}
+		priority := object.DefaultPriority
+		pod.Spec.Priority = &priority
+		pod.Spec.PreemptionPolicy = object.PreemptLowerPriority
+		return true, nil
+	}
+
+	priority := found.Value
+	pod.Spec.PriorityClassName = found.Name
+	pod.Spec.Priority = &priority
+	pod.Spec.PreemptionPolicy = found.PreemptionPolicy
+	if pod.Spec.PreemptionPolicy == "" {
+		pod.Spec.PreemptionPolicy = object.PreemptLowerPriority
+	}
+	return true, nil
+}
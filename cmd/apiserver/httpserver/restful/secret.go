@@ -0,0 +1,156 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func GetSecret(ctx *gin.Context) {
+	getObj(ctx, object.SecretEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetSecrets(ctx *gin.Context) {
+	getObjs(ctx, object.SecretEtcdPrefix)
+}
+
+func PostSecret(ctx *gin.Context) {
+	secret := object.Secret{}
+	err := ctx.BindJSON(&secret)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if !checkSecret(&secret) {
+		utils.BadRequest(ctx)
+		return
+	}
+	secret.UID = uuid.New().String()
+	buf, _ := json.Marshal(secret)
+	err = etcdrw.PutObj(object.SecretEtcdPrefix+secret.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, secret)
+}
+
+func PutSecret(ctx *gin.Context) {
+	newSecret := object.Secret{}
+	err := ctx.BindJSON(&newSecret)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newSecret.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.SecretEtcdPrefix + newSecret.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if !checkSecret(&newSecret) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newSecret)
+	err = etcdrw.PutObj(object.SecretEtcdPrefix+newSecret.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelSecret(ctx *gin.Context) {
+	delObj(ctx, object.SecretEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectSecrets(ctx *gin.Context) {
+	var selectors map[string]string
+	err := ctx.BindJSON(&selectors)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if len(selectors) == 0 {
+		getObjs(ctx, object.SecretEtcdPrefix)
+		return
+	}
+
+	selectObjs(ctx, object.SecretEtcdPrefix, func(str []byte) bool {
+		var secret object.Secret
+		err = json.Unmarshal(str, &secret)
+		if err != nil {
+			return false
+		}
+
+		for key, val := range selectors {
+			v := secret.Labels[key]
+			if v != val {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// checkSecret makes sure secret names are unique and values are base64 encoded,
+// values in StringData are encoded into Data
+func checkSecret(secret *object.Secret) bool {
+	if secret.Name == "" {
+		return false
+	}
+	if secret.Type == "" {
+		secret.Type = object.SecretTypeOpaque
+	}
+	for _, value := range secret.Data {
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return false
+		}
+	}
+	if len(secret.StringData) != 0 {
+		if secret.Data == nil {
+			secret.Data = make(map[string]string)
+		}
+		for key, value := range secret.StringData {
+			secret.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		secret.StringData = nil
+	}
+	if _, ok := secret.Data[object.DockerConfigJsonKey]; secret.Type == object.SecretTypeDockerConfigJson && !ok {
+		return false
+	}
+
+	bufs, err := etcdrw.GetObjs(object.SecretEtcdPrefix)
+	if err != nil {
+		return false
+	}
+	for _, buf := range bufs {
+		var s object.Secret
+		if err = json.Unmarshal(buf, &s); err != nil {
+			continue
+		}
+		if s.UID != secret.UID && s.Name == secret.Name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/etcdrw"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"path/filepath"
+)
+
+func GetStorageClass(ctx *gin.Context) {
+	getObj(ctx, object.StorageClassEtcdPrefix+ctx.Param("uid"))
+}
+
+func GetStorageClasses(ctx *gin.Context) {
+	getObjs(ctx, object.StorageClassEtcdPrefix)
+}
+
+func PostStorageClass(ctx *gin.Context) {
+	storageClass := object.StorageClass{}
+	err := ctx.BindJSON(&storageClass)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+	if !checkStorageClass(&storageClass) {
+		utils.BadRequest(ctx)
+		return
+	}
+	storageClass.ReclaimPolicy = object.GetStorageClassReclaimPolicy(&storageClass)
+	storageClass.VolumeBindingMode = object.GetVolumeBindingMode(&storageClass)
+	storageClass.UID = uuid.New().String()
+	buf, _ := json.Marshal(storageClass)
+	err = etcdrw.PutObj(object.StorageClassEtcdPrefix+storageClass.UID, string(buf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	ctx.JSON(http.StatusOK, storageClass)
+}
+
+func PutStorageClass(ctx *gin.Context) {
+	newStorageClass := object.StorageClass{}
+	err := ctx.BindJSON(&newStorageClass)
+	if err != nil {
+		utils.ParseFail(ctx)
+		return
+	}
+
+	if newStorageClass.UID != ctx.Param("uid") {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	oldBuf, err := etcdrw.GetObj(object.StorageClassEtcdPrefix + newStorageClass.UID)
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if oldBuf == nil {
+		utils.NotFound(ctx)
+		return
+	}
+	if !checkStorageClass(&newStorageClass) {
+		utils.BadRequest(ctx)
+		return
+	}
+
+	newBuf, _ := json.Marshal(newStorageClass)
+	err = etcdrw.PutObj(object.StorageClassEtcdPrefix+newStorageClass.UID, string(newBuf))
+	if err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.String(http.StatusOK, string(newBuf))
+}
+
+func DelStorageClass(ctx *gin.Context) {
+	delObj(ctx, object.StorageClassEtcdPrefix+ctx.Param("uid"))
+}
+
+func SelectStorageClasses(ctx *gin.Context) {
+	selectByLabels(ctx, object.StorageClassEtcdPrefix)
+}
+
+// checkStorageClass checks parameters of the provisioner, and makes sure
+// class names are unique, since claims refer to them by name
+func checkStorageClass(storageClass *object.StorageClass) bool {
+	if storageClass.Name == "" {
+		return false
+	}
+	switch object.GetStorageClassReclaimPolicy(storageClass) {
+	case object.PersistentVolumeReclaimRetain, object.PersistentVolumeReclaimDelete:
+	default:
+		return false
+	}
+	mode := object.GetVolumeBindingMode(storageClass)
+	if mode != object.VolumeBindingImmediate && mode != object.VolumeBindingWaitForFirstConsumer {
+		return false
+	}
+
+	switch storageClass.Provisioner {
+	case object.LocalProvisioner:
+		// the node is unknown until a pod uses the claim
+		if mode != object.VolumeBindingWaitForFirstConsumer {
+			return false
+		}
+	case object.NFSProvisioner:
+		if storageClass.Parameters[object.StorageClassParamServer] == "" ||
+			!filepath.IsAbs(storageClass.Parameters[object.StorageClassParamPath]) {
+			return false
+		}
+	case object.NoProvisioner:
+	default:
+		return false
+	}
+	return checkUniqueName(object.StorageClassEtcdPrefix, storageClass.UID, storageClass.Name)
+}
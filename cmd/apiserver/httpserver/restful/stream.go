@@ -0,0 +1,100 @@
+package restful
+
+import (
+	"Cubernetes/cmd/apiserver/httpserver/utils"
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// ExecPod proxies an exec stream to the cubelet of the pod node
+func ExecPod(ctx *gin.Context) {
+	streamPod(ctx, "/exec/pod/")
+}
+
+func AttachPod(ctx *gin.Context) {
+	streamPod(ctx, "/attach/pod/")
+}
+
+func PortForwardPod(ctx *gin.Context) {
+	if _, err := object.ParsePortForwardPort(ctx.Request.URL.Query()); err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if !remotecommand.IsUpgradeRequest(ctx.Request) {
+		ctx.String(http.StatusBadRequest, "upgrade to %s is required", remotecommand.StreamProtocol)
+		return
+	}
+
+	buf, ok := getTarget(ctx, object.PodEtcdPrefix+ctx.Param("uid"))
+	if !ok {
+		return
+	}
+	var pod object.Pod
+	if err := json.Unmarshal(buf, &pod); err != nil {
+		utils.ServerError(ctx)
+		return
+	}
+	if pod.Status == nil || pod.Status.NodeUID == "" {
+		ctx.String(http.StatusBadRequest, "pod %s is not scheduled yet", pod.Name)
+		return
+	}
+
+	proxyStream(ctx, pod.Status.NodeUID, "/portForward/pod/"+pod.UID+"?"+ctx.Request.URL.RawQuery)
+}
+
+func streamPod(ctx *gin.Context, prefix string) {
+	if _, err := object.ParseExecOptions(ctx.Request.URL.Query()); err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if !remotecommand.IsUpgradeRequest(ctx.Request) {
+		ctx.String(http.StatusBadRequest, "upgrade to %s is required", remotecommand.StreamProtocol)
+		return
+	}
+	pod, query, ok := getPodContainerTarget(ctx)
+	if !ok {
+		return
+	}
+
+	proxyStream(ctx, pod.Status.NodeUID, prefix+pod.UID+"?"+query.Encode())
+}
+
+// proxyStream upgrades the request once cubelet accepts it, and pipes the two connections
+func proxyStream(ctx *gin.Context, nodeUID string, path string) {
+	node, cubeletURL, ok := getCubeletURL(ctx, nodeUID, path)
+	if !ok {
+		return
+	}
+	token, ok := getCubeletToken(ctx)
+	if !ok {
+		return
+	}
+
+	header := http.Header{}
+	header.Set(cubeconfig.CubeletTokenHeader, token)
+	upstream, err := remotecommand.Dial(cubeletURL, header)
+	if err != nil {
+		var upgradeErr *remotecommand.UpgradeError
+		if errors.As(err, &upgradeErr) {
+			ctx.String(upgradeErr.StatusCode, upgradeErr.Message)
+			return
+		}
+		log.Printf("[Error]: fail to reach cubelet of node %s: %v\n", node.Name, err)
+		ctx.String(http.StatusBadGateway, "fail to reach cubelet of node %s", node.Name)
+		return
+	}
+
+	conn, err := remotecommand.Upgrade(ctx.Writer, ctx.Request)
+	if err != nil {
+		log.Printf("[Error]: fail to upgrade stream request: %v\n", err)
+		_ = upstream.Close()
+		return
+	}
+	remotecommand.Pipe(conn, upstream)
+}
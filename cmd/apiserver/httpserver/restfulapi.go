@@ -25,6 +25,10 @@ var restfulList = []Handler{
 	{http.MethodDelete, "/apis/pod/:uid", restful.DelPod},
 	{http.MethodPost, "/apis/select/pods", restful.SelectPods},
 	{http.MethodPut, "/apis/pod/status/:uid", restful.UpdatePodStatus},
+	{http.MethodGet, "/apis/pod/:uid/log", restful.GetPodLog},
+	{http.MethodGet, "/apis/pod/:uid/exec", restful.ExecPod},
+	{http.MethodGet, "/apis/pod/:uid/attach", restful.AttachPod},
+	{http.MethodGet, "/apis/pod/:uid/portForward", restful.PortForwardPod},
 
 	{http.MethodGet, "/apis/service/:uid", restful.GetService},
 	{http.MethodGet, "/apis/services", restful.GetServices},
@@ -67,6 +71,7 @@ var restfulList = []Handler{
 	{http.MethodPut, "/apis/gpuJob/:uid", restful.PutGpuJob},
 	{http.MethodDelete, "/apis/gpuJob/:uid", restful.DelGpuJob},
 	{http.MethodPost, "/apis/select/gpuJobs", restful.SelectGpuJobs},
+	{http.MethodGet, "/apis/gpuJob/:uid/log", restful.GetGpuJobLog},
 
 	{http.MethodGet, "/apis/action/:uid", restful.GetAction},
 	{http.MethodGet, "/apis/actions", restful.GetActions},
@@ -81,6 +86,7 @@ var restfulList = []Handler{
 	{http.MethodPut, "/apis/actor/:uid", restful.PutActor},
 	{http.MethodDelete, "/apis/actor/:uid", restful.DelActor},
 	{http.MethodPost, "/apis/select/actors", restful.SelectActors},
+	{http.MethodGet, "/apis/actor/:uid/log", restful.GetActorLog},
 
 	{http.MethodGet, "/apis/ingress/:uid", restful.GetIngress},
 	{http.MethodGet, "/apis/ingresses", restful.GetIngresses},
@@ -89,5 +95,54 @@ var restfulList = []Handler{
 	{http.MethodDelete, "/apis/ingress/:uid", restful.DelIngress},
 	{http.MethodPost, "/apis/select/ingresses", restful.SelectIngresses},
 
+	{http.MethodGet, "/apis/priorityClass/:uid", restful.GetPriorityClass},
+	{http.MethodGet, "/apis/priorityClasses", restful.GetPriorityClasses},
+	{http.MethodPost, "/apis/priorityClass", restful.PostPriorityClass},
+	{http.MethodPut, "/apis/priorityClass/:uid", restful.PutPriorityClass},
+	{http.MethodDelete, "/apis/priorityClass/:uid", restful.DelPriorityClass},
+	{http.MethodPost, "/apis/select/priorityClasses", restful.SelectPriorityClasses},
+
+	{http.MethodGet, "/apis/podGroup/:uid", restful.GetPodGroup},
+	{http.MethodGet, "/apis/podGroups", restful.GetPodGroups},
+	{http.MethodPost, "/apis/podGroup", restful.PostPodGroup},
+	{http.MethodPut, "/apis/podGroup/:uid", restful.PutPodGroup},
+	{http.MethodDelete, "/apis/podGroup/:uid", restful.DelPodGroup},
+	{http.MethodPost, "/apis/select/podGroups", restful.SelectPodGroups},
+
+	{http.MethodGet, "/apis/configMap/:uid", restful.GetConfigMap},
+	{http.MethodGet, "/apis/configMaps", restful.GetConfigMaps},
+	{http.MethodPost, "/apis/configMap", restful.PostConfigMap},
+	{http.MethodPut, "/apis/configMap/:uid", restful.PutConfigMap},
+	{http.MethodDelete, "/apis/configMap/:uid", restful.DelConfigMap},
+	{http.MethodPost, "/apis/select/configMaps", restful.SelectConfigMaps},
+
+	{http.MethodGet, "/apis/secret/:uid", restful.GetSecret},
+	{http.MethodGet, "/apis/secrets", restful.GetSecrets},
+	{http.MethodPost, "/apis/secret", restful.PostSecret},
+	{http.MethodPut, "/apis/secret/:uid", restful.PutSecret},
+	{http.MethodDelete, "/apis/secret/:uid", restful.DelSecret},
+	{http.MethodPost, "/apis/select/secrets", restful.SelectSecrets},
+
+	{http.MethodGet, "/apis/persistentVolume/:uid", restful.GetPersistentVolume},
+	{http.MethodGet, "/apis/persistentVolumes", restful.GetPersistentVolumes},
+	{http.MethodPost, "/apis/persistentVolume", restful.PostPersistentVolume},
+	{http.MethodPut, "/apis/persistentVolume/:uid", restful.PutPersistentVolume},
+	{http.MethodDelete, "/apis/persistentVolume/:uid", restful.DelPersistentVolume},
+	{http.MethodPost, "/apis/select/persistentVolumes", restful.SelectPersistentVolumes},
+
+	{http.MethodGet, "/apis/persistentVolumeClaim/:uid", restful.GetPersistentVolumeClaim},
+	{http.MethodGet, "/apis/persistentVolumeClaims", restful.GetPersistentVolumeClaims},
+	{http.MethodPost, "/apis/persistentVolumeClaim", restful.PostPersistentVolumeClaim},
+	{http.MethodPut, "/apis/persistentVolumeClaim/:uid", restful.PutPersistentVolumeClaim},
+	{http.MethodDelete, "/apis/persistentVolumeClaim/:uid", restful.DelPersistentVolumeClaim},
+	{http.MethodPost, "/apis/select/persistentVolumeClaims", restful.SelectPersistentVolumeClaims},
+
+	{http.MethodGet, "/apis/storageClass/:uid", restful.GetStorageClass},
+	{http.MethodGet, "/apis/storageClasses", restful.GetStorageClasses},
+	{http.MethodPost, "/apis/storageClass", restful.PostStorageClass},
+	{http.MethodPut, "/apis/storageClass/:uid", restful.PutStorageClass},
+	{http.MethodDelete, "/apis/storageClass/:uid", restful.DelStorageClass},
+	{http.MethodPost, "/apis/select/storageClasses", restful.SelectStorageClasses},
+
 	{http.MethodGet, "/apis/workflow", restful.GetWorkflow},
 }
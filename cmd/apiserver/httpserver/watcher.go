@@ -43,6 +43,21 @@ var watchList = []Handler{
 
 	{http.MethodPost, "/apis/watch/ingress/:uid", watchIngress},
 	{http.MethodPost, "/apis/watch/ingresses", watchIngresses},
+
+	{http.MethodPost, "/apis/watch/priorityClass/:uid", watchPriorityClass},
+	{http.MethodPost, "/apis/watch/priorityClasses", watchPriorityClasses},
+
+	{http.MethodPost, "/apis/watch/podGroup/:uid", watchPodGroup},
+	{http.MethodPost, "/apis/watch/podGroups", watchPodGroups},
+
+	{http.MethodPost, "/apis/watch/configMap/:uid", watchConfigMap},
+	{http.MethodPost, "/apis/watch/configMaps", watchConfigMaps},
+
+	{http.MethodPost, "/apis/watch/secret/:uid", watchSecret},
+	{http.MethodPost, "/apis/watch/secrets", watchSecrets},
+
+	{http.MethodPost, "/apis/watch/persistentVolumes", watchPersistentVolumes},
+	{http.MethodPost, "/apis/watch/persistentVolumeClaims", watchPersistentVolumeClaims},
 }
 
 func handleEvent(ctx *gin.Context, e *clientv3.Event) {
@@ -179,3 +194,43 @@ func watchIngress(ctx *gin.Context) {
 func watchIngresses(ctx *gin.Context) {
 	postWatch(ctx, object.IngressEtcdPrefix, true)
 }
+
+func watchPriorityClass(ctx *gin.Context) {
+	postWatch(ctx, object.PriorityClassEtcdPrefix+ctx.Param("uid"), false)
+}
+
+func watchPriorityClasses(ctx *gin.Context) {
+	postWatch(ctx, object.PriorityClassEtcdPrefix, true)
+}
+
+func watchPodGroup(ctx *gin.Context) {
+	postWatch(ctx, object.PodGroupEtcdPrefix+ctx.Param("uid"), false)
+}
+
+func watchPodGroups(ctx *gin.Context) {
+	postWatch(ctx, object.PodGroupEtcdPrefix, true)
+}
+
+func watchConfigMap(ctx *gin.Context) {
+	postWatch(ctx, object.ConfigMapEtcdPrefix+ctx.Param("uid"), false)
+}
+
+func watchConfigMaps(ctx *gin.Context) {
+	postWatch(ctx, object.ConfigMapEtcdPrefix, true)
+}
+
+func watchSecret(ctx *gin.Context) {
+	postWatch(ctx, object.SecretEtcdPrefix+ctx.Param("uid"), false)
+}
+
+func watchSecrets(ctx *gin.Context) {
+	postWatch(ctx, object.SecretEtcdPrefix, true)
+}
+
+func watchPersistentVolumes(ctx *gin.Context) {
+	postWatch(ctx, object.PersistentVolumeEtcdPrefix, true)
+}
+
+func watchPersistentVolumeClaims(ctx *gin.Context) {
+	postWatch(ctx, object.PersistentVolumeClaimEtcdPrefix, true)
+}
@@ -177,6 +177,90 @@ for example:
 			}
 			log.Printf("Ingress UID=%s created\n", newIngress.UID)
 
+		case object.KindPriorityClass:
+			var priorityClass object.PriorityClass
+			err = yaml.Unmarshal(file, &priorityClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse PriorityClass", err)
+			}
+			newPriorityClass, err := crudobj.CreatePriorityClass(priorityClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new PriorityClass")
+			}
+			log.Printf("PriorityClass UID=%s created\n", newPriorityClass.UID)
+
+		case object.KindPodGroup:
+			var podGroup object.PodGroup
+			err = yaml.Unmarshal(file, &podGroup)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse PodGroup", err)
+			}
+			newPodGroup, err := crudobj.CreatePodGroup(podGroup)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new PodGroup")
+			}
+			log.Printf("PodGroup UID=%s created\n", newPodGroup.UID)
+
+		case object.KindConfigMap:
+			var configMap object.ConfigMap
+			err = yaml.Unmarshal(file, &configMap)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse ConfigMap", err)
+			}
+			newConfigMap, err := crudobj.CreateConfigMap(configMap)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new ConfigMap")
+			}
+			log.Printf("ConfigMap UID=%s created\n", newConfigMap.UID)
+
+		case object.KindSecret:
+			var secret object.Secret
+			err = yaml.Unmarshal(file, &secret)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse Secret", err)
+			}
+			newSecret, err := crudobj.CreateSecret(secret)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new Secret")
+			}
+			log.Printf("Secret UID=%s created\n", newSecret.UID)
+
+		case object.KindPersistentVolume:
+			var persistentVolume object.PersistentVolume
+			err = yaml.Unmarshal(file, &persistentVolume)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse PersistentVolume", err)
+			}
+			newPersistentVolume, err := crudobj.CreatePersistentVolume(persistentVolume)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new PersistentVolume")
+			}
+			log.Printf("PersistentVolume UID=%s created\n", newPersistentVolume.UID)
+
+		case object.KindPersistentVolumeClaim:
+			var persistentVolumeClaim object.PersistentVolumeClaim
+			err = yaml.Unmarshal(file, &persistentVolumeClaim)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse PersistentVolumeClaim", err)
+			}
+			newPersistentVolumeClaim, err := crudobj.CreatePersistentVolumeClaim(persistentVolumeClaim)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new PersistentVolumeClaim")
+			}
+			log.Printf("PersistentVolumeClaim UID=%s created\n", newPersistentVolumeClaim.UID)
+
+		case object.KindStorageClass:
+			var storageClass object.StorageClass
+			err = yaml.Unmarshal(file, &storageClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to parse StorageClass", err)
+			}
+			newStorageClass, err := crudobj.CreateStorageClass(storageClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to create new StorageClass")
+			}
+			log.Printf("StorageClass UID=%s created\n", newStorageClass.UID)
+
 		default:
 			log.Fatal("[FATAL] Unknown kind: " + t.Kind)
 		}
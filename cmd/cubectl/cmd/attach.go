@@ -0,0 +1,50 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// attachCmd represents the attach command
+var attachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Attach to a running container",
+	Long: `
+Attach to the main process of a running container in a pod,
+stdin can only be attached if the container keeps it open
+for example:
+	cubectl attach 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl attach -it -c shell 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl attach [-i] [-t] [-c container] [Pod UID]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+
+		opts := object.ExecOptions{}
+		opts.Container, _ = cmd.Flags().GetString("container")
+		opts.Stdin, _ = cmd.Flags().GetBool("stdin")
+		opts.TTY, _ = cmd.Flags().GetBool("tty")
+
+		conn, err := crudobj.AttachPod(args[0], opts)
+		if err != nil {
+			log.Fatal("[FATAL] fail to attach Pod, err: ", err)
+		}
+		os.Exit(serveRemoteStream(conn, opts.Stdin, opts.TTY && opts.Stdin))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	attachCmd.Flags().StringP("container", "c", "", "container name, can be omitted if the pod has only one container")
+	attachCmd.Flags().BoolP("stdin", "i", false, "pass stdin to the container")
+	attachCmd.Flags().BoolP("tty", "t", false, "stdin is a TTY, the container must be created with tty")
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// cordonCmd represents the cordon command
+var cordonCmd = &cobra.Command{
+	Use:   "cordon",
+	Short: "Mark a node as unschedulable",
+	Long: `
+Mark a node as unschedulable, running pods are not affected
+for example:
+	cubectl cordon 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl cordon [Node UID]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		err := setNodeUnschedulable(args[0], true)
+		if err != nil {
+			log.Fatal("[FATAL] fail to cordon Node, err: ", err)
+		}
+		fmt.Printf("Node UID=%s cordoned\n", args[0])
+	},
+}
+
+// uncordonCmd represents the uncordon command
+var uncordonCmd = &cobra.Command{
+	Use:   "uncordon",
+	Short: "Mark a node as schedulable",
+	Long: `
+Mark a node as schedulable again
+for example:
+	cubectl uncordon 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl uncordon [Node UID]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		err := setNodeUnschedulable(args[0], false)
+		if err != nil {
+			log.Fatal("[FATAL] fail to uncordon Node, err: ", err)
+		}
+		fmt.Printf("Node UID=%s uncordoned\n", args[0])
+	},
+}
+
+func setNodeUnschedulable(UID string, unschedulable bool) error {
+	node, err := crudobj.GetNode(UID)
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = unschedulable
+	_, err = crudobj.UpdateNode(node)
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(cordonCmd)
+	rootCmd.AddCommand(uncordonCmd)
+}
@@ -20,6 +20,8 @@ var deleteCmd = &cobra.Command{
 Delete an object from Cubernetes
 for example:
 	cubectl delete pod nginx:452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl delete pod 452cbd60-131c-4efa-9e06-7b364692a737 --grace-period 5
+	cubectl delete pod 452cbd60-131c-4efa-9e06-7b364692a737 --force
 	cubectl delete [Object kind] [UID]
 `,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -28,11 +30,22 @@ for example:
 		}
 		switch strings.ToLower(args[0]) {
 		case "pod":
-			err := crudobj.DeletePod(args[1])
+			gracePeriod, _ := cmd.Flags().GetInt64("grace-period")
+			if force, _ := cmd.Flags().GetBool("force"); force {
+				gracePeriod = 0
+			}
+			var err error
+			if gracePeriod < 0 {
+				err = crudobj.DeletePod(args[1])
+			} else {
+				err = crudobj.DeletePodWithGracePeriod(args[1], gracePeriod)
+			}
 			if err != nil {
 				log.Fatal("[FATAL] fail to delete Pod")
-			} else {
+			} else if gracePeriod == 0 {
 				fmt.Printf("Pod UID=%s deleted\n", args[1])
+			} else {
+				fmt.Printf("Pod UID=%s terminating\n", args[1])
 			}
 		case "service", "svc":
 			err := crudobj.DeleteService(args[1])
@@ -83,6 +96,55 @@ for example:
 			} else {
 				fmt.Printf("Ingress UID=%s deleted\n", args[1])
 			}
+		case "priorityclass", "pc":
+			err := crudobj.DeletePriorityClass(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete PriorityClass")
+			} else {
+				fmt.Printf("PriorityClass UID=%s deleted\n", args[1])
+			}
+		case "podgroup", "pg":
+			err := crudobj.DeletePodGroup(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete PodGroup")
+			} else {
+				fmt.Printf("PodGroup UID=%s deleted\n", args[1])
+			}
+		case "configmap", "cm":
+			err := crudobj.DeleteConfigMap(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete ConfigMap")
+			} else {
+				fmt.Printf("ConfigMap UID=%s deleted\n", args[1])
+			}
+		case "secret":
+			err := crudobj.DeleteSecret(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete Secret")
+			} else {
+				fmt.Printf("Secret UID=%s deleted\n", args[1])
+			}
+		case "persistentvolume", "pv":
+			err := crudobj.DeletePersistentVolume(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete PersistentVolume")
+			} else {
+				fmt.Printf("PersistentVolume UID=%s deleted\n", args[1])
+			}
+		case "persistentvolumeclaim", "pvc":
+			err := crudobj.DeletePersistentVolumeClaim(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete PersistentVolumeClaim")
+			} else {
+				fmt.Printf("PersistentVolumeClaim UID=%s deleted\n", args[1])
+			}
+		case "storageclass", "sc":
+			err := crudobj.DeleteStorageClass(args[1])
+			if err != nil {
+				log.Fatal("[FATAL] fail to delete StorageClass")
+			} else {
+				fmt.Printf("StorageClass UID=%s deleted\n", args[1])
+			}
 		default:
 			log.Fatal("[FATAL] Unknown kind: " + args[0])
 		}
@@ -91,6 +153,8 @@ for example:
 
 func init() {
 	rootCmd.AddCommand(deleteCmd)
+	deleteCmd.Flags().Int64("grace-period", -1, "seconds for the pod to terminate, default to its terminationGracePeriodSeconds")
+	deleteCmd.Flags().Bool("force", false, "remove the pod at once without waiting for its containers to stop")
 
 	// Here you will define your flags and configuration settings.
 
@@ -24,6 +24,7 @@ var describeCmd = &cobra.Command{
 Describe detailed information of an object
 for example:
 	cubectl describe pod nginx:452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl describe pod 452cbd60-131c-4efa-9e06-7b364692a737 -o yaml
 	cubectl describe [Object kind] [UID]`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 2 {
@@ -36,6 +37,10 @@ for example:
 			if err != nil {
 				log.Fatal("[FATAL] fail to get Pod")
 			}
+			if output, _ := cmd.Flags().GetString("output"); output != "yaml" {
+				describePod(&pod)
+				return
+			}
 			str, err := yaml.Marshal(pod)
 			if err != nil {
 				log.Fatal("[FATAL] fail to marshall Pod")
@@ -140,6 +145,76 @@ for example:
 				log.Fatal("[FATAL] fail to marshall Ingress")
 			}
 			fmt.Print(string(str))
+		case "priorityclass", "pc":
+			priorityClass, err := crudobj.GetPriorityClass(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PriorityClass")
+			}
+			str, err := yaml.Marshal(priorityClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall PriorityClass")
+			}
+			fmt.Print(string(str))
+		case "podgroup", "pg":
+			podGroup, err := crudobj.GetPodGroup(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PodGroup")
+			}
+			str, err := yaml.Marshal(podGroup)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall PodGroup")
+			}
+			fmt.Print(string(str))
+		case "configmap", "cm":
+			configMap, err := crudobj.GetConfigMap(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get ConfigMap")
+			}
+			str, err := yaml.Marshal(configMap)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall ConfigMap")
+			}
+			fmt.Print(string(str))
+		case "secret":
+			secret, err := crudobj.GetSecret(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get Secret")
+			}
+			str, err := yaml.Marshal(secret)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall Secret")
+			}
+			fmt.Print(string(str))
+		case "persistentvolume", "pv":
+			persistentVolume, err := crudobj.GetPersistentVolume(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PersistentVolume")
+			}
+			str, err := yaml.Marshal(persistentVolume)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall PersistentVolume")
+			}
+			fmt.Print(string(str))
+		case "persistentvolumeclaim", "pvc":
+			persistentVolumeClaim, err := crudobj.GetPersistentVolumeClaim(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PersistentVolumeClaim")
+			}
+			str, err := yaml.Marshal(persistentVolumeClaim)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall PersistentVolumeClaim")
+			}
+			fmt.Print(string(str))
+		case "storageclass", "sc":
+			storageClass, err := crudobj.GetStorageClass(UID)
+			if err != nil {
+				log.Fatal("[FATAL] fail to get StorageClass")
+			}
+			str, err := yaml.Marshal(storageClass)
+			if err != nil {
+				log.Fatal("[FATAL] fail to marshall StorageClass")
+			}
+			fmt.Print(string(str))
 		default:
 			log.Fatal("[FATAL] Unknown kind: " + args[0])
 		}
@@ -148,6 +223,7 @@ for example:
 
 func init() {
 	rootCmd.AddCommand(describeCmd)
+	describeCmd.Flags().StringP("output", "o", "", "output format of pod, 'yaml' for the whole object")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,174 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/object"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const describeTimeFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "<none>"
+	}
+	return t.Format(describeTimeFormat)
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// describePod prints pod with its conditions and container statuses in a readable form
+func describePod(pod *object.Pod) {
+	status := object.PodStatus{}
+	if pod.Status != nil {
+		status = *pod.Status
+	}
+
+	fmt.Printf("%-16s%s\n", "Name:", pod.Name)
+	fmt.Printf("%-16s%s\n", "UID:", pod.UID)
+	fmt.Printf("%-16s%s\n", "Labels:", formatLabels(pod.Labels))
+	fmt.Printf("%-16s%s\n", "Node:", valueOrNone(status.NodeUID))
+	fmt.Printf("%-16s%d\n", "Priority:", object.GetPodPriority(pod))
+	fmt.Printf("%-16s%s\n", "QoS Class:", object.GetPodQOS(pod))
+	fmt.Printf("%-16s%s\n", "Restart Policy:", valueOrNone(string(pod.Spec.RestartPolicy)))
+	fmt.Printf("%-16s%s\n", "Start Time:", formatTime(status.StartTime))
+	fmt.Printf("%-16s%s\n", "Status:", podStatusString(pod))
+	if object.IsPodTerminating(pod) {
+		fmt.Printf("%-16s%s\n", "Terminating:", "killed at "+formatTime(*pod.DeletionTimestamp))
+	}
+	if status.IP != nil {
+		fmt.Printf("%-16s%s\n", "IP:", status.IP.String())
+	} else {
+		fmt.Printf("%-16s%s\n", "IP:", "<none>")
+	}
+	if status.Reason != "" {
+		fmt.Printf("%-16s%s\n", "Reason:", status.Reason)
+	}
+	if status.Message != "" {
+		fmt.Printf("%-16s%s\n", "Message:", status.Message)
+	}
+
+	if len(pod.Spec.InitContainers) != 0 {
+		fmt.Println("Init Containers:")
+		for _, container := range pod.Spec.InitContainers {
+			describeContainer(&container, findContainerStatus(status.InitContainerStatuses, container.Name))
+		}
+	}
+
+	fmt.Println("Containers:")
+	for _, container := range pod.Spec.Containers {
+		describeContainer(&container, findContainerStatus(status.ContainerStatuses, container.Name))
+	}
+
+	fmt.Println("Conditions:")
+	if len(status.Conditions) == 0 {
+		fmt.Println("  <none>")
+	} else {
+		fmt.Printf("  %-18s%-10s%-40s%s\n", "Type", "Status", "LastTransitionTime", "Reason")
+		for _, condition := range status.Conditions {
+			fmt.Printf("  %-18s%-10s%-40s%s\n", condition.Type, condition.Status,
+				formatTime(condition.LastTransitionTime), condition.Reason)
+			if condition.Message != "" {
+				fmt.Printf("  %-18s%s\n", "", condition.Message)
+			}
+		}
+	}
+}
+
+func describeContainer(container *object.Container, status *object.ContainerStatus) {
+	if object.IsSidecarContainer(container) {
+		fmt.Printf("  %s (sidecar):\n", container.Name)
+	} else {
+		fmt.Printf("  %s:\n", container.Name)
+	}
+	fmt.Printf("    %-16s%s\n", "Image:", container.Image)
+	if len(container.Command) != 0 {
+		fmt.Printf("    %-16s%s\n", "Command:", strings.Join(container.Command, " "))
+	}
+	if len(container.Args) != 0 {
+		fmt.Printf("    %-16s%s\n", "Args:", strings.Join(container.Args, " "))
+	}
+	if container.WorkingDir != "" {
+		fmt.Printf("    %-16s%s\n", "Working Dir:", container.WorkingDir)
+	}
+	if status == nil {
+		fmt.Printf("    %-16s%s\n", "State:", "<unknown>")
+		return
+	}
+	fmt.Printf("    %-16s%s\n", "Container ID:", valueOrNone(status.ContainerID))
+	fmt.Printf("    %-16s%s\n", "Image ID:", valueOrNone(status.ImageID))
+	describeContainerState("State:", &status.State)
+	if status.LastTerminationState.Terminated != nil {
+		describeContainerState("Last State:", &status.LastTerminationState)
+	}
+	fmt.Printf("    %-16s%v\n", "Ready:", status.Ready)
+	fmt.Printf("    %-16s%d\n", "Restart Count:", status.RestartCount)
+}
+
+func describeContainerState(title string, state *object.ContainerState) {
+	switch {
+	case state.Running != nil:
+		fmt.Printf("    %-16s%s\n", title, "Running")
+		fmt.Printf("      %-14s%s\n", "Started:", formatTime(state.Running.StartedAt))
+	case state.Waiting != nil:
+		fmt.Printf("    %-16s%s\n", title, "Waiting")
+		fmt.Printf("      %-14s%s\n", "Reason:", state.Waiting.Reason)
+		if state.Waiting.Message != "" {
+			fmt.Printf("      %-14s%s\n", "Message:", state.Waiting.Message)
+		}
+	case state.Terminated != nil:
+		fmt.Printf("    %-16s%s\n", title, "Terminated")
+		fmt.Printf("      %-14s%s\n", "Reason:", state.Terminated.Reason)
+		if state.Terminated.Message != "" {
+			fmt.Printf("      %-14s%s\n", "Message:", state.Terminated.Message)
+		}
+		fmt.Printf("      %-14s%d\n", "Exit Code:", state.Terminated.ExitCode)
+		fmt.Printf("      %-14s%s\n", "Started:", formatTime(state.Terminated.StartedAt))
+		fmt.Printf("      %-14s%s\n", "Finished:", formatTime(state.Terminated.FinishedAt))
+	default:
+		fmt.Printf("    %-16s%s\n", title, "<unknown>")
+	}
+}
+
+func findContainerStatus(statuses []object.ContainerStatus, name string) *object.ContainerStatus {
+	for idx := range statuses {
+		if statuses[idx].Name == name {
+			return &statuses[idx]
+		}
+	}
+	return nil
+}
+
+// podStatusString is the phase of the pod, or Terminating if it is being deleted
+func podStatusString(pod *object.Pod) string {
+	if object.IsPodTerminating(pod) {
+		return "Terminating"
+	}
+	if pod.Status == nil || pod.Status.Phase == "" {
+		return "<none>"
+	}
+	return string(pod.Status.Phase)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
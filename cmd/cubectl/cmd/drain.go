@@ -0,0 +1,123 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"time"
+)
+
+const drainPollInterval = 2 * time.Second
+
+// drainCmd represents the drain command
+var drainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Cordon a node and evict all pods on it",
+	Long: `
+Cordon a node, evict all pods on it, and wait until
+pods managed by ReplicaSets are running elsewhere.
+Pods not managed by any ReplicaSet are lost after eviction,
+so --force is needed to drain a node running them
+for example:
+	cubectl drain 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl drain [Node UID] [--force] [--timeout seconds]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		UID := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+
+		if err := setNodeUnschedulable(UID, true); err != nil {
+			log.Fatal("[FATAL] fail to cordon Node, err: ", err)
+		}
+		fmt.Printf("Node UID=%s cordoned\n", UID)
+
+		pods, err := crudobj.GetPods()
+		if err != nil {
+			log.Fatal("[FATAL] fail to get Pods")
+		}
+		rss, err := crudobj.GetReplicaSets()
+		if err != nil {
+			log.Fatal("[FATAL] fail to get ReplicaSets")
+		}
+
+		toEvict := make([]object.Pod, 0)
+		owners := make(map[string]bool)
+		unmanaged := make([]string, 0)
+		for _, pod := range pods {
+			if pod.Status == nil || pod.Status.NodeUID != UID {
+				continue
+			}
+			toEvict = append(toEvict, pod)
+
+			managed := false
+			for _, rs := range rss {
+				if len(rs.Spec.Selector) != 0 && object.MatchLabelSelector(rs.Spec.Selector, pod.Labels) {
+					owners[rs.UID] = true
+					managed = true
+				}
+			}
+			if !managed {
+				unmanaged = append(unmanaged, pod.UID)
+			}
+		}
+
+		if len(unmanaged) != 0 && !force {
+			log.Fatalf("[FATAL] pods not managed by ReplicaSet found (use --force to override): %v", unmanaged)
+		}
+
+		for _, pod := range toEvict {
+			if err := crudobj.DeletePod(pod.UID); err != nil {
+				log.Fatalf("[FATAL] fail to evict Pod UID=%s, err: %v", pod.UID, err)
+			}
+			fmt.Printf("Pod UID=%s evicted\n", pod.UID)
+		}
+
+		deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+		for !drained(toEvict, owners) {
+			if time.Now().After(deadline) {
+				log.Fatalf("[FATAL] timeout waiting for evicted pods to be rescheduled")
+			}
+			time.Sleep(drainPollInterval)
+		}
+		fmt.Printf("Node UID=%s drained\n", UID)
+	},
+}
+
+// drained checks if all evicted pods are gone,
+// and all ReplicaSets owning them are running enough replicas again
+func drained(evicted []object.Pod, owners map[string]bool) bool {
+	for _, pod := range evicted {
+		if _, err := crudobj.GetPod(pod.UID); err == nil {
+			return false
+		}
+	}
+
+	for UID := range owners {
+		rs, err := crudobj.GetReplicaSet(UID)
+		if err != nil {
+			// ReplicaSet removed, nothing to wait for
+			continue
+		}
+		if rs.Status == nil || rs.Status.RunningReplicas < rs.Spec.Replicas {
+			return false
+		}
+	}
+
+	return true
+}
+
+func init() {
+	rootCmd.AddCommand(drainCmd)
+
+	drainCmd.Flags().Bool("force", false, "evict pods not managed by ReplicaSet as well")
+	drainCmd.Flags().Int("timeout", 120, "seconds to wait for evicted pods to be rescheduled")
+}
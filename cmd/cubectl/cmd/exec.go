@@ -0,0 +1,112 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Execute a command in a container",
+	Long: `
+Execute a command in a container of a pod
+for example:
+	cubectl exec 452cbd60-131c-4efa-9e06-7b364692a737 -- ls /
+	cubectl exec -it -c nginx 452cbd60-131c-4efa-9e06-7b364692a737 -- sh
+	cubectl exec [-i] [-t] [-c container] [Pod UID] -- [command] [args...]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		if dash := cmd.ArgsLenAtDash(); dash > 1 {
+			log.Fatal("[FATAL] only one pod is allowed before --")
+		}
+
+		opts := object.ExecOptions{Command: args[1:]}
+		opts.Container, _ = cmd.Flags().GetString("container")
+		opts.Stdin, _ = cmd.Flags().GetBool("stdin")
+		opts.TTY, _ = cmd.Flags().GetBool("tty")
+		if opts.TTY && !opts.Stdin {
+			fmt.Fprintln(os.Stderr, "Unable to use a TTY - input is not attached, ignoring -t")
+			opts.TTY = false
+		}
+
+		conn, err := crudobj.ExecPod(args[0], opts)
+		if err != nil {
+			log.Fatal("[FATAL] fail to exec in Pod, err: ", err)
+		}
+		os.Exit(serveRemoteStream(conn, opts.Stdin, opts.TTY))
+	},
+}
+
+// serveRemoteStream connects the local terminal to an exec or attach stream,
+// and returns the exit code of the remote process
+func serveRemoteStream(conn io.ReadWriteCloser, stdin, tty bool) int {
+	opts := remotecommand.StreamOptions{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	if stdin {
+		opts.Stdin = os.Stdin
+	}
+
+	if fd, isTerminal := term.GetFdInfo(os.Stdin); tty && isTerminal {
+		state, err := term.SetRawTerminal(fd)
+		if err != nil {
+			log.Fatal("[FATAL] fail to set raw terminal, err: ", err)
+		}
+		defer func() { _ = term.RestoreTerminal(fd, state) }()
+
+		resize := make(chan remotecommand.TerminalSize, 1)
+		sendSize := func() {
+			if size, err := term.GetWinsize(fd); err == nil {
+				select {
+				case resize <- remotecommand.TerminalSize{Width: size.Width, Height: size.Height}:
+				default:
+				}
+			}
+		}
+		sendSize()
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer signal.Stop(winch)
+		go func() {
+			for range winch {
+				sendSize()
+			}
+		}()
+		opts.Resize = resize
+	}
+
+	status, err := remotecommand.Stream(conn, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	if status.Message != "" {
+		fmt.Fprintln(os.Stderr, status.Message)
+	}
+	return status.ExitCode
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().StringP("container", "c", "", "container name, can be omitted if the pod has only one container")
+	execCmd.Flags().BoolP("stdin", "i", false, "pass stdin to the container")
+	execCmd.Flags().BoolP("tty", "t", false, "stdin is a TTY")
+}
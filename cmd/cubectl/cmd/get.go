@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
 	"fmt"
 	"github.com/spf13/cobra"
 	"log"
@@ -39,9 +40,9 @@ for example:
 				return
 			}
 			fmt.Printf("%d Pods Found\n", len(pods))
-			fmt.Printf("%-30s\t%-s\n", "Name", "UID")
+			fmt.Printf("%-30s\t%-40s\t%-s\n", "Name", "UID", "Status")
 			for _, pod := range pods {
-				fmt.Printf("%-30s\t%-s\n", pod.Name, pod.UID)
+				fmt.Printf("%-30s\t%-40s\t%-s\n", pod.Name, pod.UID, podStatusString(&pod))
 			}
 
 		case "service", "services", "svc", "svcs":
@@ -93,7 +94,7 @@ for example:
 				return
 			}
 			fmt.Printf("%d Nodes found\n", len(nodes))
-			fmt.Printf("%-30s\t%-40s\t%-v\n", "Name", "UID", "Ready")
+			fmt.Printf("%-30s\t%-40s\t%-v\t%-v\n", "Name", "UID", "Ready", "Schedulable")
 			for _, node := range nodes {
 				var ready bool
 				if node.Status != nil {
@@ -101,7 +102,7 @@ for example:
 				} else {
 					ready = false
 				}
-				fmt.Printf("%-30s\t%-40s\t%-v\n", node.Name, node.UID, ready)
+				fmt.Printf("%-30s\t%-40s\t%-v\t%-v\n", node.Name, node.UID, ready, !node.Spec.Unschedulable)
 			}
 
 		case "dns", "dnses":
@@ -201,6 +202,139 @@ for example:
 			for _, ingress := range ingresses {
 				fmt.Printf("%-30s\t%-40s\t%-30s\t%-v\n", ingress.Name, ingress.UID, ingress.Spec.TriggerPath, ingress.Spec.InvokeAction)
 			}
+
+		case "priorityclass", "priorityclasses", "pc":
+			priorityClasses, err := crudobj.GetPriorityClasses()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PriorityClasses")
+				return
+			}
+			if len(priorityClasses) == 0 {
+				fmt.Println("No PriorityClasses Found")
+				return
+			}
+			fmt.Printf("%d PriorityClasses found\n", len(priorityClasses))
+			fmt.Printf("%-30s\t%-40s\t%-12s\t%-s\n", "Name", "UID", "Value", "GlobalDefault")
+			for _, pc := range priorityClasses {
+				fmt.Printf("%-30s\t%-40s\t%-12d\t%-v\n", pc.Name, pc.UID, pc.Value, pc.GlobalDefault)
+			}
+
+		case "podgroup", "podgroups", "pg":
+			podGroups, err := crudobj.GetPodGroups()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PodGroups")
+				return
+			}
+			if len(podGroups) == 0 {
+				fmt.Println("No PodGroups Found")
+				return
+			}
+			fmt.Printf("%d PodGroups found\n", len(podGroups))
+			fmt.Printf("%-30s\t%-40s\t%-10s\t%-10s\t%-10s\t%-s\n", "Name", "UID", "Phase", "MinMember", "Scheduled", "Running")
+			for _, pg := range podGroups {
+				status := object.PodGroupStatus{}
+				if pg.Status != nil {
+					status = *pg.Status
+				}
+				fmt.Printf("%-30s\t%-40s\t%-10s\t%-10d\t%-10d\t%-d\n", pg.Name, pg.UID, status.Phase,
+					pg.Spec.MinMember, status.Scheduled, status.Running)
+			}
+
+		case "configmap", "configmaps", "cm":
+			configMaps, err := crudobj.GetConfigMaps()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get ConfigMaps")
+				return
+			}
+			if len(configMaps) == 0 {
+				fmt.Println("No ConfigMaps Found")
+				return
+			}
+			fmt.Printf("%d ConfigMaps found\n", len(configMaps))
+			fmt.Printf("%-30s\t%-40s\t%-s\n", "Name", "UID", "Data")
+			for _, obj := range configMaps {
+				fmt.Printf("%-30s\t%-40s\t%-d\n", obj.Name, obj.UID, len(obj.Data))
+			}
+
+		case "secret", "secrets":
+			secrets, err := crudobj.GetSecrets()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get Secrets")
+				return
+			}
+			if len(secrets) == 0 {
+				fmt.Println("No Secrets Found")
+				return
+			}
+			fmt.Printf("%d Secrets found\n", len(secrets))
+			fmt.Printf("%-30s\t%-40s\t%-s\n", "Name", "UID", "Data")
+			for _, obj := range secrets {
+				fmt.Printf("%-30s\t%-40s\t%-d\n", obj.Name, obj.UID, len(obj.Data))
+			}
+
+		case "persistentvolume", "persistentvolumes", "pv", "pvs":
+			pvs, err := crudobj.GetPersistentVolumes()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PersistentVolumes")
+				return
+			}
+			if len(pvs) == 0 {
+				fmt.Println("No PersistentVolumes Found")
+				return
+			}
+			fmt.Printf("%d PersistentVolumes found\n", len(pvs))
+			fmt.Printf("%-30s\t%-40s\t%-12s\t%-10s\t%-10s\t%-20s\t%-s\n",
+				"Name", "UID", "Capacity", "Reclaim", "Phase", "StorageClass", "Claim")
+			for _, pv := range pvs {
+				phase, claim := object.PersistentVolumePhase(""), ""
+				if pv.Status != nil {
+					phase = pv.Status.Phase
+				}
+				if pv.Spec.ClaimRef != nil {
+					claim = pv.Spec.ClaimRef.Name
+				}
+				fmt.Printf("%-30s\t%-40s\t%-12d\t%-10s\t%-10s\t%-20s\t%-s\n", pv.Name, pv.UID,
+					pv.Spec.Capacity, pv.Spec.ReclaimPolicy, phase, pv.Spec.StorageClassName, claim)
+			}
+
+		case "persistentvolumeclaim", "persistentvolumeclaims", "pvc", "pvcs":
+			pvcs, err := crudobj.GetPersistentVolumeClaims()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get PersistentVolumeClaims")
+				return
+			}
+			if len(pvcs) == 0 {
+				fmt.Println("No PersistentVolumeClaims Found")
+				return
+			}
+			fmt.Printf("%d PersistentVolumeClaims found\n", len(pvcs))
+			fmt.Printf("%-30s\t%-40s\t%-10s\t%-12s\t%-20s\t%-s\n",
+				"Name", "UID", "Phase", "Storage", "StorageClass", "Volume")
+			for _, pvc := range pvcs {
+				phase := object.PersistentVolumeClaimPhase("")
+				if pvc.Status != nil {
+					phase = pvc.Status.Phase
+				}
+				fmt.Printf("%-30s\t%-40s\t%-10s\t%-12d\t%-20s\t%-s\n", pvc.Name, pvc.UID,
+					phase, pvc.Spec.Storage, pvc.Spec.StorageClassName, pvc.Spec.VolumeName)
+			}
+
+		case "storageclass", "storageclasses", "sc":
+			storageClasses, err := crudobj.GetStorageClasses()
+			if err != nil {
+				log.Fatal("[FATAL] fail to get StorageClasses")
+				return
+			}
+			if len(storageClasses) == 0 {
+				fmt.Println("No StorageClasses Found")
+				return
+			}
+			fmt.Printf("%d StorageClasses found\n", len(storageClasses))
+			fmt.Printf("%-30s\t%-40s\t%-30s\t%-10s\t%-s\n", "Name", "UID", "Provisioner", "Reclaim", "BindingMode")
+			for _, sc := range storageClasses {
+				fmt.Printf("%-30s\t%-40s\t%-30s\t%-10s\t%-s\n", sc.Name, sc.UID, sc.Provisioner,
+					sc.ReclaimPolicy, sc.VolumeBindingMode)
+			}
 		default:
 			log.Fatal("[FATAL] Unknown kind: " + args[0])
 		}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print the logs of a container",
+	Long: `
+Print the logs of a container in a pod, an actor or a gpu job,
+the kind defaults to pod
+for example:
+	cubectl logs 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl logs -f -c nginx 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl logs --previous --tail 20 pod 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl logs actor 452cbd60-131c-4efa-9e06-7b364692a737
+	cubectl logs [pod|actor|gpujob] [UID]`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		kind, UID := "pod", args[0]
+		if len(args) >= 2 {
+			kind, UID = strings.ToLower(args[0]), args[1]
+		}
+
+		opts := object.LogOptions{}
+		opts.Container, _ = cmd.Flags().GetString("container")
+		opts.Follow, _ = cmd.Flags().GetBool("follow")
+		opts.TailLines, _ = cmd.Flags().GetInt("tail")
+		opts.Since, _ = cmd.Flags().GetDuration("since")
+		opts.Timestamps, _ = cmd.Flags().GetBool("timestamps")
+		opts.Previous, _ = cmd.Flags().GetBool("previous")
+
+		var logs io.ReadCloser
+		var err error
+		switch kind {
+		case "pod":
+			logs, err = crudobj.GetPodLog(UID, opts)
+		case "actor":
+			logs, err = crudobj.GetActorLog(UID, opts)
+		case "gpujob":
+			logs, err = crudobj.GetGpuJobLog(UID, opts)
+		default:
+			log.Fatal("[FATAL] Unknown kind: " + args[0])
+		}
+		if err != nil {
+			log.Fatal("[FATAL] fail to get logs, err: ", err)
+		}
+		defer func() { _ = logs.Close() }()
+
+		_, err = io.Copy(os.Stdout, logs)
+		if err != nil {
+			log.Fatal("[FATAL] log stream broken, err: ", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().StringP("container", "c", "", "container name, can be omitted if the pod has only one container")
+	logsCmd.Flags().BoolP("follow", "f", false, "keep streaming new logs")
+	logsCmd.Flags().Int("tail", -1, "number of recent lines to show, all lines by default")
+	logsCmd.Flags().Duration("since", 0, "only show logs newer than a relative duration like 5s, 2m or 3h")
+	logsCmd.Flags().Bool("timestamps", false, "include timestamps on each line")
+	logsCmd.Flags().BoolP("previous", "p", false, "print the logs of the previous terminated instance of the container")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/utils/remotecommand"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// portForwardCmd represents the port-forward command
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward",
+	Short: "Forward local ports to a pod",
+	Long: `
+Forward local ports to ports in the network namespace of a pod,
+the local port defaults to the pod port
+for example:
+	cubectl port-forward 452cbd60-131c-4efa-9e06-7b364692a737 8080:80
+	cubectl port-forward 452cbd60-131c-4efa-9e06-7b364692a737 6379 5000:5001
+	cubectl port-forward [Pod UID] [LOCAL_PORT:]REMOTE_PORT...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+		address, _ := cmd.Flags().GetString("address")
+
+		wg := sync.WaitGroup{}
+		for _, mapping := range args[1:] {
+			local, remote, err := parsePortMapping(mapping)
+			if err != nil {
+				log.Fatalf("[FATAL] invalid port mapping %s: %v", mapping, err)
+			}
+			listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(local)))
+			if err != nil {
+				log.Fatalf("[FATAL] fail to listen on port %d, err: %v", local, err)
+			}
+			fmt.Printf("Forwarding from %s -> %d\n", listener.Addr().String(), remote)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				forwardPort(listener, args[0], remote)
+			}()
+		}
+		wg.Wait()
+	},
+}
+
+func parsePortMapping(mapping string) (int, int, error) {
+	ports := strings.Split(mapping, ":")
+	if len(ports) > 2 {
+		return 0, 0, errors.New("too many colons")
+	}
+
+	remote, err := strconv.Atoi(ports[len(ports)-1])
+	if err != nil || remote <= 0 || remote > 65535 {
+		return 0, 0, errors.New("invalid pod port")
+	}
+	local := remote
+	if len(ports) == 2 {
+		local, err = strconv.Atoi(ports[0])
+		if err != nil || local < 0 || local > 65535 {
+			return 0, 0, errors.New("invalid local port")
+		}
+	}
+	return local, remote, nil
+}
+
+// forwardPort opens a stream to the pod for every accepted connection
+func forwardPort(listener net.Listener, UID string, port int) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Printf("[Error]: fail to accept connection, err: %v\n", err)
+			return
+		}
+
+		go func() {
+			fmt.Printf("Handling connection for %d\n", port)
+			stream, err := crudobj.PortForwardPod(UID, port)
+			if err != nil {
+				log.Printf("[Error]: fail to forward port %d, err: %v\n", port, err)
+				_ = conn.Close()
+				return
+			}
+			remotecommand.Pipe(conn, stream)
+		}()
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(portForwardCmd)
+	portForwardCmd.Flags().String("address", "127.0.0.1", "local address to listen on")
+}
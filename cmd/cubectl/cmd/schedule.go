@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/simulator"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"log"
+	"sort"
+)
+
+// scheduleCmd represents the schedule command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Simulate scheduling without binding any pod",
+	Long: `
+Simulate scheduling without binding any pod
+for example:
+	cubectl schedule snapshot -o snapshot.yaml
+	cubectl schedule simulate
+	cubectl schedule simulate --snapshot snapshot.yaml --add-node new-node.yaml --remove-node [Node UID]
+	cubectl schedule simulate --pod test-pod.yaml --all`,
+}
+
+var scheduleSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save nodes, pods and pod groups from apiserver to a file",
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("output")
+		if out == "" {
+			log.Fatal("[FATAL] missing output file")
+		}
+
+		snapshot := takeSnapshot()
+		buf, err := yaml.Marshal(snapshot)
+		if err != nil {
+			log.Fatal("[FATAL] fail to marshal snapshot, err: ", err)
+		}
+		if err = ioutil.WriteFile(out, buf, 0644); err != nil {
+			log.Fatal("[FATAL] fail to write snapshot, err: ", err)
+		}
+		fmt.Printf("Snapshot of %d nodes, %d pods saved to %s\n", len(snapshot.Nodes), len(snapshot.Pods), out)
+	},
+}
+
+var scheduleSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Show where pending pods would be placed",
+	Long: `
+Run the scheduling plugins in-process on a snapshot of the cluster,
+and show where pending pods would be placed, and why other nodes don't fit.
+Nothing is bound or preempted.
+	--snapshot      load cluster state from a file instead of apiserver
+	--add-node      add a hypothetical node from a Node yaml (repeatable)
+	--remove-node   remove a node by UID or name (repeatable)
+	--pod           add a hypothetical pending pod from a Pod yaml (repeatable)
+	--all           reschedule all pods as if none were bound
+	--verbose       show failure reasons of placed pods too`,
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshotFile, _ := cmd.Flags().GetString("snapshot")
+		addNodes, _ := cmd.Flags().GetStringArray("add-node")
+		removeNodes, _ := cmd.Flags().GetStringArray("remove-node")
+		podFiles, _ := cmd.Flags().GetStringArray("pod")
+		all, _ := cmd.Flags().GetBool("all")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		var snapshot *simulator.Snapshot
+		if snapshotFile != "" {
+			snapshot = &simulator.Snapshot{}
+			readYaml(snapshotFile, snapshot)
+		} else {
+			snapshot = takeSnapshot()
+		}
+
+		if all {
+			snapshot.UnbindAll()
+		}
+		for _, f := range addNodes {
+			var node object.Node
+			readYaml(f, &node)
+			snapshot.AddNodes(node)
+		}
+		if len(removeNodes) != 0 {
+			snapshot.RemoveNodes(removeNodes...)
+		}
+		for _, f := range podFiles {
+			var pod object.Pod
+			readYaml(f, &pod)
+			if pod.UID == "" {
+				pod.UID = "simulated-" + pod.Name
+			}
+			pod.Status = nil
+			snapshot.Pods = append(snapshot.Pods, pod)
+		}
+
+		nodeNames := make(map[string]string)
+		for _, node := range snapshot.Nodes {
+			nodeNames[node.UID] = node.Name
+		}
+
+		placements := simulator.NewSimulator().Simulate(snapshot)
+		if len(placements) == 0 {
+			fmt.Println("No pending Pods to simulate")
+			return
+		}
+
+		fmt.Printf("%d Pods simulated\n", len(placements))
+		fmt.Printf("%-30s\t%-40s\t%-s\n", "Name", "UID", "Node")
+		for _, p := range placements {
+			node := "<none>"
+			if p.NodeUID != "" {
+				node = nodeNames[p.NodeUID] + " (" + p.NodeUID + ")"
+			}
+			fmt.Printf("%-30s\t%-40s\t%-s\n", p.PodName, p.PodUID, node)
+			if len(p.Victims) != 0 {
+				fmt.Printf("\tpreempts: %v\n", p.Victims)
+			}
+			if p.NodeUID != "" && !verbose {
+				continue
+			}
+			if p.Message != "" {
+				fmt.Printf("\t%s\n", p.Message)
+			}
+			uids := make([]string, 0, len(p.Failures))
+			for uid := range p.Failures {
+				uids = append(uids, uid)
+			}
+			sort.Strings(uids)
+			for _, uid := range uids {
+				fmt.Printf("\t%-30s\t%-s\n", nodeNames[uid], p.Failures[uid])
+			}
+		}
+	},
+}
+
+func takeSnapshot() *simulator.Snapshot {
+	nodes, err := crudobj.GetNodes()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get Nodes")
+	}
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get Pods")
+	}
+	podGroups, err := crudobj.GetPodGroups()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get PodGroups")
+	}
+	pvcs, err := crudobj.GetPersistentVolumeClaims()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get PersistentVolumeClaims")
+	}
+	pvs, err := crudobj.GetPersistentVolumes()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get PersistentVolumes")
+	}
+	classes, err := crudobj.GetStorageClasses()
+	if err != nil {
+		log.Fatal("[FATAL] fail to get StorageClasses")
+	}
+	return &simulator.Snapshot{
+		Nodes:                  nodes,
+		Pods:                   pods,
+		PodGroups:              podGroups,
+		PersistentVolumeClaims: pvcs,
+		PersistentVolumes:      pvs,
+		StorageClasses:         classes,
+	}
+}
+
+func readYaml(path string, obj interface{}) {
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal("[FATAL] cannot read file ", path)
+	}
+	if err = yaml.Unmarshal(file, obj); err != nil {
+		log.Fatal("[FATAL] fail to parse file ", path, ", err: ", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleSnapshotCmd)
+	scheduleCmd.AddCommand(scheduleSimulateCmd)
+
+	scheduleSnapshotCmd.Flags().StringP("output", "o", "", "path of the snapshot file")
+
+	scheduleSimulateCmd.Flags().String("snapshot", "", "path of a snapshot file, default to current cluster")
+	scheduleSimulateCmd.Flags().StringArray("add-node", nil, "path of a hypothetical Node yaml")
+	scheduleSimulateCmd.Flags().StringArray("remove-node", nil, "UID or name of a node to remove")
+	scheduleSimulateCmd.Flags().StringArray("pod", nil, "path of a hypothetical Pod yaml")
+	scheduleSimulateCmd.Flags().Bool("all", false, "reschedule all pods as if none were bound")
+	scheduleSimulateCmd.Flags().BoolP("verbose", "v", false, "show failure reasons of placed pods too")
+}
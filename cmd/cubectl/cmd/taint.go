@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"strings"
+)
+
+// taintCmd represents the taint command
+var taintCmd = &cobra.Command{
+	Use:   "taint",
+	Short: "Add or remove taints of a node",
+	Long: `
+Add or remove taints of a node,
+a taint ending with '-' is removed
+for example:
+	cubectl taint 452cbd60-131c-4efa-9e06-7b364692a737 dedicated=gpu:NoSchedule
+	cubectl taint 452cbd60-131c-4efa-9e06-7b364692a737 dedicated:NoSchedule-
+	cubectl taint [Node UID] key[=value]:Effect[-] ...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			log.Fatal("[FATAL] lack arguments")
+		}
+
+		node, err := crudobj.GetNode(args[0])
+		if err != nil {
+			log.Fatal("[FATAL] fail to get Node")
+		}
+
+		for _, arg := range args[1:] {
+			remove := strings.HasSuffix(arg, "-")
+			taint, err := parseTaint(strings.TrimSuffix(arg, "-"))
+			if err != nil {
+				log.Fatal("[FATAL] ", err)
+			}
+
+			// taints are identified by key and effect
+			taints := make([]object.Taint, 0, len(node.Spec.Taints)+1)
+			for _, t := range node.Spec.Taints {
+				if t.Key != taint.Key || (taint.Effect != "" && t.Effect != taint.Effect) {
+					taints = append(taints, t)
+				}
+			}
+			if !remove {
+				if taint.Effect == "" {
+					log.Fatal("[FATAL] missing taint effect: " + arg)
+				}
+				taints = append(taints, taint)
+			}
+			node.Spec.Taints = taints
+		}
+
+		if _, err = crudobj.UpdateNode(node); err != nil {
+			log.Fatal("[FATAL] fail to update Node taints")
+		}
+		fmt.Printf("Node UID=%s tainted\n", node.UID)
+	},
+}
+
+func parseTaint(str string) (object.Taint, error) {
+	var taint object.Taint
+
+	keyValue := str
+	if idx := strings.LastIndex(str, ":"); idx != -1 {
+		keyValue = str[:idx]
+		taint.Effect = object.TaintEffect(str[idx+1:])
+		switch taint.Effect {
+		case object.TaintEffectNoSchedule, object.TaintEffectPreferNoSchedule, object.TaintEffectNoExecute:
+		default:
+			return taint, fmt.Errorf("unknown taint effect: %s", taint.Effect)
+		}
+	}
+
+	if idx := strings.Index(keyValue, "="); idx != -1 {
+		taint.Key = keyValue[:idx]
+		taint.Value = keyValue[idx+1:]
+	} else {
+		taint.Key = keyValue
+	}
+
+	if taint.Key == "" {
+		return taint, fmt.Errorf("empty taint key: %s", str)
+	}
+	return taint, nil
+}
+
+func init() {
+	rootCmd.AddCommand(taintCmd)
+}
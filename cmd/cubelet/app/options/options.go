@@ -1,5 +1,12 @@
 package options
 
+import (
+	runtimeoptions "Cubernetes/pkg/cubelet/cuberuntime/options"
+	"Cubernetes/pkg/cubelet/staticpod"
+
+	"github.com/spf13/pflag"
+)
+
 const (
 	containerdRuntimeEndpoint = "/run/containerd/containerd.sock"
 )
@@ -8,16 +15,60 @@ type CubeletFlags struct {
 	// The Cubelet will load its initial configuration from this file.
 	// Omit this flag to use the combination of built-in default configuration values and flags.
 	CubeletConfigFile string
+	// ContainerRuntime is docker, or remote for CRI runtimes
+	ContainerRuntime string
 	// remoteRuntimeEndpoint is the endpoint of remote runtime service
 	RemoteRuntimeEndpoint string
 	// remoteImageEndpoint is the endpoint of remote image service
 	RemoteImageEndpoint string
+	// CgroupDriver is the cgroup driver of remote runtime
+	CgroupDriver string
+	// PodManifestPath is the directory of static pods, empty to disable static pods
+	PodManifestPath string
+	// ClusterDNS are nameservers of pods with ClusterFirst DNS policy,
+	// ClusterDomain is searched by them as <ns>.svc.<domain>
+	ClusterDNS    []string
+	ClusterDomain string
 }
 
 func NewCubeletFlags() *CubeletFlags {
 
 	return &CubeletFlags{
+		ContainerRuntime:      runtimeoptions.DockerContainerRuntime,
 		RemoteRuntimeEndpoint: containerdRuntimeEndpoint,
 		RemoteImageEndpoint:   containerdRuntimeEndpoint,
+		CgroupDriver:          "cgroupfs",
+		PodManifestPath:       staticpod.DefaultManifestPath,
+		ClusterDNS:            []string{runtimeoptions.WeaveDNSServer},
+		ClusterDomain:         runtimeoptions.DefaultClusterDomain,
+	}
+}
+
+// AddFlags adds flags of cubelet to fs
+func (f *CubeletFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.ContainerRuntime, "container-runtime", f.ContainerRuntime, "The container runtime to use, docker or remote")
+	fs.StringVar(&f.RemoteRuntimeEndpoint, "container-runtime-endpoint", f.RemoteRuntimeEndpoint,
+		"The endpoint of remote runtime service, used by remote container runtime")
+	fs.StringVar(&f.RemoteImageEndpoint, "image-service-endpoint", f.RemoteImageEndpoint,
+		"The endpoint of remote image service, used by remote container runtime")
+	fs.StringVar(&f.CgroupDriver, "cgroup-driver", f.CgroupDriver,
+		"The cgroup driver of remote container runtime, cgroupfs or systemd")
+	fs.StringVar(&f.PodManifestPath, "pod-manifest-path", f.PodManifestPath,
+		"The directory of static pod manifests run without apiserver, empty to disable static pods")
+	fs.StringSliceVar(&f.ClusterDNS, "cluster-dns", f.ClusterDNS,
+		"Comma-separated nameservers of pods with ClusterFirst DNS policy")
+	fs.StringVar(&f.ClusterDomain, "cluster-domain", f.ClusterDomain,
+		"The domain of the cluster, pods search services in <namespace>.svc.<domain>")
+}
+
+// RuntimeOptions returns options of the container runtime
+func (f *CubeletFlags) RuntimeOptions() runtimeoptions.RuntimeOptions {
+	return runtimeoptions.RuntimeOptions{
+		ContainerRuntime:      f.ContainerRuntime,
+		RemoteRuntimeEndpoint: f.RemoteRuntimeEndpoint,
+		RemoteImageEndpoint:   f.RemoteImageEndpoint,
+		CgroupDriver:          f.CgroupDriver,
+		ClusterDNS:            f.ClusterDNS,
+		ClusterDomain:         f.ClusterDomain,
 	}
 }
@@ -1,31 +1,43 @@
 package main
 
 import (
+	"Cubernetes/cmd/cubelet/app/options"
 	"Cubernetes/pkg/cubelet"
 	"Cubernetes/pkg/cubelet/network"
 	"Cubernetes/pkg/cubenetwork/nodenetwork"
 	"log"
 	"os"
+
+	"github.com/spf13/pflag"
 )
 
 func main() {
+	// flags may be given anywhere, e.g. --container-runtime=remote
+	flags := options.NewCubeletFlags()
+	fs := pflag.NewFlagSet(os.Args[0], pflag.ExitOnError)
+	flags.AddFlags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		log.Fatal("[FATAL] ", err)
+	}
+	args := append([]string{os.Args[0]}, fs.Args()...)
+
 	// Init network according to params
 	// 2 param (master): cubelet [UID Of Node] [LocalIP]
 	// 3 params (slave): cubelet [UID Of Node] [LocalIP] [MasterIP]
-	if len(os.Args) < 3 {
+	if len(args) < 3 {
 		log.Fatal("[FATAL] Lack arguments")
 	}
 
-	if len(os.Args) == 3 {
-		nodenetwork.SetMasterIP(os.Args[2])
-	} else if len(os.Args) == 4 {
-		nodenetwork.SetMasterIP(os.Args[3])
+	if len(args) == 3 {
+		nodenetwork.SetMasterIP(args[2])
+	} else if len(args) == 4 {
+		nodenetwork.SetMasterIP(args[3])
 	}
 
-	ip := network.InitNodeNetwork(os.Args)
+	ip := network.InitNodeNetwork(args)
 	network.InitNodeHeartbeat()
 
-	cubeletInstance := cubelet.NewCubelet()
-	cubeletInstance.InitCubelet(os.Args[1], ip)
+	cubeletInstance := cubelet.NewCubelet(flags.RuntimeOptions(), flags.PodManifestPath)
+	cubeletInstance.InitCubelet(args[1], ip)
 	cubeletInstance.Run()
 }
@@ -52,6 +52,15 @@ example:
 			log.Fatalf("[FATAL] illegal ip address: %v", node.Status.Addresses.InternalIP)
 		}
 
+		token, err := localstorage.GenerateCubeletToken()
+		if err != nil {
+			log.Fatal("[FATAL] fail to generate cubelet token, err: ", err)
+		}
+		err = localstorage.SaveCubeletToken(token)
+		if err != nil {
+			log.Fatal("[FATAL] fail to save cubelet token, err: ", err)
+		}
+
 		log.Println("Starting etcd & apiserver processes, this may take 4s")
 
 		err = utils.PreStartMaster()
@@ -84,7 +93,7 @@ example:
 		time.Sleep(12 * time.Second)
 		log.Printf("Master node launched successfully\n"+
 			"To join Cubernetes cluster, execute:\n"+
-			"\tcuberoot join %s -f [node config file]\n", node.Status.Addresses.InternalIP)
+			"\tcuberoot join %s -f [node config file] -t %s\n", node.Status.Addresses.InternalIP, token)
 	},
 }
 
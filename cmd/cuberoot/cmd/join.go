@@ -20,9 +20,9 @@ var joinCmd = &cobra.Command{
 	Long: `
 Join an existed master as a slave
 usage:
-	cuberoot join [Master IP] -f [file path]
+	cuberoot join [Master IP] -f [file path] -t [cubelet token]
 example:
-	cuberoot join 192.168.1.11 -f node.yaml`,
+	cuberoot join 192.168.1.11 -f node.yaml -t 3f9a...`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		meta, err := localstorage.TryLoadMeta()
@@ -61,6 +61,12 @@ example:
 			log.Fatalf("[FATAL] illegal ip address: %v", node.Status.Addresses.InternalIP)
 		}
 
+		// the token printed by cuberoot init, cubelet serves apiserver only if they share it
+		token, err := cmd.Flags().GetString("token")
+		if err != nil || token == "" {
+			log.Fatal("[FATAL] missing cubelet token, get it from the output of cuberoot init")
+		}
+
 		nodenetwork.SetMasterIP(masterIP)
 
 		log.Println("Registering as slave...")
@@ -69,6 +75,11 @@ example:
 			log.Fatal("[FATAL] fail to register as slave, err: ", err)
 		}
 
+		err = localstorage.SaveCubeletToken(token)
+		if err != nil {
+			log.Fatal("[FATAL] fail to save cubelet token, err: ", err)
+		}
+
 		time.Sleep(3 * time.Second)
 		log.Println("Registered as slave, starting processes...")
 
@@ -101,4 +112,5 @@ func init() {
 	// is called directly, e.g.:
 	// getCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	joinCmd.Flags().StringP("file", "f", "", "path of your node config yaml file")
+	joinCmd.Flags().StringP("token", "t", "", "cubelet token printed by cuberoot init")
 }
@@ -25,6 +25,7 @@ usage:
 		meta, err := localstorage.TryLoadMeta()
 		if err != nil {
 			_ = localstorage.ClearMeta()
+			_ = localstorage.ClearCubeletToken()
 			return
 		}
 
@@ -56,6 +57,11 @@ usage:
 			log.Println("[FATAL] fail to clear local metadata, err: ", err)
 		}
 
+		err = localstorage.ClearCubeletToken()
+		if err != nil {
+			log.Println("[FATAL] fail to clear cubelet token, err: ", err)
+		}
+
 		err = proxyruntime.CleanIptables()
 		if err != nil {
 			log.Println("[Error]: fail to clean iptables chain and rules")
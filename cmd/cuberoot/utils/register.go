@@ -82,9 +82,13 @@ func StartFromRegistry() {
 			log.Fatal("[FATAL] fail to start master processes, err: ", err)
 		}
 
+		token, err := localstorage.LoadCubeletToken()
+		if err != nil {
+			log.Println("[WARNING] fail to load cubelet token, err: ", err)
+		}
 		log.Printf("Master node launched successfully\n"+
 			"To join Cubernetes cluster, execute:\n"+
-			"\tcuberoot join %s -f [node config file]\n", meta.Node.Status.Addresses.InternalIP)
+			"\tcuberoot join %s -f [node config file] -t %s\n", meta.Node.Status.Addresses.InternalIP, token)
 
 	} else {
 		log.Printf("Starting Master, UID = %v, It may takes 15s...", meta.Node.UID)
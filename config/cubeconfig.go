@@ -13,6 +13,7 @@ const ServiceClusterIPRange = "172.16.0.0/16"
 
 const APIServerPort = 8080
 const HeartbeatPort = 8081
+const CubeletPort = 10250
 const DefaultApiVersion = "v1"
 const CubeVersion = "v1.0"
 
@@ -23,3 +24,10 @@ const (
 	MetaDir       = "/etc/cubernetes/cubernetes/"
 	MetaFile      = MetaDir + "meta"
 )
+
+// cubelet http endpoints require the token in CubeletTokenFile in header CubeletTokenHeader,
+// the token is generated by cuberoot init and handed to every node by cuberoot join
+const (
+	CubeletTokenFile   = "/etc/cubernetes/cubelet/token"
+	CubeletTokenHeader = "X-Cubelet-Token"
+)
@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetConfigMap(UID string) (object.ConfigMap, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/configMap/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.ConfigMap{}, err
+	}
+
+	var configMap object.ConfigMap
+	err = json.Unmarshal(body, &configMap)
+	if err != nil {
+		log.Println("fail to parse ConfigMap")
+		return object.ConfigMap{}, err
+	}
+
+	return configMap, nil
+}
+
+func GetConfigMaps() ([]object.ConfigMap, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/configMaps"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var configMaps []object.ConfigMap
+	err = json.Unmarshal(body, &configMaps)
+	if err != nil {
+		log.Println("fail to parse ConfigMaps")
+		return nil, err
+	}
+
+	return configMaps, nil
+}
+
+func SelectConfigMaps(selectors map[string]string) ([]object.ConfigMap, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/configMaps"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var configMaps []object.ConfigMap
+	err = json.Unmarshal(body, &configMaps)
+	if err != nil {
+		log.Println("fail to parse ConfigMaps")
+		return nil, err
+	}
+
+	return configMaps, nil
+}
+
+func CreateConfigMap(configMap object.ConfigMap) (object.ConfigMap, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/configMap"
+
+	body, err := postRequest(url, configMap)
+	if err != nil {
+		log.Println("postRequest fail")
+		return configMap, err
+	}
+
+	var newConfigMap object.ConfigMap
+	err = json.Unmarshal(body, &newConfigMap)
+	if err != nil {
+		log.Println("fail to parse ConfigMap")
+		return configMap, err
+	}
+
+	return newConfigMap, nil
+}
+
+func UpdateConfigMap(configMap object.ConfigMap) (object.ConfigMap, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/configMap/" + configMap.UID
+
+	body, err := putRequest(url, configMap)
+	if err != nil {
+		log.Println("putRequest fail")
+		return configMap, err
+	}
+
+	var newConfigMap object.ConfigMap
+	err = json.Unmarshal(body, &newConfigMap)
+	if err != nil {
+		log.Println("fail to parse ConfigMap")
+		return configMap, err
+	}
+
+	return newConfigMap, nil
+}
+
+func DeleteConfigMap(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/configMap/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// GetPodLog returns the log stream of a pod container, the caller closes it
+func GetPodLog(UID string, opts object.LogOptions) (io.ReadCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID + "/log"
+	return getLogStream(url, opts)
+}
+
+func GetActorLog(UID string, opts object.LogOptions) (io.ReadCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/actor/" + UID + "/log"
+	return getLogStream(url, opts)
+}
+
+func GetGpuJobLog(UID string, opts object.LogOptions) (io.ReadCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/gpuJob/" + UID + "/log"
+	return getLogStream(url, opts)
+}
+
+func getLogStream(url string, opts object.LogOptions) (io.ReadCloser, error) {
+	// no timeout, followed logs last until the stream is closed
+	resp, err := http.Get(url + "?" + opts.Query().Encode())
+	if err != nil {
+		log.Println("fail to send http get request, err: ", err)
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, errors.New(string(body))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetPersistentVolume(UID string) (object.PersistentVolume, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolume/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.PersistentVolume{}, err
+	}
+
+	var persistentVolume object.PersistentVolume
+	err = json.Unmarshal(body, &persistentVolume)
+	if err != nil {
+		log.Println("fail to parse PersistentVolume")
+		return object.PersistentVolume{}, err
+	}
+
+	return persistentVolume, nil
+}
+
+func GetPersistentVolumes() ([]object.PersistentVolume, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumes"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var persistentVolumes []object.PersistentVolume
+	err = json.Unmarshal(body, &persistentVolumes)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumes")
+		return nil, err
+	}
+
+	return persistentVolumes, nil
+}
+
+func SelectPersistentVolumes(selectors map[string]string) ([]object.PersistentVolume, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/persistentVolumes"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var persistentVolumes []object.PersistentVolume
+	err = json.Unmarshal(body, &persistentVolumes)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumes")
+		return nil, err
+	}
+
+	return persistentVolumes, nil
+}
+
+func CreatePersistentVolume(persistentVolume object.PersistentVolume) (object.PersistentVolume, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolume"
+
+	body, err := postRequest(url, persistentVolume)
+	if err != nil {
+		log.Println("postRequest fail")
+		return persistentVolume, err
+	}
+
+	var newPersistentVolume object.PersistentVolume
+	err = json.Unmarshal(body, &newPersistentVolume)
+	if err != nil {
+		log.Println("fail to parse PersistentVolume")
+		return persistentVolume, err
+	}
+
+	return newPersistentVolume, nil
+}
+
+func UpdatePersistentVolume(persistentVolume object.PersistentVolume) (object.PersistentVolume, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolume/" + persistentVolume.UID
+
+	body, err := putRequest(url, persistentVolume)
+	if err != nil {
+		log.Println("putRequest fail")
+		return persistentVolume, err
+	}
+
+	var newPersistentVolume object.PersistentVolume
+	err = json.Unmarshal(body, &newPersistentVolume)
+	if err != nil {
+		log.Println("fail to parse PersistentVolume")
+		return persistentVolume, err
+	}
+
+	return newPersistentVolume, nil
+}
+
+func DeletePersistentVolume(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolume/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetPersistentVolumeClaim(UID string) (object.PersistentVolumeClaim, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumeClaim/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.PersistentVolumeClaim{}, err
+	}
+
+	var persistentVolumeClaim object.PersistentVolumeClaim
+	err = json.Unmarshal(body, &persistentVolumeClaim)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumeClaim")
+		return object.PersistentVolumeClaim{}, err
+	}
+
+	return persistentVolumeClaim, nil
+}
+
+func GetPersistentVolumeClaims() ([]object.PersistentVolumeClaim, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumeClaims"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var persistentVolumeClaims []object.PersistentVolumeClaim
+	err = json.Unmarshal(body, &persistentVolumeClaims)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumeClaims")
+		return nil, err
+	}
+
+	return persistentVolumeClaims, nil
+}
+
+func SelectPersistentVolumeClaims(selectors map[string]string) ([]object.PersistentVolumeClaim, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/persistentVolumeClaims"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var persistentVolumeClaims []object.PersistentVolumeClaim
+	err = json.Unmarshal(body, &persistentVolumeClaims)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumeClaims")
+		return nil, err
+	}
+
+	return persistentVolumeClaims, nil
+}
+
+func CreatePersistentVolumeClaim(persistentVolumeClaim object.PersistentVolumeClaim) (object.PersistentVolumeClaim, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumeClaim"
+
+	body, err := postRequest(url, persistentVolumeClaim)
+	if err != nil {
+		log.Println("postRequest fail")
+		return persistentVolumeClaim, err
+	}
+
+	var newPersistentVolumeClaim object.PersistentVolumeClaim
+	err = json.Unmarshal(body, &newPersistentVolumeClaim)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumeClaim")
+		return persistentVolumeClaim, err
+	}
+
+	return newPersistentVolumeClaim, nil
+}
+
+func UpdatePersistentVolumeClaim(persistentVolumeClaim object.PersistentVolumeClaim) (object.PersistentVolumeClaim, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumeClaim/" + persistentVolumeClaim.UID
+
+	body, err := putRequest(url, persistentVolumeClaim)
+	if err != nil {
+		log.Println("putRequest fail")
+		return persistentVolumeClaim, err
+	}
+
+	var newPersistentVolumeClaim object.PersistentVolumeClaim
+	err = json.Unmarshal(body, &newPersistentVolumeClaim)
+	if err != nil {
+		log.Println("fail to parse PersistentVolumeClaim")
+		return persistentVolumeClaim, err
+	}
+
+	return newPersistentVolumeClaim, nil
+}
+
+func DeletePersistentVolumeClaim(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/persistentVolumeClaim/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
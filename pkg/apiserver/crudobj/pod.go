@@ -127,6 +127,21 @@ func UpdatePodStatus(UID string, status object.PodStatus) (object.Pod, error) {
 	return newPod, nil
 }
 
+// DeletePodWithGracePeriod overrides terminationGracePeriodSeconds of the pod,
+// 0 removes the pod at once without waiting for its containers to stop
+func DeletePodWithGracePeriod(UID string, gracePeriod int64) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID +
+		"?gracePeriod=" + strconv.FormatInt(gracePeriod, 10)
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
+
 func DeletePod(UID string) error {
 	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID
 
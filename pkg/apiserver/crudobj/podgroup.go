@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetPodGroup(UID string) (object.PodGroup, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/podGroup/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.PodGroup{}, err
+	}
+
+	var podGroup object.PodGroup
+	err = json.Unmarshal(body, &podGroup)
+	if err != nil {
+		log.Println("fail to parse PodGroup")
+		return object.PodGroup{}, err
+	}
+
+	return podGroup, nil
+}
+
+func GetPodGroups() ([]object.PodGroup, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/podGroups"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var podGroups []object.PodGroup
+	err = json.Unmarshal(body, &podGroups)
+	if err != nil {
+		log.Println("fail to parse PodGroups")
+		return nil, err
+	}
+
+	return podGroups, nil
+}
+
+func SelectPodGroups(selectors map[string]string) ([]object.PodGroup, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/podGroups"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var podGroups []object.PodGroup
+	err = json.Unmarshal(body, &podGroups)
+	if err != nil {
+		log.Println("fail to parse PodGroups")
+		return nil, err
+	}
+
+	return podGroups, nil
+}
+
+func CreatePodGroup(podGroup object.PodGroup) (object.PodGroup, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/podGroup"
+
+	body, err := postRequest(url, podGroup)
+	if err != nil {
+		log.Println("postRequest fail")
+		return podGroup, err
+	}
+
+	var newPodGroup object.PodGroup
+	err = json.Unmarshal(body, &newPodGroup)
+	if err != nil {
+		log.Println("fail to parse PodGroup")
+		return podGroup, err
+	}
+
+	return newPodGroup, nil
+}
+
+func UpdatePodGroup(podGroup object.PodGroup) (object.PodGroup, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/podGroup/" + podGroup.UID
+
+	body, err := putRequest(url, podGroup)
+	if err != nil {
+		log.Println("putRequest fail")
+		return podGroup, err
+	}
+
+	var newPodGroup object.PodGroup
+	err = json.Unmarshal(body, &newPodGroup)
+	if err != nil {
+		log.Println("fail to parse PodGroup")
+		return podGroup, err
+	}
+
+	return newPodGroup, nil
+}
+
+func DeletePodGroup(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/podGroup/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
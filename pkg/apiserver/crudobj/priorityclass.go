@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetPriorityClass(UID string) (object.PriorityClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/priorityClass/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.PriorityClass{}, err
+	}
+
+	var priorityClass object.PriorityClass
+	err = json.Unmarshal(body, &priorityClass)
+	if err != nil {
+		log.Println("fail to parse PriorityClass")
+		return object.PriorityClass{}, err
+	}
+
+	return priorityClass, nil
+}
+
+func GetPriorityClasses() ([]object.PriorityClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/priorityClasses"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var priorityClasses []object.PriorityClass
+	err = json.Unmarshal(body, &priorityClasses)
+	if err != nil {
+		log.Println("fail to parse PriorityClasses")
+		return nil, err
+	}
+
+	return priorityClasses, nil
+}
+
+func SelectPriorityClasses(selectors map[string]string) ([]object.PriorityClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/priorityClasses"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var priorityClasses []object.PriorityClass
+	err = json.Unmarshal(body, &priorityClasses)
+	if err != nil {
+		log.Println("fail to parse PriorityClasses")
+		return nil, err
+	}
+
+	return priorityClasses, nil
+}
+
+func CreatePriorityClass(priorityClass object.PriorityClass) (object.PriorityClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/priorityClass"
+
+	body, err := postRequest(url, priorityClass)
+	if err != nil {
+		log.Println("postRequest fail")
+		return priorityClass, err
+	}
+
+	var newPriorityClass object.PriorityClass
+	err = json.Unmarshal(body, &newPriorityClass)
+	if err != nil {
+		log.Println("fail to parse PriorityClass")
+		return priorityClass, err
+	}
+
+	return newPriorityClass, nil
+}
+
+func UpdatePriorityClass(priorityClass object.PriorityClass) (object.PriorityClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/priorityClass/" + priorityClass.UID
+
+	body, err := putRequest(url, priorityClass)
+	if err != nil {
+		log.Println("putRequest fail")
+		return priorityClass, err
+	}
+
+	var newPriorityClass object.PriorityClass
+	err = json.Unmarshal(body, &newPriorityClass)
+	if err != nil {
+		log.Println("fail to parse PriorityClass")
+		return priorityClass, err
+	}
+
+	return newPriorityClass, nil
+}
+
+func DeletePriorityClass(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/priorityClass/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetSecret(UID string) (object.Secret, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/secret/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.Secret{}, err
+	}
+
+	var secret object.Secret
+	err = json.Unmarshal(body, &secret)
+	if err != nil {
+		log.Println("fail to parse Secret")
+		return object.Secret{}, err
+	}
+
+	return secret, nil
+}
+
+func GetSecrets() ([]object.Secret, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/secrets"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var secrets []object.Secret
+	err = json.Unmarshal(body, &secrets)
+	if err != nil {
+		log.Println("fail to parse Secrets")
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func SelectSecrets(selectors map[string]string) ([]object.Secret, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/secrets"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var secrets []object.Secret
+	err = json.Unmarshal(body, &secrets)
+	if err != nil {
+		log.Println("fail to parse Secrets")
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func CreateSecret(secret object.Secret) (object.Secret, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/secret"
+
+	body, err := postRequest(url, secret)
+	if err != nil {
+		log.Println("postRequest fail")
+		return secret, err
+	}
+
+	var newSecret object.Secret
+	err = json.Unmarshal(body, &newSecret)
+	if err != nil {
+		log.Println("fail to parse Secret")
+		return secret, err
+	}
+
+	return newSecret, nil
+}
+
+func UpdateSecret(secret object.Secret) (object.Secret, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/secret/" + secret.UID
+
+	body, err := putRequest(url, secret)
+	if err != nil {
+		log.Println("putRequest fail")
+		return secret, err
+	}
+
+	var newSecret object.Secret
+	err = json.Unmarshal(body, &newSecret)
+	if err != nil {
+		log.Println("fail to parse Secret")
+		return secret, err
+	}
+
+	return newSecret, nil
+}
+
+func DeleteSecret(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/secret/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
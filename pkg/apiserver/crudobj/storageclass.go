@@ -0,0 +1,116 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"log"
+	"strconv"
+)
+
+func GetStorageClass(UID string) (object.StorageClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/storageClass/" + UID
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return object.StorageClass{}, err
+	}
+
+	var storageClass object.StorageClass
+	err = json.Unmarshal(body, &storageClass)
+	if err != nil {
+		log.Println("fail to parse StorageClass")
+		return object.StorageClass{}, err
+	}
+
+	return storageClass, nil
+}
+
+func GetStorageClasses() ([]object.StorageClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/storageClasses"
+
+	body, err := getRequest(url)
+	if err != nil {
+		log.Println("getRequest fail")
+		return nil, err
+	}
+
+	var storageClasses []object.StorageClass
+	err = json.Unmarshal(body, &storageClasses)
+	if err != nil {
+		log.Println("fail to parse StorageClasses")
+		return nil, err
+	}
+
+	return storageClasses, nil
+}
+
+func SelectStorageClasses(selectors map[string]string) ([]object.StorageClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/select/storageClasses"
+
+	body, err := postRequest(url, selectors)
+	if err != nil {
+		log.Println("postRequest fail")
+		return nil, err
+	}
+
+	var storageClasses []object.StorageClass
+	err = json.Unmarshal(body, &storageClasses)
+	if err != nil {
+		log.Println("fail to parse StorageClasses")
+		return nil, err
+	}
+
+	return storageClasses, nil
+}
+
+func CreateStorageClass(storageClass object.StorageClass) (object.StorageClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/storageClass"
+
+	body, err := postRequest(url, storageClass)
+	if err != nil {
+		log.Println("postRequest fail")
+		return storageClass, err
+	}
+
+	var newStorageClass object.StorageClass
+	err = json.Unmarshal(body, &newStorageClass)
+	if err != nil {
+		log.Println("fail to parse StorageClass")
+		return storageClass, err
+	}
+
+	return newStorageClass, nil
+}
+
+func UpdateStorageClass(storageClass object.StorageClass) (object.StorageClass, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/storageClass/" + storageClass.UID
+
+	body, err := putRequest(url, storageClass)
+	if err != nil {
+		log.Println("putRequest fail")
+		return storageClass, err
+	}
+
+	var newStorageClass object.StorageClass
+	err = json.Unmarshal(body, &newStorageClass)
+	if err != nil {
+		log.Println("fail to parse StorageClass")
+		return storageClass, err
+	}
+
+	return newStorageClass, nil
+}
+
+func DeleteStorageClass(UID string) error {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/storageClass/" + UID
+
+	err := deleteRequest(url)
+	if err != nil {
+		log.Println("deleteRequest fail")
+		return err
+	}
+
+	return nil
+}
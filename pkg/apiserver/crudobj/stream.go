@@ -0,0 +1,26 @@
+package crudobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"io"
+	"strconv"
+)
+
+// ExecPod starts opts.Command in a pod container, and returns the stream to serve by remotecommand.Stream
+func ExecPod(UID string, opts object.ExecOptions) (io.ReadWriteCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID + "/exec"
+	return remotecommand.Dial(url+"?"+opts.Query().Encode(), nil)
+}
+
+func AttachPod(UID string, opts object.ExecOptions) (io.ReadWriteCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID + "/attach"
+	return remotecommand.Dial(url+"?"+opts.Query().Encode(), nil)
+}
+
+// PortForwardPod returns a raw connection to port in the pod network namespace
+func PortForwardPod(UID string, port int) (io.ReadWriteCloser, error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/pod/" + UID + "/portForward"
+	return remotecommand.Dial(url+"?"+object.PortForwardQueryPort+"="+strconv.Itoa(port), nil)
+}
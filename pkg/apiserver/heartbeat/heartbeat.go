@@ -27,6 +27,7 @@ var lastUpdate time.Time
 
 var timeLock sync.Mutex
 var connLock sync.Mutex
+var nodeLock sync.Mutex
 
 // CheckConn
 // package heartbeat is designed for cubelet
@@ -73,7 +74,9 @@ func maintainHealth(conn *net.Conn, cnt int) {
 			return
 		}
 
+		nodeLock.Lock()
 		buf, err := json.Marshal(node)
+		nodeLock.Unlock()
 		if err != nil {
 			log.Println("Fail to marshal Node, err: ", err)
 			return
@@ -140,11 +143,24 @@ func retryConnection() {
 	go updateHeartBeat()
 }
 
+// UpdateNodeStatus changes status of the node sent with heartbeats,
+// apiserver stores it once it is different from the last one
+func UpdateNodeStatus(update func(status *object.NodeStatus)) {
+	nodeLock.Lock()
+	defer nodeLock.Unlock()
+	if node.Status == nil {
+		node.Status = &object.NodeStatus{}
+	}
+	update(node.Status)
+}
+
 // InitNode
 // package heartbeat is designed for cubelet
 func InitNode(n object.Node) {
+	nodeLock.Lock()
 	node = n
 	node.Status.Condition.Ready = true
+	nodeLock.Unlock()
 	connected = false
 	lostCnt = 0
 	go updateHeartBeat()
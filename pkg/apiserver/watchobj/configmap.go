@@ -0,0 +1,73 @@
+package watchobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type ConfigMapEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// ConfigMap will only have its UID
+	ConfigMap object.ConfigMap
+}
+
+func WatchConfigMap(UID string) (chan ConfigMapEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/configMap/" + UID
+	ch, cancel, err := createConfigMapWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+// WatchConfigMaps
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchConfigMaps() (chan ConfigMapEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/configMaps"
+	ch, cancel, err := createConfigMapWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+func createConfigMapWatch(url string) (chan ConfigMapEvent, context.CancelFunc, error) {
+	ch := make(chan ConfigMapEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing ConfigMapEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var configMapEvent ConfigMapEvent
+		configMapEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &configMapEvent.ConfigMap)
+			if err != nil {
+				log.Println("fail to parse ConfigMap in ConfigMapEvent")
+				return
+			}
+		case EVENT_DELETE:
+			configMapEvent.ConfigMap.UID = e.Path[len(object.ConfigMapEtcdPrefix):]
+		}
+		ch <- configMapEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
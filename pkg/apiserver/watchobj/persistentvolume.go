@@ -0,0 +1,117 @@
+package watchobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type PersistentVolumeEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// PersistentVolume will only have its UID
+	PersistentVolume object.PersistentVolume
+}
+
+type PersistentVolumeClaimEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// PersistentVolumeClaim will only have its UID
+	PersistentVolumeClaim object.PersistentVolumeClaim
+}
+
+// WatchPersistentVolumes
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchPersistentVolumes() (chan PersistentVolumeEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/persistentVolumes"
+	ch, cancel, err := createPersistentVolumeWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+// WatchPersistentVolumeClaims
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchPersistentVolumeClaims() (chan PersistentVolumeClaimEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/persistentVolumeClaims"
+	ch, cancel, err := createPersistentVolumeClaimWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+func createPersistentVolumeWatch(url string) (chan PersistentVolumeEvent, context.CancelFunc, error) {
+	ch := make(chan PersistentVolumeEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing PersistentVolumeEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var pvEvent PersistentVolumeEvent
+		pvEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &pvEvent.PersistentVolume)
+			if err != nil {
+				log.Println("fail to parse PersistentVolume in PersistentVolumeEvent")
+				return
+			}
+		case EVENT_DELETE:
+			pvEvent.PersistentVolume.UID = e.Path[len(object.PersistentVolumeEtcdPrefix):]
+		}
+		ch <- pvEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
+
+func createPersistentVolumeClaimWatch(url string) (chan PersistentVolumeClaimEvent, context.CancelFunc, error) {
+	ch := make(chan PersistentVolumeClaimEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing PersistentVolumeClaimEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var pvcEvent PersistentVolumeClaimEvent
+		pvcEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &pvcEvent.PersistentVolumeClaim)
+			if err != nil {
+				log.Println("fail to parse PersistentVolumeClaim in PersistentVolumeClaimEvent")
+				return
+			}
+		case EVENT_DELETE:
+			pvcEvent.PersistentVolumeClaim.UID = e.Path[len(object.PersistentVolumeClaimEtcdPrefix):]
+		}
+		ch <- pvcEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
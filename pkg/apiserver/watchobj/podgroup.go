@@ -0,0 +1,73 @@
+package watchobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type PodGroupEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// PodGroup will only have its UID
+	PodGroup object.PodGroup
+}
+
+func WatchPodGroup(UID string) (chan PodGroupEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/podGroup/" + UID
+	ch, cancel, err := createPodGroupWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+// WatchPodGroups
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchPodGroups() (chan PodGroupEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/podGroups"
+	ch, cancel, err := createPodGroupWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+func createPodGroupWatch(url string) (chan PodGroupEvent, context.CancelFunc, error) {
+	ch := make(chan PodGroupEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing PodGroupEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var podGroupEvent PodGroupEvent
+		podGroupEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &podGroupEvent.PodGroup)
+			if err != nil {
+				log.Println("fail to parse PodGroup in PodGroupEvent")
+				return
+			}
+		case EVENT_DELETE:
+			podGroupEvent.PodGroup.UID = e.Path[len(object.PodGroupEtcdPrefix):]
+		}
+		ch <- podGroupEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
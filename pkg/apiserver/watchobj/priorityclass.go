@@ -0,0 +1,73 @@
+package watchobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type PriorityClassEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// PriorityClass will only have its UID
+	PriorityClass object.PriorityClass
+}
+
+func WatchPriorityClass(UID string) (chan PriorityClassEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/priorityClass/" + UID
+	ch, cancel, err := createPriorityClassWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+// WatchPriorityClasses
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchPriorityClasses() (chan PriorityClassEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/priorityClasses"
+	ch, cancel, err := createPriorityClassWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+func createPriorityClassWatch(url string) (chan PriorityClassEvent, context.CancelFunc, error) {
+	ch := make(chan PriorityClassEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing PriorityClassEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var priorityClassEvent PriorityClassEvent
+		priorityClassEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &priorityClassEvent.PriorityClass)
+			if err != nil {
+				log.Println("fail to parse PriorityClass in PriorityClassEvent")
+				return
+			}
+		case EVENT_DELETE:
+			priorityClassEvent.PriorityClass.UID = e.Path[len(object.PriorityClassEtcdPrefix):]
+		}
+		ch <- priorityClassEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
@@ -0,0 +1,73 @@
+package watchobj
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/object"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync/atomic"
+)
+
+type SecretEvent struct {
+	EType EventType
+	// if EType == EVENT_DELETE,
+	// Secret will only have its UID
+	Secret object.Secret
+}
+
+func WatchSecret(UID string) (chan SecretEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/secret/" + UID
+	ch, cancel, err := createSecretWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+// WatchSecrets
+// if err != nil, chan and cancel() will be nil
+// if you call cancel() or connection failed, channel will be closed
+func WatchSecrets() (chan SecretEvent, func(), error) {
+	url := "http://" + cubeconfig.APIServerIp + ":" + strconv.Itoa(cubeconfig.APIServerPort) + "/apis/watch/secrets"
+	ch, cancel, err := createSecretWatch(url)
+	if err != nil && cancel != nil {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+	return ch, cancel, err
+}
+
+func createSecretWatch(url string) (chan SecretEvent, context.CancelFunc, error) {
+	ch := make(chan SecretEvent)
+	var closed int32 = 0
+	closeChan := func() {
+		swapped := atomic.CompareAndSwapInt32(&closed, 0, 1)
+		if swapped {
+			log.Println("closing SecretEvent channel")
+			close(ch)
+		}
+	}
+	stop, err := postWatch(url, closeChan, func(e ObjEvent) {
+		var secretEvent SecretEvent
+		secretEvent.EType = e.EType
+		switch e.EType {
+		case EVENT_PUT:
+			err := json.Unmarshal([]byte(e.Object), &secretEvent.Secret)
+			if err != nil {
+				log.Println("fail to parse Secret in SecretEvent")
+				return
+			}
+		case EVENT_DELETE:
+			secretEvent.Secret.UID = e.Path[len(object.SecretEtcdPrefix):]
+		}
+		ch <- secretEvent
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, func() {
+		closeChan()
+		stop()
+	}, nil
+}
@@ -0,0 +1,82 @@
+package podgc_controller
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/apiserver/health"
+	"Cubernetes/pkg/object"
+	"log"
+	"time"
+)
+
+const podGCCheckInterval = time.Second * 5
+
+// PodGCController removes pods no cubelet will ever clean up:
+// pods bound to deleted nodes, and terminating pods on not ready nodes
+// whose grace period has expired
+type PodGCController interface {
+	Run()
+}
+
+func NewPodGCController() (PodGCController, error) {
+	return &podGCController{}, nil
+}
+
+type podGCController struct{}
+
+func (pc *podGCController) Run() {
+	for {
+		time.Sleep(podGCCheckInterval)
+		pc.gcRoutine()
+	}
+}
+
+func (pc *podGCController) gcRoutine() {
+	if !health.CheckApiServerHealth() {
+		log.Printf("[FATAL] lost connection with apiserver: not collect pods this time\n")
+		return
+	}
+
+	nodes, err := crudobj.GetNodes()
+	if err != nil {
+		log.Printf("fail to get nodes from apiserver: %v\n", err)
+		return
+	}
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		log.Printf("fail to get pods from apiserver: %v\n", err)
+		return
+	}
+
+	for _, UID := range PodsToForceDelete(nodes, pods, time.Now()) {
+		log.Printf("[INFO]: force delete pod %s, its node is gone or not ready\n", UID)
+		if err = crudobj.DeletePodWithGracePeriod(UID, 0); err != nil {
+			log.Printf("fail to force delete pod %s: %v\n", UID, err)
+		}
+	}
+}
+
+// PodsToForceDelete returns UID of pods bound to nodes that no longer exist,
+// and of terminating pods past their deletionTimestamp on not ready nodes
+func PodsToForceDelete(nodes []object.Node, pods []object.Pod, now time.Time) []string {
+	ready := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		ready[node.UID] = node.Status != nil && node.Status.Condition.Ready
+	}
+
+	UIDs := make([]string, 0)
+	for _, pod := range pods {
+		if pod.Status == nil || pod.Status.NodeUID == "" {
+			continue
+		}
+
+		nodeReady, exist := ready[pod.Status.NodeUID]
+		if !exist {
+			UIDs = append(UIDs, pod.UID)
+			continue
+		}
+		if !nodeReady && object.IsPodTerminating(&pod) && !now.Before(*pod.DeletionTimestamp) {
+			UIDs = append(UIDs, pod.UID)
+		}
+	}
+	return UIDs
+}
@@ -0,0 +1,48 @@
+package testing
+
+import (
+	"Cubernetes/pkg/controllermanager/controller/podgc_controller"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func buildNode(uid string, ready bool) object.Node {
+	return object.Node{
+		ObjectMeta: object.ObjectMeta{UID: uid},
+		Status:     &object.NodeStatus{Condition: object.NodeCondition{Ready: ready}},
+	}
+}
+
+func buildPod(uid string, nodeUID string, deletionTimestamp *time.Time) object.Pod {
+	return object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: uid, DeletionTimestamp: deletionTimestamp},
+		Status:     &object.PodStatus{NodeUID: nodeUID, Phase: object.PodRunning},
+	}
+}
+
+func TestPodsToForceDelete(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Second)
+	pending := now.Add(time.Minute)
+
+	nodes := []object.Node{buildNode("ready", true), buildNode("down", false)}
+	pods := []object.Pod{
+		buildPod("running-on-ready", "ready", nil),
+		buildPod("expired-on-ready", "ready", &expired),
+		buildPod("running-on-down", "down", nil),
+		buildPod("pending-on-down", "down", &pending),
+		buildPod("expired-on-down", "down", &expired),
+		buildPod("on-deleted-node", "deleted", nil),
+		buildPod("unscheduled", "", nil),
+	}
+
+	// the cubelet of a ready node finishes termination itself,
+	// taint controller decides when pods on a down node are evicted
+	UIDs := podgc_controller.PodsToForceDelete(nodes, pods, now)
+	assert.ElementsMatch(t, []string{"expired-on-down", "on-deleted-node"}, UIDs)
+
+	UIDs = podgc_controller.PodsToForceDelete(nodes, pods, pending)
+	assert.ElementsMatch(t, []string{"pending-on-down", "expired-on-down", "on-deleted-node"}, UIDs)
+}
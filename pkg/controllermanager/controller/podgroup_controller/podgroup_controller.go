@@ -0,0 +1,101 @@
+package podgroup_controller
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/apiserver/health"
+	"Cubernetes/pkg/object"
+	"log"
+	"time"
+)
+
+const podGroupCheckInterval = time.Second * 5
+
+// PodGroupController counts members of pod groups,
+// and updates group status when the counts change
+type PodGroupController interface {
+	Run()
+}
+
+func NewPodGroupController() (PodGroupController, error) {
+	return &podGroupController{}, nil
+}
+
+type podGroupController struct{}
+
+func (pc *podGroupController) Run() {
+	for {
+		time.Sleep(podGroupCheckInterval)
+		pc.updateStatusRoutine()
+	}
+}
+
+func (pc *podGroupController) updateStatusRoutine() {
+	if !health.CheckApiServerHealth() {
+		log.Printf("[FATAL] lost connection with apiserver: not update pod groups this time\n")
+		return
+	}
+
+	podGroups, err := crudobj.GetPodGroups()
+	if err != nil {
+		log.Printf("fail to get pod groups from apiserver: %v\n", err)
+		return
+	}
+	if len(podGroups) == 0 {
+		return
+	}
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		log.Printf("fail to get pods from apiserver: %v\n", err)
+		return
+	}
+
+	for _, pg := range podGroups {
+		status := ComputePodGroupStatus(&pg, pods)
+		if pg.Status != nil && pg.Status.Phase == status.Phase &&
+			pg.Status.Scheduled == status.Scheduled && pg.Status.Running == status.Running &&
+			pg.Status.Succeeded == status.Succeeded && pg.Status.Failed == status.Failed {
+			continue
+		}
+
+		log.Printf("[INFO]: pod group %s is %s, scheduled %d, running %d\n",
+			pg.Name, status.Phase, status.Scheduled, status.Running)
+		pg.Status = &status
+		if _, err = crudobj.UpdatePodGroup(pg); err != nil {
+			log.Printf("fail to update pod group %s: %v\n", pg.UID, err)
+		}
+	}
+}
+
+// ComputePodGroupStatus counts pods labeled with the group name by phase
+func ComputePodGroupStatus(pg *object.PodGroup, pods []object.Pod) object.PodGroupStatus {
+	status := object.PodGroupStatus{LastUpdateTime: time.Now()}
+	for idx := range pods {
+		pod := &pods[idx]
+		if object.GetPodGroupName(pod) != pg.Name || pod.Status == nil {
+			continue
+		}
+		if pod.Status.NodeUID != "" {
+			status.Scheduled++
+		}
+		switch pod.Status.Phase {
+		case object.PodRunning:
+			status.Running++
+		case object.PodSucceeded:
+			status.Succeeded++
+		case object.PodFailed:
+			status.Failed++
+		}
+	}
+
+	switch {
+	case status.Succeeded >= pg.Spec.MinMember:
+		status.Phase = object.PodGroupFinished
+	case status.Running >= pg.Spec.MinMember:
+		status.Phase = object.PodGroupRunning
+	case status.Scheduled >= pg.Spec.MinMember:
+		status.Phase = object.PodGroupScheduled
+	default:
+		status.Phase = object.PodGroupPending
+	}
+	return status
+}
@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"Cubernetes/pkg/controllermanager/controller/podgroup_controller"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildPod(group string, nodeUID string, phase object.PodPhase) object.Pod {
+	return object.Pod{
+		ObjectMeta: object.ObjectMeta{Labels: map[string]string{object.PodGroupLabel: group}},
+		Status:     &object.PodStatus{NodeUID: nodeUID, Phase: phase},
+	}
+}
+
+func TestComputePodGroupStatus(t *testing.T) {
+	pg := &object.PodGroup{
+		ObjectMeta: object.ObjectMeta{Name: "train"},
+		Spec:       object.PodGroupSpec{MinMember: 2},
+	}
+
+	pods := []object.Pod{
+		buildPod("train", "node1", object.PodRunning),
+		buildPod("train", "", object.PodCreated),
+		buildPod("other", "node1", object.PodRunning),
+	}
+	status := podgroup_controller.ComputePodGroupStatus(pg, pods)
+	assert.Equal(t, object.PodGroupPending, status.Phase)
+	assert.Equal(t, int32(1), status.Scheduled)
+	assert.Equal(t, int32(1), status.Running)
+
+	pods[1] = buildPod("train", "node2", object.PodBound)
+	status = podgroup_controller.ComputePodGroupStatus(pg, pods)
+	assert.Equal(t, object.PodGroupScheduled, status.Phase)
+	assert.Equal(t, int32(2), status.Scheduled)
+
+	pods[1] = buildPod("train", "node2", object.PodRunning)
+	status = podgroup_controller.ComputePodGroupStatus(pg, pods)
+	assert.Equal(t, object.PodGroupRunning, status.Phase)
+}
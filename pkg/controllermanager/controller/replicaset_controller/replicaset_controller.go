@@ -146,6 +146,10 @@ func (rsc *replicaSetController) checkAndUpdateReplicaSetStatus(rs *object.Repli
 	runnings := make([]string, 0)
 	bads := make([]string, 0)
 	for _, pod := range currentPods {
+		if object.IsPodTerminating(&pod) {
+			// being deleted, replaced as if it were gone
+			continue
+		}
 		if phase.Running(pod.Status.Phase) {
 			runnings = append(runnings, pod.UID)
 		} else if phase.Bad(pod.Status.Phase) {
@@ -0,0 +1,144 @@
+package taint_controller
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/apiserver/health"
+	"Cubernetes/pkg/object"
+	"log"
+	"time"
+)
+
+const (
+	taintCheckInterval = time.Second * 5
+	// pods not tolerating not-ready taint explicitly are evicted after this
+	defaultNotReadyTolerationSeconds = 300
+)
+
+// TaintController evicts pods from nodes with NoExecute taints
+// which are not tolerated by the pods
+type TaintController interface {
+	Run()
+}
+
+func NewTaintController() (TaintController, error) {
+	return &taintController{
+		taintedSince: make(map[string]time.Time),
+	}, nil
+}
+
+type taintController struct {
+	// nodeUID/taintKey -> first time this controller saw the taint
+	taintedSince map[string]time.Time
+}
+
+func (tc *taintController) Run() {
+	for {
+		time.Sleep(taintCheckInterval)
+		tc.checkTaintsRoutine()
+	}
+}
+
+func (tc *taintController) checkTaintsRoutine() {
+	if !health.CheckApiServerHealth() {
+		log.Printf("[FATAL] lost connection with apiserver: not check taints this time\n")
+		return
+	}
+
+	nodes, err := crudobj.GetNodes()
+	if err != nil {
+		log.Printf("fail to get nodes from apiserver: %v\n", err)
+		return
+	}
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		log.Printf("fail to get pods from apiserver: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	noExecuteTaints := make(map[string][]object.Taint)
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		taints := make([]object.Taint, 0)
+		for _, taint := range node.EffectiveTaints() {
+			if taint.Effect != object.TaintEffectNoExecute {
+				continue
+			}
+			key := taintKey(node.UID, &taint)
+			seen[key] = true
+			if _, ok := tc.taintedSince[key]; !ok {
+				tc.taintedSince[key] = now
+			}
+			taints = append(taints, taint)
+		}
+		noExecuteTaints[node.UID] = taints
+	}
+
+	// forget taints already removed
+	for key := range tc.taintedSince {
+		if !seen[key] {
+			delete(tc.taintedSince, key)
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.Status == nil || pod.Status.NodeUID == "" {
+			continue
+		}
+		taints, ok := noExecuteTaints[pod.Status.NodeUID]
+		if !ok || len(taints) == 0 {
+			continue
+		}
+
+		if tc.shouldEvict(&pod, pod.Status.NodeUID, taints, now) {
+			log.Printf("[INFO]: evict pod %s from tainted node %s\n", pod.UID, pod.Status.NodeUID)
+			if err := crudobj.DeletePod(pod.UID); err != nil {
+				log.Printf("fail to evict pod %s: %v\n", pod.UID, err)
+			}
+		}
+	}
+}
+
+func (tc *taintController) shouldEvict(pod *object.Pod, nodeUID string, taints []object.Taint, now time.Time) bool {
+	tolerations := pod.Spec.Tolerations
+	for idx := range taints {
+		taint := &taints[idx]
+		var seconds int64 = -1
+
+		tolerated := false
+		for _, toleration := range tolerations {
+			if !toleration.ToleratesTaint(taint) {
+				continue
+			}
+			tolerated = true
+			if toleration.TolerationSeconds == nil {
+				// tolerate forever
+				seconds = -1
+				break
+			}
+			if seconds == -1 || *toleration.TolerationSeconds < seconds {
+				seconds = *toleration.TolerationSeconds
+			}
+		}
+
+		if !tolerated {
+			if taint.Key != object.TaintNodeNotReady {
+				return true
+			}
+			seconds = defaultNotReadyTolerationSeconds
+		} else if seconds == -1 {
+			continue
+		}
+
+		since := tc.taintedSince[taintKey(nodeUID, taint)]
+		if now.Sub(since) >= time.Duration(seconds)*time.Second {
+			return true
+		}
+	}
+
+	return false
+}
+
+func taintKey(nodeUID string, taint *object.Taint) string {
+	return nodeUID + "/" + taint.Key
+}
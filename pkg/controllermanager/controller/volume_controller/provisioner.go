@@ -0,0 +1,67 @@
+package volume_controller
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/mount"
+	"fmt"
+	"os"
+	"path"
+)
+
+// provisionVolume returns the volume to create for the claim. Directories of local volumes
+// are created by cubelet when pods use them, directories of NFS volumes are created here
+func provisionVolume(pvc *object.PersistentVolumeClaim, class *object.StorageClass) (*object.PersistentVolume, error) {
+	pv := &object.PersistentVolume{
+		TypeMeta: object.TypeMeta{Kind: object.KindPersistentVolume, APIVersion: "v1"},
+		ObjectMeta: object.ObjectMeta{
+			Name:        "pvc-" + pvc.UID,
+			Annotations: map[string]string{object.AnnProvisionedBy: class.Provisioner},
+		},
+		Spec: object.PersistentVolumeSpec{
+			Capacity:         pvc.Spec.Storage,
+			AccessModes:      pvc.Spec.AccessModes,
+			ReclaimPolicy:    object.GetStorageClassReclaimPolicy(class),
+			StorageClassName: class.Name,
+			ClaimRef:         &object.ClaimReference{Name: pvc.Name, UID: pvc.UID},
+		},
+	}
+
+	switch class.Provisioner {
+	case object.LocalProvisioner:
+		node := pvc.Annotations[object.AnnSelectedNode]
+		if node == "" {
+			return nil, fmt.Errorf("no node is selected for claim %s", pvc.Name)
+		}
+		pv.Spec.Local = &object.LocalVolumeSource{Path: path.Join(object.LocalProvisionerBaseDir, pv.Name)}
+		pv.Spec.NodeAffinity = &object.VolumeNodeAffinity{NodeNames: []string{node}}
+	case object.NFSProvisioner:
+		server := class.Parameters[object.StorageClassParamServer]
+		export := class.Parameters[object.StorageClassParamPath]
+		err := mount.WithNFSExport(server, export, func(dir string) error {
+			if err := os.MkdirAll(path.Join(dir, pv.Name), 0777); err != nil {
+				return err
+			}
+			return os.Chmod(path.Join(dir, pv.Name), 0777)
+		})
+		if err != nil {
+			return nil, err
+		}
+		pv.Spec.NFS = &object.NFSVolumeSource{Server: server, Path: path.Join(export, pv.Name)}
+	default:
+		return nil, fmt.Errorf("unknown provisioner %s", class.Provisioner)
+	}
+	return pv, nil
+}
+
+// deleteVolume removes data of a provisioned NFS volume. Data of local volumes are
+// removed by cubelet of the node once the volume is gone, volumes created by hand are kept
+func deleteVolume(pv *object.PersistentVolume) error {
+	if pv.Annotations[object.AnnProvisionedBy] != object.NFSProvisioner || pv.Spec.NFS == nil {
+		return nil
+	}
+
+	export, name := path.Split(pv.Spec.NFS.Path)
+	return mount.WithNFSExport(pv.Spec.NFS.Server, export, func(dir string) error {
+		return os.RemoveAll(path.Join(dir, name))
+	})
+}
@@ -0,0 +1,56 @@
+package testing
+
+import (
+	"Cubernetes/pkg/controllermanager/controller/volume_controller"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildPV(name string, capacity int64, modes ...object.PersistentVolumeAccessMode) object.PersistentVolume {
+	return object.PersistentVolume{
+		ObjectMeta: object.ObjectMeta{Name: name},
+		Spec:       object.PersistentVolumeSpec{Capacity: capacity, AccessModes: modes},
+		Status:     &object.PersistentVolumeStatus{Phase: object.VolumeAvailable},
+	}
+}
+
+func TestFindMatchingVolume(t *testing.T) {
+	pvc := &object.PersistentVolumeClaim{
+		ObjectMeta: object.ObjectMeta{Name: "data", UID: "pvc-uid"},
+		Spec: object.PersistentVolumeClaimSpec{
+			AccessModes: []object.PersistentVolumeAccessMode{object.ReadWriteOnce},
+			Storage:     100,
+		},
+	}
+
+	pvs := []object.PersistentVolume{
+		buildPV("small", 50, object.ReadWriteOnce),
+		buildPV("large", 300, object.ReadWriteOnce, object.ReadWriteMany),
+		buildPV("fit", 200, object.ReadWriteOnce),
+		buildPV("readonly", 100, object.ReadOnlyMany),
+	}
+	assert.Equal(t, "fit", volume_controller.FindMatchingVolume(pvc, pvs).Name)
+
+	// bound volumes and volumes of other classes are skipped
+	pvs[2].Status.Phase = object.VolumeBound
+	pvs[1].Spec.StorageClassName = "local"
+	assert.Nil(t, volume_controller.FindMatchingVolume(pvc, pvs))
+
+	// volumes reserved for the claim are preferred
+	pvs[1].Spec.StorageClassName = ""
+	pvs = append(pvs, buildPV("reserved", 500, object.ReadWriteOnce))
+	pvs[4].Spec.ClaimRef = &object.ClaimReference{Name: "data"}
+	assert.Equal(t, "reserved", volume_controller.FindMatchingVolume(pvc, pvs).Name)
+
+	pvs[4].Spec.ClaimRef.UID = "other-uid"
+	assert.Equal(t, "large", volume_controller.FindMatchingVolume(pvc, pvs).Name)
+
+	pvc.Spec.Selector = map[string]string{"usage": "db"}
+	assert.Nil(t, volume_controller.FindMatchingVolume(pvc, pvs))
+
+	// a volume bound to the claim is adopted, though the claim was not updated
+	pvs[0].Spec.ClaimRef = &object.ClaimReference{Name: "data", UID: "pvc-uid"}
+	pvs[0].Status.Phase = object.VolumeBound
+	assert.Equal(t, "small", volume_controller.FindMatchingVolume(pvc, pvs).Name)
+}
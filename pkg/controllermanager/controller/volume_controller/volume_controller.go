@@ -0,0 +1,244 @@
+package volume_controller
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/apiserver/health"
+	"Cubernetes/pkg/object"
+	"log"
+	"time"
+)
+
+const volumeCheckInterval = time.Second * 5
+
+// VolumeController binds claims to volumes matching their capacity, access modes and class,
+// provisions volumes for claims of storage classes, and reclaims volumes whose claims are deleted
+type VolumeController interface {
+	Run()
+}
+
+func NewVolumeController() (VolumeController, error) {
+	return &volumeController{}, nil
+}
+
+type volumeController struct{}
+
+func (vc *volumeController) Run() {
+	for {
+		time.Sleep(volumeCheckInterval)
+		vc.syncRoutine()
+	}
+}
+
+func (vc *volumeController) syncRoutine() {
+	if !health.CheckApiServerHealth() {
+		log.Printf("[FATAL] lost connection with apiserver: not sync volumes this time\n")
+		return
+	}
+
+	pvs, err := crudobj.GetPersistentVolumes()
+	if err != nil {
+		log.Printf("fail to get persistent volumes from apiserver: %v\n", err)
+		return
+	}
+	pvcs, err := crudobj.GetPersistentVolumeClaims()
+	if err != nil {
+		log.Printf("fail to get persistent volume claims from apiserver: %v\n", err)
+		return
+	}
+	classes, err := crudobj.GetStorageClasses()
+	if err != nil {
+		log.Printf("fail to get storage classes from apiserver: %v\n", err)
+		return
+	}
+
+	claims := make(map[string]*object.PersistentVolumeClaim)
+	for idx := range pvcs {
+		claims[pvcs[idx].Name] = &pvcs[idx]
+	}
+	for idx := range pvs {
+		vc.syncVolume(&pvs[idx], claims)
+	}
+
+	// volumes are looked up again, since some may be reclaimed
+	volumes := make(map[string]*object.PersistentVolume)
+	for idx := range pvs {
+		volumes[pvs[idx].Name] = &pvs[idx]
+	}
+	classMap := make(map[string]*object.StorageClass)
+	for idx := range classes {
+		classMap[classes[idx].Name] = &classes[idx]
+	}
+	for idx := range pvcs {
+		vc.syncClaim(&pvcs[idx], pvs, volumes, classMap)
+	}
+}
+
+// syncVolume updates phase of the volume, and reclaims it if its claim is deleted
+func (vc *volumeController) syncVolume(pv *object.PersistentVolume, claims map[string]*object.PersistentVolumeClaim) {
+	phase := object.PersistentVolumePhase("")
+	if pv.Status != nil {
+		phase = pv.Status.Phase
+	}
+
+	if pv.Spec.ClaimRef == nil {
+		if phase != object.VolumeAvailable && phase != object.VolumeFailed {
+			vc.setVolumePhase(pv, object.VolumeAvailable, "")
+		}
+		return
+	}
+
+	claim, ok := claims[pv.Spec.ClaimRef.Name]
+	if ok && (pv.Spec.ClaimRef.UID == "" || pv.Spec.ClaimRef.UID == claim.UID) {
+		// reserved by users for the claim, which is bound by syncClaim
+		if claim.Spec.VolumeName == pv.Name && phase != object.VolumeBound {
+			vc.setVolumePhase(pv, object.VolumeBound, "")
+		}
+		return
+	}
+	if pv.Spec.ClaimRef.UID == "" || phase == object.VolumeReleased || phase == object.VolumeFailed {
+		// waiting for the claim to be created, or reclaimed already
+		return
+	}
+
+	if pv.Spec.ReclaimPolicy != object.PersistentVolumeReclaimDelete {
+		log.Printf("[INFO]: claim %s of volume %s is deleted, volume released\n", pv.Spec.ClaimRef.Name, pv.Name)
+		vc.setVolumePhase(pv, object.VolumeReleased, "claim "+pv.Spec.ClaimRef.Name+" is deleted")
+		return
+	}
+
+	log.Printf("[INFO]: claim %s of volume %s is deleted, deleting volume\n", pv.Spec.ClaimRef.Name, pv.Name)
+	if err := deleteVolume(pv); err != nil {
+		log.Printf("[Error]: fail to delete volume %s: %v\n", pv.Name, err)
+		vc.setVolumePhase(pv, object.VolumeFailed, err.Error())
+		return
+	}
+	if err := crudobj.DeletePersistentVolume(pv.UID); err != nil {
+		log.Printf("[Error]: fail to delete volume %s: %v\n", pv.Name, err)
+		return
+	}
+	pv.Spec.ClaimRef = nil
+	pv.Status = &object.PersistentVolumeStatus{Phase: object.VolumeFailed, Message: "deleted"}
+}
+
+// syncClaim binds the claim to a matching volume, or provisions one by its storage class
+func (vc *volumeController) syncClaim(pvc *object.PersistentVolumeClaim, pvs []object.PersistentVolume,
+	volumes map[string]*object.PersistentVolume, classes map[string]*object.StorageClass) {
+
+	if pvc.Spec.VolumeName != "" {
+		pv, ok := volumes[pvc.Spec.VolumeName]
+		switch {
+		case !ok:
+			if pvc.Status == nil || pvc.Status.Phase != object.ClaimLost {
+				log.Printf("[INFO]: volume %s of claim %s is lost\n", pvc.Spec.VolumeName, pvc.Name)
+				pvc.Status = &object.PersistentVolumeClaimStatus{Phase: object.ClaimLost}
+				vc.updateClaim(pvc)
+			}
+		case pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.Name != pvc.Name:
+			log.Printf("[WARNING]: volume %s wanted by claim %s is bound to %s\n",
+				pv.Name, pvc.Name, pv.Spec.ClaimRef.Name)
+		case pvc.Status == nil || pvc.Status.Phase != object.ClaimBound || pv.Spec.ClaimRef == nil ||
+			pv.Spec.ClaimRef.UID != pvc.UID:
+			vc.bind(pv, pvc)
+		}
+		return
+	}
+
+	if pv := FindMatchingVolume(pvc, pvs); pv != nil {
+		vc.bind(pv, pvc)
+		return
+	}
+
+	class, ok := classes[pvc.Spec.StorageClassName]
+	if !ok || class.Provisioner == object.NoProvisioner {
+		return
+	}
+	if object.GetVolumeBindingMode(class) == object.VolumeBindingWaitForFirstConsumer &&
+		pvc.Annotations[object.AnnSelectedNode] == "" {
+		// provisioned once scheduler picks a node for the first pod
+		return
+	}
+
+	pv, err := provisionVolume(pvc, class)
+	if err != nil {
+		log.Printf("[Error]: fail to provision volume for claim %s: %v\n", pvc.Name, err)
+		return
+	}
+	newPV, err := crudobj.CreatePersistentVolume(*pv)
+	if err != nil {
+		log.Printf("[Error]: fail to create volume for claim %s: %v\n", pvc.Name, err)
+		return
+	}
+	log.Printf("[INFO]: volume %s provisioned for claim %s\n", newPV.Name, pvc.Name)
+	vc.bind(&newPV, pvc)
+}
+
+// bind updates the volume first, so a claim is never bound to a volume taken by others
+func (vc *volumeController) bind(pv *object.PersistentVolume, pvc *object.PersistentVolumeClaim) {
+	pv.Spec.ClaimRef = &object.ClaimReference{Name: pvc.Name, UID: pvc.UID}
+	pv.Status = &object.PersistentVolumeStatus{Phase: object.VolumeBound}
+	if _, err := crudobj.UpdatePersistentVolume(*pv); err != nil {
+		log.Printf("[Error]: fail to bind volume %s to claim %s: %v\n", pv.Name, pvc.Name, err)
+		return
+	}
+
+	pvc.Spec.VolumeName = pv.Name
+	pvc.Status = &object.PersistentVolumeClaimStatus{
+		Phase:       object.ClaimBound,
+		Capacity:    pv.Spec.Capacity,
+		AccessModes: pv.Spec.AccessModes,
+	}
+	vc.updateClaim(pvc)
+	log.Printf("[INFO]: claim %s bound to volume %s\n", pvc.Name, pv.Name)
+}
+
+func (vc *volumeController) setVolumePhase(pv *object.PersistentVolume, phase object.PersistentVolumePhase, message string) {
+	pv.Status = &object.PersistentVolumeStatus{Phase: phase, Message: message}
+	if _, err := crudobj.UpdatePersistentVolume(*pv); err != nil {
+		log.Printf("[Error]: fail to update volume %s: %v\n", pv.Name, err)
+	}
+}
+
+func (vc *volumeController) updateClaim(pvc *object.PersistentVolumeClaim) {
+	if _, err := crudobj.UpdatePersistentVolumeClaim(*pvc); err != nil {
+		log.Printf("[Error]: fail to update claim %s: %v\n", pvc.Name, err)
+	}
+}
+
+// FindMatchingVolume returns the smallest available volume satisfying the claim, or nil.
+// Volumes reserved for the claim by ClaimRef are preferred, and a volume already bound to the claim
+// is returned whatever its phase is, since the claim may fail to be updated after the volume is bound
+func FindMatchingVolume(pvc *object.PersistentVolumeClaim, pvs []object.PersistentVolume) *object.PersistentVolume {
+	for idx := range pvs {
+		if ref := pvs[idx].Spec.ClaimRef; ref != nil && ref.UID != "" && ref.UID == pvc.UID {
+			return &pvs[idx]
+		}
+	}
+
+	var best *object.PersistentVolume
+	for idx := range pvs {
+		pv := &pvs[idx]
+		if pv.Status != nil && pv.Status.Phase != "" && pv.Status.Phase != object.VolumeAvailable {
+			continue
+		}
+		reserved := false
+		if pv.Spec.ClaimRef != nil {
+			if pv.Spec.ClaimRef.Name != pvc.Name || (pv.Spec.ClaimRef.UID != "" && pv.Spec.ClaimRef.UID != pvc.UID) {
+				continue
+			}
+			reserved = true
+		}
+		if pv.Spec.StorageClassName != pvc.Spec.StorageClassName || pv.Spec.Capacity < pvc.Spec.Storage ||
+			!object.HasAccessModes(pv.Spec.AccessModes, pvc.Spec.AccessModes) ||
+			!object.MatchLabelSelector(pvc.Spec.Selector, pv.Labels) {
+			continue
+		}
+
+		if reserved {
+			return pv
+		}
+		if best == nil || pv.Spec.Capacity < best.Spec.Capacity {
+			best = pv
+		}
+	}
+	return best
+}
@@ -2,7 +2,11 @@ package controllermanager
 
 import (
 	"Cubernetes/pkg/controllermanager/controller/autoscaler_controller"
+	"Cubernetes/pkg/controllermanager/controller/podgc_controller"
+	"Cubernetes/pkg/controllermanager/controller/podgroup_controller"
 	"Cubernetes/pkg/controllermanager/controller/replicaset_controller"
+	"Cubernetes/pkg/controllermanager/controller/taint_controller"
+	"Cubernetes/pkg/controllermanager/controller/volume_controller"
 	"Cubernetes/pkg/controllermanager/informer"
 	"log"
 	"sync"
@@ -12,6 +16,10 @@ type ControllerManager struct {
 	// controller daemons
 	rsController replicaset_controller.ReplicaSetController
 	asController autoscaler_controller.AutoScalerController
+	tController  taint_controller.TaintController
+	pgController podgroup_controller.PodGroupController
+	vController  volume_controller.VolumeController
+	gcController podgc_controller.PodGCController
 	// informer that watch from apiserver
 	podInformer informer.PodInformer
 	rsInformer  informer.ReplicaSetInformer
@@ -29,9 +37,17 @@ func NewControllerManager() ControllerManager {
 	// controllers
 	rsController, _ := replicaset_controller.NewReplicaSetController(podInformer, rsInformer, &wg)
 	asController, _ := autoscaler_controller.NewAutoScalerController(podInformer, rsInformer, asInformer, &wg)
+	tController, _ := taint_controller.NewTaintController()
+	pgController, _ := podgroup_controller.NewPodGroupController()
+	vController, _ := volume_controller.NewVolumeController()
+	gcController, _ := podgc_controller.NewPodGCController()
 	return ControllerManager{
 		rsController: rsController,
 		asController: asController,
+		tController:  tController,
+		pgController: pgController,
+		vController:  vController,
+		gcController: gcController,
 		podInformer:  podInformer,
 		rsInformer:   rsInformer,
 		asInformer:   asInformer,
@@ -44,6 +60,10 @@ func (cm *ControllerManager) Run() {
 	// running controllers daemon
 	go cm.rsController.Run()
 	go cm.asController.Run()
+	go cm.tController.Run()
+	go cm.pgController.Run()
+	go cm.vController.Run()
+	go cm.gcController.Run()
 
 	// informer watch must start after all controller watch
 	// so we add a WaitGroup here
@@ -27,10 +27,15 @@ func NewActorRuntime() (ActorRuntime, error) {
 		log.Println("Fail to create docker client")
 	}
 
+	return NewActorRuntimeWithDocker(dockerRuntime), nil
+}
+
+// NewActorRuntimeWithDocker creates the actor runtime on dockerRuntime, e.g. a fake one in tests
+func NewActorRuntimeWithDocker(dockerRuntime dockershim.DockerRuntime) ActorRuntime {
 	return &actorRuntimeManager{
 		dockerRuntime: dockerRuntime,
 		scriptManager: NewScriptManager(),
-	}, nil
+	}
 }
 
 type actorRuntimeManager struct {
@@ -0,0 +1,50 @@
+package testing
+
+import (
+	actor_runtime "Cubernetes/pkg/cubelet/actorruntime"
+	"Cubernetes/pkg/cubelet/dockershim/fake"
+	"Cubernetes/pkg/object"
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func startFakeContainer(t *testing.T, docker *fake.FakeDockerRuntime, name, containerType, actorUID string) string {
+	id, err := docker.CreateContainer(&dockertypes.ContainerCreateConfig{
+		Name: name,
+		Config: &dockercontainer.Config{
+			Image: "python:3.9",
+			Labels: map[string]string{
+				actor_runtime.ActorUIDLabel:      actorUID,
+				actor_runtime.ContainerTypeLabel: containerType,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, docker.StartContainer(id))
+	return id
+}
+
+func TestInspectActor(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages("python:3.9")
+	runtime := actor_runtime.NewActorRuntimeWithDocker(docker)
+
+	sandboxID := startFakeContainer(t, docker, "actor-sandbox", actor_runtime.ContainerTypeSandbox, "actor-uid")
+	containerID := startFakeContainer(t, docker, "actor-container", actor_runtime.ContainerTypeContainer, "actor-uid")
+
+	phase, err := runtime.InspectActor("actor-uid")
+	assert.NoError(t, err)
+	assert.Equal(t, object.ActorRunning, phase)
+
+	assert.NoError(t, docker.ExitContainer(containerID, 1, false))
+	phase, err = runtime.InspectActor("actor-uid")
+	assert.NoError(t, err)
+	assert.Equal(t, object.ActorFailed, phase)
+
+	assert.NoError(t, runtime.KillActor("actor-uid"))
+	_, ok := docker.GetContainer(sandboxID)
+	assert.False(t, ok)
+}
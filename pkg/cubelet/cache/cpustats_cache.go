@@ -8,18 +8,24 @@ import (
 
 type CpuStatsCache interface {
 	CalculateCpuPercent(containerID string, newCpuStats dockertypes.CPUStats) float64
+	// CalculateCoreUsagePercent is for CRI runtimes, which report cumulative cpu usage of
+	// cores at timestamp, both in nanoseconds
+	CalculateCoreUsagePercent(containerID string, usageCoreNanoSeconds uint64, timestamp int64) float64
 }
 
 func NewCpuStatsCache() CpuStatsCache {
 	return &cpuStatsCache{
-		cpuCount: runtime.NumCPU(),
-		cache:    make(map[string]cpuStats),
+		cpuCount:  runtime.NumCPU(),
+		cache:     make(map[string]cpuStats),
+		coreCache: make(map[string]cpuStats),
 	}
 }
 
 type cpuStatsCache struct {
 	cpuCount int
 	cache    map[string]cpuStats
+	// SystemUsage of CRI stats is the timestamp
+	coreCache map[string]cpuStats
 }
 
 type cpuStats struct {
@@ -48,3 +54,25 @@ func (c *cpuStatsCache) CalculateCpuPercent(containerID string, newCpuStats dock
 
 	return cpuPercent
 }
+
+// CalculateCoreUsagePercent returns 100 for each core in use since the last call,
+// return 0.0 if containerID not present.
+func (c *cpuStatsCache) CalculateCoreUsagePercent(containerID string, usageCoreNanoSeconds uint64, timestamp int64) float64 {
+	cpuPercent := 0.0
+
+	oldStats, ok := c.coreCache[containerID]
+	if ok {
+		cpuDelta := float64(usageCoreNanoSeconds) - float64(oldStats.TotalUsage)
+		timeDelta := float64(timestamp) - float64(oldStats.SystemUsage)
+		if cpuDelta > 0.0 && timeDelta > 0.0 {
+			cpuPercent = cpuDelta / timeDelta * 100.0
+		}
+	}
+
+	c.coreCache[containerID] = cpuStats{
+		TotalUsage:  usageCoreNanoSeconds,
+		SystemUsage: uint64(timestamp),
+	}
+
+	return cpuPercent
+}
@@ -0,0 +1,273 @@
+package cgroup
+
+import (
+	"Cubernetes/pkg/object"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// cgroups of pods are put under cubepods by QoS class: cubepods/pod<UID> for Guaranteed pods,
+// cubepods/burstable/pod<UID> and cubepods/besteffort/pod<UID> for the others
+const (
+	rootCgroupName       = "cubepods"
+	burstableCgroupName  = "burstable"
+	bestEffortCgroupName = "besteffort"
+	podCgroupNamePrefix  = "pod"
+)
+
+const (
+	CgroupfsDriver = "cgroupfs"
+	SystemdDriver  = "systemd"
+)
+
+const cgroupMountPoint = "/sys/fs/cgroup"
+
+const (
+	cpuPeriod    = 100000
+	sharesPerCPU = 1024
+	minShares    = 2
+	maxShares    = 262144
+)
+
+// PodCgroupManager puts sandbox and containers of a pod in the pod cgroup,
+// which is limited to the sum of limits of its containers. Cgroups are created by docker
+// when the sandbox is created, then limits are written to them
+type PodCgroupManager interface {
+	// GetPodCgroupParent returns the cgroup parent of sandbox and containers of the pod
+	GetPodCgroupParent(pod *object.Pod) string
+	// UpdatePodCgroup sets cpu shares, cpu quota and memory limit of the pod cgroup
+	UpdatePodCgroup(pod *object.Pod) error
+	// DestroyPodCgroup removes the pod cgroup, containers of the pod must be removed first
+	DestroyPodCgroup(podUID string) error
+	// ListPodCgroups returns UID of pods whose cgroups exist
+	ListPodCgroups() ([]string, error)
+}
+
+// NewPodCgroupManager returns the manager for driver of docker, cgroup v1 and v2 are both supported
+func NewPodCgroupManager(driver string) PodCgroupManager {
+	_, err := os.Stat(filepath.Join(cgroupMountPoint, "cgroup.controllers"))
+	return &podCgroupManager{
+		mountPoint: cgroupMountPoint,
+		systemd:    driver == SystemdDriver,
+		unified:    err == nil,
+	}
+}
+
+type podCgroupManager struct {
+	mountPoint string
+	systemd    bool
+	// unified tells if cgroup v2 is used
+	unified bool
+}
+
+// cgroupName is the path of a cgroup from the root, e.g. [cubepods burstable pod<UID>]
+type cgroupName []string
+
+func qosCgroupName(qos object.PodQOSClass) cgroupName {
+	switch qos {
+	case object.PodQOSBurstable:
+		return cgroupName{rootCgroupName, burstableCgroupName}
+	case object.PodQOSBestEffort:
+		return cgroupName{rootCgroupName, bestEffortCgroupName}
+	default:
+		return cgroupName{rootCgroupName}
+	}
+}
+
+func podCgroupName(qos object.PodQOSClass, podUID string) cgroupName {
+	return append(qosCgroupName(qos), podCgroupNamePrefix+podUID)
+}
+
+// systemdSlice returns the slice name of the cgroup, e.g. cubepods-burstable-pod<UID>.slice,
+// dashes in each part are escaped since they separate parents in slice names
+func systemdSlice(name cgroupName) string {
+	parts := make([]string, len(name))
+	for idx, part := range name {
+		parts[idx] = strings.ReplaceAll(part, "-", "_")
+	}
+	return strings.Join(parts, "-") + ".slice"
+}
+
+// parent returns the cgroup parent passed to docker
+func (m *podCgroupManager) parent(name cgroupName) string {
+	if m.systemd {
+		return systemdSlice(name)
+	}
+	return "/" + strings.Join(name, "/")
+}
+
+// path returns the path of the cgroup relative to a hierarchy
+func (m *podCgroupManager) path(name cgroupName) string {
+	if !m.systemd {
+		return filepath.Join(name...)
+	}
+	slices := make([]string, len(name))
+	for idx := range name {
+		slices[idx] = systemdSlice(name[:idx+1])
+	}
+	return filepath.Join(slices...)
+}
+
+// dir returns the directory of the cgroup in the hierarchy of subsystem, subsystem is ignored in cgroup v2
+func (m *podCgroupManager) dir(name cgroupName, subsystem string) string {
+	if m.unified {
+		return filepath.Join(m.mountPoint, m.path(name))
+	}
+	return filepath.Join(m.mountPoint, subsystem, m.path(name))
+}
+
+func (m *podCgroupManager) GetPodCgroupParent(pod *object.Pod) string {
+	return m.parent(podCgroupName(object.GetPodQOS(pod), pod.UID))
+}
+
+func (m *podCgroupManager) UpdatePodCgroup(pod *object.Pod) error {
+	qos := object.GetPodQOS(pod)
+	name := podCgroupName(qos, pod.UID)
+	requestCpus, _ := object.GetPodRequests(pod)
+	limitCpus, limitMemory := object.GetPodLimits(pod)
+
+	shares := int64(minShares)
+	if qos != object.PodQOSBestEffort {
+		shares = cpuSharesOf(requestCpus)
+	}
+	quota := int64(-1)
+	if limitCpus > 0 {
+		quota = int64(limitCpus * cpuPeriod)
+	}
+	memory := int64(-1)
+	if limitMemory > 0 {
+		memory = limitMemory
+	}
+
+	if qos == object.PodQOSBestEffort {
+		// best-effort pods share the least cpu time altogether
+		if err := m.setCPU(qosCgroupName(qos), minShares, -1); err != nil {
+			return err
+		}
+	}
+	if err := m.setCPU(name, shares, quota); err != nil {
+		return err
+	}
+	return m.setMemory(name, memory)
+}
+
+// setCPU writes cpu shares and quota of the cgroup, quota -1 means not limited
+func (m *podCgroupManager) setCPU(name cgroupName, shares int64, quota int64) error {
+	if m.unified {
+		max := "max"
+		if quota > 0 {
+			max = strconv.FormatInt(quota, 10)
+		}
+		dir := m.dir(name, "")
+		if err := writeFile(dir, "cpu.weight", strconv.FormatInt(cpuWeightOf(shares), 10)); err != nil {
+			return err
+		}
+		return writeFile(dir, "cpu.max", max+" "+strconv.Itoa(cpuPeriod))
+	}
+
+	dir := m.dir(name, "cpu")
+	if err := writeFile(dir, "cpu.shares", strconv.FormatInt(shares, 10)); err != nil {
+		return err
+	}
+	if err := writeFile(dir, "cpu.cfs_period_us", strconv.Itoa(cpuPeriod)); err != nil {
+		return err
+	}
+	return writeFile(dir, "cpu.cfs_quota_us", strconv.FormatInt(quota, 10))
+}
+
+// setMemory writes memory limit of the cgroup, -1 means not limited
+func (m *podCgroupManager) setMemory(name cgroupName, limit int64) error {
+	if m.unified {
+		max := "max"
+		if limit > 0 {
+			max = strconv.FormatInt(limit, 10)
+		}
+		return writeFile(m.dir(name, ""), "memory.max", max)
+	}
+	return writeFile(m.dir(name, "memory"), "memory.limit_in_bytes", strconv.FormatInt(limit, 10))
+}
+
+func (m *podCgroupManager) DestroyPodCgroup(podUID string) error {
+	subsystems := []string{""}
+	if !m.unified {
+		entries, err := os.ReadDir(m.mountPoint)
+		if err != nil {
+			return err
+		}
+		subsystems = subsystems[:0]
+		for _, entry := range entries {
+			subsystems = append(subsystems, entry.Name())
+		}
+	}
+
+	for _, qos := range []object.PodQOSClass{object.PodQOSGuaranteed, object.PodQOSBurstable, object.PodQOSBestEffort} {
+		name := podCgroupName(qos, podUID)
+		for _, subsystem := range subsystems {
+			// cgroups are removed by rmdir, files in them are not real files
+			err := syscall.Rmdir(m.dir(name, subsystem))
+			if err != nil && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ENOTDIR) {
+				return fmt.Errorf("fail to remove cgroup of pod %s: %v", podUID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *podCgroupManager) ListPodCgroups() ([]string, error) {
+	subsystem := ""
+	if !m.unified {
+		subsystem = "memory"
+	}
+
+	uids := make([]string, 0)
+	for _, qos := range []object.PodQOSClass{object.PodQOSGuaranteed, object.PodQOSBurstable, object.PodQOSBestEffort} {
+		entries, err := os.ReadDir(m.dir(qosCgroupName(qos), subsystem))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if m.systemd {
+				// cubepods-burstable-pod<UID>.slice
+				name = strings.TrimSuffix(name[strings.LastIndex(name, "-")+1:], ".slice")
+				name = strings.ReplaceAll(name, "_", "-")
+			}
+			if entry.IsDir() && strings.HasPrefix(name, podCgroupNamePrefix) {
+				uids = append(uids, strings.TrimPrefix(name, podCgroupNamePrefix))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// cpuSharesOf converts cpus to cpu shares of cgroup v1
+func cpuSharesOf(cpus float64) int64 {
+	shares := int64(cpus * sharesPerCPU)
+	if shares < minShares {
+		return minShares
+	}
+	if shares > maxShares {
+		return maxShares
+	}
+	return shares
+}
+
+// cpuWeightOf converts cpu shares of cgroup v1 in [2, 262144] to cpu weight of cgroup v2 in [1, 10000]
+func cpuWeightOf(shares int64) int64 {
+	return 1 + ((shares-minShares)*9999)/(maxShares-minShares)
+}
+
+func writeFile(dir, file, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("fail to set %s of cgroup %s: %v", file, dir, err)
+	}
+	return nil
+}
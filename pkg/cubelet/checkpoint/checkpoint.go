@@ -0,0 +1,97 @@
+package checkpoint
+
+import (
+	"Cubernetes/pkg/object"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultPodCheckpointPath is where cubelet saves pods bound to its node
+const DefaultPodCheckpointPath = "/var/lib/cubelet/checkpoints/pods"
+
+// ErrCorruptCheckpoint is returned if the checksum of a checkpoint doesn't match its pods
+var ErrCorruptCheckpoint = errors.New("checkpoint is corrupted")
+
+// PodCheckpoint saves pods desired on this node, so that cubelet knows them after restart
+// before apiserver is connected
+type PodCheckpoint interface {
+	SavePods(pods []object.Pod) error
+	// LoadPods returns no pod if nothing is saved
+	LoadPods() ([]object.Pod, error)
+}
+
+type podCheckpointData struct {
+	Pods     []object.Pod `json:"pods"`
+	Checksum uint64       `json:"checksum"`
+}
+
+type podCheckpoint struct {
+	path string
+}
+
+func NewPodCheckpoint(path string) PodCheckpoint {
+	return &podCheckpoint{path: path}
+}
+
+// SavePods writes a temporary file and renames it, so that the checkpoint is never half written
+func (c *podCheckpoint) SavePods(pods []object.Pod) error {
+	sorted := append([]object.Pod{}, pods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UID < sorted[j].UID
+	})
+	checksum, err := computeChecksum(sorted)
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(podCheckpointData{Pods: sorted, Checksum: checksum})
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err = ioutil.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func (c *podCheckpoint) LoadPods() ([]object.Pod, error) {
+	buf, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var data podCheckpointData
+	if err = json.Unmarshal(buf, &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptCheckpoint, err)
+	}
+	checksum, err := computeChecksum(data.Pods)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != data.Checksum {
+		return nil, ErrCorruptCheckpoint
+	}
+	return data.Pods, nil
+}
+
+func computeChecksum(pods []object.Pod) (uint64, error) {
+	buf, err := json.Marshal(pods)
+	if err != nil {
+		return 0, err
+	}
+	hash := fnv.New64a()
+	hash.Write(buf)
+	return hash.Sum64(), nil
+}
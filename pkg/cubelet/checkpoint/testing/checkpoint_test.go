@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/checkpoint"
+	"Cubernetes/pkg/object"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints", "pods")
+	podCheckpoint := checkpoint.NewPodCheckpoint(path)
+
+	pods, err := podCheckpoint.LoadPods()
+	assert.NoError(t, err)
+	assert.Empty(t, pods)
+
+	saved := []object.Pod{
+		{ObjectMeta: object.ObjectMeta{Name: "b", UID: "uid-b"}},
+		{ObjectMeta: object.ObjectMeta{Name: "a", UID: "uid-a"}},
+	}
+	assert.NoError(t, podCheckpoint.SavePods(saved))
+
+	pods, err = podCheckpoint.LoadPods()
+	assert.NoError(t, err)
+	assert.Len(t, pods, 2)
+	assert.Equal(t, "uid-a", pods[0].UID)
+	assert.Equal(t, "uid-b", pods[1].UID)
+
+	buf, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	corrupted := strings.Replace(string(buf), "uid-b", "uid-c", 1)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(corrupted), 0644))
+
+	_, err = podCheckpoint.LoadPods()
+	assert.ErrorIs(t, err, checkpoint.ErrCorruptCheckpoint)
+}
@@ -0,0 +1,168 @@
+package container
+
+import (
+	"Cubernetes/pkg/object"
+	"fmt"
+	"sort"
+)
+
+// EnvContext is what env of containers may refer to,
+// it is built again each time containers of a pod are (re-)created
+type EnvContext struct {
+	PodIP    string
+	NodeName string
+	// name -> object
+	ConfigMaps map[string]*object.ConfigMap
+	Secrets    map[string]*object.Secret
+}
+
+// MakeEnvironmentVariables returns env of container in the form of KEY=VALUE,
+// variables from EnvFrom go first, and are overridden by Env with the same name
+func MakeEnvironmentVariables(pod *object.Pod, container *object.Container, ctx *EnvContext) ([]string, error) {
+	names := make([]string, 0)
+	values := make(map[string]string)
+	set := func(name, value string) {
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = value
+	}
+
+	for _, from := range container.EnvFrom {
+		data, err := envFromData(&from, ctx)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			set(from.Prefix+key, data[key])
+		}
+	}
+
+	for _, env := range container.Env {
+		if env.ValueFrom == nil {
+			set(env.Name, env.Value)
+			continue
+		}
+		value, ok, err := envVarValue(pod, env.ValueFrom, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %v", env.Name, err)
+		}
+		if ok {
+			set(env.Name, value)
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		result = append(result, name+"="+values[name])
+	}
+	return result, nil
+}
+
+func envFromData(from *object.EnvFromSource, ctx *EnvContext) (map[string]string, error) {
+	if ref := from.ConfigMapRef; ref != nil {
+		cm, ok := ctx.ConfigMaps[ref.Name]
+		if !ok {
+			if ref.Optional {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("config map %s not found", ref.Name)
+		}
+		return cm.Data, nil
+	}
+
+	if ref := from.SecretRef; ref != nil {
+		secret, ok := ctx.Secrets[ref.Name]
+		if !ok {
+			if ref.Optional {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("secret %s not found", ref.Name)
+		}
+		data := make(map[string]string)
+		for key := range secret.Data {
+			if value, ok := secret.GetValue(key); ok {
+				data[key] = value
+			}
+		}
+		return data, nil
+	}
+
+	return nil, nil
+}
+
+// envVarValue returns false if an optional key is missing
+func envVarValue(pod *object.Pod, source *object.EnvVarSource, ctx *EnvContext) (string, bool, error) {
+	if ref := source.FieldRef; ref != nil {
+		value, err := podFieldValue(pod, ref.FieldPath, ctx)
+		return value, err == nil, err
+	}
+
+	if ref := source.ConfigMapKeyRef; ref != nil {
+		if cm, ok := ctx.ConfigMaps[ref.Name]; ok {
+			if value, ok := cm.Data[ref.Key]; ok {
+				return value, true, nil
+			}
+		}
+		if ref.Optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key %s of config map %s not found", ref.Key, ref.Name)
+	}
+
+	if ref := source.SecretKeyRef; ref != nil {
+		if secret, ok := ctx.Secrets[ref.Name]; ok {
+			if value, ok := secret.GetValue(ref.Key); ok {
+				return value, true, nil
+			}
+		}
+		if ref.Optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key %s of secret %s not found", ref.Key, ref.Name)
+	}
+
+	return "", false, fmt.Errorf("empty valueFrom")
+}
+
+func podFieldValue(pod *object.Pod, fieldPath string, ctx *EnvContext) (string, error) {
+	switch fieldPath {
+	case object.FieldPathPodName:
+		return pod.Name, nil
+	case object.FieldPathPodUID:
+		return pod.UID, nil
+	case object.FieldPathPodIP:
+		return ctx.PodIP, nil
+	case object.FieldPathNodeUID:
+		if pod.Status == nil {
+			return "", nil
+		}
+		return pod.Status.NodeUID, nil
+	case object.FieldPathNodeName:
+		return ctx.NodeName, nil
+	}
+	return "", fmt.Errorf("unsupported field path %s", fieldPath)
+}
+
+// NeedsEnvObjects tells whether env of the pod refers to any ConfigMap or Secret
+func NeedsEnvObjects(pod *object.Pod) (configMaps bool, secrets bool) {
+	containers := append([]object.Container{}, pod.Spec.InitContainers...)
+	for _, container := range append(containers, pod.Spec.Containers...) {
+		for _, from := range container.EnvFrom {
+			configMaps = configMaps || from.ConfigMapRef != nil
+			secrets = secrets || from.SecretRef != nil
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				configMaps = configMaps || env.ValueFrom.ConfigMapKeyRef != nil
+				secrets = secrets || env.ValueFrom.SecretKeyRef != nil
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,245 @@
+package fake
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeRuntime simulates pods in memory without containers, SyncPod starts a ready sandbox and
+// runs every container of the pod, init containers complete at once. Tests change states of
+// containers by SetContainerExited, and inject errors by InjectError
+type FakeRuntime struct {
+	sync.Mutex
+
+	Called []string
+	Errors map[string][]error
+
+	// Pods is keyed by pod UID
+	Pods map[string]*cubecontainer.PodStatus
+	// Specs are pods last synced, keyed by pod UID
+	Specs map[string]*object.Pod
+
+	nextID int
+}
+
+var _ cubecontainer.Runtime = &FakeRuntime{}
+
+func NewFakeRuntime() *FakeRuntime {
+	return &FakeRuntime{
+		Called: make([]string, 0),
+		Errors: make(map[string][]error),
+		Pods:   make(map[string]*cubecontainer.PodStatus),
+		Specs:  make(map[string]*object.Pod),
+	}
+}
+
+// InjectError makes the next call of method f fail with err, errors of f are returned in order
+func (r *FakeRuntime) InjectError(f string, err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.Errors[f] = append(r.Errors[f], err)
+}
+
+// GetCalls returns names of methods called in order
+func (r *FakeRuntime) GetCalls() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string{}, r.Called...)
+}
+
+// SetContainerExited makes the latest container with the name in the pod exit with exitCode
+func (r *FakeRuntime) SetContainerExited(podUID, containerName string, exitCode int) error {
+	r.Lock()
+	defer r.Unlock()
+	podStatus, ok := r.Pods[podUID]
+	if !ok {
+		return fmt.Errorf("pod %s not found", podUID)
+	}
+	status := podStatus.FindContainerStatusByName(containerName)
+	if status == nil {
+		return fmt.Errorf("container %s of pod %s not found", containerName, podUID)
+	}
+	status.State = cubecontainer.ContainerStateExited
+	status.ExitCode = exitCode
+	status.FinishedAt = time.Now()
+	return nil
+}
+
+// SetSandboxNotReady makes sandboxes of the pod not ready, as if they were stopped
+func (r *FakeRuntime) SetSandboxNotReady(podUID string) error {
+	r.Lock()
+	defer r.Unlock()
+	podStatus, ok := r.Pods[podUID]
+	if !ok {
+		return fmt.Errorf("pod %s not found", podUID)
+	}
+	for _, sandbox := range podStatus.SandboxStatuses {
+		sandbox.State = cubecontainer.SandboxStateNotReady
+	}
+	return nil
+}
+
+func (r *FakeRuntime) GetPodStatus(UID string) (*cubecontainer.PodStatus, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("GetPodStatus"); err != nil {
+		return nil, err
+	}
+	podStatus, ok := r.Pods[UID]
+	if !ok {
+		return &cubecontainer.PodStatus{}, nil
+	}
+	return copyPodStatus(podStatus), nil
+}
+
+func (r *FakeRuntime) KillPod(UID string) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("KillPod"); err != nil {
+		return err
+	}
+	delete(r.Pods, UID)
+	delete(r.Specs, UID)
+	return nil
+}
+
+// SyncPod re-creates the pod if its sandbox is not ready, and restarts exited containers by restart policy
+func (r *FakeRuntime) SyncPod(pod *object.Pod, _ *cubecontainer.PodStatus) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("SyncPod"); err != nil {
+		return err
+	}
+	r.Specs[pod.UID] = pod
+
+	podStatus, ok := r.Pods[pod.UID]
+	if !ok || len(podStatus.SandboxStatuses) == 0 ||
+		podStatus.SandboxStatuses[0].State != cubecontainer.SandboxStateReady {
+		r.nextID++
+		podStatus = &cubecontainer.PodStatus{
+			UID:        pod.UID,
+			Name:       pod.Name,
+			Namespace:  pod.Namespace,
+			PodNetWork: cubecontainer.PodNetworkStatus{IP: net.IPv4(10, 32, 0, byte(r.nextID))},
+			SandboxStatuses: []*cubecontainer.SandboxStatus{{
+				Id:     r.newID(),
+				Name:   "sandbox_" + pod.Name,
+				PodUID: pod.UID,
+				State:  cubecontainer.SandboxStateReady,
+				Ip:     net.IPv4(10, 32, 0, byte(r.nextID)).String(),
+			}},
+		}
+		r.Pods[pod.UID] = podStatus
+	}
+
+	for idx := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[idx]
+		policy := cubecontainer.InitContainerRestartPolicy(pod.Spec.RestartPolicy, container)
+		if r.startContainer(podStatus, container, policy) && !object.IsSidecarContainer(container) {
+			// init containers complete at once
+			status := podStatus.FindContainerStatusByName(container.Name)
+			status.State = cubecontainer.ContainerStateExited
+			status.FinishedAt = status.StartedAt
+		}
+	}
+	for idx := range pod.Spec.Containers {
+		r.startContainer(podStatus, &pod.Spec.Containers[idx], pod.Spec.RestartPolicy)
+	}
+	return nil
+}
+
+func (r *FakeRuntime) InspectPod(pod *object.Pod) (*object.PodStatus, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("InspectPod"); err != nil {
+		return nil, err
+	}
+	podStatus, ok := r.Pods[pod.UID]
+	if !ok || len(podStatus.SandboxStatuses) == 0 {
+		return nil, fmt.Errorf("pod %s not found", pod.UID)
+	}
+	return &object.PodStatus{
+		IP:             podStatus.PodNetWork.IP,
+		Phase:          cubecontainer.ComputePodPhase(podStatus.ContainerStatuses, podStatus.SandboxStatuses[0], &pod.Spec),
+		LastUpdateTime: time.Now(),
+	}, nil
+}
+
+func (r *FakeRuntime) ListPodsUID() ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("ListPodsUID"); err != nil {
+		return nil, err
+	}
+	uids := make([]string, 0, len(r.Pods))
+	for uid := range r.Pods {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+func (r *FakeRuntime) Close() {}
+
+// startContainer starts the container if it never ran, or exited and is to be restarted by policy,
+// it must be called with lock held
+func (r *FakeRuntime) startContainer(podStatus *cubecontainer.PodStatus, container *object.Container,
+	policy object.RestartPolicy) bool {
+	latest := podStatus.FindContainerStatusByName(container.Name)
+	restartCount := 0
+	if latest != nil {
+		if !cubecontainer.ShouldContainerBeRestarted(policy, latest) {
+			return false
+		}
+		restartCount = latest.RestartCount + 1
+	}
+
+	now := time.Now()
+	podStatus.ContainerStatuses = append(podStatus.ContainerStatuses, &cubecontainer.ContainerStatus{
+		ID:           cubecontainer.ContainerID{Type: "fake", ID: r.newID()},
+		Name:         container.Name,
+		State:        cubecontainer.ContainerStateRunning,
+		CreatedAt:    now,
+		StartedAt:    now,
+		Image:        container.Image,
+		RestartCount: restartCount,
+		Sidecar:      object.IsSidecarContainer(container),
+	})
+	return true
+}
+
+func (r *FakeRuntime) newID() string {
+	r.nextID++
+	return fmt.Sprintf("%064x", r.nextID)
+}
+
+// called records the call of f, and pops an error injected, it must be called with lock held
+func (r *FakeRuntime) called(f string) error {
+	r.Called = append(r.Called, f)
+	errs := r.Errors[f]
+	if len(errs) == 0 {
+		return nil
+	}
+	r.Errors[f] = errs[1:]
+	return errs[0]
+}
+
+func copyPodStatus(podStatus *cubecontainer.PodStatus) *cubecontainer.PodStatus {
+	result := *podStatus
+	result.ContainerStatuses = make([]*cubecontainer.ContainerStatus, 0, len(podStatus.ContainerStatuses))
+	for _, status := range podStatus.ContainerStatuses {
+		copied := *status
+		result.ContainerStatuses = append(result.ContainerStatuses, &copied)
+	}
+	result.SandboxStatuses = make([]*cubecontainer.SandboxStatus, 0, len(podStatus.SandboxStatuses))
+	for _, status := range podStatus.SandboxStatuses {
+		copied := *status
+		result.SandboxStatuses = append(result.SandboxStatuses, &copied)
+	}
+	return &result
+}
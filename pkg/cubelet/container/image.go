@@ -0,0 +1,22 @@
+package container
+
+// Image is an image stored by the container runtime
+type Image struct {
+	ID          string
+	RepoTags    []string
+	RepoDigests []string
+	Size        int64
+}
+
+// ImageService lists and removes images of the container runtime pods run on,
+// it is used by image GC, eviction and node status
+type ImageService interface {
+	// RuntimeVersion returns <runtime name>://<version>, e.g. docker://20.10.17
+	RuntimeVersion() (string, error)
+	ListImages() ([]Image, error)
+	// ListImagesInUse returns IDs of images used by containers of all states
+	ListImagesInUse() (map[string]bool, error)
+	RemoveImage(imageID string) error
+	// ImageFsPath returns a path on the filesystem where images and writable layers are stored
+	ImageFsPath() (string, error)
+}
@@ -53,6 +53,13 @@ type ContainerStatus struct {
 	ExitCode      int
 	Image         string
 	ImageID       string
+	// RestartCount is the number of containers with the same name created before this one
+	RestartCount int
+	// Reason and Message are set for exited container, e.g. OOMKilled
+	Reason  string
+	Message string
+	// Sidecar is an init container running along with app containers, it is stopped last
+	Sidecar bool
 }
 
 type ContainerResourceUsage struct {
@@ -66,6 +73,8 @@ type SandboxStatus struct {
 	PodUID string
 	State  SandboxState
 	Ip     string
+	// HostNetwork is true if the sandbox is in the network namespace of the node
+	HostNetwork bool
 }
 
 type PodStatus struct {
@@ -90,49 +99,133 @@ type PodNetworkStatus struct {
 	IP net.IP `json:"ip" description:"Primary IP address of the pod"`
 }
 
+// FindContainerStatusByName returns the latest container with the name
 func (s *PodStatus) FindContainerStatusByName(containerName string) *ContainerStatus {
-	for _, containerStatus := range s.ContainerStatuses {
-		if containerStatus.Name == containerName {
-			return containerStatus
+	return latestContainerStatus(s.ContainerStatuses, containerName)
+}
+
+// FindPreviousContainerStatus returns the container with the name created before the latest one
+func (s *PodStatus) FindPreviousContainerStatus(containerName string) *ContainerStatus {
+	latest := s.FindContainerStatusByName(containerName)
+	if latest == nil {
+		return nil
+	}
+	var previous *ContainerStatus
+	for _, status := range s.ContainerStatuses {
+		if status.Name == containerName && status != latest &&
+			(previous == nil || status.CreatedAt.After(previous.CreatedAt)) {
+			previous = status
 		}
 	}
-	return nil
+	return previous
 }
 
 func (s *PodStatus) UpdateSandboxStatuses(sandboxStatuses []*SandboxStatus) {
 	s.SandboxStatuses = sandboxStatuses
 }
 
+// ComputePodPhase only looks at the latest container of each name in spec
 func ComputePodPhase(statuses []*ContainerStatus, sandboxStatus *SandboxStatus, podSpec *object.PodSpec) object.PodPhase {
-	isRunning := true
-	runningContainer := 0
-	isSuccess := true
-	isFail := false
-
-	for _, container := range statuses {
-		if container.State == ContainerStateRunning {
-			runningContainer += 1
-		} else if container.State != ContainerStateCreated {
-			isRunning = false
-		}
-		if container.State != ContainerStateExited || container.ExitCode != 0 {
-			isSuccess = false
-			if container.State == ContainerStateExited {
-				isFail = true
-			}
+	if done, failed := InitContainersDone(statuses, podSpec); failed {
+		return object.PodFailed
+	} else if !done {
+		return object.PodPending
+	}
+
+	waiting, running, restarting, succeeded, failed := 0, 0, 0, 0, 0
+
+	for _, container := range podSpec.Containers {
+		status := latestContainerStatus(statuses, container.Name)
+		switch {
+		case status == nil || status.State == ContainerStateCreated || status.State == ContainerStateUnknown:
+			waiting++
+		case status.State == ContainerStateRunning:
+			running++
+		case ShouldContainerBeRestarted(podSpec.RestartPolicy, status):
+			restarting++
+		case status.ExitCode == 0:
+			succeeded++
+		default:
+			failed++
 		}
 	}
 
-	if isFail {
-		return object.PodFailed
-	} else if isRunning && sandboxStatus.State == SandboxStateReady &&
-		runningContainer == len(podSpec.Containers) {
-		return object.PodRunning
-	} else if sandboxStatus.State == SandboxStateReady {
+	if sandboxStatus.State == SandboxStateReady && waiting > 0 {
 		return object.PodPending
-	} else if isSuccess {
+	} else if running > 0 || restarting > 0 {
+		// containers exited are to be restarted
+		if sandboxStatus.State == SandboxStateReady {
+			return object.PodRunning
+		}
+		return object.PodUnknown
+	} else if failed > 0 {
+		return object.PodFailed
+	} else if succeeded == len(podSpec.Containers) {
 		return object.PodSucceeded
 	} else {
 		return object.PodUnknown
 	}
 }
+
+// ShouldContainerBeRestarted tells whether an exited container is to be restarted by the policy
+func ShouldContainerBeRestarted(policy object.RestartPolicy, status *ContainerStatus) bool {
+	if status.State != ContainerStateExited {
+		return false
+	}
+	switch policy {
+	case object.RestartPolicyNever:
+		return false
+	case object.RestartPolicyOnFailure:
+		return status.ExitCode != 0
+	default:
+		return true
+	}
+}
+
+// InitContainerRestartPolicy returns how an init container is restarted: sidecars always,
+// others on failure, unless the pod is never restarted
+func InitContainerRestartPolicy(podPolicy object.RestartPolicy, container *object.Container) object.RestartPolicy {
+	if object.IsSidecarContainer(container) {
+		return object.RestartPolicyAlways
+	}
+	if podPolicy == object.RestartPolicyNever {
+		return object.RestartPolicyNever
+	}
+	return object.RestartPolicyOnFailure
+}
+
+// InitContainersDone tells whether all init containers have completed and sidecars have started,
+// failed is true if an init container failed and won't be restarted
+func InitContainersDone(statuses []*ContainerStatus, podSpec *object.PodSpec) (done bool, failed bool) {
+	for idx := range podSpec.InitContainers {
+		container := &podSpec.InitContainers[idx]
+		status := latestContainerStatus(statuses, container.Name)
+		if status == nil {
+			return false, false
+		}
+		if object.IsSidecarContainer(container) {
+			if status.State != ContainerStateRunning && status.State != ContainerStateExited {
+				return false, false
+			}
+			continue
+		}
+		if status.State != ContainerStateExited {
+			return false, false
+		}
+		if status.ExitCode != 0 {
+			policy := InitContainerRestartPolicy(podSpec.RestartPolicy, container)
+			return false, !ShouldContainerBeRestarted(policy, status)
+		}
+	}
+	return true, false
+}
+
+func latestContainerStatus(statuses []*ContainerStatus, name string) *ContainerStatus {
+	var latest *ContainerStatus
+	for _, status := range statuses {
+		if status.Name == name && (latest == nil || status.CreatedAt.After(latest.CreatedAt)) {
+			latest = status
+		}
+	}
+	return latest
+}
@@ -0,0 +1,44 @@
+package container
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrContainerNotFound is returned for logs of a container not created yet
+	ErrContainerNotFound = errors.New("container is not created yet")
+	// ErrPreviousContainerNotFound is returned for logs of the previous instance of a container never restarted
+	ErrPreviousContainerNotFound = errors.New("previous terminated container not found")
+	// ErrSandboxNotRunning is returned for port-forward to a pod without a running sandbox
+	ErrSandboxNotRunning = errors.New("pod sandbox is not running")
+)
+
+// Streams are attached to a process in a container, Stdin is nil if it is not attached.
+// Stderr is not used if the process has a TTY
+type Streams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	TTY    bool
+	// Resize receives sizes of the client terminal, it is closed once the client is gone
+	Resize <-chan remotecommand.TerminalSize
+}
+
+// StreamingRuntime serves logs, exec, attach and port-forward of containers in pods
+type StreamingRuntime interface {
+	// GetContainerLogs writes logs of the container to stdout and stderr, the previous instance
+	// if opts.Previous. It returns when the logs end, or when ctx is done if they are followed
+	GetContainerLogs(ctx context.Context, podUID string, opts object.LogOptions, stdout, stderr io.Writer) error
+	// ExecInContainer runs cmd in the running container with streams attached,
+	// until it exits or ctx is done, and returns its exit code
+	ExecInContainer(ctx context.Context, containerID string, cmd []string, streams Streams) (int, error)
+	// AttachContainer attaches streams to the main process of the running container,
+	// until it exits or ctx is done, and returns its exit code. The TTY of the container is used
+	AttachContainer(ctx context.Context, containerID string, streams Streams) (int, error)
+	// PortForward connects to port on localhost in the network namespace of the pod
+	PortForward(podUID string, port int) (io.ReadWriteCloser, error)
+}
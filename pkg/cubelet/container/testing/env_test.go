@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMakeEnvironmentVariables(t *testing.T) {
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "test-pod", UID: "pod-uid"},
+		Status:     &object.PodStatus{NodeUID: "node-uid"},
+	}
+	ctx := &container.EnvContext{
+		PodIP:    "10.32.0.2",
+		NodeName: "node1",
+		ConfigMaps: map[string]*object.ConfigMap{
+			"config": {Data: map[string]string{"MODE": "debug", "LEVEL": "1"}},
+		},
+		Secrets: map[string]*object.Secret{
+			"secret": {Data: map[string]string{"password": base64.StdEncoding.EncodeToString([]byte("pw"))}},
+		},
+	}
+	c := &object.Container{
+		EnvFrom: []object.EnvFromSource{
+			{Prefix: "APP_", ConfigMapRef: &object.LocalObjectReference{Name: "config"}},
+		},
+		Env: []object.EnvVar{
+			{Name: "APP_MODE", Value: "release"},
+			{Name: "PASSWORD", ValueFrom: &object.EnvVarSource{
+				SecretKeyRef: &object.KeySelector{Name: "secret", Key: "password"}}},
+			{Name: "POD_IP", ValueFrom: &object.EnvVarSource{
+				FieldRef: &object.ObjectFieldSelector{FieldPath: object.FieldPathPodIP}}},
+			{Name: "NODE", ValueFrom: &object.EnvVarSource{
+				FieldRef: &object.ObjectFieldSelector{FieldPath: object.FieldPathNodeName}}},
+			{Name: "MISSING", ValueFrom: &object.EnvVarSource{
+				ConfigMapKeyRef: &object.KeySelector{Name: "config", Key: "none", Optional: true}}},
+		},
+	}
+
+	env, err := container.MakeEnvironmentVariables(pod, c, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"APP_LEVEL=1",
+		"APP_MODE=release",
+		"PASSWORD=pw",
+		"POD_IP=10.32.0.2",
+		"NODE=node1",
+	}, env)
+
+	// missing key which is not optional fails the container
+	c.Env = append(c.Env, object.EnvVar{Name: "REQUIRED", ValueFrom: &object.EnvVarSource{
+		SecretKeyRef: &object.KeySelector{Name: "no-such-secret", Key: "key"}}})
+	_, err = container.MakeEnvironmentVariables(pod, c, ctx)
+	assert.NotNil(t, err)
+}
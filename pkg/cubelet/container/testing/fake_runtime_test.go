@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/container/fake"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFakeRuntimePodLifecycle(t *testing.T) {
+	runtime := fake.NewFakeRuntime()
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "job", UID: "fake-pod"},
+		Spec: object.PodSpec{
+			RestartPolicy:  object.RestartPolicyOnFailure,
+			InitContainers: []object.Container{{Name: "init"}},
+			Containers:     []object.Container{{Name: "app"}},
+		},
+	}
+
+	assert.NoError(t, runtime.SyncPod(pod, &container.PodStatus{}))
+	status, err := runtime.InspectPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, object.PodRunning, status.Phase)
+
+	// failed container is restarted
+	assert.NoError(t, runtime.SetContainerExited(pod.UID, "app", 1))
+	assert.NoError(t, runtime.SyncPod(pod, nil))
+	podStatus, err := runtime.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, podStatus.FindContainerStatusByName("app").RestartCount)
+
+	assert.NoError(t, runtime.SetContainerExited(pod.UID, "app", 0))
+	assert.NoError(t, runtime.SyncPod(pod, nil))
+	status, err = runtime.InspectPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, object.PodSucceeded, status.Phase)
+
+	assert.NoError(t, runtime.KillPod(pod.UID))
+	uids, err := runtime.ListPodsUID()
+	assert.NoError(t, err)
+	assert.Empty(t, uids)
+}
@@ -0,0 +1,83 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestComputePodPhase(t *testing.T) {
+	sandbox := &container.SandboxStatus{State: container.SandboxStateReady}
+	spec := &object.PodSpec{Containers: []object.Container{{Name: "app"}, {Name: "sidecar"}}}
+	now := time.Now()
+
+	exited := &container.ContainerStatus{Name: "app", State: container.ContainerStateExited, ExitCode: 1, CreatedAt: now}
+	sidecar := &container.ContainerStatus{Name: "sidecar", State: container.ContainerStateRunning, CreatedAt: now}
+	statuses := []*container.ContainerStatus{exited, sidecar}
+
+	// crashed container is to be restarted
+	assert.Equal(t, object.PodRunning, container.ComputePodPhase(statuses, sandbox, spec))
+	spec.RestartPolicy = object.RestartPolicyOnFailure
+	assert.Equal(t, object.PodRunning, container.ComputePodPhase(statuses, sandbox, spec))
+
+	// only the latest container of each name counts
+	restarted := &container.ContainerStatus{Name: "app", State: container.ContainerStateRunning, CreatedAt: now.Add(time.Second)}
+	statuses = append(statuses, restarted)
+	assert.Equal(t, object.PodRunning, container.ComputePodPhase(statuses, sandbox, spec))
+
+	spec.RestartPolicy = object.RestartPolicyNever
+	sidecar.State = container.ContainerStateExited
+	restarted.State = container.ContainerStateExited
+	assert.Equal(t, object.PodSucceeded, container.ComputePodPhase(statuses, sandbox, spec))
+	sidecar.ExitCode = 2
+	assert.Equal(t, object.PodFailed, container.ComputePodPhase(statuses, sandbox, spec))
+
+	assert.False(t, container.ShouldContainerBeRestarted(object.RestartPolicyOnFailure, restarted))
+	assert.True(t, container.ShouldContainerBeRestarted(object.RestartPolicyOnFailure, sidecar))
+	assert.True(t, container.ShouldContainerBeRestarted("", restarted))
+}
+
+func TestInitContainers(t *testing.T) {
+	sandbox := &container.SandboxStatus{State: container.SandboxStateReady}
+	spec := &object.PodSpec{
+		RestartPolicy: object.RestartPolicyNever,
+		InitContainers: []object.Container{
+			{Name: "sidecar", RestartPolicy: object.ContainerRestartPolicyAlways},
+			{Name: "init"},
+		},
+		Containers: []object.Container{{Name: "app"}},
+	}
+	now := time.Now()
+
+	sidecar := &container.ContainerStatus{Name: "sidecar", State: container.ContainerStateRunning, CreatedAt: now}
+	init := &container.ContainerStatus{Name: "init", State: container.ContainerStateRunning, CreatedAt: now}
+	statuses := []*container.ContainerStatus{sidecar, init}
+
+	done, failed := container.InitContainersDone(statuses, spec)
+	assert.False(t, done)
+	assert.False(t, failed)
+	assert.Equal(t, object.PodPending, container.ComputePodPhase(statuses, sandbox, spec))
+
+	// init container failed and won't be restarted
+	init.State, init.ExitCode = container.ContainerStateExited, 1
+	_, failed = container.InitContainersDone(statuses, spec)
+	assert.True(t, failed)
+	assert.Equal(t, object.PodFailed, container.ComputePodPhase(statuses, sandbox, spec))
+	spec.RestartPolicy = object.RestartPolicyAlways
+	assert.Equal(t, object.PodPending, container.ComputePodPhase(statuses, sandbox, spec))
+
+	// sidecar doesn't count in phase once app containers complete
+	init.ExitCode = 0
+	app := &container.ContainerStatus{Name: "app", State: container.ContainerStateExited, CreatedAt: now}
+	statuses = append(statuses, app)
+	done, _ = container.InitContainersDone(statuses, spec)
+	assert.True(t, done)
+	assert.Equal(t, object.PodRunning, container.ComputePodPhase(statuses, sandbox, spec))
+	spec.RestartPolicy = object.RestartPolicyOnFailure
+	assert.Equal(t, object.PodSucceeded, container.ComputePodPhase(statuses, sandbox, spec))
+
+	assert.Equal(t, object.RestartPolicyAlways, container.InitContainerRestartPolicy(object.RestartPolicyNever, &spec.InitContainers[0]))
+	assert.Equal(t, object.RestartPolicyOnFailure, container.InitContainerRestartPolicy(object.RestartPolicyAlways, &spec.InitContainers[1]))
+}
@@ -0,0 +1,96 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// RemoteImageService talks to ImageService of a CRI runtime
+type RemoteImageService struct {
+	timeout     time.Duration
+	conn        *grpc.ClientConn
+	imageClient runtimeapi.ImageServiceClient
+}
+
+var _ internalapi.ImageManagerService = &RemoteImageService{}
+
+// NewRemoteImageService connects to endpoint, each request except pulls is limited by timeout
+func NewRemoteImageService(endpoint string, timeout time.Duration) (*RemoteImageService, error) {
+	conn, err := dial(endpoint, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteImageService{
+		timeout:     timeout,
+		conn:        conn,
+		imageClient: runtimeapi.NewImageServiceClient(conn),
+	}, nil
+}
+
+func (r *RemoteImageService) Close() error {
+	return r.conn.Close()
+}
+
+func (r *RemoteImageService) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), r.timeout)
+}
+
+func (r *RemoteImageService) ListImages(filter *runtimeapi.ImageFilter) ([]*runtimeapi.Image, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.imageClient.ListImages(ctx, &runtimeapi.ListImagesRequest{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Images, nil
+}
+
+// ImageStatus returns nil if the image is not present
+func (r *RemoteImageService) ImageStatus(image *runtimeapi.ImageSpec) (*runtimeapi.Image, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.imageClient.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{Image: image})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Image, nil
+}
+
+// PullImage is not limited by timeout, large images take long
+func (r *RemoteImageService) PullImage(image *runtimeapi.ImageSpec, auth *runtimeapi.AuthConfig,
+	podSandboxConfig *runtimeapi.PodSandboxConfig) (string, error) {
+	resp, err := r.imageClient.PullImage(context.Background(), &runtimeapi.PullImageRequest{
+		Image:         image,
+		Auth:          auth,
+		SandboxConfig: podSandboxConfig,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.ImageRef == "" {
+		return "", errors.New("imageRef of image " + image.Image + " is not set")
+	}
+	return resp.ImageRef, nil
+}
+
+func (r *RemoteImageService) RemoveImage(image *runtimeapi.ImageSpec) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.imageClient.RemoveImage(ctx, &runtimeapi.RemoveImageRequest{Image: image})
+	return err
+}
+
+func (r *RemoteImageService) ImageFsInfo() ([]*runtimeapi.FilesystemUsage, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.imageClient.ImageFsInfo(ctx, &runtimeapi.ImageFsInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.ImageFilesystems, nil
+}
@@ -0,0 +1,269 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// RemoteRuntimeService talks to RuntimeService of a CRI runtime, e.g. containerd or CRI-O
+type RemoteRuntimeService struct {
+	timeout       time.Duration
+	conn          *grpc.ClientConn
+	runtimeClient runtimeapi.RuntimeServiceClient
+}
+
+var _ internalapi.RuntimeService = &RemoteRuntimeService{}
+
+// NewRemoteRuntimeService connects to endpoint, each request is limited by timeout
+func NewRemoteRuntimeService(endpoint string, timeout time.Duration) (*RemoteRuntimeService, error) {
+	conn, err := dial(endpoint, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteRuntimeService{
+		timeout:       timeout,
+		conn:          conn,
+		runtimeClient: runtimeapi.NewRuntimeServiceClient(conn),
+	}, nil
+}
+
+func (r *RemoteRuntimeService) Close() error {
+	return r.conn.Close()
+}
+
+func (r *RemoteRuntimeService) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), r.timeout)
+}
+
+func (r *RemoteRuntimeService) Version(apiVersion string) (*runtimeapi.VersionResponse, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	return r.runtimeClient.Version(ctx, &runtimeapi.VersionRequest{Version: apiVersion})
+}
+
+// RunPodSandbox is not limited by timeout, since the runtime may pull the sandbox image
+func (r *RemoteRuntimeService) RunPodSandbox(config *runtimeapi.PodSandboxConfig, runtimeHandler string) (string, error) {
+	resp, err := r.runtimeClient.RunPodSandbox(context.Background(), &runtimeapi.RunPodSandboxRequest{
+		Config:         config,
+		RuntimeHandler: runtimeHandler,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.PodSandboxId == "" {
+		return "", errors.New("PodSandboxId is not set for sandbox")
+	}
+	return resp.PodSandboxId, nil
+}
+
+func (r *RemoteRuntimeService) StopPodSandbox(podSandboxID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.StopPodSandbox(ctx, &runtimeapi.StopPodSandboxRequest{PodSandboxId: podSandboxID})
+	return err
+}
+
+func (r *RemoteRuntimeService) RemovePodSandbox(podSandboxID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.RemovePodSandbox(ctx, &runtimeapi.RemovePodSandboxRequest{PodSandboxId: podSandboxID})
+	return err
+}
+
+func (r *RemoteRuntimeService) PodSandboxStatus(podSandboxID string) (*runtimeapi.PodSandboxStatus, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.PodSandboxStatus(ctx, &runtimeapi.PodSandboxStatusRequest{PodSandboxId: podSandboxID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+func (r *RemoteRuntimeService) ListPodSandbox(filter *runtimeapi.PodSandboxFilter) ([]*runtimeapi.PodSandbox, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+func (r *RemoteRuntimeService) PortForward(req *runtimeapi.PortForwardRequest) (*runtimeapi.PortForwardResponse, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	return r.runtimeClient.PortForward(ctx, req)
+}
+
+func (r *RemoteRuntimeService) CreateContainer(podSandboxID string, config *runtimeapi.ContainerConfig,
+	sandboxConfig *runtimeapi.PodSandboxConfig) (string, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.CreateContainer(ctx, &runtimeapi.CreateContainerRequest{
+		PodSandboxId:  podSandboxID,
+		Config:        config,
+		SandboxConfig: sandboxConfig,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.ContainerId == "" {
+		return "", errors.New("ContainerId is not set for container")
+	}
+	return resp.ContainerId, nil
+}
+
+func (r *RemoteRuntimeService) StartContainer(containerID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.StartContainer(ctx, &runtimeapi.StartContainerRequest{ContainerId: containerID})
+	return err
+}
+
+// StopContainer gives the container timeout seconds to exit, the request waits as long
+func (r *RemoteRuntimeService) StopContainer(containerID string, timeout int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout+time.Duration(timeout)*time.Second)
+	defer cancel()
+	_, err := r.runtimeClient.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+		ContainerId: containerID,
+		Timeout:     timeout,
+	})
+	return err
+}
+
+func (r *RemoteRuntimeService) RemoveContainer(containerID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.RemoveContainer(ctx, &runtimeapi.RemoveContainerRequest{ContainerId: containerID})
+	return err
+}
+
+func (r *RemoteRuntimeService) ListContainers(filter *runtimeapi.ContainerFilter) ([]*runtimeapi.Container, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Containers, nil
+}
+
+func (r *RemoteRuntimeService) ContainerStatus(containerID string) (*runtimeapi.ContainerStatus, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: containerID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+func (r *RemoteRuntimeService) UpdateContainerResources(containerID string, resources *runtimeapi.LinuxContainerResources) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.UpdateContainerResources(ctx, &runtimeapi.UpdateContainerResourcesRequest{
+		ContainerId: containerID,
+		Linux:       resources,
+	})
+	return err
+}
+
+// ExecSync returns *ExitError if the command exits with non-zero code
+func (r *RemoteRuntimeService) ExecSync(containerID string, cmd []string, timeout time.Duration) ([]byte, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout+timeout)
+	defer cancel()
+	resp, err := r.runtimeClient.ExecSync(ctx, &runtimeapi.ExecSyncRequest{
+		ContainerId: containerID,
+		Cmd:         cmd,
+		Timeout:     int64(timeout.Seconds()),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.ExitCode != 0 {
+		return resp.Stdout, resp.Stderr, &ExitError{Code: int(resp.ExitCode), Stderr: resp.Stderr}
+	}
+	return resp.Stdout, resp.Stderr, nil
+}
+
+func (r *RemoteRuntimeService) Exec(req *runtimeapi.ExecRequest) (*runtimeapi.ExecResponse, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	return r.runtimeClient.Exec(ctx, req)
+}
+
+func (r *RemoteRuntimeService) Attach(req *runtimeapi.AttachRequest) (*runtimeapi.AttachResponse, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	return r.runtimeClient.Attach(ctx, req)
+}
+
+func (r *RemoteRuntimeService) ReopenContainerLog(containerID string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.ReopenContainerLog(ctx, &runtimeapi.ReopenContainerLogRequest{ContainerId: containerID})
+	return err
+}
+
+func (r *RemoteRuntimeService) ContainerStats(containerID string) (*runtimeapi.ContainerStats, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ContainerStats(ctx, &runtimeapi.ContainerStatsRequest{ContainerId: containerID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (r *RemoteRuntimeService) ListContainerStats(filter *runtimeapi.ContainerStatsFilter) ([]*runtimeapi.ContainerStats, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ListContainerStats(ctx, &runtimeapi.ListContainerStatsRequest{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (r *RemoteRuntimeService) PodSandboxStats(podSandboxID string) (*runtimeapi.PodSandboxStats, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.PodSandboxStats(ctx, &runtimeapi.PodSandboxStatsRequest{PodSandboxId: podSandboxID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (r *RemoteRuntimeService) ListPodSandboxStats(filter *runtimeapi.PodSandboxStatsFilter) ([]*runtimeapi.PodSandboxStats, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.ListPodSandboxStats(ctx, &runtimeapi.ListPodSandboxStatsRequest{Filter: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (r *RemoteRuntimeService) UpdateRuntimeConfig(runtimeConfig *runtimeapi.RuntimeConfig) error {
+	ctx, cancel := r.context()
+	defer cancel()
+	_, err := r.runtimeClient.UpdateRuntimeConfig(ctx, &runtimeapi.UpdateRuntimeConfigRequest{RuntimeConfig: runtimeConfig})
+	return err
+}
+
+func (r *RemoteRuntimeService) Status() (*runtimeapi.RuntimeStatus, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+	resp, err := r.runtimeClient.Status(ctx, &runtimeapi.StatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
@@ -0,0 +1,323 @@
+package remote
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Exec, Attach and PortForward of a CRI runtime return URLs of its streaming server, which serves
+// websocket besides SPDY. Each binary message of the stream is [channel byte][data]
+const (
+	// ChannelProtocol is the websocket subprotocol of exec, attach and port-forward streams
+	ChannelProtocol = "v4.channel.k8s.io"
+	// websocketGUID is appended to the key by the server to compute Sec-WebSocket-Accept
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// maxMessageSize is far larger than messages of streaming servers, which are at most 32KB
+	maxMessageSize = 1024 * 1024
+)
+
+// channels of exec and attach streams, resize messages are json terminal sizes
+// and the error channel carries a json status once the process exits
+const (
+	StdinChannel byte = iota
+	StdoutChannel
+	StderrChannel
+	ErrorChannel
+	ResizeChannel
+)
+
+// channels of each port of port-forward streams, the first message of both is the port in uint16 little endian
+const (
+	portDataChannel byte = iota
+	portErrorChannel
+)
+
+const (
+	opBinary byte = 0x2
+	opClose  byte = 0x8
+	opPing   byte = 0x9
+	opPong   byte = 0xa
+)
+
+// StreamConn is a websocket connection to the streaming server of a CRI runtime,
+// stdin can't be closed alone in this version of the protocol
+type StreamConn struct {
+	conn      io.ReadWriteCloser
+	reader    *bufio.Reader
+	writeLock sync.Mutex
+	closeOnce sync.Once
+}
+
+// DialStream opens a websocket stream to streamURL, only plain http servers are supported,
+// which is how containerd and CRI-O serve on localhost by default
+func DialStream(streamURL string) (*StreamConn, error) {
+	parsed, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" {
+		return nil, fmt.Errorf("streaming server %s is not supported, only http is", parsed.Host)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := http.NewRequest(http.MethodGet, streamURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Protocol", ChannelProtocol)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("streaming server replies %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	stream, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, errors.New("upgraded response is not writable")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		_ = stream.Close()
+		return nil, errors.New("invalid Sec-WebSocket-Accept from streaming server")
+	}
+	if protocol := resp.Header.Get("Sec-WebSocket-Protocol"); protocol != ChannelProtocol {
+		_ = stream.Close()
+		return nil, fmt.Errorf("streaming server doesn't support protocol %s", ChannelProtocol)
+	}
+
+	return &StreamConn{conn: stream, reader: bufio.NewReader(stream)}, nil
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ReadMessage returns the channel and data of the next message, pings are answered on the way.
+// io.EOF is returned once the server closes the stream
+func (c *StreamConn) ReadMessage() (byte, []byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opClose:
+			return 0, nil, io.EOF
+		case opPing:
+			if err = c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		}
+
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+		// a message without channel carries nothing
+		if len(message) == 0 {
+			continue
+		}
+		return message[0], message[1:], nil
+	}
+}
+
+// WriteMessage is safe for concurrent use
+func (c *StreamConn) WriteMessage(channel byte, data []byte) error {
+	message := make([]byte, 1+len(data))
+	message[0] = channel
+	copy(message[1:], data)
+	return c.writeFrame(opBinary, message)
+}
+
+// Close tells the server the stream is closed, and closes the connection
+func (c *StreamConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		// status 1000, normal closure
+		_ = c.writeFrame(opClose, []byte{0x03, 0xe8})
+		err = c.conn.Close()
+	})
+	return err
+}
+
+func (c *StreamConn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+
+	size := uint64(header[1] & 0x7f)
+	switch size {
+	case 126:
+		buf := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(buf))
+	case 127:
+		buf := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(buf)
+	}
+	if size > maxMessageSize {
+		return false, 0, nil, fmt.Errorf("websocket frame of %d bytes is too large", size)
+	}
+
+	mask := make([]byte, 4)
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends payload in a single frame, which is masked as clients must do
+func (c *StreamConn) writeFrame(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch size := len(payload); {
+	case size < 126:
+		frame = append(frame, 0x80|byte(size))
+	case size <= 0xffff:
+		frame = append(frame, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(size))
+	default:
+		frame = append(frame, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(size))
+	}
+
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// DialPortForward opens a port-forward stream to port through streamURL of the pod sandbox,
+// reads and writes of the returned stream carry data of the port
+func DialPortForward(streamURL string, port int) (io.ReadWriteCloser, error) {
+	parsed, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+	query := parsed.Query()
+	query.Set("port", strconv.Itoa(port))
+	parsed.RawQuery = query.Encode()
+
+	conn, err := DialStream(parsed.String())
+	if err != nil {
+		return nil, err
+	}
+	return &portForwardStream{conn: conn}, nil
+}
+
+type portForwardStream struct {
+	conn *StreamConn
+	// data received but not read yet
+	pending []byte
+	// whether the port prefixing each channel is received
+	dataStarted, errorStarted bool
+}
+
+func (s *portForwardStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		channel, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return 0, err
+		}
+
+		switch channel {
+		case portDataChannel:
+			if !s.dataStarted {
+				s.dataStarted = true
+				data = skipPort(data)
+			}
+			s.pending = data
+		case portErrorChannel:
+			if !s.errorStarted {
+				s.errorStarted = true
+				data = skipPort(data)
+			}
+			if len(data) != 0 {
+				return 0, fmt.Errorf("port-forward: %s", strings.TrimSpace(string(data)))
+			}
+		}
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *portForwardStream) Write(p []byte) (int, error) {
+	if err := s.conn.WriteMessage(portDataChannel, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *portForwardStream) Close() error {
+	return s.conn.Close()
+}
+
+func skipPort(data []byte) []byte {
+	if len(data) < 2 {
+		return nil
+	}
+	return data[2:]
+}
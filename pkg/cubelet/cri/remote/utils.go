@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	unixProtocol = "unix"
+	// maxMsgSize is large enough for list responses of busy nodes
+	maxMsgSize = 1024 * 1024 * 16
+)
+
+// ExitError is returned by ExecSync if the command exits with non-zero code
+type ExitError struct {
+	Code   int
+	Stderr []byte
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exits with code %d: %s", e.Code, strings.TrimSpace(string(e.Stderr)))
+}
+
+// parseEndpoint accepts both unix:///path/to/sock and /path/to/sock
+func parseEndpoint(endpoint string) (string, error) {
+	if strings.HasPrefix(endpoint, "/") {
+		return endpoint, nil
+	}
+	protocol, addr, found := strings.Cut(endpoint, "://")
+	if !found || protocol != unixProtocol {
+		return "", fmt.Errorf("endpoint %s is not a unix socket", endpoint)
+	}
+	return addr, nil
+}
+
+// dial connects to the unix socket of endpoint, and fails if it is not ready within timeout
+func dial(endpoint string, timeout time.Duration) (*grpc.ClientConn, error) {
+	addr, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, unixProtocol, addr)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)),
+	)
+}
@@ -1,28 +1,96 @@
 package cubelet
 
 import (
+	cubeconfig "Cubernetes/config"
 	"Cubernetes/pkg/apiserver/crudobj"
 	"Cubernetes/pkg/apiserver/heartbeat"
 	actruntime "Cubernetes/pkg/cubelet/actorruntime"
+	"Cubernetes/pkg/cubelet/checkpoint"
 	"Cubernetes/pkg/cubelet/container"
 	"Cubernetes/pkg/cubelet/cuberuntime"
+	"Cubernetes/pkg/cubelet/cuberuntime/options"
+	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/cubelet/eviction"
 	"Cubernetes/pkg/cubelet/gpuserver"
+	"Cubernetes/pkg/cubelet/images"
 	"Cubernetes/pkg/cubelet/informer"
 	informertypes "Cubernetes/pkg/cubelet/informer/types"
+	"Cubernetes/pkg/cubelet/nodestatus"
+	"Cubernetes/pkg/cubelet/prober"
+	"Cubernetes/pkg/cubelet/server"
+	"Cubernetes/pkg/cubelet/staticpod"
 	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/localstorage"
 	"encoding/json"
 	"log"
 	"net"
+	"os"
 	"sync"
 	"time"
 )
 
+// unused images are removed by imageGCPolicy every imageGCPeriod
+const imageGCPeriod = time.Minute * 5
+
+// data of deleted local volumes are removed every localVolumeGCPeriod
+const localVolumeGCPeriod = time.Minute
+
+// signals of node pressure are observed every evictionMonitoringPeriod, one pod is evicted each time
+const evictionMonitoringPeriod = time.Second * 10
+
+var evictionConfig = eviction.Config{
+	Thresholds:               eviction.DefaultThresholds,
+	PressureTransitionPeriod: time.Minute * 5,
+	NodeFsPath:               "/",
+}
+
+// capacity, allocatable, system info and images of the node are reported every nodeStatusUpdatePeriod
+const nodeStatusUpdatePeriod = time.Second * 10
+
+// manifests of static pods are read every staticPodCheckPeriod
+const staticPodCheckPeriod = time.Second * 20
+
+// defaultMaxPods is used if maxPods is not given in node capacity
+const defaultMaxPods = 110
+
+var nodeStatusConfig = nodestatus.Config{
+	RootDir: "/",
+	SystemReserved: object.NodeResources{
+		Cpus:             0.1,
+		Memory:           256 * 1024 * 1024,
+		EphemeralStorage: 1024 * 1024 * 1024,
+	},
+	EvictionThresholds: eviction.DefaultThresholds,
+	CubeletPort:        cubeconfig.CubeletPort,
+}
+
+var imageGCPolicy = images.ImageGCPolicy{
+	HighThresholdPercent: 85,
+	LowThresholdPercent:  80,
+	MinAge:               time.Minute * 2,
+	PinnedImages:         []string{options.PauseImage},
+}
+
 type Cubelet struct {
 	NodeID  string
 	WeaveIP net.IP
 
-	podInformer informer.PodInformer
-	podRuntime  cuberuntime.CubeRuntime
+	podInformer  informer.PodInformer
+	podRuntime   cuberuntime.CubeRuntime
+	probeManager prober.Manager
+	// UID of pods being terminated, guarded by bigLock
+	terminating    map[string]bool
+	imageGCManager images.ImageGCManager
+	// evicts pods under node pressure
+	evictionManager eviction.Manager
+	// UID of pods evicted, before the status is seen by podInformer, guarded by bigLock
+	evicted map[string]bool
+	// detects resources, system info and images reported in node status
+	nodeStatusManager nodestatus.Manager
+	// serves container logs, exec, attach and port-forward to apiserver
+	server *server.Server
+	// static pods read from manifest files, they are run without apiserver
+	staticPodManager staticpod.Manager
 
 	jobInformer informer.JobInformer
 	jobRuntime  gpuserver.JobRuntime
@@ -33,23 +101,61 @@ type Cubelet struct {
 	bigLock sync.Mutex
 }
 
-func NewCubelet() *Cubelet {
+// NewCubelet creates cubelet on the container runtime, static pods are read from podManifestPath
+// if it is not empty
+func NewCubelet(runtimeOptions options.RuntimeOptions, podManifestPath string) *Cubelet {
 	log.Printf("[INFO]: creating cubelet podRuntime manager\n")
-	podRuntime, err := cuberuntime.NewCubeRuntimeManager()
+	podRuntime, err := cuberuntime.NewRuntimeManager(runtimeOptions)
 	if err != nil {
 		panic(err)
 	}
 	jobRuntime := gpuserver.NewJobRuntime()
 	actorRuntime, _ := actruntime.NewActorRuntime()
-	podInformer, _ := informer.NewPodInformer()
+	podInformer, _ := informer.NewPodInformer(checkpoint.NewPodCheckpoint(checkpoint.DefaultPodCheckpointPath))
 	jobInformer, _ := informer.NewJobInformer()
 	actorInformer, _ := informer.NewActorInformer()
+	// actors and gpu jobs always run on docker, their logs are read from it
+	dockerRuntime, err := dockershim.NewDockerRuntime()
+	if err != nil {
+		panic(err)
+	}
+	imageGCManager, err := images.NewImageGCManager(podRuntime, imageGCPolicy)
+	if err != nil {
+		panic(err)
+	}
+	config := evictionConfig
+	if config.ImageFsPath, err = podRuntime.ImageFsPath(); err != nil {
+		// signals of the image filesystem are not observed then
+		log.Printf("[Error]: fail to get image filesystem of container runtime: %v\n", err)
+	}
+	statusConfig := nodeStatusConfig
+	statusConfig.MaxPods = defaultMaxPods
+	meta, err := localstorage.LoadMeta()
+	if err == nil && meta.Node.Spec.Capacity.MaxPods > 0 {
+		statusConfig.MaxPods = meta.Node.Spec.Capacity.MaxPods
+	}
+	nodeName := meta.Node.Name
+	if nodeName == "" {
+		nodeName, _ = os.Hostname()
+	}
+	if meta.Node.Status != nil {
+		// hostNetwork pods have the IP of the node
+		podRuntime.SetNodeIP(net.ParseIP(meta.Node.Status.Addresses.InternalIP))
+	}
 
 	log.Println("[INFO]: cubelet init ends")
 
 	return &Cubelet{
-		podInformer: podInformer,
-		podRuntime:  podRuntime,
+		podInformer:       podInformer,
+		podRuntime:        podRuntime,
+		probeManager:      prober.NewManager(podRuntime),
+		terminating:       make(map[string]bool),
+		imageGCManager:    imageGCManager,
+		evictionManager:   eviction.NewManager(config, imageGCManager),
+		evicted:           make(map[string]bool),
+		nodeStatusManager: nodestatus.NewManager(podRuntime, statusConfig),
+		server:            server.NewServer(podRuntime, dockerRuntime),
+		staticPodManager:  staticpod.NewManager(podManifestPath, nodeName),
 
 		jobInformer: jobInformer,
 		jobRuntime:  jobRuntime,
@@ -75,7 +181,7 @@ func (cl *Cubelet) Run() {
 	// push pod status to apiserver every 10 sec
 	// simply using for loop to achieve block timer
 	wg := sync.WaitGroup{}
-	wg.Add(8)
+	wg.Add(16)
 
 	go func() {
 		defer wg.Done()
@@ -85,6 +191,15 @@ func (cl *Cubelet) Run() {
 		}
 	}()
 
+	// restart exited containers by restart policy
+	go func() {
+		defer wg.Done()
+		for {
+			time.Sleep(time.Second * 5)
+			cl.syncPodsRoutine()
+		}
+	}()
+
 	go func() {
 		defer wg.Done()
 		for {
@@ -93,6 +208,49 @@ func (cl *Cubelet) Run() {
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		for {
+			time.Sleep(imageGCPeriod)
+			if err := cl.imageGCManager.GarbageCollect(); err != nil {
+				log.Printf("[Error]: image garbage collection failed: %v\n", err)
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for {
+			time.Sleep(localVolumeGCPeriod)
+			cl.cleanupLocalVolumesRoutine()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for {
+			time.Sleep(evictionMonitoringPeriod)
+			cl.evictionRoutine()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for {
+			cl.updateNodeStatusRoutine()
+			time.Sleep(nodeStatusUpdatePeriod)
+		}
+	}()
+
+	// run static pods, and show them in apiserver by mirror pods
+	go func() {
+		defer wg.Done()
+		for {
+			cl.syncStaticPodsRoutine()
+			time.Sleep(staticPodCheckPeriod)
+		}
+	}()
+
 	// deal with pod event
 	go func() {
 		defer wg.Done()
@@ -104,6 +262,11 @@ func (cl *Cubelet) Run() {
 		cl.podInformer.ListAndWatchPodsWithRetry()
 	}()
 
+	go func() {
+		defer wg.Done()
+		cl.restartFailedContainers()
+	}()
+
 	go func() {
 		defer wg.Done()
 		cl.jobInformer.ListAndWatchJobsWithRetry()
@@ -124,6 +287,11 @@ func (cl *Cubelet) Run() {
 		cl.syncActorLoop()
 	}()
 
+	go func() {
+		defer wg.Done()
+		cl.server.Run()
+	}()
+
 	wg.Wait()
 	log.Fatalln("[Fatal]: sUnreachable here")
 }
@@ -135,17 +303,42 @@ func (cl *Cubelet) syncPodLoop() {
 		log.Printf("Main loop working, types is %v, pod id is %v", podEvent.Type, podEvent.Pod.UID)
 		pod := podEvent.Pod
 		eType := podEvent.Type
+		if object.IsMirrorPod(&pod) {
+			// mirror pods only show static pods, they are handled by syncStaticPodsRoutine
+			continue
+		}
 		cl.bigLock.Lock()
 
 		switch eType {
 		case informertypes.Create:
 			log.Printf("[INFO]: podEvent coming: create pod %s\n", pod.UID)
-			err := cl.podRuntime.SyncPod(&pod, &container.PodStatus{})
+			if cl.isPodEvicted(&pod) {
+				break
+			}
+			if ok, message := cl.evictionManager.Admit(&pod); !ok {
+				cl.evictPod(pod, message)
+				break
+			}
+			// containers of the pod are adopted if they are left by cubelet restarted
+			podStatus, err := cl.podRuntime.GetPodStatus(pod.UID)
+			if err != nil {
+				log.Printf("fail to get pod %s status: %v\n", pod.Name, err)
+				podStatus = &container.PodStatus{}
+			}
+			err = cl.podRuntime.SyncPod(&pod, podStatus)
 			if err != nil {
 				log.Printf("fail to create pod %s: %v\n", pod.Name, err)
 			}
+			cl.probeManager.AddPod(&pod)
 		case informertypes.Update:
 			log.Printf("[INFO]: podEvent coming: update pod %s\n", pod.UID)
+			if object.IsPodTerminating(&pod) {
+				cl.terminatePod(pod)
+				break
+			}
+			if cl.isPodEvicted(&pod) {
+				break
+			}
 			podStatus, err := cl.podRuntime.GetPodStatus(pod.UID)
 			if err != nil {
 				log.Printf("fail to get pod %s status: %v\n", pod.Name, err)
@@ -155,6 +348,8 @@ func (cl *Cubelet) syncPodLoop() {
 				log.Printf("fail to update pod %s: %v\n", pod.Name, err)
 			}
 		case informertypes.Remove:
+			delete(cl.evicted, pod.UID)
+			cl.probeManager.RemovePod(pod.UID)
 			err := cl.podRuntime.KillPod(pod.UID)
 			if err != nil {
 				log.Printf("fail to kill pod %s: %v\n", pod.Name, err)
@@ -165,6 +360,130 @@ func (cl *Cubelet) syncPodLoop() {
 	}
 }
 
+// terminatePod stops containers of the pod gracefully in background, and removes the pod
+// from apiserver then. It must be called with bigLock held
+func (cl *Cubelet) terminatePod(pod object.Pod) {
+	if cl.terminating[pod.UID] {
+		return
+	}
+	cl.terminating[pod.UID] = true
+	cl.probeManager.RemovePod(pod.UID)
+
+	gracePeriod := time.Until(*pod.DeletionTimestamp)
+	if gracePeriod < 0 {
+		gracePeriod = 0
+	}
+	go func() {
+		// retried by syncPodsRoutine if the pod is not terminated or not removed
+		defer func() {
+			cl.bigLock.Lock()
+			delete(cl.terminating, pod.UID)
+			cl.bigLock.Unlock()
+		}()
+
+		if err := cl.podRuntime.TerminatePod(&pod, gracePeriod); err != nil {
+			// the pod is kept in apiserver, or its containers may be left running without it
+			log.Printf("[Error]: fail to terminate pod %s: %v\n", pod.Name, err)
+			return
+		}
+		log.Printf("[INFO]: pod %s terminated, removing it\n", pod.Name)
+		if err := crudobj.DeletePodWithGracePeriod(pod.UID, 0); err != nil {
+			log.Printf("[Error]: fail to remove pod %s: %v\n", pod.Name, err)
+		}
+	}()
+}
+
+// updateNodeStatusRoutine detects the node and reports it with heartbeats
+func (cl *Cubelet) updateNodeStatusRoutine() {
+	cl.nodeStatusManager.Update()
+	heartbeat.UpdateNodeStatus(cl.nodeStatusManager.SetNodeStatus)
+}
+
+// evictionRoutine reports pressure conditions of the node with heartbeats,
+// and evicts a pod if the node is low on memory or disk
+func (cl *Cubelet) evictionRoutine() {
+	cl.bigLock.Lock()
+	pods := make([]object.Pod, 0)
+	for _, pod := range cl.podInformer.ListPods() {
+		// static pods are never evicted
+		if !object.IsMirrorPod(&pod) && !object.IsPodTerminating(&pod) && !cl.terminating[pod.UID] && !cl.isPodEvicted(&pod) {
+			pods = append(pods, pod)
+		}
+	}
+	cl.bigLock.Unlock()
+
+	victim, message := cl.evictionManager.Synchronize(pods)
+	heartbeat.UpdateNodeStatus(func(status *object.NodeStatus) {
+		cl.evictionManager.UpdateNodeCondition(&status.Condition)
+	})
+	if victim == nil {
+		return
+	}
+
+	cl.bigLock.Lock()
+	defer cl.bigLock.Unlock()
+	if pod, ok := cl.podInformer.GetPod(victim.UID); ok && !object.IsPodTerminating(&pod) && !cl.terminating[pod.UID] {
+		cl.evictPod(pod, message)
+	}
+}
+
+// evictPod kills containers of the pod, and reports it Failed with reason Evicted,
+// so that it is never restarted on this node. It must be called with bigLock held
+func (cl *Cubelet) evictPod(pod object.Pod, message string) {
+	if cl.isPodEvicted(&pod) {
+		return
+	}
+	log.Printf("[WARNING]: evicting pod %s: %s\n", pod.Name, message)
+	cl.evicted[pod.UID] = true
+	cl.probeManager.RemovePod(pod.UID)
+	if err := cl.podRuntime.KillPod(pod.UID); err != nil {
+		log.Printf("[Error]: fail to kill evicted pod %s: %v\n", pod.Name, err)
+	}
+
+	if pod.Status == nil {
+		pod.Status = &object.PodStatus{}
+	}
+	pod.Status.Phase = object.PodFailed
+	pod.Status.Reason = object.PodReasonEvicted
+	pod.Status.Message = message
+	pod.Status.QOSClass = object.GetPodQOS(&pod)
+	pod.Status.LastUpdateTime = time.Now()
+	if _, err := crudobj.UpdatePod(pod); err != nil {
+		log.Printf("[Error]: fail to report evicted pod %s: %v\n", pod.Name, err)
+	}
+}
+
+// isPodEvicted must be called with bigLock held
+func (cl *Cubelet) isPodEvicted(pod *object.Pod) bool {
+	return cl.evicted[pod.UID] || object.IsPodEvicted(pod)
+}
+
+// restartFailedContainers restarts containers whose liveness or startup probe failed
+func (cl *Cubelet) restartFailedContainers() {
+	for failure := range cl.probeManager.ContainerFailures() {
+		log.Printf("[INFO]: %s probe of container %s in pod %s failed, restarting: %s\n",
+			failure.ProbeType, failure.ContainerName, failure.PodUID, failure.Message)
+		pod, ok := cl.getPod(failure.PodUID)
+		if !ok || object.IsPodTerminating(&pod) {
+			continue
+		}
+
+		cl.bigLock.Lock()
+		if cl.isPodEvicted(&pod) {
+			cl.bigLock.Unlock()
+			continue
+		}
+		if err := cl.podRuntime.KillContainer(failure.ContainerID); err != nil {
+			log.Printf("[Error]: fail to kill container %s: %v\n", failure.ContainerName, err)
+		} else if podStatus, err := cl.podRuntime.GetPodStatus(pod.UID); err != nil {
+			log.Printf("[Error]: fail to get pod %s status: %v\n", pod.Name, err)
+		} else if err = cl.podRuntime.SyncPod(&pod, podStatus); err != nil {
+			log.Printf("[Error]: fail to restart container %s: %v\n", failure.ContainerName, err)
+		}
+		cl.bigLock.Unlock()
+	}
+}
+
 func (cl *Cubelet) syncJobLoop() {
 	informEvent := cl.jobInformer.WatchJobEvent()
 
@@ -176,7 +495,7 @@ func (cl *Cubelet) syncJobLoop() {
 			log.Printf("[INFO]: Event: create job %s\n", jobEvent.Job.UID)
 			err := cl.jobRuntime.AddGPUJob(&jobEvent.Job)
 			if err != nil {
-				log.Printf("[Error]: fail to create job %s: %v\n", jobEvent, err)
+				log.Printf("[Error]: fail to create job %s: %v\n", jobEvent.Job.UID, err)
 			}
 		default:
 			log.Printf("[WARN]: Job only support adding now\n")
@@ -213,6 +532,92 @@ func (cl *Cubelet) syncActorLoop() {
 	}
 }
 
+// syncPodsRoutine syncs all pods on this node, containers exited are restarted
+// by restart policy when their back-off expires. Nothing is done if pod is synced.
+func (cl *Cubelet) syncPodsRoutine() {
+	cl.bigLock.Lock()
+	defer cl.bigLock.Unlock()
+
+	pods := cl.podInformer.ListPods()
+	staticPods := cl.staticPodManager.ListPods()
+	activePods := make(map[string]bool, len(pods)+len(staticPods))
+	for _, pod := range append(pods, staticPods...) {
+		activePods[pod.UID] = true
+	}
+	// volumes and cgroups of pods removed while cubelet is down are left behind
+	defer cl.podRuntime.CleanupOrphanedPods(activePods)
+	cl.killOrphanedPods(activePods)
+
+	for _, pod := range staticPods {
+		if podStatus, err := cl.podRuntime.GetPodStatus(pod.UID); err != nil {
+			log.Printf("[Error]: fail to get static pod %s status: %v\n", pod.Name, err)
+		} else if err = cl.podRuntime.SyncPod(&pod, podStatus); err != nil {
+			log.Printf("[Error]: fail to sync static pod %s: %v\n", pod.Name, err)
+		}
+	}
+
+	for _, pod := range pods {
+		if object.IsMirrorPod(&pod) {
+			continue
+		}
+		if object.IsPodTerminating(&pod) {
+			// containers are not restarted, pods missed by informer events are terminated here
+			cl.terminatePod(pod)
+			continue
+		}
+		if cl.isPodEvicted(&pod) {
+			continue
+		}
+		podStatus, err := cl.podRuntime.GetPodStatus(pod.UID)
+		if err != nil {
+			log.Printf("[Error]: fail to get pod %s status: %v\n", pod.Name, err)
+			continue
+		}
+		if err = cl.podRuntime.SyncPod(&pod, podStatus); err != nil {
+			log.Printf("[Error]: fail to sync pod %s: %v\n", pod.Name, err)
+		}
+	}
+}
+
+// killOrphanedPods kills pods running on this node but not in activePods, which are removed
+// while cubelet is down. Nothing is done before pods are listed from apiserver, since pods
+// restored from checkpoint may miss those bound recently. It must be called with bigLock held
+func (cl *Cubelet) killOrphanedPods(activePods map[string]bool) {
+	if !cl.podInformer.HasSynced() {
+		return
+	}
+	uids, err := cl.podRuntime.ListPodsUID()
+	if err != nil {
+		log.Printf("[Error]: fail to list pods in runtime: %v\n", err)
+		return
+	}
+	for _, uid := range uids {
+		if activePods[uid] {
+			continue
+		}
+		log.Printf("[INFO]: killing orphaned pod %s\n", uid)
+		cl.probeManager.RemovePod(uid)
+		if err = cl.podRuntime.KillPod(uid); err != nil {
+			log.Printf("[Error]: fail to kill orphaned pod %s: %v\n", uid, err)
+		}
+	}
+}
+
+// cleanupLocalVolumesRoutine removes data of local volumes provisioned on this node,
+// once their persistent volumes are deleted by the volume controller
+func (cl *Cubelet) cleanupLocalVolumesRoutine() {
+	pvs, err := crudobj.GetPersistentVolumes()
+	if err != nil {
+		log.Printf("[Error]: fail to get persistent volumes: %v\n", err)
+		return
+	}
+	volumeNames := make(map[string]bool, len(pvs))
+	for _, pv := range pvs {
+		volumeNames[pv.Name] = true
+	}
+	cl.podRuntime.CleanupProvisionedLocalVolumes(volumeNames)
+}
+
 func (cl *Cubelet) updatePodsRoutine() {
 	cl.bigLock.Lock()
 	defer cl.bigLock.Unlock()
@@ -230,6 +635,11 @@ func (cl *Cubelet) updatePodsRoutine() {
 	wg.Add(len(pods))
 
 	for _, pod := range pods {
+		if object.IsMirrorPod(&pod) {
+			// status of static pods is reported by updateMirrorPodsStatus
+			wg.Done()
+			continue
+		}
 		ip := pod.Status.IP
 		nodeUID := pod.Status.NodeUID
 		log.Printf("[INFO]: Ready to update pod, ip is %v, nodeID is %v",
@@ -240,6 +650,11 @@ func (cl *Cubelet) updatePodsRoutine() {
 			wg.Done()
 			continue
 		}
+		if cl.isPodEvicted(&pod) {
+			// status of evicted pods is reported by evictPod once
+			wg.Done()
+			continue
+		}
 
 		go func(p object.Pod, ip net.IP, uid string) {
 			defer wg.Done()
@@ -247,10 +662,18 @@ func (cl *Cubelet) updatePodsRoutine() {
 			if err != nil {
 				log.Printf("[Error]: fail to get pod status %s: %v\n", p.Name, err)
 				podStatus = &object.PodStatus{Phase: object.PodUnknown}
+				if p.Status != nil {
+					podStatus.Conditions = p.Status.Conditions
+				}
 			}
 
 			podStatus.IP = ip
 			podStatus.NodeUID = nodeUID
+			podStatus.QOSClass = object.GetPodQOS(&p)
+			if !object.IsPodTerminating(&p) {
+				cl.probeManager.AddPod(&p)
+			}
+			cl.probeManager.UpdatePodStatus(p.UID, podStatus)
 			log.Printf("[INFO]: updating pod status, ip is %v, status is %v, cpu usage is %v",
 				podStatus.IP.String(), podStatus.Phase, podStatus.ActualResourceUsage.ActualCPUUsage)
 
@@ -260,7 +683,10 @@ func (cl *Cubelet) updatePodsRoutine() {
 				status, _ := json.Marshal(*podStatus)
 				log.Printf("[Error]: updating pod status, %v", string(status))
 				log.Printf("[Error]: fail to push pod status %s: %v\n", p.UID, err)
-				cl.podInformer.ForceRemove(p.UID)
+				if err.Error() == "no objects found" {
+					// containers of the pod removed from apiserver are killed by killOrphanedPods
+					cl.podInformer.ForceRemove(p.UID)
+				}
 			} else {
 				log.Printf("[INFO]: push pod status %s: %s\n", rp.Name, podStatus.Phase)
 			}
@@ -268,6 +694,99 @@ func (cl *Cubelet) updatePodsRoutine() {
 	}
 
 	wg.Wait()
+	cl.updateMirrorPodsStatus()
+}
+
+// syncStaticPodsRoutine runs static pods added to the manifest directory and kills those removed,
+// then creates and deletes mirror pods, so that apiserver shows static pods on this node
+func (cl *Cubelet) syncStaticPodsRoutine() {
+	added, removed := cl.staticPodManager.Update()
+
+	cl.bigLock.Lock()
+	defer cl.bigLock.Unlock()
+
+	for _, pod := range removed {
+		cl.probeManager.RemovePod(pod.UID)
+		if err := cl.podRuntime.KillPod(pod.UID); err != nil {
+			log.Printf("[Error]: fail to kill static pod %s: %v\n", pod.Name, err)
+		}
+	}
+	for _, pod := range added {
+		// containers are kept if cubelet restarts
+		podStatus, err := cl.podRuntime.GetPodStatus(pod.UID)
+		if err != nil {
+			log.Printf("[Error]: fail to get static pod %s status: %v\n", pod.Name, err)
+			podStatus = &container.PodStatus{}
+		}
+		if err = cl.podRuntime.SyncPod(&pod, podStatus); err != nil {
+			log.Printf("[Error]: fail to create static pod %s: %v\n", pod.Name, err)
+		}
+		cl.probeManager.AddPod(&pod)
+	}
+
+	if cl.NodeID == "" || !heartbeat.CheckConn() {
+		// static pods run without apiserver, mirror pods are created once it is connected
+		return
+	}
+	toCreate, toDelete := staticpod.ComputeMirrorPodChanges(cl.staticPodManager.ListPods(), cl.listMirrorPods())
+	for _, mirror := range toDelete {
+		log.Printf("[INFO]: deleting mirror pod %s\n", mirror.Name)
+		if err := crudobj.DeletePodWithGracePeriod(mirror.UID, 0); err != nil {
+			log.Printf("[Error]: fail to delete mirror pod %s: %v\n", mirror.Name, err)
+		}
+	}
+	for _, pod := range toCreate {
+		log.Printf("[INFO]: creating mirror pod of static pod %s\n", pod.Name)
+		if _, err := crudobj.CreatePod(staticpod.MakeMirrorPod(&pod, cl.NodeID)); err != nil {
+			log.Printf("[Error]: fail to create mirror pod %s: %v\n", pod.Name, err)
+		}
+	}
+}
+
+// updateMirrorPodsStatus reports status of static pods on their mirror pods, it must be called with bigLock held
+func (cl *Cubelet) updateMirrorPodsStatus() {
+	for _, mirror := range cl.listMirrorPods() {
+		pod, ok := cl.staticPodManager.GetPod(mirror.Annotations[object.ConfigMirrorAnnotationKey])
+		if !ok || object.IsPodTerminating(&mirror) {
+			continue
+		}
+
+		podStatus, err := cl.podRuntime.InspectPod(&pod)
+		if err != nil {
+			log.Printf("[Error]: fail to get static pod status %s: %v\n", pod.Name, err)
+			podStatus = &object.PodStatus{Phase: object.PodUnknown}
+		}
+		if runtimeStatus, err := cl.podRuntime.GetPodStatus(pod.UID); err == nil {
+			podStatus.IP = runtimeStatus.PodNetWork.IP
+		}
+		podStatus.NodeUID = cl.NodeID
+		podStatus.QOSClass = object.GetPodQOS(&pod)
+		cl.probeManager.UpdatePodStatus(pod.UID, podStatus)
+
+		mirror.Status = podStatus
+		if _, err = crudobj.UpdatePod(mirror); err != nil {
+			log.Printf("[Error]: fail to push mirror pod status %s: %v\n", mirror.Name, err)
+		}
+	}
+}
+
+// listMirrorPods returns mirror pods of this node in apiserver
+func (cl *Cubelet) listMirrorPods() []object.Pod {
+	mirrors := make([]object.Pod, 0)
+	for _, pod := range cl.podInformer.ListPods() {
+		if object.IsMirrorPod(&pod) {
+			mirrors = append(mirrors, pod)
+		}
+	}
+	return mirrors
+}
+
+// getPod returns the pod in apiserver, or the static pod with the UID
+func (cl *Cubelet) getPod(uid string) (object.Pod, bool) {
+	if pod, ok := cl.podInformer.GetPod(uid); ok {
+		return pod, true
+	}
+	return cl.staticPodManager.GetPod(uid)
 }
 
 func (cl *Cubelet) updateActorsRoutine() {
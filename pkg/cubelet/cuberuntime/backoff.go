@@ -0,0 +1,77 @@
+package cuberuntime
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type backOffEntry struct {
+	delay      time.Duration
+	lastUpdate time.Time
+}
+
+// backOff keeps restart delay of containers, keyed by pod UID and container name
+type backOff struct {
+	lock    sync.Mutex
+	initial time.Duration
+	max     time.Duration
+	entries map[string]*backOffEntry
+}
+
+func newBackOff(initial, max time.Duration) *backOff {
+	return &backOff{
+		initial: initial,
+		max:     max,
+		entries: make(map[string]*backOffEntry),
+	}
+}
+
+func backOffKey(podUID, containerName string) string {
+	return podUID + "/" + containerName
+}
+
+// remaining returns how long the container exited at finishedAt still waits before restart
+func (b *backOff) remaining(key string, finishedAt time.Time) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	entry, ok := b.entries[key]
+	if !ok {
+		return 0
+	}
+	if left := entry.delay - time.Since(finishedAt); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// next is called when the container is restarted, delay is reset
+// if the container has not been restarted for a long time
+func (b *backOff) next(key string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	entry, ok := b.entries[key]
+	if !ok || time.Since(entry.lastUpdate) > 2*b.max {
+		b.entries[key] = &backOffEntry{delay: b.initial, lastUpdate: time.Now()}
+		return
+	}
+	entry.delay *= 2
+	if entry.delay > b.max {
+		entry.delay = b.max
+	}
+	entry.lastUpdate = time.Now()
+}
+
+// forget removes entries of a pod
+func (b *backOff) forget(podUID string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for key := range b.entries {
+		if strings.HasPrefix(key, podUID+"/") {
+			delete(b.entries, key)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package cuberuntime
 import (
 	cubecontainer "Cubernetes/pkg/cubelet/container"
 	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/object"
 	"strings"
 
 	dockertypes "github.com/docker/docker/api/types"
@@ -14,6 +15,8 @@ func toSandboxStatus(dc *dockertypes.Container) *cubecontainer.SandboxStatus {
 		Name:   dockershim.ParseSandboxName(dc.Names[0]),
 		PodUID: dc.Labels[PodUIDLabel],
 		State:  toSandboxState(dc.Status),
+
+		HostNetwork: dc.Labels[SandboxHostNetworkLabel] == "true",
 	}
 
 	return status
@@ -63,9 +66,34 @@ func toContainerStateAndExitCode(jsonState *dockertypes.ContainerState) (cubecon
 		return cubecontainer.ContainerStateRunning, 0
 	} else if jsonState.Status == "exited" {
 		return cubecontainer.ContainerStateExited, jsonState.ExitCode
+	} else if jsonState.Status == "created" && jsonState.ExitCode != 0 {
+		// fail to start, e.g. command not found
+		return cubecontainer.ContainerStateExited, jsonState.ExitCode
 	} else if jsonState.Status == "created" {
 		return cubecontainer.ContainerStateCreated, 0
 	} else {
 		return cubecontainer.ContainerStateUnknown, 0
 	}
 }
+
+// Reason, Message of exited container
+func toContainerReason(jsonState *dockertypes.ContainerState) (string, string) {
+	switch {
+	case jsonState.OOMKilled:
+		return object.ContainerReasonOOMKilled, jsonState.Error
+	case jsonState.ExitCode == 0:
+		return object.ContainerReasonCompleted, jsonState.Error
+	default:
+		return object.ContainerReasonError, jsonState.Error
+	}
+}
+
+func toContainerStateTerminated(status *cubecontainer.ContainerStatus) *object.ContainerStateTerminated {
+	return &object.ContainerStateTerminated{
+		ExitCode:   int32(status.ExitCode),
+		Reason:     status.Reason,
+		Message:    status.Message,
+		StartedAt:  status.StartedAt,
+		FinishedAt: status.FinishedAt,
+	}
+}
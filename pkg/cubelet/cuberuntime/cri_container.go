@@ -0,0 +1,368 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cri/remote"
+	"Cubernetes/pkg/cubelet/cuberuntime/options"
+	"Cubernetes/pkg/object"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// cpuPeriod is the cfs period of containers, quota is cpus of it
+const cpuPeriod = 100000
+
+func (m *criRuntimeManager) startContainer(container *object.Container, pod *object.Pod, sandboxID string,
+	sandboxConfig *runtimeapi.PodSandboxConfig, envCtx *cubecontainer.EnvContext, restartCount int) (string, error) {
+	// image is ensured by ensureImage before
+	env, err := cubecontainer.MakeEnvironmentVariables(pod, container, envCtx)
+	if err != nil {
+		log.Printf("fail to make env of container %s: %v\n", container.Name, err)
+		return "", err
+	}
+
+	config := m.generateContainerConfig(container, pod, env, restartCount)
+	log.Println("creating normal container...")
+	containerID, err := m.runtimeService.CreateContainer(sandboxID, config, sandboxConfig)
+	if err != nil {
+		log.Printf("fail to create container %s: %v\n", container.Name, err)
+		return "", err
+	}
+
+	if err = m.runtimeService.StartContainer(containerID); err != nil {
+		log.Printf("fail to start container %s: %v\n", container.Name, err)
+		return "", err
+	}
+
+	// the container is killed if postStart hook fails, and restarted by restart policy
+	if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
+		err = m.runLifecycleHandler(containerID, container.Lifecycle.PostStart, envCtx.PodIP, options.PostStartHookTimeout)
+		if err != nil {
+			log.Printf("[Error]: postStart hook of container %s failed: %v\n", container.Name, err)
+			if err := m.runtimeService.StopContainer(containerID, toStopTimeout(minStopGracePeriod)); err != nil {
+				log.Printf("[Error]: fail to kill container %s: %v\n", container.Name, err)
+			}
+			return "", err
+		}
+	}
+
+	return containerID, nil
+}
+
+func (m *criRuntimeManager) generateContainerConfig(container *object.Container, pod *object.Pod,
+	env []string, restartCount int) *runtimeapi.ContainerConfig {
+	envs := make([]*runtimeapi.KeyValue, 0, len(env))
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		envs = append(envs, &runtimeapi.KeyValue{Key: key, Value: value})
+	}
+
+	mounts := make([]*runtimeapi.Mount, 0)
+	for _, mount := range container.VolumeMounts {
+		volume := findVolume(pod, mount.Name)
+		if volume == nil {
+			continue
+		}
+		hostPath, readOnly := m.volumeManager.GetVolumeHostPath(pod, volume)
+		mounts = append(mounts, &runtimeapi.Mount{
+			ContainerPath: mount.MountPath,
+			HostPath:      hostPath,
+			Readonly:      mount.ReadOnly || readOnly || volume.IsReadOnly(),
+		})
+	}
+
+	config := &runtimeapi.ContainerConfig{
+		Metadata: &runtimeapi.ContainerMetadata{
+			Name:    container.Name,
+			Attempt: uint32(restartCount),
+		},
+		Image:      &runtimeapi.ImageSpec{Image: container.Image},
+		Command:    container.Command,
+		Args:       container.Args,
+		WorkingDir: container.WorkingDir,
+		Envs:       envs,
+		Mounts:     mounts,
+		Labels:     newContainerLabels(container, pod, restartCount),
+		// relative to LogDirectory of the sandbox
+		LogPath: filepath.Join(container.Name, strconv.Itoa(restartCount)+".log"),
+		Stdin:   container.Stdin,
+		Tty:     container.TTY,
+		Linux: &runtimeapi.LinuxContainerConfig{
+			Resources:       &runtimeapi.LinuxContainerResources{},
+			SecurityContext: generateLinuxSecurityContext(pod, container),
+		},
+	}
+
+	// set resource if specified, containers are limited by the pod cgroup as well
+	if container.Resources != nil {
+		resources := config.Linux.Resources
+		if container.Resources.Cpus > 0 {
+			resources.CpuPeriod = cpuPeriod
+			resources.CpuQuota = int64(container.Resources.Cpus * cpuPeriod)
+		}
+		resources.MemoryLimitInBytes = container.Resources.Memory
+		// shares of requested cpus, at least 2 as cgroup takes
+		if cpus, _ := object.GetContainerRequests(container); cpus > 0 {
+			resources.CpuShares = int64(cpus * 1024)
+			if resources.CpuShares < 2 {
+				resources.CpuShares = 2
+			}
+		}
+	}
+
+	return config
+}
+
+// generateLinuxSecurityContext sets user, groups and privileges of the container by the effective security context
+func generateLinuxSecurityContext(pod *object.Pod, container *object.Container) *runtimeapi.LinuxContainerSecurityContext {
+	context := &runtimeapi.LinuxContainerSecurityContext{
+		NamespaceOptions: &runtimeapi.NamespaceOption{Pid: runtimeapi.NamespaceMode_POD},
+	}
+	if pod.Spec.SecurityContext != nil {
+		context.SupplementalGroups = pod.Spec.SecurityContext.SupplementalGroups
+	}
+
+	sc := object.EffectiveSecurityContext(&pod.Spec, container)
+	if sc == nil {
+		return context
+	}
+
+	if sc.RunAsUser != nil {
+		context.RunAsUser = &runtimeapi.Int64Value{Value: *sc.RunAsUser}
+	}
+	if sc.RunAsGroup != nil {
+		context.RunAsGroup = &runtimeapi.Int64Value{Value: *sc.RunAsGroup}
+	}
+	if sc.ReadOnlyRootFilesystem != nil {
+		context.ReadonlyRootfs = *sc.ReadOnlyRootFilesystem
+	}
+	if sc.Privileged != nil {
+		context.Privileged = *sc.Privileged
+	}
+	if sc.AllowPrivilegeEscalation != nil {
+		context.NoNewPrivs = !*sc.AllowPrivilegeEscalation
+	}
+	if sc.Capabilities != nil {
+		context.Capabilities = &runtimeapi.Capability{
+			AddCapabilities:  sc.Capabilities.Add,
+			DropCapabilities: sc.Capabilities.Drop,
+		}
+	}
+	return context
+}
+
+// getContainerStatusesByPodUID gets resource usage only if withUsage
+func (m *criRuntimeManager) getContainerStatusesByPodUID(UID string, withUsage bool) ([]*cubecontainer.ContainerStatus, error) {
+	containers, err := m.runtimeService.ListContainers(&runtimeapi.ContainerFilter{
+		LabelSelector: map[string]string{
+			ContainerTypeLabel: ContainerTypeContainer,
+			PodUIDLabel:        UID,
+		},
+	})
+	if err != nil {
+		log.Printf("fail to list pod containers %s: %v\n", UID, err)
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, nil
+	}
+
+	statuses := make([]*cubecontainer.ContainerStatus, 0, len(containers))
+	for _, container := range containers {
+		if status, err := m.getContainerStatus(container.Id, withUsage); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+func (m *criRuntimeManager) getContainerStatus(containerID string, withUsage bool) (*cubecontainer.ContainerStatus, error) {
+	criStatus, err := m.runtimeService.ContainerStatus(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	restartCount, _ := strconv.Atoi(criStatus.Labels[ContainerRestartCountLabel])
+	status := &cubecontainer.ContainerStatus{
+		ID: cubecontainer.ContainerID{
+			Type: m.runtimeName,
+			ID:   criStatus.Id,
+		},
+		Name:         criStatus.Metadata.Name,
+		State:        toCRIContainerState(criStatus.State),
+		CreatedAt:    fromUnixNano(criStatus.CreatedAt),
+		StartedAt:    fromUnixNano(criStatus.StartedAt),
+		FinishedAt:   fromUnixNano(criStatus.FinishedAt),
+		ExitCode:     int(criStatus.ExitCode),
+		ImageID:      criStatus.ImageRef,
+		RestartCount: restartCount,
+		Sidecar:      criStatus.Labels[ContainerSidecarLabel] == "true",
+	}
+	if criStatus.Image != nil {
+		status.Image = criStatus.Image.Image
+	}
+
+	if status.State == cubecontainer.ContainerStateExited {
+		status.Reason, status.Message = toCRIContainerReason(criStatus)
+		return status, nil
+	}
+	if status.State != cubecontainer.ContainerStateRunning || !withUsage {
+		return status, nil
+	}
+
+	// only running containers use resources
+	stats, err := m.runtimeService.ContainerStats(containerID)
+	if err != nil {
+		return nil, err
+	}
+	if cpu := stats.GetCpu(); cpu != nil && cpu.UsageCoreNanoSeconds != nil {
+		status.ResourceUsage.CPUUsage = m.cpuStatsCache.CalculateCoreUsagePercent(containerID,
+			cpu.UsageCoreNanoSeconds.Value, cpu.Timestamp)
+	}
+	if memory := stats.GetMemory(); memory != nil && memory.WorkingSetBytes != nil {
+		status.ResourceUsage.MemoryUsage = int64(memory.WorkingSetBytes.Value)
+	}
+	return status, nil
+}
+
+// GetRunningContainerID returns "" if the container of the pod is not running
+func (m *criRuntimeManager) GetRunningContainerID(podUID, containerName string) (string, error) {
+	containers, err := m.runtimeService.ListContainers(&runtimeapi.ContainerFilter{
+		State: &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState_CONTAINER_RUNNING},
+		LabelSelector: map[string]string{
+			ContainerTypeLabel: ContainerTypeContainer,
+			PodUIDLabel:        podUID,
+			ContainerNameLabel: containerName,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(containers) == 0 {
+		return "", nil
+	}
+	return containers[0].Id, nil
+}
+
+func (m *criRuntimeManager) RunInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error) {
+	stdout, stderr, err := m.runtimeService.ExecSync(containerID, cmd, timeout)
+	output := append(stdout, stderr...)
+	var exitErr *remote.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code, output, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return 0, output, nil
+}
+
+// KillContainer stops the container, it is started again on next SyncPod
+func (m *criRuntimeManager) KillContainer(containerID string) error {
+	return m.runtimeService.StopContainer(containerID, toStopTimeout(defaultStopGracePeriod))
+}
+
+// killPodContainers stops sidecars after other containers
+func (m *criRuntimeManager) killPodContainers(pod *cubecontainer.PodStatus, remove bool) {
+	containers, sidecars := splitSidecars(pod.ContainerStatuses)
+	m.killContainers(containers, remove)
+	m.killContainers(sidecars, remove)
+}
+
+func (m *criRuntimeManager) killContainers(containers []*cubecontainer.ContainerStatus, remove bool) {
+	wg := sync.WaitGroup{}
+
+	wg.Add(len(containers))
+	for _, container := range containers {
+		go func(container *cubecontainer.ContainerStatus) {
+			defer wg.Done()
+
+			if err := m.runtimeService.StopContainer(container.ID.ID, toStopTimeout(defaultStopGracePeriod)); err != nil {
+				log.Printf("error %v occurs when stoping container %s\n", err, container.Name)
+			}
+
+			if remove {
+				if err := m.runtimeService.RemoveContainer(container.ID.ID); err != nil {
+					log.Printf("error %v occurs when removing container %s\n", err, container.Name)
+				}
+			}
+		}(container)
+	}
+	wg.Wait()
+}
+
+// pruneContainers removes containers not running, except the latest one of each name,
+// which is reported as last termination state after restart
+func (m *criRuntimeManager) pruneContainers(podStatus *cubecontainer.PodStatus) {
+	for _, status := range containersToPrune(podStatus) {
+		if err := m.runtimeService.RemoveContainer(status.ID.ID); err != nil {
+			log.Printf("fail to remove old container %s: %v\n", status.ID.ID, err)
+		}
+	}
+}
+
+func toCRIContainerState(state runtimeapi.ContainerState) cubecontainer.ContainerState {
+	switch state {
+	case runtimeapi.ContainerState_CONTAINER_CREATED:
+		return cubecontainer.ContainerStateCreated
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		return cubecontainer.ContainerStateRunning
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		return cubecontainer.ContainerStateExited
+	}
+	return cubecontainer.ContainerStateUnknown
+}
+
+// Reason, Message of exited container
+func toCRIContainerReason(status *runtimeapi.ContainerStatus) (string, string) {
+	switch {
+	case status.Reason == object.ContainerReasonOOMKilled:
+		return object.ContainerReasonOOMKilled, status.Message
+	case status.ExitCode == 0:
+		return object.ContainerReasonCompleted, status.Message
+	default:
+		return object.ContainerReasonError, status.Message
+	}
+}
+
+// fromUnixNano returns zero time for 0, which CRI reports for containers not started or finished
+func fromUnixNano(nsec int64) time.Time {
+	if nsec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nsec)
+}
+
+// toStopTimeout rounds up the grace period to seconds
+func toStopTimeout(gracePeriod time.Duration) int64 {
+	return int64((gracePeriod + time.Second - 1) / time.Second)
+}
+
+// runLifecycleHandler runs the hook in the container, HTTP hooks are sent to podIP if host is not set
+func (m *criRuntimeManager) runLifecycleHandler(containerID string, handler *object.LifecycleHandler,
+	podIP string, timeout time.Duration) error {
+	switch {
+	case handler.Exec != nil:
+		code, output, err := m.RunInContainer(containerID, handler.Exec.Command, timeout)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("exit code %d: %s", code, strings.TrimSpace(string(output)))
+		}
+		return nil
+	case handler.HTTPGet != nil:
+		return runHTTPHandler(handler.HTTPGet, podIP, timeout)
+	}
+	return fmt.Errorf("no action in lifecycle handler")
+}
@@ -0,0 +1,96 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"fmt"
+
+	dockertypes "github.com/docker/docker/api/types"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// criImageService pulls images with ImageService of a CRI runtime, which reports no progress
+type criImageService struct {
+	imageService internalapi.ImageManagerService
+}
+
+func (s *criImageService) imagePresent(image string) bool {
+	status, err := s.imageService.ImageStatus(&runtimeapi.ImageSpec{Image: image})
+	return err == nil && status != nil
+}
+
+func (s *criImageService) pullImage(image string, auth *dockertypes.AuthConfig, progress func(string)) error {
+	_, err := s.imageService.PullImage(&runtimeapi.ImageSpec{Image: image}, toCRIAuthConfig(auth), nil)
+	return err
+}
+
+func toCRIAuthConfig(auth *dockertypes.AuthConfig) *runtimeapi.AuthConfig {
+	if auth == nil {
+		return nil
+	}
+	return &runtimeapi.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		Auth:          auth.Auth,
+		ServerAddress: auth.ServerAddress,
+		IdentityToken: auth.IdentityToken,
+		RegistryToken: auth.RegistryToken,
+	}
+}
+
+func (m *criRuntimeManager) RuntimeVersion() (string, error) {
+	version, err := m.runtimeService.Version("")
+	if err != nil {
+		return "", err
+	}
+	return version.RuntimeName + "://" + version.RuntimeVersion, nil
+}
+
+func (m *criRuntimeManager) ListImages() ([]cubecontainer.Image, error) {
+	criImages, err := m.imageService.ListImages(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]cubecontainer.Image, 0, len(criImages))
+	for _, image := range criImages {
+		images = append(images, cubecontainer.Image{
+			ID:          image.Id,
+			RepoTags:    image.RepoTags,
+			RepoDigests: image.RepoDigests,
+			Size:        int64(image.Size_),
+		})
+	}
+	return images, nil
+}
+
+// ListImagesInUse relies on ImageRef of containers being the image ID, as containerd and CRI-O report
+func (m *criRuntimeManager) ListImagesInUse() (map[string]bool, error) {
+	containers, err := m.runtimeService.ListContainers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for _, container := range containers {
+		inUse[container.ImageRef] = true
+	}
+	return inUse, nil
+}
+
+func (m *criRuntimeManager) RemoveImage(imageID string) error {
+	return m.imageService.RemoveImage(&runtimeapi.ImageSpec{Image: imageID})
+}
+
+func (m *criRuntimeManager) ImageFsPath() (string, error) {
+	usages, err := m.imageService.ImageFsInfo()
+	if err != nil {
+		return "", err
+	}
+	for _, usage := range usages {
+		if usage.FsId != nil && usage.FsId.Mountpoint != "" {
+			return usage.FsId.Mountpoint, nil
+		}
+	}
+	return "", fmt.Errorf("no image filesystem reported by %s", m.runtimeName)
+}
@@ -0,0 +1,183 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// followed logs are read again every logPollPeriod until the container exits
+const logPollPeriod = time.Millisecond * 500
+
+const (
+	criLogStreamStdout = "stdout"
+	criLogStreamStderr = "stderr"
+	// criLogTagPartial marks a line split by the runtime, the rest follows in the next lines
+	criLogTagPartial = "P"
+)
+
+// criLogLine is a line of log files of CRI runtimes: <RFC3339Nano time> <stream> <P|F> <content>
+type criLogLine struct {
+	timestamp time.Time
+	stream    string
+	// content ends with '\n' unless the line is partial
+	content []byte
+}
+
+func (m *criRuntimeManager) GetContainerLogs(ctx context.Context, podUID string, opts object.LogOptions,
+	stdout, stderr io.Writer) error {
+	containers, err := m.runtimeService.ListContainers(&runtimeapi.ContainerFilter{
+		LabelSelector: map[string]string{
+			ContainerTypeLabel: ContainerTypeContainer,
+			PodUIDLabel:        podUID,
+			ContainerNameLabel: opts.Container,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the latest instance first
+	sort.Slice(containers, func(i, j int) bool {
+		return restartCountOf(containers[i].Labels) > restartCountOf(containers[j].Labels)
+	})
+	index, err := logInstanceIndex(len(containers), opts.Previous)
+	if err != nil {
+		return err
+	}
+
+	containerID := containers[index].Id
+	status, err := m.runtimeService.ContainerStatus(containerID)
+	if err != nil {
+		return cubecontainer.ErrContainerNotFound
+	}
+	if status.LogPath == "" {
+		return fmt.Errorf("container %s has no log file", opts.Container)
+	}
+
+	running := func() bool {
+		status, err := m.runtimeService.ContainerStatus(containerID)
+		return err == nil && status.State == runtimeapi.ContainerState_CONTAINER_RUNNING
+	}
+	return readCRILogs(ctx, status.LogPath, opts, running, stdout, stderr)
+}
+
+// readCRILogs writes lines of the log file by opts, followed logs are read until
+// the container is no longer running or ctx is done
+func readCRILogs(ctx context.Context, path string, opts object.LogOptions, running func() bool,
+	stdout, stderr io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	var since time.Time
+	if opts.Since > 0 {
+		since = time.Now().Add(-opts.Since)
+	}
+	write := func(line criLogLine) error {
+		writer := stdout
+		if line.stream == criLogStreamStderr {
+			writer = stderr
+		}
+		if opts.Timestamps {
+			if _, err := writer.Write([]byte(line.timestamp.Format(time.RFC3339Nano) + " ")); err != nil {
+				return err
+			}
+		}
+		_, err := writer.Write(line.content)
+		return err
+	}
+
+	// the last TailLines lines are kept until the end of the file is reached for the first time
+	tailing := opts.TailLines >= 0
+	tail := make([]criLogLine, 0)
+
+	reader := bufio.NewReader(file)
+	// a line may be written in part, it is kept in pending until the rest comes
+	var pending []byte
+	exited := false
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if err == nil {
+			line, parseErr := parseCRILogLine(pending)
+			pending = nil
+			if parseErr != nil || line.timestamp.Before(since) {
+				continue
+			}
+			if tailing {
+				if tail = append(tail, line); len(tail) > opts.TailLines {
+					tail = tail[1:]
+				}
+				continue
+			}
+			if err = write(line); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		if tailing {
+			tailing = false
+			for _, line := range tail {
+				if err = write(line); err != nil {
+					return err
+				}
+			}
+			tail = nil
+		}
+		if !opts.Follow || exited {
+			return nil
+		}
+		// lines written before the container exits are read once more
+		if !running() {
+			exited = true
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logPollPeriod):
+		}
+	}
+}
+
+func parseCRILogLine(raw []byte) (criLogLine, error) {
+	raw = bytes.TrimSuffix(raw, []byte{'\n'})
+	fields := bytes.SplitN(raw, []byte{' '}, 4)
+	if len(fields) < 3 {
+		return criLogLine{}, fmt.Errorf("invalid log line %q", raw)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, string(fields[0]))
+	if err != nil {
+		return criLogLine{}, err
+	}
+	stream := string(fields[1])
+	if stream != criLogStreamStdout && stream != criLogStreamStderr {
+		return criLogLine{}, fmt.Errorf("invalid stream %s", stream)
+	}
+
+	var content []byte
+	if len(fields) == 4 {
+		content = fields[3]
+	}
+	if string(fields[2]) != criLogTagPartial {
+		content = append(content, '\n')
+	}
+	return criLogLine{timestamp: timestamp, stream: stream, content: content}, nil
+}
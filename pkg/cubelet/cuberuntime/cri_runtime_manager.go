@@ -0,0 +1,337 @@
+package cuberuntime
+
+import (
+	"Cubernetes/pkg/cubelet/cache"
+	"Cubernetes/pkg/cubelet/cgroup"
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cri/remote"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// remoteRequestTimeout limits requests to CRI runtimes, except pulls and stops
+	remoteRequestTimeout = time.Minute * 2
+	// defaultStopGracePeriod is given to containers killed, as docker does
+	defaultStopGracePeriod = time.Second * 30
+)
+
+// criRuntimeManager runs pods by a CRI runtime, e.g. containerd or CRI-O,
+// which sets up network of sandboxes by CNI
+type criRuntimeManager struct {
+	*podSyncHelper
+
+	runtimeName    string
+	cpuStatsCache  cache.CpuStatsCache
+	runtimeService internalapi.RuntimeService
+	imageService   internalapi.ImageManagerService
+}
+
+// NewCRIRuntimeManager connects to RuntimeService and ImageService of a CRI runtime,
+// cgroupDriver must be the one the runtime uses
+func NewCRIRuntimeManager(runtimeEndpoint, imageEndpoint, cgroupDriver string) (CubeRuntime, error) {
+	runtimeService, err := remote.NewRemoteRuntimeService(runtimeEndpoint, remoteRequestTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("fail to connect to runtime service %s: %v", runtimeEndpoint, err)
+	}
+	imageService, err := remote.NewRemoteImageService(imageEndpoint, remoteRequestTimeout)
+	if err != nil {
+		runtimeService.Close()
+		return nil, fmt.Errorf("fail to connect to image service %s: %v", imageEndpoint, err)
+	}
+	return newCRIRuntimeManager(runtimeService, imageService, cgroupDriver)
+}
+
+func newCRIRuntimeManager(runtimeService internalapi.RuntimeService, imageService internalapi.ImageManagerService,
+	cgroupDriver string) (*criRuntimeManager, error) {
+	version, err := runtimeService.Version("")
+	if err != nil {
+		return nil, fmt.Errorf("fail to get runtime version: %v", err)
+	}
+	log.Printf("[INFO]: container runtime %s %s, CRI %s\n",
+		version.RuntimeName, version.RuntimeVersion, version.RuntimeApiVersion)
+	if cgroupDriver == "" {
+		cgroupDriver = cgroup.CgroupfsDriver
+	}
+
+	return &criRuntimeManager{
+		podSyncHelper:  newPodSyncHelper(&criImageService{imageService}, cgroupDriver),
+		runtimeName:    version.RuntimeName,
+		cpuStatsCache:  cache.NewCpuStatsCache(),
+		runtimeService: runtimeService,
+		imageService:   imageService,
+	}, nil
+}
+
+func (m *criRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.PodStatus) error {
+	// volumes are set up on every sync, so that running containers see updates of configMaps and secrets
+	if err := m.volumeManager.SetUpPodVolumes(pod); err != nil {
+		log.Printf("fail to set up volumes of pod %s: %v\n", pod.Name, err)
+		return err
+	}
+
+	changes := m.computePodActions(pod, podStatus)
+	if !changes.KillPod && !changes.CreateSandbox && len(changes.InitContainersToStart) == 0 &&
+		len(changes.ContainersToStart) == 0 && len(changes.ContainersToKill) == 0 {
+		return nil
+	}
+
+	if changes.KillPod {
+		if err := m.killPodByStatus(podStatus, true); err != nil {
+			log.Printf("fail to kill pod %s: %v\n", pod.Name, err)
+			return err
+		}
+	} else {
+		for _, id := range changes.ContainersToKill {
+			if err := m.runtimeService.StopContainer(id, toStopTimeout(defaultStopGracePeriod)); err != nil {
+				log.Printf("fail to kill container uid %s: %v\n", id, err)
+				return err
+			}
+		}
+	}
+
+	// attempt only tells sandboxes of the pod apart when they are run
+	attempt := uint32(len(podStatus.SandboxStatuses))
+	sandboxID := changes.SandboxID
+	if changes.CreateSandbox {
+		var err error
+		if sandboxID, err = m.createPodSandbox(pod, attempt); err != nil {
+			return err
+		}
+		log.Printf("create sandbox %s for pod %s\n", sandboxID, pod.Name)
+
+		newSandboxStatuses, _ := m.getSandboxStatusesByPodUID(pod.UID)
+		podStatus.UpdateSandboxStatuses(newSandboxStatuses)
+	}
+	if len(podStatus.SandboxStatuses) != 0 && podStatus.SandboxStatuses[0].Ip != "" {
+		podStatus.PodNetWork.IP = net.ParseIP(podStatus.SandboxStatuses[0].Ip)
+	}
+
+	// Create containers, env is resolved again each time they are created
+	var envCtx *cubecontainer.EnvContext
+	if len(changes.InitContainersToStart) != 0 || len(changes.ContainersToStart) != 0 {
+		var err error
+		if envCtx, err = m.makeEnvContext(pod, podStatus.PodNetWork.IP); err != nil {
+			log.Printf("fail to get env of pod %s: %v\n", pod.Name, err)
+			return err
+		}
+	}
+	sandboxConfig := m.generatePodSandboxConfig(pod, attempt)
+	for _, idx := range changes.InitContainersToStart {
+		if err := m.startPodContainer(&pod.Spec.InitContainers[idx], pod, podStatus, sandboxID, sandboxConfig, envCtx); err != nil {
+			return err
+		}
+	}
+	for _, idx := range changes.ContainersToStart {
+		if err := m.startPodContainer(&pod.Spec.Containers[idx], pod, podStatus, sandboxID, sandboxConfig, envCtx); err != nil {
+			return err
+		}
+	}
+
+	if !changes.KillPod {
+		m.pruneContainers(podStatus)
+	}
+
+	apiPodStatus, err := m.InspectPod(pod)
+	if err != nil {
+		log.Printf("fail to get pod status %s: %v\n", pod.UID, err)
+		return err
+	}
+	return m.reportPodStatus(pod, apiPodStatus, podStatus.PodNetWork.IP)
+}
+
+func (m *criRuntimeManager) startPodContainer(container *object.Container, pod *object.Pod, podStatus *cubecontainer.PodStatus,
+	sandboxID string, sandboxConfig *runtimeapi.PodSandboxConfig, envCtx *cubecontainer.EnvContext) error {
+	if !m.ensureImage(pod, container) {
+		log.Printf("container %s of pod %s waits for its image %s\n", container.Name, pod.Name, container.Image)
+		return nil
+	}
+
+	// restart count goes on when sandbox is re-created
+	restartCount := 0
+	if old := podStatus.FindContainerStatusByName(container.Name); old != nil {
+		restartCount = old.RestartCount + 1
+		m.backOff.next(backOffKey(pod.UID, container.Name))
+	}
+	if _, err := m.startContainer(container, pod, sandboxID, sandboxConfig, envCtx, restartCount); err != nil {
+		return err
+	}
+	log.Printf("start container %s, restart count %d\n", container.Name, restartCount)
+	return nil
+}
+
+func (m *criRuntimeManager) KillPod(UID string) error {
+	log.Printf("Kill pod %s\n", UID)
+	podStatus, err := m.getPodStatusByUID(UID)
+	if err != nil {
+		log.Printf("fail to get podStatus by UID %s\n", UID)
+		return err
+	}
+	m.backOff.forget(UID)
+	m.forgetPulls(UID)
+
+	if err = m.killPodByStatus(podStatus, true); err != nil {
+		return err
+	}
+	return m.cleanupPod(UID)
+}
+
+// TerminatePod runs preStop hooks and stops containers within gracePeriod, sidecars are
+// stopped after other containers. Containers and sandbox of the pod are removed then
+func (m *criRuntimeManager) TerminatePod(pod *object.Pod, gracePeriod time.Duration) error {
+	log.Printf("Terminate pod %s, grace period %v\n", pod.Name, gracePeriod)
+	podStatus, err := m.getPodStatusByUID(pod.UID)
+	if err != nil {
+		log.Printf("fail to get podStatus by UID %s\n", pod.UID)
+		return err
+	}
+
+	podIP := ""
+	if pod.Status != nil && pod.Status.IP != nil {
+		podIP = pod.Status.IP.String()
+	}
+	deadline := time.Now().Add(gracePeriod)
+	containers, sidecars := splitSidecars(podStatus.ContainerStatuses)
+	m.stopContainers(pod, containers, podIP, deadline)
+	m.stopContainers(pod, sidecars, podIP, deadline)
+
+	m.backOff.forget(pod.UID)
+	m.forgetPulls(pod.UID)
+	if err = m.killPodByStatus(podStatus, true); err != nil {
+		return err
+	}
+	return m.cleanupPod(pod.UID)
+}
+
+// stopContainers stops running containers in parallel, each runs its preStop hook first
+func (m *criRuntimeManager) stopContainers(pod *object.Pod, statuses []*cubecontainer.ContainerStatus,
+	podIP string, deadline time.Time) {
+	wg := sync.WaitGroup{}
+	for _, status := range statuses {
+		if status.State != cubecontainer.ContainerStateRunning {
+			continue
+		}
+
+		wg.Add(1)
+		go func(status *cubecontainer.ContainerStatus) {
+			defer wg.Done()
+			container := findContainerSpec(pod, status.Name)
+			if container != nil && container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+				err := m.runLifecycleHandler(status.ID.ID, container.Lifecycle.PreStop, podIP, time.Until(deadline))
+				if err != nil {
+					log.Printf("[Error]: preStop hook of container %s failed: %v\n", status.Name, err)
+				}
+			}
+
+			gracePeriod := time.Until(deadline)
+			if gracePeriod < minStopGracePeriod {
+				gracePeriod = minStopGracePeriod
+			}
+			if err := m.runtimeService.StopContainer(status.ID.ID, toStopTimeout(gracePeriod)); err != nil {
+				log.Printf("[Error]: fail to stop container %s: %v\n", status.Name, err)
+			}
+		}(status)
+	}
+	wg.Wait()
+}
+
+// killPodByStatus stops containers, then stops the sandbox, which tears down its network
+func (m *criRuntimeManager) killPodByStatus(status *cubecontainer.PodStatus, remove bool) error {
+	m.killPodContainers(status, remove)
+
+	for _, sandbox := range status.SandboxStatuses {
+		log.Printf("start to kill sandbox %s\n", sandbox.Id)
+		if err := m.runtimeService.StopPodSandbox(sandbox.Id); err != nil {
+			log.Printf("[Error]: fail to stop sandbox %s: %v\n", sandbox.Id, err)
+			return err
+		}
+
+		if remove {
+			if err := m.runtimeService.RemovePodSandbox(sandbox.Id); err != nil {
+				log.Printf("[Error]: fail to remove sandbox %s: %v\n", sandbox.Id, err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *criRuntimeManager) CleanupOrphanedPods(activePods map[string]bool) {
+	m.cleanupOrphanedPods(activePods, m.getPodStatusByUID)
+}
+
+func (m *criRuntimeManager) GetPodStatus(UID string) (*cubecontainer.PodStatus, error) {
+	return m.getPodStatusByUID(UID)
+}
+
+func (m *criRuntimeManager) InspectPod(pod *object.Pod) (*object.PodStatus, error) {
+	containerStatuses, err := m.getContainerStatusesByPodUID(pod.UID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxStatuses, err := m.getSandboxStatusesByPodUID(pod.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sandboxStatuses) == 0 {
+		return nil, fmt.Errorf("no sandbox for pod %s found", pod.Name)
+	}
+	return m.toAPIPodStatus(pod, containerStatuses, sandboxStatuses[0]), nil
+}
+
+func (m *criRuntimeManager) ListPodsUID() ([]string, error) {
+	return m.getAllPodsUID()
+}
+
+func (m *criRuntimeManager) getPodStatusByUID(UID string) (*cubecontainer.PodStatus, error) {
+	containerStatuses, err := m.getContainerStatusesByPodUID(UID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxStatuses, err := m.getSandboxStatusesByPodUID(UID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containerStatuses) == 0 && len(sandboxStatuses) == 0 {
+		// both empty: pod not exists
+		return &cubecontainer.PodStatus{}, nil
+	}
+
+	podName := ""
+	var podIP net.IP
+	if len(sandboxStatuses) > 0 {
+		podName = sandboxStatuses[0].Name
+		if sandboxStatuses[0].State == cubecontainer.SandboxStateReady {
+			podIP = net.ParseIP(sandboxStatuses[0].Ip)
+		}
+	}
+
+	return &cubecontainer.PodStatus{
+		UID:               UID,
+		Name:              podName,
+		PodNetWork:        cubecontainer.PodNetworkStatus{IP: podIP},
+		ContainerStatuses: containerStatuses,
+		SandboxStatuses:   sandboxStatuses,
+	}, nil
+}
+
+func (m *criRuntimeManager) Close() {
+	for _, service := range []interface{}{m.runtimeService, m.imageService} {
+		if closer, ok := service.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"log"
+	"sort"
+	"strings"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// createPodSandbox runs the sandbox of the pod, the runtime sets up its network by CNI
+func (m *criRuntimeManager) createPodSandbox(pod *object.Pod, attempt uint32) (string, error) {
+	config := m.generatePodSandboxConfig(pod, attempt)
+	log.Println("creating sandbox...")
+	sandboxID, err := m.runtimeService.RunPodSandbox(config, "")
+	if err != nil {
+		log.Printf("fail to run sandbox of pod %s: %v\n", pod.Name, err)
+		return "", err
+	}
+
+	// the pod cgroup is created by the runtime along with the sandbox
+	if err = m.podCgroups.UpdatePodCgroup(pod); err != nil {
+		log.Printf("[Error]: fail to limit cgroup of pod %s: %v\n", pod.Name, err)
+	}
+	return sandboxID, nil
+}
+
+// generatePodSandboxConfig makes the sandbox config, attempt tells sandboxes of the same pod apart
+func (m *criRuntimeManager) generatePodSandboxConfig(pod *object.Pod, attempt uint32) *runtimeapi.PodSandboxConfig {
+	portMappings := make([]*runtimeapi.PortMapping, 0)
+	containers := append([]object.Container{}, pod.Spec.InitContainers...)
+	for _, c := range append(containers, pod.Spec.Containers...) {
+		for _, p := range c.Ports {
+			// No need to do port binding when HostPort is not specified
+			if p.HostPort == 0 {
+				continue
+			}
+			portMappings = append(portMappings, &runtimeapi.PortMapping{
+				Protocol:      toCRIProtocol(p.Protocol),
+				ContainerPort: p.ContainerPort,
+				HostPort:      p.HostPort,
+				HostIp:        p.HostIP,
+			})
+		}
+	}
+
+	// the runtime writes resolv.conf of the sandbox by DnsConfig
+	dnsConfig := m.dnsConfigurer.GetPodDNS(pod)
+	config := &runtimeapi.PodSandboxConfig{
+		Metadata: &runtimeapi.PodSandboxMetadata{
+			Name:      pod.Name,
+			Uid:       pod.UID,
+			Namespace: pod.Namespace,
+			Attempt:   attempt,
+		},
+		LogDirectory: BuildPodLogsDirectory(pod.Namespace, pod.Name, pod.UID),
+		DnsConfig: &runtimeapi.DNSConfig{
+			Servers:  dnsConfig.Servers,
+			Searches: dnsConfig.Searches,
+			Options:  dnsConfig.Options,
+		},
+		PortMappings: portMappings,
+		Labels:       newSandboxLabels(pod),
+		Linux: &runtimeapi.LinuxPodSandboxConfig{
+			CgroupParent: m.podCgroups.GetPodCgroupParent(pod),
+			SecurityContext: &runtimeapi.LinuxSandboxSecurityContext{
+				NamespaceOptions: &runtimeapi.NamespaceOption{Pid: runtimeapi.NamespaceMode_POD},
+			},
+		},
+	}
+	if pod.Spec.SecurityContext != nil {
+		config.Linux.SecurityContext.SupplementalGroups = pod.Spec.SecurityContext.SupplementalGroups
+	}
+	if pod.Spec.HostNetwork {
+		// the runtime skips CNI, containers listen on ports of the node
+		config.Linux.SecurityContext.NamespaceOptions.Network = runtimeapi.NamespaceMode_NODE
+		config.PortMappings = nil
+	}
+	return config
+}
+
+// getSandboxStatusesByPodUID returns sandboxes of the pod, the latest first
+func (m *criRuntimeManager) getSandboxStatusesByPodUID(UID string) ([]*cubecontainer.SandboxStatus, error) {
+	sandboxes, err := m.runtimeService.ListPodSandbox(&runtimeapi.PodSandboxFilter{
+		LabelSelector: map[string]string{
+			ContainerTypeLabel: ContainerTypeSandbox,
+			PodUIDLabel:        UID,
+		},
+	})
+	if err != nil {
+		log.Printf("fail to list pod sandbox %s: %v\n", UID, err)
+		return nil, err
+	}
+	if len(sandboxes) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(sandboxes, func(i, j int) bool {
+		return sandboxes[i].CreatedAt > sandboxes[j].CreatedAt
+	})
+	statuses := make([]*cubecontainer.SandboxStatus, 0, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		status := &cubecontainer.SandboxStatus{
+			Id:     sandbox.Id,
+			Name:   sandbox.Metadata.Name,
+			PodUID: UID,
+			State:  cubecontainer.SandboxStateNotReady,
+
+			HostNetwork: sandbox.Labels[SandboxHostNetworkLabel] == "true",
+		}
+		if sandbox.State == runtimeapi.PodSandboxState_SANDBOX_READY && status.HostNetwork {
+			// runtimes report no IP for sandboxes in the network namespace of the node
+			status.State = cubecontainer.SandboxStateReady
+			if m.nodeIP != nil {
+				status.Ip = m.nodeIP.String()
+			}
+		} else if sandbox.State == runtimeapi.PodSandboxState_SANDBOX_READY {
+			status.State = cubecontainer.SandboxStateReady
+			// only ready sandboxes have network
+			sandboxStatus, err := m.runtimeService.PodSandboxStatus(sandbox.Id)
+			if err != nil {
+				log.Printf("fail to get status of sandbox %s: %v\n", sandbox.Id, err)
+			} else if sandboxStatus.Network != nil {
+				status.Ip = sandboxStatus.Network.Ip
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *criRuntimeManager) getAllPodsUID() ([]string, error) {
+	sandboxes, err := m.runtimeService.ListPodSandbox(&runtimeapi.PodSandboxFilter{
+		LabelSelector: map[string]string{ContainerTypeLabel: ContainerTypeSandbox},
+	})
+	if err != nil {
+		log.Printf("fail to list all pods sandbox: %v\n", err)
+		return nil, err
+	}
+
+	podUIDSet := make(map[string]bool)
+	for _, sandbox := range sandboxes {
+		if uid, ok := sandbox.Labels[PodUIDLabel]; ok {
+			podUIDSet[uid] = true
+		} else {
+			log.Printf("[error] uid label of sandbox %s is empty\n", sandbox.Id)
+		}
+	}
+
+	var uids []string
+	for uid := range podUIDSet {
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
+func toCRIProtocol(protocol string) runtimeapi.Protocol {
+	switch strings.ToUpper(protocol) {
+	case "UDP":
+		return runtimeapi.Protocol_UDP
+	case "SCTP":
+		return runtimeapi.Protocol_SCTP
+	}
+	return runtimeapi.Protocol_TCP
+}
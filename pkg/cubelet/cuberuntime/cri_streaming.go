@@ -0,0 +1,198 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cri/remote"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// criStreamStatus is the status sent on the error channel of exec and attach streams
+type criStreamStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+	Details *struct {
+		Causes []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"causes"`
+	} `json:"details"`
+}
+
+const (
+	criStatusSuccess       = "Success"
+	criReasonNonZeroExit   = "NonZeroExitCode"
+	criCauseReasonExitCode = "ExitCode"
+)
+
+func (m *criRuntimeManager) ExecInContainer(ctx context.Context, containerID string, cmd []string,
+	streams cubecontainer.Streams) (int, error) {
+	resp, err := m.runtimeService.Exec(&runtimeapi.ExecRequest{
+		ContainerId: containerID,
+		Cmd:         cmd,
+		Tty:         streams.TTY,
+		Stdin:       streams.Stdin != nil,
+		Stdout:      true,
+		Stderr:      !streams.TTY,
+	})
+	if err != nil {
+		return -1, fmt.Errorf("fail to exec in container: %v", err)
+	}
+	return serveCRIStreams(ctx, resp.Url, streams, streams.TTY)
+}
+
+func (m *criRuntimeManager) AttachContainer(ctx context.Context, containerID string,
+	streams cubecontainer.Streams) (int, error) {
+	status, err := m.runtimeService.ContainerStatus(containerID)
+	if err != nil {
+		return -1, errors.New("fail to get container status")
+	}
+	// tty is decided when the container is created
+	tty := status.Labels[ContainerTTYLabel] == "true"
+	if streams.Stdin != nil && status.Labels[ContainerStdinLabel] != "true" {
+		return -1, errors.New("container doesn't keep stdin open")
+	}
+
+	resp, err := m.runtimeService.Attach(&runtimeapi.AttachRequest{
+		ContainerId: containerID,
+		Stdin:       streams.Stdin != nil,
+		Tty:         tty,
+		Stdout:      true,
+		Stderr:      !tty,
+	})
+	if err != nil {
+		return -1, fmt.Errorf("fail to attach container: %v", err)
+	}
+	// the stream ends when the container exits, whose exit code is got from its status
+	_, _ = serveCRIStreams(ctx, resp.Url, streams, tty)
+
+	for start := time.Now(); time.Since(start) < exitStatusWait; time.Sleep(time.Millisecond * 100) {
+		status, err = m.runtimeService.ContainerStatus(containerID)
+		if err != nil {
+			return -1, errors.New("fail to get container status")
+		}
+		if status.State != runtimeapi.ContainerState_CONTAINER_RUNNING {
+			return int(status.ExitCode), nil
+		}
+	}
+	return -1, errors.New("container is still running")
+}
+
+func (m *criRuntimeManager) PortForward(podUID string, port int) (io.ReadWriteCloser, error) {
+	sandboxes, err := m.runtimeService.ListPodSandbox(&runtimeapi.PodSandboxFilter{
+		State:         &runtimeapi.PodSandboxStateValue{State: runtimeapi.PodSandboxState_SANDBOX_READY},
+		LabelSelector: map[string]string{PodUIDLabel: podUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sandboxes) == 0 {
+		return nil, cubecontainer.ErrSandboxNotRunning
+	}
+
+	resp, err := m.runtimeService.PortForward(&runtimeapi.PortForwardRequest{
+		PodSandboxId: sandboxes[0].Id,
+		Port:         []int32{int32(port)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return remote.DialPortForward(resp.Url, port)
+}
+
+// serveCRIStreams connects streams to the process through streamURL of the streaming server,
+// until the output ends or ctx is done, and returns the exit code the runtime reports
+func serveCRIStreams(ctx context.Context, streamURL string, streams cubecontainer.Streams, tty bool) (int, error) {
+	conn, err := remote.DialStream(streamURL)
+	if err != nil {
+		return -1, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// stop reading output as well
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	if streams.Stdin != nil {
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := streams.Stdin.Read(buf)
+				if n > 0 && conn.WriteMessage(remote.StdinChannel, buf[:n]) != nil {
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+	if streams.Resize != nil {
+		go func() {
+			for size := range streams.Resize {
+				if !tty {
+					continue
+				}
+				if payload, err := json.Marshal(size); err == nil {
+					_ = conn.WriteMessage(remote.ResizeChannel, payload)
+				}
+			}
+		}()
+	}
+
+	for {
+		channel, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return -1, errors.New("client is gone")
+			}
+			return -1, errors.New("stream ends without exit status")
+		}
+
+		switch channel {
+		case remote.StdoutChannel:
+			_, _ = streams.Stdout.Write(data)
+		case remote.StderrChannel:
+			_, _ = streams.Stderr.Write(data)
+		case remote.ErrorChannel:
+			if len(data) != 0 {
+				return parseCRIStreamStatus(data)
+			}
+		}
+	}
+}
+
+func parseCRIStreamStatus(data []byte) (int, error) {
+	var status criStreamStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return -1, fmt.Errorf("invalid exit status: %s", data)
+	}
+	if status.Status == criStatusSuccess {
+		return 0, nil
+	}
+	if status.Reason == criReasonNonZeroExit && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Reason == criCauseReasonExitCode {
+				if code, err := strconv.Atoi(cause.Message); err == nil {
+					return code, nil
+				}
+			}
+		}
+	}
+	return -1, errors.New(status.Message)
+}
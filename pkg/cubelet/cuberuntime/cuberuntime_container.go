@@ -2,9 +2,11 @@ package cuberuntime
 
 import (
 	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cuberuntime/options"
 	"Cubernetes/pkg/cubelet/dockershim"
 	"Cubernetes/pkg/object"
 	"log"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,14 +16,16 @@ import (
 	"github.com/docker/docker/api/types/filters"
 )
 
-func (m *cubeRuntimeManager) startContainer(container *object.Container, pod *object.Pod, podSandboxName string) (string, error) {
-	// err := m.dockerRuntime.PullImage(container.Image)
-	// if err != nil {
-	// 	log.Printf("ensure image for container #{container.Name} failed\n")
-	// 	return "", err
-	// }
+func (m *cubeRuntimeManager) startContainer(container *object.Container, pod *object.Pod, podSandboxName string,
+	envCtx *cubecontainer.EnvContext, restartCount int) (string, error) {
+	// image is ensured by ensureImage before
+	env, err := cubecontainer.MakeEnvironmentVariables(pod, container, envCtx)
+	if err != nil {
+		log.Printf("fail to make env of container %s: %v\n", container.Name, err)
+		return "", err
+	}
 
-	config := m.generateContainerConfig(container, pod, podSandboxName)
+	config := m.generateContainerConfig(container, pod, podSandboxName, env, restartCount)
 	log.Println("creating normal container...")
 	containerID, err := m.dockerRuntime.CreateContainer(config)
 	if err != nil {
@@ -35,11 +39,27 @@ func (m *cubeRuntimeManager) startContainer(container *object.Container, pod *ob
 		return "", err
 	}
 
+	// the container is killed if postStart hook fails, and restarted by restart policy
+	if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
+		err = m.runLifecycleHandler(containerID, container.Lifecycle.PostStart, envCtx.PodIP, options.PostStartHookTimeout)
+		if err != nil {
+			log.Printf("[Error]: postStart hook of container %s failed: %v\n", container.Name, err)
+			if err := m.dockerRuntime.StopContainerWithGracePeriod(containerID, minStopGracePeriod); err != nil {
+				log.Printf("[Error]: fail to kill container %s: %v\n", container.Name, err)
+			}
+			return "", err
+		}
+	}
+
 	return containerID, nil
 }
 
-func (m *cubeRuntimeManager) getContainerStatusesByPodUID(UID string) ([]*cubecontainer.ContainerStatus, error) {
+// getContainerStatusesByPodUID gets resource usage only if withUsage,
+// since docker stats is slow
+func (m *cubeRuntimeManager) getContainerStatusesByPodUID(UID string, withUsage bool) ([]*cubecontainer.ContainerStatus, error) {
+	// exited containers are listed too, restart policy and restart count depend on them
 	filter := dockertypes.ContainerListOptions{
+		All: true,
 		Filters: filters.NewArgs(
 			filters.Arg("label", buildLabelSelector(ContainerTypeLabel, ContainerTypeContainer)),
 			filters.Arg("label", buildLabelSelector(PodUIDLabel, UID)),
@@ -56,24 +76,63 @@ func (m *cubeRuntimeManager) getContainerStatusesByPodUID(UID string) ([]*cubeco
 		return nil, nil
 	}
 
-	statuses := make([]*cubecontainer.ContainerStatus, len(containers))
-	for i, container := range containers {
-		statuses[i], _ = m.getContainerStatus(container.ID)
+	statuses := make([]*cubecontainer.ContainerStatus, 0, len(containers))
+	for _, container := range containers {
+		if status, err := m.getContainerStatus(container.ID, withUsage); err == nil {
+			statuses = append(statuses, status)
+		}
 	}
 
 	return statuses, nil
 }
 
-func (m *cubeRuntimeManager) generateContainerConfig(container *object.Container, pod *object.Pod, podSandboxName string) *dockertypes.ContainerCreateConfig {
+// GetRunningContainerID returns "" if the container of the pod is not running
+func (m *cubeRuntimeManager) GetRunningContainerID(podUID, containerName string) (string, error) {
+	filter := dockertypes.ContainerListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", buildLabelSelector(ContainerTypeLabel, ContainerTypeContainer)),
+			filters.Arg("label", buildLabelSelector(PodUIDLabel, podUID)),
+			filters.Arg("label", buildLabelSelector(ContainerNameLabel, containerName)),
+			filters.Arg("status", "running"),
+		),
+	}
+
+	containers, err := m.dockerRuntime.ListContainers(filter)
+	if err != nil {
+		return "", err
+	}
+	if len(containers) == 0 {
+		return "", nil
+	}
+	return containers[0].ID, nil
+}
+
+func (m *cubeRuntimeManager) RunInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error) {
+	return m.dockerRuntime.ExecInContainer(containerID, cmd, timeout)
+}
+
+// KillContainer stops the container, it is started again on next SyncPod
+func (m *cubeRuntimeManager) KillContainer(containerID string) error {
+	return m.dockerRuntime.StopContainer(containerID)
+}
 
-	podContainerName := dockershim.MakeContainerName(pod, container)
+func (m *cubeRuntimeManager) generateContainerConfig(container *object.Container, pod *object.Pod, podSandboxName string,
+	env []string, restartCount int) *dockertypes.ContainerCreateConfig {
+
+	podContainerName := dockershim.MakeContainerName(pod, container, restartCount)
 
 	volumeBinds := make([]string, 0)
 	for _, mount := range container.VolumeMounts {
-		hostPath := findVolumeHostPath(pod, mount.Name)
-		volumeBinds = append(volumeBinds,
-			strings.Join([]string{hostPath, mount.MountPath}, ":"),
-		)
+		volume := findVolume(pod, mount.Name)
+		if volume == nil {
+			continue
+		}
+		hostPath, readOnly := m.volumeManager.GetVolumeHostPath(pod, volume)
+		bind := strings.Join([]string{hostPath, mount.MountPath}, ":")
+		if mount.ReadOnly || readOnly || volume.IsReadOnly() {
+			bind += ":ro"
+		}
+		volumeBinds = append(volumeBinds, bind)
 	}
 
 	mode := "container:" + podSandboxName
@@ -81,9 +140,14 @@ func (m *cubeRuntimeManager) generateContainerConfig(container *object.Container
 	config := &dockertypes.ContainerCreateConfig{
 		Name: podContainerName,
 		Config: &dockercontainer.Config{
-			Image:  container.Image,
-			Cmd:    container.Command,
-			Labels: newContainerLabels(container, pod),
+			Image:      container.Image,
+			Entrypoint: container.Command,
+			Cmd:        container.Args,
+			WorkingDir: container.WorkingDir,
+			OpenStdin:  container.Stdin,
+			Tty:        container.TTY,
+			Env:        env,
+			Labels:     newContainerLabels(container, pod, restartCount),
 		},
 		HostConfig: &dockercontainer.HostConfig{
 			Binds:       volumeBinds,
@@ -93,22 +157,48 @@ func (m *cubeRuntimeManager) generateContainerConfig(container *object.Container
 		},
 	}
 
-	// set resource if specified
+	// set resource if specified, containers are limited by the pod cgroup as well
+	config.HostConfig.CgroupParent = m.podCgroups.GetPodCgroupParent(pod)
 	if container.Resources != nil {
-		config.HostConfig.Resources = dockercontainer.Resources{
-			NanoCPUs: int64(container.Resources.Cpus * 1000000000),
-			Memory:   container.Resources.Memory,
+		config.HostConfig.NanoCPUs = int64(container.Resources.Cpus * 1000000000)
+		config.HostConfig.Memory = container.Resources.Memory
+		// shares of requested cpus, at least 2 as cgroup takes
+		if cpus, _ := object.GetContainerRequests(container); cpus > 0 {
+			config.HostConfig.CPUShares = int64(cpus * 1024)
+			if config.HostConfig.CPUShares < 2 {
+				config.HostConfig.CPUShares = 2
+			}
 		}
 	}
 
+	applySecurityContext(config, pod, container)
 	return config
 }
 
+// killPodContainers stops sidecars after other containers
 func (m *cubeRuntimeManager) killPodContainers(pod *cubecontainer.PodStatus, remove bool) {
+	containers, sidecars := splitSidecars(pod.ContainerStatuses)
+	m.killContainers(containers, remove)
+	m.killContainers(sidecars, remove)
+}
+
+func splitSidecars(statuses []*cubecontainer.ContainerStatus) ([]*cubecontainer.ContainerStatus, []*cubecontainer.ContainerStatus) {
+	containers, sidecars := make([]*cubecontainer.ContainerStatus, 0), make([]*cubecontainer.ContainerStatus, 0)
+	for _, status := range statuses {
+		if status.Sidecar {
+			sidecars = append(sidecars, status)
+		} else {
+			containers = append(containers, status)
+		}
+	}
+	return containers, sidecars
+}
+
+func (m *cubeRuntimeManager) killContainers(containers []*cubecontainer.ContainerStatus, remove bool) {
 	wg := sync.WaitGroup{}
 
-	wg.Add(len(pod.ContainerStatuses))
-	for _, container := range pod.ContainerStatuses {
+	wg.Add(len(containers))
+	for _, container := range containers {
 		go func(container *cubecontainer.ContainerStatus) {
 			defer wg.Done()
 
@@ -126,7 +216,7 @@ func (m *cubeRuntimeManager) killPodContainers(pod *cubecontainer.PodStatus, rem
 	wg.Wait()
 }
 
-func (m *cubeRuntimeManager) getContainerStatus(UID string) (*cubecontainer.ContainerStatus, error) {
+func (m *cubeRuntimeManager) getContainerStatus(UID string, withUsage bool) (*cubecontainer.ContainerStatus, error) {
 	containerJson, err := m.dockerRuntime.InspectContainer(UID)
 	if err != nil {
 		return nil, err
@@ -137,28 +227,41 @@ func (m *cubeRuntimeManager) getContainerStatus(UID string) (*cubecontainer.Cont
 	started, _ := time.Parse(time.RFC3339Nano, containerJson.State.StartedAt)
 	finished, _ := time.Parse(time.RFC3339Nano, containerJson.State.FinishedAt)
 
-	statsJson, err := m.dockerRuntime.GetContainerStats(UID)
-	if err != nil {
-		return nil, err
-	}
+	restartCount, _ := strconv.Atoi(containerJson.Config.Labels[ContainerRestartCountLabel])
 
 	status := &cubecontainer.ContainerStatus{
 		ID: cubecontainer.ContainerID{
 			Type: "docker",
 			ID:   containerJson.ID,
 		},
-		Name:       dockershim.ParseContainerName(containerJson.Name),
-		State:      state,
-		CreatedAt:  created,
-		StartedAt:  started,
-		FinishedAt: finished,
-		ResourceUsage: cubecontainer.ContainerResourceUsage{
-			CPUUsage:    m.cpuStatsCache.CalculateCpuPercent(UID, statsJson.CPUStats),
-			MemoryUsage: int64(statsJson.MemoryStats.Usage),
-		},
-		ExitCode: exitCode,
-		Image:    containerJson.Config.Image,
-		ImageID:  strings.TrimLeft(containerJson.Image, "sha256:"),
+		Name:         dockershim.ParseContainerName(containerJson.Name),
+		State:        state,
+		CreatedAt:    created,
+		StartedAt:    started,
+		FinishedAt:   finished,
+		ExitCode:     exitCode,
+		Image:        containerJson.Config.Image,
+		ImageID:      strings.TrimLeft(containerJson.Image, "sha256:"),
+		RestartCount: restartCount,
+		Sidecar:      containerJson.Config.Labels[ContainerSidecarLabel] == "true",
+	}
+
+	if state == cubecontainer.ContainerStateExited {
+		status.Reason, status.Message = toContainerReason(containerJson.State)
+		return status, nil
+	}
+	if state != cubecontainer.ContainerStateRunning || !withUsage {
+		return status, nil
+	}
+
+	// only running containers use resources
+	statsJson, err := m.dockerRuntime.GetContainerStats(UID)
+	if err != nil {
+		return nil, err
+	}
+	status.ResourceUsage = cubecontainer.ContainerResourceUsage{
+		CPUUsage:    m.cpuStatsCache.CalculateCpuPercent(UID, statsJson.CPUStats),
+		MemoryUsage: int64(statsJson.MemoryStats.Usage),
 	}
 
 	log.Printf("[CUBELET] Updating Container Cpu Usage: %f %%\n", status.ResourceUsage.CPUUsage)
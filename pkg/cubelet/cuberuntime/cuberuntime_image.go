@@ -0,0 +1,54 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+
+	dockertypes "github.com/docker/docker/api/types"
+)
+
+func (m *cubeRuntimeManager) RuntimeVersion() (string, error) {
+	version, err := m.dockerRuntime.GetRuntimeVersion()
+	if err != nil {
+		return "", err
+	}
+	return "docker://" + version, nil
+}
+
+func (m *cubeRuntimeManager) ListImages() ([]cubecontainer.Image, error) {
+	summaries, err := m.dockerRuntime.ListImages(false)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]cubecontainer.Image, 0, len(summaries))
+	for _, summary := range summaries {
+		images = append(images, cubecontainer.Image{
+			ID:          summary.ID,
+			RepoTags:    summary.RepoTags,
+			RepoDigests: summary.RepoDigests,
+			Size:        summary.Size,
+		})
+	}
+	return images, nil
+}
+
+func (m *cubeRuntimeManager) ListImagesInUse() (map[string]bool, error) {
+	containers, err := m.dockerRuntime.ListContainers(dockertypes.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for _, container := range containers {
+		inUse[container.ImageID] = true
+	}
+	return inUse, nil
+}
+
+func (m *cubeRuntimeManager) RemoveImage(imageID string) error {
+	return m.dockerRuntime.RemoveImageByID(imageID)
+}
+
+func (m *cubeRuntimeManager) ImageFsPath() (string, error) {
+	return m.dockerRuntime.GetDockerRootDir()
+}
@@ -3,12 +3,19 @@ package cuberuntime
 import (
 	"Cubernetes/pkg/apiserver/crudobj"
 	"Cubernetes/pkg/cubelet/cache"
+	"Cubernetes/pkg/cubelet/cgroup"
 	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cuberuntime/options"
 	dockershim "Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/cubelet/network/dns"
+	"Cubernetes/pkg/cubelet/network/hostport"
+	"Cubernetes/pkg/cubelet/volume"
 	"Cubernetes/pkg/cubenetwork/weaveplugins"
 	object "Cubernetes/pkg/object"
 	"fmt"
 	"log"
+	"net"
+	"sync"
 	"time"
 )
 
@@ -20,9 +27,70 @@ const (
 )
 
 type cubeRuntimeManager struct {
+	*podSyncHelper
+
 	runtimeName   string
 	cpuStatsCache cache.CpuStatsCache
 	dockerRuntime dockershim.DockerRuntime
+	// getPodIP returns IP allocated to the sandbox in pod network
+	getPodIP func(sandboxID string) (net.IP, error)
+	// sandboxIPs caches IPs got by getPodIP, which runs weave, by pod UID. Only the ready sandbox of
+	// a pod is kept, the IP of a sandbox never changes until it stops
+	sandboxIPLock sync.Mutex
+	sandboxIPs    map[string]sandboxIP
+	// hostports forwards host ports to pods in weave network
+	hostports hostport.Manager
+}
+
+// podSyncHelper holds what syncing pods needs regardless of the container runtime,
+// it is shared by runtime managers of docker and CRI
+type podSyncHelper struct {
+	// restart delay of crashed containers
+	backOff *backOff
+
+	// images of containers being pulled, keyed by backOffKey
+	pullLock    sync.Mutex
+	pulls       map[string]*pullState
+	pullBackOff *backOff
+	images      imageService
+
+	volumeManager volume.Manager
+	podCgroups    cgroup.PodCgroupManager
+
+	// updatePod writes status of pods synced into apiserver
+	updatePod func(pod object.Pod) (object.Pod, error)
+
+	// nodeIP is the IP of hostNetwork pods
+	nodeIP net.IP
+	// dnsConfigurer makes resolv.conf of pods
+	dnsConfigurer *dns.Configurer
+}
+
+func (m *podSyncHelper) SetNodeIP(ip net.IP) {
+	m.nodeIP = ip
+}
+
+func (m *podSyncHelper) SetClusterDNS(clusterDNS []string, clusterDomain string) {
+	m.dnsConfigurer = newDNSConfigurer(clusterDNS, clusterDomain)
+}
+
+// newDNSConfigurer makes ClusterFirst pods search the weave DNS domain after the cluster domain,
+// where hosts of Dns objects are, it is not searched twice if it is the cluster domain
+func newDNSConfigurer(clusterDNS []string, clusterDomain string) *dns.Configurer {
+	return dns.NewConfigurer(clusterDNS, clusterDomain, []string{options.WeaveDNSSearchDomain}, dns.HostResolvConf)
+}
+
+func newPodSyncHelper(images imageService, cgroupDriver string) *podSyncHelper {
+	return &podSyncHelper{
+		backOff:       newBackOff(options.CrashLoopInitialBackOff, options.CrashLoopMaxBackOff),
+		pulls:         make(map[string]*pullState),
+		pullBackOff:   newBackOff(options.ImagePullInitialBackOff, options.ImagePullMaxBackOff),
+		images:        images,
+		volumeManager: volume.NewManager(),
+		podCgroups:    cgroup.NewPodCgroupManager(cgroupDriver),
+		updatePod:     crudobj.UpdatePod,
+		dnsConfigurer: newDNSConfigurer([]string{options.WeaveDNSServer}, options.DefaultClusterDomain),
+	}
 }
 
 type podActions struct {
@@ -30,6 +98,8 @@ type podActions struct {
 	CreateSandbox bool
 	// old sandbox id, kill if we need to kill old pod
 	SandboxID string
+	// index of containers in podSpec.InitContainers to start, before ContainersToStart
+	InitContainersToStart []int
 	// index of containers in podSpec.Containers to start
 	ContainersToStart []int
 	// UID of containers to kill
@@ -38,12 +108,40 @@ type podActions struct {
 
 type CubeRuntime interface {
 	cubecontainer.Runtime
+	// images, logs and streams of pods are served by the runtime they run on
+	cubecontainer.ImageService
+	cubecontainer.StreamingRuntime
+
+	// GetRunningContainerID returns "" if the container of the pod is not running
+	GetRunningContainerID(podUID, containerName string) (string, error)
+	// RunInContainer runs cmd in the container, and returns its exit code and output
+	RunInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error)
+	KillContainer(containerID string) error
+	// TerminatePod runs preStop hooks and stops containers within gracePeriod, then removes the pod
+	TerminatePod(pod *object.Pod, gracePeriod time.Duration) error
+	// CleanupOrphanedPods removes volumes and cgroups of pods not in activePods, which have no containers left
+	CleanupOrphanedPods(activePods map[string]bool)
+	// CleanupProvisionedLocalVolumes removes data of provisioned local volumes not in volumeNames
+	CleanupProvisionedLocalVolumes(volumeNames map[string]bool)
+	// SetNodeIP sets the IP of the node, which hostNetwork pods have
+	SetNodeIP(ip net.IP)
+	// SetClusterDNS sets nameservers and the domain of the cluster DNS, used by ClusterFirst pods
+	SetClusterDNS(clusterDNS []string, clusterDomain string)
 }
 
 func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.PodStatus) error {
+	// volumes are set up on every sync, so that running containers see updates of configMaps and secrets
+	if err := m.volumeManager.SetUpPodVolumes(pod); err != nil {
+		log.Printf("fail to set up volumes of pod %s: %v\n", pod.Name, err)
+		return err
+	}
 
 	// Compute sandbox and container changes.
 	podContainerChanges := m.computePodActions(pod, podStatus)
+	if !podContainerChanges.KillPod && !podContainerChanges.CreateSandbox && len(podContainerChanges.InitContainersToStart) == 0 &&
+		len(podContainerChanges.ContainersToStart) == 0 && len(podContainerChanges.ContainersToKill) == 0 {
+		return nil
+	}
 
 	removeContainer := true
 	// Kill the pod if sandbox changed
@@ -67,7 +165,7 @@ func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.P
 	if podContainerChanges.CreateSandbox {
 		var err error
 
-		existName, podSandboxID, err := m.createPodSandbox(pod); 
+		existName, podSandboxID, err := m.createPodSandbox(pod)
 		if err != nil {
 			return err
 		}
@@ -78,25 +176,50 @@ func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.P
 		newSandboxStatuses, _ := m.getSandboxStatusesByPodUID(pod.UID)
 		podStatus.UpdateSandboxStatuses(newSandboxStatuses)
 
-		ip, err := weaveplugins.GetPodIPByID(podSandboxID)
-		if err != nil || ip == nil {
-			log.Printf("[Error]: add pod to weave network failed")
-			return err
-		}
-		log.Printf("IP Allocated: %v", ip.String())
-		//network.InitNetwork(network.ProbeNetworkPlugins("", ""), podStatus)
+		if pod.Spec.HostNetwork {
+			podStatus.PodNetWork.IP = m.nodeIP
+		} else {
+			ip, err := m.getSandboxIP(pod.UID, podSandboxID)
+			if err != nil || ip == nil {
+				log.Printf("[Error]: add pod to weave network failed")
+				return err
+			}
+			log.Printf("IP Allocated: %v", ip.String())
+			//network.InitNetwork(network.ProbeNetworkPlugins("", ""), podStatus)
 
-		podStatus.PodNetWork.IP = ip
+			podStatus.PodNetWork.IP = ip
+			if err = m.hostports.Add(pod, ip); err != nil {
+				log.Printf("[Error]: fail to forward host ports of pod %s: %v\n", pod.Name, err)
+			}
+		}
 	}
 
-	// Create containers
+	// Create containers, env is resolved again each time they are created
+	var envCtx *cubecontainer.EnvContext
+	if len(podContainerChanges.InitContainersToStart) != 0 || len(podContainerChanges.ContainersToStart) != 0 {
+		podIP := podStatus.PodNetWork.IP
+		if podIP == nil && pod.Status != nil {
+			podIP = pod.Status.IP
+		}
+		var err error
+		if envCtx, err = m.makeEnvContext(pod, podIP); err != nil {
+			log.Printf("fail to get env of pod %s: %v\n", pod.Name, err)
+			return err
+		}
+	}
+	for _, idx := range podContainerChanges.InitContainersToStart {
+		if err := m.startPodContainer(&pod.Spec.InitContainers[idx], pod, podStatus, podSandboxName, envCtx); err != nil {
+			return err
+		}
+	}
 	for _, idx := range podContainerChanges.ContainersToStart {
-		msg, err := m.startContainer(&pod.Spec.Containers[idx], pod, podSandboxName)
-		if err != nil {
-			log.Printf("fail to start container %s: %s\n", pod.Spec.Containers[idx].Name, msg)
+		if err := m.startPodContainer(&pod.Spec.Containers[idx], pod, podStatus, podSandboxName, envCtx); err != nil {
 			return err
 		}
-		log.Printf("start container %s\n", pod.Spec.Containers[idx].Name)
+	}
+
+	if !podContainerChanges.KillPod {
+		m.pruneContainers(podStatus)
 	}
 
 	apiPodStatus, err := m.InspectPod(pod)
@@ -104,8 +227,22 @@ func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.P
 		log.Printf("fail to get pod status %s: %v\n", pod.UID, err)
 		return err
 	}
+	return m.reportPodStatus(pod, apiPodStatus, podStatus.PodNetWork.IP)
+}
+
+// reportPodStatus writes status of the pod synced into apiserver, IP of the sandbox is kept
+// if it is not re-created
+func (m *podSyncHelper) reportPodStatus(pod *object.Pod, apiPodStatus *object.PodStatus, podIP net.IP) error {
+	if object.IsStaticPod(pod) {
+		// static pods are not in apiserver, cubelet reports them on their mirror pods
+		return nil
+	}
 
-	apiPodStatus.IP = podStatus.PodNetWork.IP
+	apiPodStatus.IP = podIP
+	if apiPodStatus.IP == nil && pod.Status != nil {
+		// sandbox is not re-created, keep its IP
+		apiPodStatus.IP = pod.Status.IP
+	}
 	apiPodStatus.StartTime = time.Now()
 	if pod.Status != nil {
 		apiPodStatus.NodeUID = pod.Status.NodeUID
@@ -115,7 +252,7 @@ func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.P
 		apiPodStatus.IP.String(), apiPodStatus.NodeUID)
 
 	pod.Status = apiPodStatus
-	_, err = crudobj.UpdatePod(*pod)
+	_, err := m.updatePod(*pod)
 	if err != nil {
 		log.Printf("fail to update Pod %s status to apiserver\n", pod.Name)
 		return err
@@ -126,79 +263,214 @@ func (m *cubeRuntimeManager) SyncPod(pod *object.Pod, podStatus *cubecontainer.P
 	return nil
 }
 
-// FIXME: only compute container changes by its name now
-func (m *cubeRuntimeManager) computePodActions(pod *object.Pod, podStatus *cubecontainer.PodStatus) podActions {
+func (m *cubeRuntimeManager) startPodContainer(container *object.Container, pod *object.Pod,
+	podStatus *cubecontainer.PodStatus, podSandboxName string, envCtx *cubecontainer.EnvContext) error {
+	if !m.ensureImage(pod, container) {
+		log.Printf("container %s of pod %s waits for its image %s\n", container.Name, pod.Name, container.Image)
+		return nil
+	}
+
+	// restart count goes on when sandbox is re-created
+	restartCount := 0
+	if old := podStatus.FindContainerStatusByName(container.Name); old != nil {
+		restartCount = old.RestartCount + 1
+		m.backOff.next(backOffKey(pod.UID, container.Name))
+	}
+	msg, err := m.startContainer(container, pod, podSandboxName, envCtx, restartCount)
+	if err != nil {
+		log.Printf("fail to start container %s: %s\n", container.Name, msg)
+		return err
+	}
+	log.Printf("start container %s, restart count %d\n", container.Name, restartCount)
+	return nil
+}
+
+// computePodActions compares containers by name only, spec changes are not detected
+func (m *podSyncHelper) computePodActions(pod *object.Pod, podStatus *cubecontainer.PodStatus) podActions {
 	createPodSandbox, sandboxID := m.podSandboxChanged(pod, podStatus)
 	changes := podActions{
-		KillPod:           createPodSandbox,
-		CreateSandbox:     createPodSandbox,
-		SandboxID:         sandboxID,
-		ContainersToStart: []int{},
-		ContainersToKill:  []string{},
+		KillPod:               createPodSandbox,
+		CreateSandbox:         createPodSandbox,
+		SandboxID:             sandboxID,
+		InitContainersToStart: []int{},
+		ContainersToStart:     []int{},
+		ContainersToKill:      []string{},
 	}
 
-	// create sandbox need to (re-)create all containers
+	// create sandbox need to (re-)create all containers not done
 	if createPodSandbox {
-		var containersToStart []int
-		var containersToKill []string
-
-		for idx := range pod.Spec.Containers {
-			// TODO: RestartPolicy == OnFailure && ExitSucceeded => no need to start
-			containersToStart = append(containersToStart, idx)
+		for idx, container := range pod.Spec.Containers {
+			status := podStatus.FindContainerStatusByName(container.Name)
+			if status != nil && status.State == cubecontainer.ContainerStateExited &&
+				!cubecontainer.ShouldContainerBeRestarted(pod.Spec.RestartPolicy, status) {
+				continue
+			}
+			changes.ContainersToStart = append(changes.ContainersToStart, idx)
 		}
 
 		for _, oldContainer := range podStatus.ContainerStatuses {
 			// kill all old containers
-			containersToKill = append(containersToKill, oldContainer.ID.ID)
+			changes.ContainersToKill = append(changes.ContainersToKill, oldContainer.ID.ID)
 		}
 
-		if len(containersToStart) == 0 {
-			// nothing to create, so don't create sandbox
+		if len(changes.ContainersToStart) == 0 {
+			// nothing to create, so don't create sandbox, and keep containers done
 			changes.CreateSandbox = false
+			changes.KillPod = false
+			changes.ContainersToKill = []string{}
+		} else if len(pod.Spec.InitContainers) != 0 {
+			// init containers run again in the new sandbox, before containers
+			if !m.computeInitContainerActions(pod, &cubecontainer.PodStatus{}, &changes, make(map[string]bool)) {
+				changes.ContainersToStart = []int{}
+			}
 		}
+		return changes
+	}
 
-		changes.ContainersToStart = containersToStart
-		changes.ContainersToKill = containersToKill
+	keep := make(map[string]bool)
+	if !m.computeInitContainerActions(pod, podStatus, &changes, keep) {
+		// containers wait for init containers
 		return changes
 	}
 
-	var remain []string
+	for idx := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[idx]
+		containerStatus := podStatus.FindContainerStatusByName(container.Name)
+		if containerStatus != nil {
+			keep[containerStatus.ID.ID] = true
+		}
+		if m.shouldStartContainer(pod, container, containerStatus, pod.Spec.RestartPolicy, &changes) {
+			changes.ContainersToStart = append(changes.ContainersToStart, idx)
+		}
+	}
+
+	// kill running containers not mentioned
 	for _, oldContainer := range podStatus.ContainerStatuses {
-		remain = append(remain, oldContainer.ID.ID)
+		if oldContainer.State == cubecontainer.ContainerStateRunning && !keep[oldContainer.ID.ID] {
+			changes.ContainersToKill = append(changes.ContainersToKill, oldContainer.ID.ID)
+		}
 	}
 
-	for idx, container := range pod.Spec.Containers {
+	return changes
+}
+
+// computeInitContainerActions starts init containers one by one, each after the former completes,
+// sidecars are started in order and restarted whenever they exit, until containers complete.
+// It returns whether all init containers have completed, so that containers can be started
+func (m *podSyncHelper) computeInitContainerActions(pod *object.Pod, podStatus *cubecontainer.PodStatus,
+	changes *podActions, keep map[string]bool) bool {
+	completed := containersCompleted(pod, podStatus)
+
+	for idx := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[idx]
+		policy := cubecontainer.InitContainerRestartPolicy(pod.Spec.RestartPolicy, container)
 		containerStatus := podStatus.FindContainerStatusByName(container.Name)
 
-		if containerStatus == nil || containerStatus.State != cubecontainer.ContainerStateRunning {
-			// container not exist or container not running => simply restart
-			// assume no name change => no spec change for easy impl
-			changes.ContainersToStart = append(changes.ContainersToStart, idx)
-			if containerStatus != nil /* just kill all old containers now */ {
-				changes.ContainersToKill = append(changes.ContainersToKill, containerStatus.ID.ID)
+		if object.IsSidecarContainer(container) {
+			if completed {
+				// not kept, so it is stopped if still running
+				continue
 			}
-		} else {
-			// container:name no change: keep the old container
-			var keep int
-			for idx := range remain {
-				if remain[idx] == containerStatus.ID.ID {
-					keep = idx
-					break
-				}
+			if containerStatus != nil {
+				keep[containerStatus.ID.ID] = true
+			}
+			if m.shouldStartContainer(pod, container, containerStatus, policy, changes) {
+				changes.InitContainersToStart = append(changes.InitContainersToStart, idx)
 			}
-			remain = append(remain[:keep], remain[keep+1:]...)
+			continue
 		}
+
+		if containerStatus != nil && containerStatus.State == cubecontainer.ContainerStateExited &&
+			containerStatus.ExitCode == 0 {
+			continue
+		}
+		if containerStatus != nil {
+			keep[containerStatus.ID.ID] = true
+		}
+		if m.shouldStartContainer(pod, container, containerStatus, policy, changes) {
+			changes.InitContainersToStart = append(changes.InitContainersToStart, idx)
+		}
+		return false
 	}
 
-	// kill not mentioned containers
-	changes.ContainersToKill = append(changes.ContainersToKill, remain...)
+	return true
+}
 
-	return changes
+// shouldStartContainer tells whether a new container should be started by its latest status,
+// the latest one is killed if it is created but not started
+func (m *podSyncHelper) shouldStartContainer(pod *object.Pod, container *object.Container,
+	containerStatus *cubecontainer.ContainerStatus, policy object.RestartPolicy, changes *podActions) bool {
+	if containerStatus == nil {
+		return true
+	}
+
+	switch containerStatus.State {
+	case cubecontainer.ContainerStateRunning:
+		// container:name no change: keep the old container
+		return false
+	case cubecontainer.ContainerStateExited:
+		if !cubecontainer.ShouldContainerBeRestarted(policy, containerStatus) {
+			return false
+		}
+		wait := m.backOff.remaining(backOffKey(pod.UID, container.Name), containerStatus.FinishedAt)
+		if wait > 0 {
+			log.Printf("back-off %v restarting container %s of pod %s\n", wait.Round(time.Second), container.Name, pod.Name)
+			return false
+		}
+		return true
+	default:
+		// created but not started: start a new one
+		changes.ContainersToKill = append(changes.ContainersToKill, containerStatus.ID.ID)
+		return true
+	}
+}
+
+// containersCompleted tells whether all containers have exited and won't be restarted
+func containersCompleted(pod *object.Pod, podStatus *cubecontainer.PodStatus) bool {
+	for _, container := range pod.Spec.Containers {
+		containerStatus := podStatus.FindContainerStatusByName(container.Name)
+		if containerStatus == nil || containerStatus.State != cubecontainer.ContainerStateExited ||
+			cubecontainer.ShouldContainerBeRestarted(pod.Spec.RestartPolicy, containerStatus) {
+			return false
+		}
+	}
+	return len(pod.Spec.Containers) != 0
+}
+
+// pruneContainers removes containers not running, except the latest one of each name,
+// which is reported as last termination state after restart
+func (m *cubeRuntimeManager) pruneContainers(podStatus *cubecontainer.PodStatus) {
+	for _, status := range containersToPrune(podStatus) {
+		if err := m.dockerRuntime.RemoveContainer(status.ID.ID, false); err != nil {
+			log.Printf("fail to remove old container %s: %v\n", status.ID.ID, err)
+		}
+	}
+}
+
+// containersToPrune returns containers not running, except the latest one of each name
+func containersToPrune(podStatus *cubecontainer.PodStatus) []*cubecontainer.ContainerStatus {
+	latest := make(map[string]*cubecontainer.ContainerStatus)
+	for _, status := range podStatus.ContainerStatuses {
+		if status.State == cubecontainer.ContainerStateRunning {
+			continue
+		}
+		if old, ok := latest[status.Name]; !ok || status.CreatedAt.After(old.CreatedAt) {
+			latest[status.Name] = status
+		}
+	}
+
+	prune := make([]*cubecontainer.ContainerStatus, 0)
+	for _, status := range podStatus.ContainerStatuses {
+		if status.State != cubecontainer.ContainerStateRunning && latest[status.Name] != status {
+			prune = append(prune, status)
+		}
+	}
+	return prune
 }
 
 // podSandboxChanged checks whether the spec of the pod is changed and returns
 // (changed, original sandboxID if exist).
-func (m *cubeRuntimeManager) podSandboxChanged(pod *object.Pod, podStatus *cubecontainer.PodStatus) (bool, string) {
+func (m *podSyncHelper) podSandboxChanged(pod *object.Pod, podStatus *cubecontainer.PodStatus) (bool, string) {
 	if len(podStatus.SandboxStatuses) == 0 {
 		log.Printf("no sandbox for pod %s can be found. Need to start a new one.", pod.Name)
 		// This branch should return
@@ -211,8 +483,14 @@ func (m *cubeRuntimeManager) podSandboxChanged(pod *object.Pod, podStatus *cubec
 		return true, sandboxStatus.Id
 	}
 
-	// Needs to create a new sandbox when the sandbox does not have an IP address.
-	if sandboxStatus.Ip == "" {
+	// Needs to create a new sandbox when the network namespace of the pod is changed.
+	if sandboxStatus.HostNetwork != pod.Spec.HostNetwork {
+		return true, sandboxStatus.Id
+	}
+
+	// Needs to create a new sandbox when the sandbox does not have an IP address,
+	// hostNetwork pods have the IP of the node, which may be unknown.
+	if sandboxStatus.Ip == "" && !pod.Spec.HostNetwork {
 		// Sandbox for pod has no IP address. Need to start a new one.
 		return true, sandboxStatus.Id
 	}
@@ -230,8 +508,70 @@ func (m *cubeRuntimeManager) KillPod(UID string) error {
 	}
 	// for debug only
 	removeContainer := true
+	m.backOff.forget(UID)
+	m.forgetPulls(UID)
 
-	return m.killPodByStatus(podStatus, removeContainer)
+	if err = m.killPodByStatus(podStatus, removeContainer); err != nil {
+		return err
+	}
+	return m.cleanupPod(UID)
+}
+
+// cleanupPod removes the cgroup and volumes of the pod, after its containers are removed
+func (m *podSyncHelper) cleanupPod(UID string) error {
+	if err := m.podCgroups.DestroyPodCgroup(UID); err != nil {
+		log.Printf("[Error]: %v\n", err)
+	}
+	return m.volumeManager.CleanupPodVolumes(UID)
+}
+
+func (m *cubeRuntimeManager) CleanupOrphanedPods(activePods map[string]bool) {
+	m.cleanupOrphanedPods(activePods, m.getPodStatusByUID)
+
+	m.sandboxIPLock.Lock()
+	defer m.sandboxIPLock.Unlock()
+	for uid := range m.sandboxIPs {
+		if !activePods[uid] {
+			delete(m.sandboxIPs, uid)
+		}
+	}
+}
+
+// cleanupOrphanedPods removes volumes and cgroups of pods not in activePods, if getPodStatus
+// finds no sandbox or container of the pod
+func (m *podSyncHelper) cleanupOrphanedPods(activePods map[string]bool,
+	getPodStatus func(UID string) (*cubecontainer.PodStatus, error)) {
+	uids, err := m.volumeManager.ListPodsWithVolumes()
+	if err != nil {
+		log.Printf("[Error]: fail to list pod volumes: %v\n", err)
+		return
+	}
+	cgroupUIDs, err := m.podCgroups.ListPodCgroups()
+	if err != nil {
+		log.Printf("[Error]: fail to list pod cgroups: %v\n", err)
+	}
+	uids = append(uids, cgroupUIDs...)
+
+	checked := make(map[string]bool)
+	for _, uid := range uids {
+		if activePods[uid] || checked[uid] {
+			continue
+		}
+		checked[uid] = true
+		podStatus, err := getPodStatus(uid)
+		if err != nil || len(podStatus.SandboxStatuses) != 0 || len(podStatus.ContainerStatuses) != 0 {
+			continue
+		}
+		if err = m.cleanupPod(uid); err != nil {
+			log.Printf("[Error]: fail to remove volumes of pod %s: %v\n", uid, err)
+		}
+	}
+}
+
+func (m *podSyncHelper) CleanupProvisionedLocalVolumes(volumeNames map[string]bool) {
+	if err := m.volumeManager.CleanupProvisionedLocalVolumes(volumeNames); err != nil {
+		log.Printf("[Error]: fail to remove local volumes: %v\n", err)
+	}
 }
 
 func (m *cubeRuntimeManager) killPodByStatus(status *cubecontainer.PodStatus, remove bool) error {
@@ -261,6 +601,12 @@ func (m *cubeRuntimeManager) killPodByStatus(status *cubecontainer.PodStatus, re
 
 		//network.ReleaseNetwork(network.ProbeNetworkPlugins("", ""), status)
 	}
+	if len(status.SandboxStatuses) != 0 {
+		m.forgetSandboxIP(status.UID)
+		if err := m.hostports.Remove(status.UID); err != nil {
+			log.Printf("[Error]: fail to remove host ports of pod %s: %v\n", status.UID, err)
+		}
+	}
 
 	return nil
 }
@@ -270,7 +616,7 @@ func (m *cubeRuntimeManager) GetPodStatus(UID string) (*cubecontainer.PodStatus,
 }
 
 func (m *cubeRuntimeManager) InspectPod(pod *object.Pod) (*object.PodStatus, error) {
-	containerStatuses, err := m.getContainerStatusesByPodUID(pod.UID)
+	containerStatuses, err := m.getContainerStatusesByPodUID(pod.UID, true)
 	if err != nil {
 		return nil, err
 	}
@@ -283,9 +629,14 @@ func (m *cubeRuntimeManager) InspectPod(pod *object.Pod) (*object.PodStatus, err
 	if len(sandboxStatus) == 0 {
 		return nil, fmt.Errorf("no sandbox for pod %s found", pod.Name)
 	}
+	return m.toAPIPodStatus(pod, containerStatuses, sandboxStatus[0]), nil
+}
 
+// toAPIPodStatus makes status of the pod by its containers and the latest sandbox, IP is not set
+func (m *podSyncHelper) toAPIPodStatus(pod *object.Pod, containerStatuses []*cubecontainer.ContainerStatus,
+	sandboxStatus *cubecontainer.SandboxStatus) *object.PodStatus {
 	var sandboxIP []byte
-	podPhase := cubecontainer.ComputePodPhase(containerStatuses, sandboxStatus[0], &pod.Spec)
+	podPhase := cubecontainer.ComputePodPhase(containerStatuses, sandboxStatus, &pod.Spec)
 
 	usage := &object.ResourceUsage{}
 	for _, status := range containerStatuses {
@@ -293,12 +644,101 @@ func (m *cubeRuntimeManager) InspectPod(pod *object.Pod) (*object.PodStatus, err
 		usage.ActualMemoryUsage += status.ResourceUsage.MemoryUsage
 	}
 
-	return &object.PodStatus{
+	initialized, _ := cubecontainer.InitContainersDone(containerStatuses, &pod.Spec)
+	waitingReason := object.ContainerReasonContainerCreating
+	if !initialized {
+		waitingReason = object.ContainerReasonPodInitializing
+	}
+
+	status := &object.PodStatus{
 		IP:                  sandboxIP,
 		Phase:               podPhase,
 		ActualResourceUsage: usage,
 		LastUpdateTime:      time.Now(),
-	}, nil
+		InitContainerStatuses: m.toAPIContainerStatuses(pod, pod.Spec.InitContainers, containerStatuses,
+			true, object.ContainerReasonPodInitializing),
+		ContainerStatuses: m.toAPIContainerStatuses(pod, pod.Spec.Containers, containerStatuses,
+			false, waitingReason),
+	}
+
+	// conditions set by others are kept, e.g. PodScheduled by scheduler
+	if pod.Status != nil {
+		status.Conditions = append([]object.PodCondition{}, pod.Status.Conditions...)
+	}
+	initializedCondition := object.PodCondition{
+		Type:   object.PodInitialized,
+		Status: object.ConditionTrue,
+	}
+	if !initialized {
+		initializedCondition.Status = object.ConditionFalse
+		initializedCondition.Reason = "ContainersNotInitialized"
+		initializedCondition.Message = "init containers have not completed"
+	}
+	object.SetPodCondition(status, initializedCondition)
+	return status
+}
+
+// toAPIContainerStatuses reports the latest container of each container in spec,
+// those not created yet are waiting with waitingReason
+func (m *podSyncHelper) toAPIContainerStatuses(pod *object.Pod, containers []object.Container,
+	statuses []*cubecontainer.ContainerStatus, init bool, waitingReason string) []object.ContainerStatus {
+	podStatus := &cubecontainer.PodStatus{ContainerStatuses: statuses}
+	result := make([]object.ContainerStatus, 0, len(containers))
+
+	for idx := range containers {
+		container := &containers[idx]
+		policy := pod.Spec.RestartPolicy
+		if init {
+			policy = cubecontainer.InitContainerRestartPolicy(pod.Spec.RestartPolicy, container)
+		}
+
+		apiStatus := object.ContainerStatus{Name: container.Name}
+		latest := podStatus.FindContainerStatusByName(container.Name)
+		imageWaiting := m.imageWaitingState(pod.UID, container.Name)
+		if latest == nil {
+			apiStatus.State.Waiting = &object.ContainerStateWaiting{Reason: waitingReason}
+			if imageWaiting != nil {
+				apiStatus.State.Waiting = imageWaiting
+			}
+			result = append(result, apiStatus)
+			continue
+		}
+
+		apiStatus.ContainerID = latest.ID.ID
+		apiStatus.Image = latest.Image
+		apiStatus.ImageID = latest.ImageID
+		apiStatus.RestartCount = int32(latest.RestartCount)
+		if previous := podStatus.FindPreviousContainerStatus(container.Name); previous != nil &&
+			previous.State == cubecontainer.ContainerStateExited {
+			apiStatus.LastTerminationState.Terminated = toContainerStateTerminated(previous)
+		}
+
+		switch latest.State {
+		case cubecontainer.ContainerStateRunning:
+			apiStatus.State.Running = &object.ContainerStateRunning{StartedAt: latest.StartedAt}
+		case cubecontainer.ContainerStateExited:
+			wait := m.backOff.remaining(backOffKey(pod.UID, container.Name), latest.FinishedAt)
+			if cubecontainer.ShouldContainerBeRestarted(policy, latest) && wait > 0 {
+				apiStatus.State.Waiting = &object.ContainerStateWaiting{
+					Reason: object.ContainerReasonCrashLoopBackOff,
+					Message: fmt.Sprintf("back-off %v restarting failed container %s",
+						wait.Round(time.Second), container.Name),
+				}
+				apiStatus.LastTerminationState.Terminated = toContainerStateTerminated(latest)
+			} else {
+				apiStatus.State.Terminated = toContainerStateTerminated(latest)
+			}
+			if imageWaiting != nil && apiStatus.State.Terminated == nil {
+				// waiting for image to restart
+				apiStatus.State.Waiting = imageWaiting
+			}
+		default:
+			apiStatus.State.Waiting = &object.ContainerStateWaiting{Reason: object.ContainerReasonContainerCreating}
+		}
+		result = append(result, apiStatus)
+	}
+
+	return result
 }
 
 func (m *cubeRuntimeManager) ListPodsUID() ([]string, error) {
@@ -306,7 +746,7 @@ func (m *cubeRuntimeManager) ListPodsUID() ([]string, error) {
 }
 
 func (c *cubeRuntimeManager) getPodStatusByUID(UID string) (*cubecontainer.PodStatus, error) {
-	containerStatuses, err := c.getContainerStatusesByPodUID(UID)
+	containerStatuses, err := c.getContainerStatusesByPodUID(UID, false)
 	if err != nil {
 		return nil, err
 	}
@@ -316,39 +756,101 @@ func (c *cubeRuntimeManager) getPodStatusByUID(UID string) (*cubecontainer.PodSt
 		return nil, err
 	}
 
+	if len(sandboxStatuses) == 0 || sandboxStatuses[0].State != cubecontainer.SandboxStateReady {
+		// the sandbox is gone or exited, its IP may be given to others
+		c.forgetSandboxIP(UID)
+	}
 	if len(containerStatuses) == 0 && len(sandboxStatuses) == 0 {
 		// both empty: pod not exists
 		return &cubecontainer.PodStatus{}, nil
 	}
 
 	podName := ""
+	var podIP net.IP
 	if len(sandboxStatuses) > 0 {
 		podName = sandboxStatuses[0].Name
+		// docker does not know IPs in weave network, without it the sandbox is re-created on every sync
+		if sandboxStatuses[0].State == cubecontainer.SandboxStateReady && sandboxStatuses[0].HostNetwork {
+			podIP = c.nodeIP
+			if podIP != nil {
+				sandboxStatuses[0].Ip = podIP.String()
+			}
+		} else if sandboxStatuses[0].State == cubecontainer.SandboxStateReady {
+			if ip, err := c.getSandboxIP(UID, sandboxStatuses[0].Id); err == nil && ip != nil {
+				podIP = ip
+				sandboxStatuses[0].Ip = ip.String()
+			}
+		}
 	}
 
 	return &cubecontainer.PodStatus{
-		UID:              UID,
-		Name:             podName,
-		NetworkNamespace: "/var/run/netns/default",
-		// Update PodNetworkStatus?
+		UID:               UID,
+		Name:              podName,
+		NetworkNamespace:  "/var/run/netns/default",
+		PodNetWork:        cubecontainer.PodNetworkStatus{IP: podIP},
 		ContainerStatuses: containerStatuses,
 		SandboxStatuses:   sandboxStatuses,
 	}, nil
 }
 
+// NewRuntimeManager creates the runtime manager of the container runtime selected
+func NewRuntimeManager(opts options.RuntimeOptions) (CubeRuntime, error) {
+	var runtime CubeRuntime
+	var err error
+	switch opts.ContainerRuntime {
+	case "", options.DockerContainerRuntime:
+		runtime, err = NewCubeRuntimeManager()
+	case options.RemoteContainerRuntime:
+		imageEndpoint := opts.RemoteImageEndpoint
+		if imageEndpoint == "" {
+			imageEndpoint = opts.RemoteRuntimeEndpoint
+		}
+		runtime, err = NewCRIRuntimeManager(opts.RemoteRuntimeEndpoint, imageEndpoint, opts.CgroupDriver)
+	default:
+		return nil, fmt.Errorf("unknown container runtime %s", opts.ContainerRuntime)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// weave DNS and the default cluster domain are used if not specified
+	if len(opts.ClusterDNS) != 0 || opts.ClusterDomain != "" {
+		runtime.SetClusterDNS(opts.ClusterDNS, opts.ClusterDomain)
+	}
+	return runtime, nil
+}
+
 func NewCubeRuntimeManager() (CubeRuntime, error) {
 	dockerRuntime, err := dockershim.NewDockerRuntime()
 	if err != nil {
 		log.Println("Fail to create docker client")
 	}
 
-	cm := &cubeRuntimeManager{
+	return NewCubeRuntimeManagerWithDocker(dockerRuntime, weaveplugins.GetPodIPByID, crudobj.UpdatePod), nil
+}
+
+// NewCubeRuntimeManagerWithDocker creates the runtime manager on dockerRuntime, IPs of pods are got by getPodIP
+// and status of pods is written by updatePod, so that pods can be synced without weave and apiserver in tests
+func NewCubeRuntimeManagerWithDocker(dockerRuntime dockershim.DockerRuntime, getPodIP func(sandboxID string) (net.IP, error),
+	updatePod func(pod object.Pod) (object.Pod, error)) CubeRuntime {
+	cgroupDriver := cgroup.CgroupfsDriver
+	if dockerRuntime != nil {
+		if driver, err := dockerRuntime.GetCgroupDriver(); err == nil && driver != "" {
+			cgroupDriver = driver
+		}
+	}
+
+	helper := newPodSyncHelper(&dockerImageService{dockerRuntime}, cgroupDriver)
+	helper.updatePod = updatePod
+	return &cubeRuntimeManager{
+		podSyncHelper: helper,
 		dockerRuntime: dockerRuntime,
 		cpuStatsCache: cache.NewCpuStatsCache(),
 		runtimeName:   containerdRuntimeName,
+		getPodIP:      getPodIP,
+		sandboxIPs:    make(map[string]sandboxIP),
+		hostports:     hostport.NewManager(),
 	}
-
-	return cm, nil
 }
 
 func (c *cubeRuntimeManager) Close() {
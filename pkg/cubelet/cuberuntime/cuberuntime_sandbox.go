@@ -4,29 +4,29 @@ import (
 	cubecontainer "Cubernetes/pkg/cubelet/container"
 	"Cubernetes/pkg/cubelet/cuberuntime/options"
 	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/cubelet/network/dns"
 	"Cubernetes/pkg/object"
+	"io/ioutil"
 	"log"
-	"strconv"
+	"net"
 
 	dockertypes "github.com/docker/docker/api/types"
 	dockercontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	dockernat "github.com/docker/go-connections/nat"
-)
-
-const (
-	defaultPauseImage = "docker/desktop-kubernetes-pause:3.7"
 )
 
 // sandboxName, sandboxID, err
 func (m *cubeRuntimeManager) createPodSandbox(pod *object.Pod) (string, string, error) {
-	// err := m.dockerRuntime.PullImage(defaultPauseImage)
-	// if err != nil {
-	// 	log.Printf("ensure image for sandbox of pod %s failed\n", pod.Name)
-	// 	return "", "", err
-	// }
+	if image, err := m.dockerRuntime.InspectImage(options.PauseImage); err != nil || image == nil {
+		if err = m.dockerRuntime.PullImage(options.PauseImage); err != nil {
+			log.Printf("ensure image for sandbox of pod %s failed\n", pod.Name)
+			return "", "", err
+		}
+	}
 
-	podSandboxConfig := generatePodSandboxConfig(pod)
+	dnsConfig := m.dnsConfigurer.GetPodDNS(pod)
+	podSandboxConfig := generatePodSandboxConfig(pod, dnsConfig)
+	podSandboxConfig.HostConfig.CgroupParent = m.podCgroups.GetPodCgroupParent(pod)
 	log.Println("creating sandbox...")
 	sandboxID, err := m.dockerRuntime.CreateContainer(podSandboxConfig)
 	if err != nil {
@@ -40,11 +40,22 @@ func (m *cubeRuntimeManager) createPodSandbox(pod *object.Pod) (string, string,
 		return "", "", err
 	}
 
+	// containers share resolv.conf of the sandbox
+	if err = m.writeResolvConf(sandboxID, dnsConfig); err != nil {
+		log.Printf("[Error]: fail to write resolv.conf of pod %s: %v\n", pod.Name, err)
+	}
+
+	// the pod cgroup is created by docker along with the sandbox
+	if err = m.podCgroups.UpdatePodCgroup(pod); err != nil {
+		log.Printf("[Error]: fail to limit cgroup of pod %s: %v\n", pod.Name, err)
+	}
+
 	return podSandboxConfig.Name, sandboxID, nil
 }
 
 func (m *cubeRuntimeManager) getSandboxStatusesByPodUID(UID string) ([]*cubecontainer.SandboxStatus, error) {
 	filter := dockertypes.ContainerListOptions{
+		All: true,
 		Filters: filters.NewArgs(
 			filters.Arg("label", buildLabelSelector(ContainerTypeLabel, ContainerTypeSandbox)),
 			filters.Arg("label", buildLabelSelector(PodUIDLabel, UID)),
@@ -69,64 +80,53 @@ func (m *cubeRuntimeManager) getSandboxStatusesByPodUID(UID string) ([]*cubecont
 	return statuses, nil
 }
 
-func generatePodSandboxConfig(pod *object.Pod) *dockertypes.ContainerCreateConfig {
-	sandboxName := dockershim.MakeSandboxName(pod)
-
-	exposedPorts := dockernat.PortSet{}
-	portBindings := map[dockernat.Port][]dockernat.PortBinding{}
-
-	// port bindings
-	for _, c := range pod.Spec.Containers {
-		for _, p := range c.Ports {
-			exteriorPort := p.HostPort
-			if exteriorPort == 0 {
-				// No need to do port binding when HostPort is not specified
-				continue
-			}
-			interiorPort := p.ContainerPort
-			dockerPort := dockernat.Port(
-				strconv.Itoa(int(interiorPort)) +
-					toPortProtocol(p.Protocol))
-			exposedPorts[dockerPort] = struct{}{}
-
-			hostBinding := dockernat.PortBinding{
-				HostPort: strconv.Itoa(int(exteriorPort)),
-				HostIP:   p.HostIP,
-			}
-			// Allow multiple host ports bind to same docker port
-			if existedBindings, ok := portBindings[dockerPort]; ok {
-				// If a docker port already map to a host port, just append the host ports
-				portBindings[dockerPort] = append(existedBindings, hostBinding)
-			} else {
-				// Otherwise, it's fresh new port binding
-				portBindings[dockerPort] = []dockernat.PortBinding{
-					hostBinding,
-				}
-			}
-		}
+// writeResolvConf replaces the resolv.conf docker made for the sandbox, which points to the embedded
+// DNS of docker in weave network
+func (m *cubeRuntimeManager) writeResolvConf(sandboxID string, dnsConfig *dns.Config) error {
+	info, err := m.dockerRuntime.InspectContainer(sandboxID)
+	if err != nil {
+		return err
+	}
+	if info.ContainerJSONBase == nil || info.ResolvConfPath == "" {
+		log.Printf("[WARNING]: sandbox %s has no resolv.conf\n", sandboxID)
+		return nil
 	}
+	return ioutil.WriteFile(info.ResolvConfPath, dnsConfig.ResolvConf(), 0644)
+}
+
+func generatePodSandboxConfig(pod *object.Pod, dnsConfig *dns.Config) *dockertypes.ContainerCreateConfig {
+	sandboxName := dockershim.MakeSandboxName(pod)
 
+	// ports are not published by docker, which doesn't do it in weave network,
+	// host ports are forwarded to the pod by hostport manager instead
 	sandboxConfig := &dockertypes.ContainerCreateConfig{
 		Name: sandboxName,
 		Config: &dockercontainer.Config{
-			Image:        defaultPauseImage,
-			ExposedPorts: exposedPorts,
-			Labels:       newSandboxLabels(pod),
+			Image:  options.PauseImage,
+			Labels: newSandboxLabels(pod),
 		},
 		HostConfig: &dockercontainer.HostConfig{
-			IpcMode:      dockercontainer.IpcMode("shareable"),
-			PortBindings: portBindings,
-			DNS:          []string{options.WeaveDNSServer},
-			DNSSearch:    []string{options.WeaveDNSSearchDomain},
-			NetworkMode:  options.WeaveNetwork,
+			IpcMode:     dockercontainer.IpcMode("shareable"),
+			DNS:         dnsConfig.Servers,
+			DNSSearch:   dnsConfig.Searches,
+			DNSOptions:  dnsConfig.Options,
+			NetworkMode: options.WeaveNetwork,
 		},
 	}
+	if pod.Spec.HostNetwork {
+		// containers listen on ports of the node directly, resolv.conf is written after the sandbox starts
+		sandboxConfig.HostConfig.NetworkMode = "host"
+		sandboxConfig.HostConfig.DNS = nil
+		sandboxConfig.HostConfig.DNSSearch = nil
+		sandboxConfig.HostConfig.DNSOptions = nil
+	}
 
 	return sandboxConfig
 }
 
 func (m *cubeRuntimeManager) getAllPodsUID() ([]string, error) {
 	filter := dockertypes.ContainerListOptions{
+		All: true,
 		Filters: filters.NewArgs(
 			filters.Arg("label", buildLabelSelector(ContainerTypeLabel, ContainerTypeSandbox)),
 		),
@@ -155,3 +155,35 @@ func (m *cubeRuntimeManager) getAllPodsUID() ([]string, error) {
 
 	return uids, nil
 }
+
+type sandboxIP struct {
+	sandboxID string
+	ip        net.IP
+}
+
+// getSandboxIP returns IP of the ready sandbox of the pod in weave network,
+// it is cached since getPodIP forks weave
+func (m *cubeRuntimeManager) getSandboxIP(podUID, sandboxID string) (net.IP, error) {
+	m.sandboxIPLock.Lock()
+	cached, ok := m.sandboxIPs[podUID]
+	m.sandboxIPLock.Unlock()
+	if ok && cached.sandboxID == sandboxID {
+		return cached.ip, nil
+	}
+
+	ip, err := m.getPodIP(sandboxID)
+	if err != nil || ip == nil {
+		return ip, err
+	}
+	m.sandboxIPLock.Lock()
+	m.sandboxIPs[podUID] = sandboxIP{sandboxID: sandboxID, ip: ip}
+	m.sandboxIPLock.Unlock()
+	return ip, nil
+}
+
+// forgetSandboxIP is called when the sandbox of the pod is stopped or gone, and its IP is released
+func (m *cubeRuntimeManager) forgetSandboxIP(podUID string) {
+	m.sandboxIPLock.Lock()
+	defer m.sandboxIPLock.Unlock()
+	delete(m.sandboxIPs, podUID)
+}
@@ -0,0 +1,193 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerapi "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// the remote process may still be seen running for a moment after its stdio is closed
+const exitStatusWait = time.Second
+
+func (m *cubeRuntimeManager) GetContainerLogs(ctx context.Context, podUID string, opts object.LogOptions,
+	stdout, stderr io.Writer) error {
+	containers, err := m.dockerRuntime.ListContainers(dockertypes.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", ContainerTypeLabel+"="+ContainerTypeContainer),
+			filters.Arg("label", PodUIDLabel+"="+podUID),
+			filters.Arg("label", ContainerNameLabel+"="+opts.Container),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the latest instance first
+	sort.Slice(containers, func(i, j int) bool {
+		return restartCountOf(containers[i].Labels) > restartCountOf(containers[j].Labels)
+	})
+	index, err := logInstanceIndex(len(containers), opts.Previous)
+	if err != nil {
+		return err
+	}
+
+	err = dockershim.WriteContainerLogs(ctx, m.dockerRuntime, containers[index].ID, opts, stdout, stderr)
+	if dockerapi.IsErrNotFound(err) {
+		return cubecontainer.ErrContainerNotFound
+	}
+	return err
+}
+
+func (m *cubeRuntimeManager) ExecInContainer(ctx context.Context, containerID string, cmd []string,
+	streams cubecontainer.Streams) (int, error) {
+	execID, hijacked, err := m.dockerRuntime.StartExecStream(containerID, cmd, streams.Stdin != nil, streams.TTY)
+	if err != nil {
+		return -1, fmt.Errorf("fail to exec in container: %v", err)
+	}
+	defer hijacked.Close()
+
+	streamHijacked(ctx, hijacked, streams, streams.TTY, func(size remotecommand.TerminalSize) error {
+		return m.dockerRuntime.ResizeExec(execID, uint(size.Height), uint(size.Width))
+	})
+
+	for start := time.Now(); time.Since(start) < exitStatusWait; time.Sleep(time.Millisecond * 100) {
+		inspect, err := m.dockerRuntime.InspectExec(execID)
+		if err != nil {
+			return -1, errors.New("fail to inspect exec")
+		}
+		if !inspect.Running {
+			return inspect.ExitCode, nil
+		}
+	}
+	return -1, errors.New("exec is still running")
+}
+
+func (m *cubeRuntimeManager) AttachContainer(ctx context.Context, containerID string,
+	streams cubecontainer.Streams) (int, error) {
+	inspect, err := m.dockerRuntime.InspectContainer(containerID)
+	if err != nil {
+		return -1, errors.New("fail to inspect container")
+	}
+	// tty is decided when the container is created
+	tty := inspect.Config != nil && inspect.Config.Tty
+	if streams.Stdin != nil && (inspect.Config == nil || !inspect.Config.OpenStdin) {
+		return -1, errors.New("container doesn't keep stdin open")
+	}
+
+	hijacked, err := m.dockerRuntime.AttachContainer(containerID, streams.Stdin != nil)
+	if err != nil {
+		return -1, fmt.Errorf("fail to attach container: %v", err)
+	}
+	defer hijacked.Close()
+
+	streamHijacked(ctx, hijacked, streams, tty, func(size remotecommand.TerminalSize) error {
+		return m.dockerRuntime.ResizeContainer(containerID, uint(size.Height), uint(size.Width))
+	})
+
+	for start := time.Now(); time.Since(start) < exitStatusWait; time.Sleep(time.Millisecond * 100) {
+		inspect, err = m.dockerRuntime.InspectContainer(containerID)
+		if err != nil {
+			return -1, errors.New("fail to inspect container")
+		}
+		if !inspect.State.Running {
+			return inspect.State.ExitCode, nil
+		}
+	}
+	return -1, errors.New("container is still running")
+}
+
+func (m *cubeRuntimeManager) PortForward(podUID string, port int) (io.ReadWriteCloser, error) {
+	sandboxes, err := m.dockerRuntime.ListContainers(dockertypes.ContainerListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", ContainerTypeLabel+"="+ContainerTypeSandbox),
+			filters.Arg("label", PodUIDLabel+"="+podUID),
+			filters.Arg("status", "running"),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sandboxes) == 0 {
+		return nil, cubecontainer.ErrSandboxNotRunning
+	}
+	inspect, err := m.dockerRuntime.InspectContainer(sandboxes[0].ID)
+	if err != nil || inspect.State == nil || inspect.State.Pid == 0 {
+		return nil, errors.New("fail to inspect pod sandbox")
+	}
+
+	return dialInNetNS(inspect.State.Pid, port)
+}
+
+// streamHijacked copies stdin to the hijacked process and its output to streams,
+// until the output ends or ctx is done
+func streamHijacked(ctx context.Context, hijacked dockertypes.HijackedResponse, streams cubecontainer.Streams,
+	tty bool, resize func(size remotecommand.TerminalSize) error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// stop copying output as well
+			hijacked.Close()
+		case <-done:
+		}
+	}()
+
+	if streams.Stdin != nil {
+		go func() {
+			// EOF of stdin is passed on to the process
+			if _, err := io.Copy(hijacked.Conn, streams.Stdin); err == nil {
+				_ = hijacked.CloseWrite()
+			}
+		}()
+	}
+	if streams.Resize != nil {
+		go func() {
+			for size := range streams.Resize {
+				if tty {
+					_ = resize(size)
+				}
+			}
+		}()
+	}
+
+	if tty {
+		_, _ = io.Copy(streams.Stdout, hijacked.Reader)
+	} else {
+		_, _ = stdcopy.StdCopy(streams.Stdout, streams.Stderr, hijacked.Reader)
+	}
+}
+
+func restartCountOf(labels map[string]string) int {
+	count, _ := strconv.Atoi(labels[ContainerRestartCountLabel])
+	return count
+}
+
+// logInstanceIndex returns the index of the instance whose logs are read among count instances
+// of a container, which are sorted latest first
+func logInstanceIndex(count int, previous bool) (int, error) {
+	if previous {
+		if count < 2 {
+			return 0, cubecontainer.ErrPreviousContainerNotFound
+		}
+		return 1, nil
+	}
+	if count == 0 {
+		return 0, cubecontainer.ErrContainerNotFound
+	}
+	return 0, nil
+}
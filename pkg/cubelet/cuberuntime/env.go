@@ -0,0 +1,48 @@
+package cuberuntime
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"net"
+)
+
+// makeEnvContext fetches objects env of the pod refers to, so that
+// changes of config maps and secrets are applied when containers are re-created
+func (m *podSyncHelper) makeEnvContext(pod *object.Pod, podIP net.IP) (*cubecontainer.EnvContext, error) {
+	ctx := &cubecontainer.EnvContext{
+		ConfigMaps: make(map[string]*object.ConfigMap),
+		Secrets:    make(map[string]*object.Secret),
+	}
+	if podIP != nil {
+		ctx.PodIP = podIP.String()
+	}
+
+	if pod.Status != nil && pod.Status.NodeUID != "" {
+		if node, err := crudobj.GetNode(pod.Status.NodeUID); err == nil {
+			ctx.NodeName = node.Name
+		}
+	}
+
+	needConfigMaps, needSecrets := cubecontainer.NeedsEnvObjects(pod)
+	if needConfigMaps {
+		configMaps, err := crudobj.GetConfigMaps()
+		if err != nil {
+			return nil, err
+		}
+		for idx := range configMaps {
+			ctx.ConfigMaps[configMaps[idx].Name] = &configMaps[idx]
+		}
+	}
+	if needSecrets {
+		secrets, err := crudobj.GetSecrets()
+		if err != nil {
+			return nil, err
+		}
+		for idx := range secrets {
+			ctx.Secrets[secrets[idx].Name] = &secrets[idx]
+		}
+	}
+
+	return ctx, nil
+}
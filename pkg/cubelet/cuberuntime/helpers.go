@@ -2,7 +2,6 @@ package cuberuntime
 
 import (
 	"Cubernetes/pkg/object"
-	"log"
 	"path/filepath"
 	"strings"
 )
@@ -12,27 +11,13 @@ func BuildPodLogsDirectory(podNamespace, podName, podUID string) string {
 		strings.Join([]string{podNamespace, podName, podUID}, "_"))
 }
 
-func toPortProtocol(protocol string) string {
-	switch protocol {
-	case "TCP":
-		return "/tcp"
-	case "UDP":
-		return "/udp"
-	case "SCTP":
-		return "/sctp"
-	}
-
-	log.Printf("Unknown protocol%s, defaulting to TCP.\n", protocol)
-	return "/tcp"
-}
-
-func findVolumeHostPath(pod *object.Pod, name string) string {
-	for _, volume := range pod.Spec.Volumes {
-		if volume.Name == name {
-			return volume.HostPath
+func findVolume(pod *object.Pod, name string) *object.Volume {
+	for idx := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[idx].Name == name {
+			return &pod.Spec.Volumes[idx]
 		}
 	}
-	return ""
+	return nil
 }
 
 func buildLabelSelector(label, value string) string {
@@ -0,0 +1,176 @@
+package cuberuntime
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/cubelet/images"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+)
+
+// imageService is how images are looked up and pulled by the container runtime
+type imageService interface {
+	// imagePresent tells whether the image is on the node
+	imagePresent(image string) bool
+	// pullImage calls progress with messages of the pull in progress
+	pullImage(image string, auth *dockertypes.AuthConfig, progress func(string)) error
+}
+
+type dockerImageService struct {
+	dockerRuntime dockershim.DockerRuntime
+}
+
+func (s *dockerImageService) imagePresent(image string) bool {
+	inspect, err := s.dockerRuntime.InspectImage(image)
+	return err == nil && inspect != nil
+}
+
+func (s *dockerImageService) pullImage(image string, auth *dockertypes.AuthConfig, progress func(string)) error {
+	return s.dockerRuntime.PullImageWithAuth(image, auth, progress)
+}
+
+// pullState is how the image of a container is being pulled, keyed by pod UID and container name
+type pullState struct {
+	pulling bool
+	// pulled is consumed when the container is started
+	pulled   bool
+	failedAt time.Time
+	// waiting is reported as state of the container
+	waiting object.ContainerStateWaiting
+}
+
+// ensureImage returns true if the container can be started with its image,
+// otherwise the image is pulled in background, and the container waits till next sync
+func (m *podSyncHelper) ensureImage(pod *object.Pod, container *object.Container) bool {
+	key := backOffKey(pod.UID, container.Name)
+	policy := container.ImagePullPolicy
+	if policy == "" {
+		policy = object.DefaultImagePullPolicy(container.Image)
+	}
+
+	m.pullLock.Lock()
+	defer m.pullLock.Unlock()
+
+	state := m.pulls[key]
+	if state != nil && state.pulling {
+		return false
+	}
+	if state != nil && state.pulled {
+		delete(m.pulls, key)
+		return true
+	}
+
+	if policy != object.PullAlways {
+		if m.images.imagePresent(container.Image) {
+			delete(m.pulls, key)
+			return true
+		}
+		if policy == object.PullNever {
+			m.pulls[key] = &pullState{waiting: object.ContainerStateWaiting{
+				Reason:  object.ContainerReasonErrImageNeverPull,
+				Message: fmt.Sprintf("image %s is not present with pull policy Never", container.Image),
+			}}
+			return false
+		}
+	}
+
+	if state != nil && !state.failedAt.IsZero() {
+		if wait := m.pullBackOff.remaining(key, state.failedAt); wait > 0 {
+			state.waiting.Reason = object.ContainerReasonImagePullBackOff
+			return false
+		}
+	}
+
+	state = &pullState{pulling: true, waiting: object.ContainerStateWaiting{
+		Reason:  object.ContainerReasonContainerCreating,
+		Message: fmt.Sprintf("pulling image %s", container.Image),
+	}}
+	m.pulls[key] = state
+	go m.pullImage(pod, container.Image, key, state)
+	return false
+}
+
+func (m *podSyncHelper) pullImage(pod *object.Pod, image string, key string, state *pullState) {
+	log.Printf("[INFO]: pulling image %s for pod %s\n", image, pod.Name)
+	auth, err := m.imagePullAuth(pod, image)
+	if err == nil {
+		err = m.images.pullImage(image, auth, func(progress string) {
+			m.pullLock.Lock()
+			defer m.pullLock.Unlock()
+			state.waiting.Message = fmt.Sprintf("pulling image %s: %s", image, progress)
+		})
+	}
+
+	m.pullLock.Lock()
+	defer m.pullLock.Unlock()
+	state.pulling = false
+	if err != nil {
+		log.Printf("[Error]: fail to pull image %s for pod %s: %v\n", image, pod.Name, err)
+		m.pullBackOff.next(key)
+		state.failedAt = time.Now()
+		state.waiting = object.ContainerStateWaiting{
+			Reason:  object.ContainerReasonErrImagePull,
+			Message: fmt.Sprintf("fail to pull image %s: %v", image, err),
+		}
+		return
+	}
+	log.Printf("[INFO]: image %s pulled for pod %s\n", image, pod.Name)
+	state.pulled = true
+}
+
+// imagePullAuth looks up credentials of the registry of image in imagePullSecrets of the pod
+func (m *podSyncHelper) imagePullAuth(pod *object.Pod, image string) (*dockertypes.AuthConfig, error) {
+	if len(pod.Spec.ImagePullSecrets) == 0 {
+		return nil, nil
+	}
+	all, err := crudobj.GetSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*object.Secret, 0)
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		found := false
+		for idx := range all {
+			if all[idx].Name == ref.Name {
+				secrets = append(secrets, &all[idx])
+				found = true
+			}
+		}
+		if !found && !ref.Optional {
+			return nil, fmt.Errorf("image pull secret %s not found", ref.Name)
+		}
+	}
+	return images.LookupAuth(image, secrets), nil
+}
+
+// imageWaitingState returns nil if the image of the container is not being pulled, or failed
+func (m *podSyncHelper) imageWaitingState(podUID, containerName string) *object.ContainerStateWaiting {
+	m.pullLock.Lock()
+	defer m.pullLock.Unlock()
+
+	state, ok := m.pulls[backOffKey(podUID, containerName)]
+	if !ok || state.pulled {
+		return nil
+	}
+	waiting := state.waiting
+	return &waiting
+}
+
+// forgetPulls removes pull states of a pod, pulls in progress go on
+func (m *podSyncHelper) forgetPulls(podUID string) {
+	m.pullLock.Lock()
+	defer m.pullLock.Unlock()
+
+	for key := range m.pulls {
+		if strings.HasPrefix(key, podUID+"/") {
+			delete(m.pulls, key)
+		}
+	}
+	m.pullBackOff.forget(podUID)
+}
@@ -1,6 +1,9 @@
 package cuberuntime
 
-import "Cubernetes/pkg/object"
+import (
+	"Cubernetes/pkg/object"
+	"strconv"
+)
 
 const (
 	PodNameLabel       = "cubernetes.pod.name"
@@ -8,12 +11,22 @@ const (
 	PodUIDLabel        = "cubernetes.pod.uid"
 	ContainerNameLabel = "cubernetes.container.name"
 	ContainerTypeLabel = "cubernetes.container.types"
+	// ContainerRestartCountLabel is the number of containers with the same name created before
+	ContainerRestartCountLabel = "cubernetes.container.restartCount"
+	// ContainerSidecarLabel is set to "true" for sidecar containers
+	ContainerSidecarLabel = "cubernetes.container.sidecar"
+	// ContainerTTYLabel and ContainerStdinLabel are set to "true" for containers with a TTY or stdin kept open,
+	// which CRI runtimes don't report but attach needs
+	ContainerTTYLabel   = "cubernetes.container.tty"
+	ContainerStdinLabel = "cubernetes.container.stdin"
+	// SandboxHostNetworkLabel is set to "true" for sandboxes in the network namespace of the node
+	SandboxHostNetworkLabel = "cubernetes.sandbox.hostNetwork"
 
 	ContainerTypeContainer = "container"
 	ContainerTypeSandbox   = "sandbox"
 )
 
-func newContainerLabels(container *object.Container, pod *object.Pod) map[string]string {
+func newContainerLabels(container *object.Container, pod *object.Pod, restartCount int) map[string]string {
 	labels := map[string]string{}
 	labels[PodNameLabel] = pod.Name
 	labels[PodNameSpaceLabel] = pod.Namespace
@@ -21,6 +34,16 @@ func newContainerLabels(container *object.Container, pod *object.Pod) map[string
 
 	labels[ContainerNameLabel] = container.Name
 	labels[ContainerTypeLabel] = ContainerTypeContainer
+	labels[ContainerRestartCountLabel] = strconv.Itoa(restartCount)
+	if object.IsSidecarContainer(container) {
+		labels[ContainerSidecarLabel] = "true"
+	}
+	if container.TTY {
+		labels[ContainerTTYLabel] = "true"
+	}
+	if container.Stdin {
+		labels[ContainerStdinLabel] = "true"
+	}
 
 	return labels
 }
@@ -36,6 +59,9 @@ func newSandboxLabels(pod *object.Pod) map[string]string {
 	labels[PodUIDLabel] = pod.UID
 
 	labels[ContainerTypeLabel] = ContainerTypeSandbox
+	if pod.Spec.HostNetwork {
+		labels[SandboxHostNetworkLabel] = "true"
+	}
 
 	return labels
 }
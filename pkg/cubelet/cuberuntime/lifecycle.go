@@ -0,0 +1,145 @@
+package cuberuntime
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minStopGracePeriod is given to containers after preStop hooks use up the grace period
+const minStopGracePeriod = time.Second * 2
+
+// runLifecycleHandler runs the hook in the container, HTTP hooks are sent to podIP if host is not set
+func (m *cubeRuntimeManager) runLifecycleHandler(containerID string, handler *object.LifecycleHandler,
+	podIP string, timeout time.Duration) error {
+	switch {
+	case handler.Exec != nil:
+		code, output, err := m.dockerRuntime.ExecInContainer(containerID, handler.Exec.Command, timeout)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("exit code %d: %s", code, strings.TrimSpace(string(output)))
+		}
+		return nil
+	case handler.HTTPGet != nil:
+		return runHTTPHandler(handler.HTTPGet, podIP, timeout)
+	}
+	return fmt.Errorf("no action in lifecycle handler")
+}
+
+func runHTTPHandler(action *object.HTTPGetAction, podIP string, timeout time.Duration) error {
+	host := action.Host
+	if host == "" {
+		host = podIP
+	}
+	scheme := strings.ToLower(action.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := action.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	target := scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(action.Port))) + path
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http hook %s returns %d", target, resp.StatusCode)
+	}
+	return nil
+}
+
+// TerminatePod runs preStop hooks and stops containers within gracePeriod, sidecars are
+// stopped after other containers. Containers and sandbox of the pod are removed then
+func (m *cubeRuntimeManager) TerminatePod(pod *object.Pod, gracePeriod time.Duration) error {
+	log.Printf("Terminate pod %s, grace period %v\n", pod.Name, gracePeriod)
+	podStatus, err := m.getPodStatusByUID(pod.UID)
+	if err != nil {
+		log.Printf("fail to get podStatus by UID %s\n", pod.UID)
+		return err
+	}
+
+	podIP := ""
+	if pod.Status != nil && pod.Status.IP != nil {
+		podIP = pod.Status.IP.String()
+	}
+	deadline := time.Now().Add(gracePeriod)
+	containers, sidecars := splitSidecars(podStatus.ContainerStatuses)
+	m.stopContainers(pod, containers, podIP, deadline)
+	m.stopContainers(pod, sidecars, podIP, deadline)
+
+	m.backOff.forget(pod.UID)
+	m.forgetPulls(pod.UID)
+	if err = m.killPodByStatus(podStatus, true); err != nil {
+		return err
+	}
+	return m.cleanupPod(pod.UID)
+}
+
+// stopContainers stops running containers in parallel, each runs its preStop hook first
+func (m *cubeRuntimeManager) stopContainers(pod *object.Pod, statuses []*cubecontainer.ContainerStatus,
+	podIP string, deadline time.Time) {
+	wg := sync.WaitGroup{}
+	for _, status := range statuses {
+		if status.State != cubecontainer.ContainerStateRunning {
+			continue
+		}
+
+		wg.Add(1)
+		go func(status *cubecontainer.ContainerStatus) {
+			defer wg.Done()
+			container := findContainerSpec(pod, status.Name)
+			if container != nil && container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+				err := m.runLifecycleHandler(status.ID.ID, container.Lifecycle.PreStop, podIP, time.Until(deadline))
+				if err != nil {
+					log.Printf("[Error]: preStop hook of container %s failed: %v\n", status.Name, err)
+				}
+			}
+
+			gracePeriod := time.Until(deadline)
+			if gracePeriod < minStopGracePeriod {
+				gracePeriod = minStopGracePeriod
+			}
+			if err := m.dockerRuntime.StopContainerWithGracePeriod(status.ID.ID, gracePeriod); err != nil {
+				log.Printf("[Error]: fail to stop container %s: %v\n", status.Name, err)
+			}
+		}(status)
+	}
+	wg.Wait()
+}
+
+// findContainerSpec looks up init containers and containers of the pod by name
+func findContainerSpec(pod *object.Pod, name string) *object.Container {
+	for idx := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[idx].Name == name {
+			return &pod.Spec.InitContainers[idx]
+		}
+	}
+	for idx := range pod.Spec.Containers {
+		if pod.Spec.Containers[idx].Name == name {
+			return &pod.Spec.Containers[idx]
+		}
+	}
+	return nil
+}
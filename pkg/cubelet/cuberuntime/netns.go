@@ -0,0 +1,53 @@
+package cuberuntime
+
+import (
+	"fmt"
+	"golang.org/x/sys/unix"
+	"log"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+const portForwardDialTimeout = time.Second * 5
+
+// dialInNetNS connects to localhost:port in the network namespace of process pid,
+// the socket stays in that namespace after the thread switches back
+func dialInNetNS(pid int, port int) (net.Conn, error) {
+	runtime.LockOSThread()
+
+	origin, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+	defer func() { _ = origin.Close() }()
+
+	target, err := os.Open(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+	defer func() { _ = target.Close() }()
+
+	if err = unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+		runtime.UnlockOSThread()
+		return nil, err
+	}
+
+	conn, dialErr := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), portForwardDialTimeout)
+
+	if err = unix.Setns(int(origin.Fd()), unix.CLONE_NEWNET); err != nil {
+		// keep the thread locked, so that it exits with the goroutine instead of serving others in the pod namespace
+		log.Printf("[Error]: fail to switch back network namespace: %v\n", err)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, err
+	}
+	runtime.UnlockOSThread()
+
+	return conn, dialErr
+}
@@ -1,7 +1,57 @@
 package options
 
+import (
+	weaveoption "Cubernetes/pkg/cubenetwork/weaveplugins/option"
+	"time"
+)
+
 const (
 	WeaveDNSServer       = "172.17.0.1"
-	WeaveDNSSearchDomain = "weave.local"
+	WeaveDNSSearchDomain = weaveoption.DNSDomain
 	WeaveNetwork         = "weave"
 )
+
+// DefaultClusterDomain is the domain of services, resolved by the cluster DNS as <svc>.<ns>.svc.<domain>,
+// weave DNS is launched to serve it
+const DefaultClusterDomain = weaveoption.DNSDomain
+
+const (
+	// CrashLoopInitialBackOff is how long an exited container waits before its second restart,
+	// it is doubled for each restart after that, up to CrashLoopMaxBackOff
+	CrashLoopInitialBackOff = time.Second * 10
+	CrashLoopMaxBackOff     = time.Minute * 5
+)
+
+const (
+	// ImagePullInitialBackOff is how long to wait before pulling a failed image again,
+	// doubled for each failure up to ImagePullMaxBackOff
+	ImagePullInitialBackOff = time.Second * 10
+	ImagePullMaxBackOff     = time.Minute * 5
+)
+
+// PauseImage is the image of sandboxes, it is pulled if not present
+const PauseImage = "docker/desktop-kubernetes-pause:3.7"
+
+// PostStartHookTimeout limits postStart hooks, preStop hooks are limited by termination grace period
+const PostStartHookTimeout = time.Second * 30
+
+const (
+	// DockerContainerRuntime runs pods by docker directly
+	DockerContainerRuntime = "docker"
+	// RemoteContainerRuntime runs pods by a CRI runtime, e.g. containerd or CRI-O
+	RemoteContainerRuntime = "remote"
+)
+
+// RuntimeOptions selects the container runtime of cubelet
+type RuntimeOptions struct {
+	// ContainerRuntime is DockerContainerRuntime or RemoteContainerRuntime
+	ContainerRuntime string
+	// endpoints of CRI services, unix:///path or /path of the socket
+	RemoteRuntimeEndpoint string
+	RemoteImageEndpoint   string
+	// CgroupDriver of the CRI runtime, cgroupfs or systemd, the one of docker is detected
+	CgroupDriver string
+	// ClusterDNS are nameservers of pods with ClusterFirst DNS policy, default to weave DNS
+	ClusterDNS    []string
+	ClusterDomain string
+}
@@ -0,0 +1,44 @@
+package cuberuntime
+
+import (
+	"Cubernetes/pkg/object"
+	"strconv"
+
+	dockertypes "github.com/docker/docker/api/types"
+)
+
+const noNewPrivileges = "no-new-privileges"
+
+// applySecurityContext sets user, groups and privileges of the container by the effective security context
+func applySecurityContext(config *dockertypes.ContainerCreateConfig, pod *object.Pod, container *object.Container) {
+	if pod.Spec.SecurityContext != nil {
+		for _, group := range pod.Spec.SecurityContext.SupplementalGroups {
+			config.HostConfig.GroupAdd = append(config.HostConfig.GroupAdd, strconv.FormatInt(group, 10))
+		}
+	}
+
+	sc := object.EffectiveSecurityContext(&pod.Spec, container)
+	if sc == nil {
+		return
+	}
+
+	if sc.RunAsUser != nil {
+		config.Config.User = strconv.FormatInt(*sc.RunAsUser, 10)
+		if sc.RunAsGroup != nil {
+			config.Config.User += ":" + strconv.FormatInt(*sc.RunAsGroup, 10)
+		}
+	}
+	if sc.ReadOnlyRootFilesystem != nil {
+		config.HostConfig.ReadonlyRootfs = *sc.ReadOnlyRootFilesystem
+	}
+	if sc.Privileged != nil {
+		config.HostConfig.Privileged = *sc.Privileged
+	}
+	if sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation {
+		config.HostConfig.SecurityOpt = append(config.HostConfig.SecurityOpt, noNewPrivileges)
+	}
+	if sc.Capabilities != nil {
+		config.HostConfig.CapAdd = sc.Capabilities.Add
+		config.HostConfig.CapDrop = sc.Capabilities.Drop
+	}
+}
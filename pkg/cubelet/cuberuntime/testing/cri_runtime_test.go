@@ -0,0 +1,243 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cri/remote"
+	"Cubernetes/pkg/cubelet/cri/remote/fake"
+	"Cubernetes/pkg/cubelet/cuberuntime"
+	"Cubernetes/pkg/object"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	apitest "k8s.io/cri-api/pkg/apis/testing"
+)
+
+func TestCRIRuntimeManager(t *testing.T) {
+	endpoint := filepath.Join(t.TempDir(), "cri.sock")
+	runtime := fake.NewFakeRemoteRuntime()
+	assert.NoError(t, runtime.Start(endpoint))
+	defer runtime.Stop()
+	runtime.ImageService.SetFakeImages([]string{"nginx:1.21"})
+
+	manager, err := cuberuntime.NewCRIRuntimeManager("unix://"+endpoint, endpoint, "cgroupfs")
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "cri-test-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:            "nginx",
+			Image:           "nginx:1.21",
+			ImagePullPolicy: object.PullIfNotPresent,
+			Ports:           []object.ContainerPort{{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"}},
+		}}},
+	}
+	// status is not reported without apiserver
+	_ = manager.SyncPod(pod, &container.PodStatus{})
+
+	podStatus, err := manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.Len(t, podStatus.SandboxStatuses, 1)
+	assert.Equal(t, container.SandboxStateReady, podStatus.SandboxStatuses[0].State)
+	assert.Equal(t, apitest.FakePodSandboxIPs[0], podStatus.SandboxStatuses[0].Ip)
+	assert.Len(t, podStatus.ContainerStatuses, 1)
+	assert.Equal(t, "nginx", podStatus.ContainerStatuses[0].Name)
+	assert.Equal(t, container.ContainerStateRunning, podStatus.ContainerStatuses[0].State)
+
+	uids, err := manager.ListPodsUID()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{pod.UID}, uids)
+
+	id, err := manager.GetRunningContainerID(pod.UID, "nginx")
+	assert.NoError(t, err)
+	assert.Equal(t, podStatus.ContainerStatuses[0].ID.ID, id)
+	code, _, err := manager.RunInContainer(id, []string{"true"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	// nothing changes on the next sync
+	_ = manager.SyncPod(pod, podStatus)
+	assert.Len(t, runtime.RuntimeService.Sandboxes, 1)
+	assert.Len(t, runtime.RuntimeService.Containers, 1)
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+	assert.Empty(t, runtime.RuntimeService.Sandboxes)
+	assert.Empty(t, runtime.RuntimeService.Containers)
+}
+
+func startFakeCRIRuntime(t *testing.T) (*fake.RemoteRuntime, cuberuntime.CubeRuntime) {
+	endpoint := filepath.Join(t.TempDir(), "cri.sock")
+	runtime := fake.NewFakeRemoteRuntime()
+	assert.NoError(t, runtime.Start(endpoint))
+	t.Cleanup(runtime.Stop)
+
+	manager, err := cuberuntime.NewCRIRuntimeManager("unix://"+endpoint, endpoint, "cgroupfs")
+	assert.NoError(t, err)
+	t.Cleanup(manager.Close)
+	return runtime, manager
+}
+
+func TestCRIRuntimeManagerImages(t *testing.T) {
+	runtime, manager := startFakeCRIRuntime(t)
+	runtime.ImageService.SetFakeImageSize(1024)
+	runtime.ImageService.SetFakeImages([]string{"nginx:1.21", "busybox:1.35"})
+	runtime.ImageService.SetFakeFilesystemUsage([]*runtimeapi.FilesystemUsage{{
+		FsId: &runtimeapi.FilesystemIdentifier{Mountpoint: "/var/lib/containerd"},
+	}})
+
+	version, err := manager.RuntimeVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, apitest.FakeRuntimeName+"://"+apitest.FakeVersion, version)
+
+	images, err := manager.ListImages()
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, int64(1024), images[0].Size)
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "cri-image-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:            "nginx",
+			Image:           "nginx:1.21",
+			ImagePullPolicy: object.PullIfNotPresent,
+		}}},
+	}
+	_ = manager.SyncPod(pod, &container.PodStatus{})
+	inUse, err := manager.ListImagesInUse()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"nginx:1.21": true}, inUse)
+
+	assert.NoError(t, manager.RemoveImage("busybox:1.35"))
+	images, err = manager.ListImages()
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+
+	path, err := manager.ImageFsPath()
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/lib/containerd", path)
+}
+
+func TestCRIRuntimeManagerLogs(t *testing.T) {
+	runtime, manager := startFakeCRIRuntime(t)
+	runtime.ImageService.SetFakeImages([]string{"nginx:1.21"})
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "cri-logs-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:            "nginx",
+			Image:           "nginx:1.21",
+			ImagePullPolicy: object.PullIfNotPresent,
+		}}},
+	}
+	opts := object.LogOptions{Container: "nginx", TailLines: -1}
+	err := manager.GetContainerLogs(context.Background(), pod.UID, opts, io.Discard, io.Discard)
+	assert.ErrorIs(t, err, container.ErrContainerNotFound)
+
+	_ = manager.SyncPod(pod, &container.PodStatus{})
+	assert.Len(t, runtime.RuntimeService.Containers, 1)
+	logPath := filepath.Join(t.TempDir(), "0.log")
+	for _, fakeContainer := range runtime.RuntimeService.Containers {
+		fakeContainer.LogPath = logPath
+	}
+	assert.NoError(t, os.WriteFile(logPath, []byte(
+		"2022-05-01T10:00:00.000000001Z stdout F first\n"+
+			"2022-05-01T10:00:01.000000001Z stderr F oops\n"+
+			"2022-05-01T10:00:02.000000001Z stdout P sec\n"+
+			"2022-05-01T10:00:02.000000002Z stdout F ond\n"+
+			"2022-05-01T10:00:03.000000001Z stdout F thi"), 0644))
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.NoError(t, manager.GetContainerLogs(context.Background(), pod.UID, opts, stdout, stderr))
+	// the last line is not written completely yet
+	assert.Equal(t, "first\nsecond\n", stdout.String())
+	assert.Equal(t, "oops\n", stderr.String())
+
+	opts.TailLines = 1
+	opts.Timestamps = true
+	stdout.Reset()
+	assert.NoError(t, manager.GetContainerLogs(context.Background(), pod.UID, opts, stdout, io.Discard))
+	assert.Equal(t, "2022-05-01T10:00:02.000000002Z ond\n", stdout.String())
+
+	opts.Previous = true
+	err = manager.GetContainerLogs(context.Background(), pod.UID, opts, io.Discard, io.Discard)
+	assert.ErrorIs(t, err, container.ErrPreviousContainerNotFound)
+}
+
+func TestCRIRuntimeManagerExec(t *testing.T) {
+	runtime, manager := startFakeCRIRuntime(t)
+	// a streaming server echoes stdin, then reports exit code 3
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebsocket(t, w, r)
+		defer conn.Close()
+
+		writeWebsocketMessage(t, conn, remote.StdoutChannel, []byte("hello "))
+		channel, stdin := readWebsocketMessage(t, conn)
+		assert.Equal(t, remote.StdinChannel, channel)
+		writeWebsocketMessage(t, conn, remote.StdoutChannel, stdin)
+		writeWebsocketMessage(t, conn, remote.ErrorChannel, []byte(`{"status":"Failure","reason":"NonZeroExitCode",`+
+			`"details":{"causes":[{"reason":"ExitCode","message":"3"}]}}`))
+	}))
+	defer server.Close()
+	runtime.StreamURL = server.URL + "/exec/token"
+
+	stdout := &bytes.Buffer{}
+	code, err := manager.ExecInContainer(context.Background(), "container", []string{"cat"}, container.Streams{
+		Stdin:  strings.NewReader("world"),
+		Stdout: stdout,
+		Stderr: io.Discard,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, code)
+	assert.Equal(t, "hello world", stdout.String())
+}
+
+// acceptWebsocket completes the handshake of a websocket client, and returns the hijacked connection
+func acceptWebsocket(t *testing.T, w http.ResponseWriter, r *http.Request) net.Conn {
+	assert.Equal(t, "websocket", r.Header.Get("Upgrade"))
+	assert.Equal(t, remote.ChannelProtocol, r.Header.Get("Sec-WebSocket-Protocol"))
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	assert.NoError(t, err)
+	_, err = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Connection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n" +
+		"Sec-WebSocket-Protocol: " + remote.ChannelProtocol + "\r\n\r\n"))
+	assert.NoError(t, err)
+	return conn
+}
+
+// writeWebsocketMessage sends a short unmasked binary frame, as servers do
+func writeWebsocketMessage(t *testing.T, conn net.Conn, channel byte, data []byte) {
+	frame := append([]byte{0x82, byte(1 + len(data)), channel}, data...)
+	_, err := conn.Write(frame)
+	assert.NoError(t, err)
+}
+
+// readWebsocketMessage reads a short masked frame sent by the client
+func readWebsocketMessage(t *testing.T, conn net.Conn) (byte, []byte) {
+	header := make([]byte, 6)
+	_, err := io.ReadFull(conn, header)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x80), header[1]&0x80)
+
+	payload := make([]byte, header[1]&0x7f)
+	_, err = io.ReadFull(conn, payload)
+	assert.NoError(t, err)
+	for i := range payload {
+		payload[i] ^= header[2+i%4]
+	}
+	return payload[0], payload[1:]
+}
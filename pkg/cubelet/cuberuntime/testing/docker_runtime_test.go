@@ -0,0 +1,269 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/cuberuntime"
+	"Cubernetes/pkg/cubelet/cuberuntime/options"
+	"Cubernetes/pkg/cubelet/dockershim/fake"
+	"Cubernetes/pkg/object"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRuntimeManager(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages("nginx:1.21")
+	reported := make([]object.Pod, 0)
+	manager := cuberuntime.NewCubeRuntimeManagerWithDocker(docker,
+		func(sandboxID string) (net.IP, error) {
+			return net.ParseIP("10.32.0.2"), nil
+		},
+		func(pod object.Pod) (object.Pod, error) {
+			reported = append(reported, pod)
+			return pod, nil
+		})
+	defer manager.Close()
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "docker-test-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:            "nginx",
+			Image:           "nginx:1.21",
+			ImagePullPolicy: object.PullIfNotPresent,
+		}}},
+	}
+	podStatus, err := manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+
+	podStatus, err = manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.Len(t, podStatus.SandboxStatuses, 1)
+	assert.Equal(t, container.SandboxStateReady, podStatus.SandboxStatuses[0].State)
+	assert.Len(t, podStatus.ContainerStatuses, 1)
+	assert.Equal(t, container.ContainerStateRunning, podStatus.ContainerStatuses[0].State)
+
+	assert.Len(t, reported, 1)
+	assert.Equal(t, object.PodRunning, reported[0].Status.Phase)
+	assert.Equal(t, "10.32.0.2", reported[0].Status.IP.String())
+
+	uids, err := manager.ListPodsUID()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{pod.UID}, uids)
+
+	// nothing changes on the next sync
+	calls := len(docker.GetCalls())
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+	assert.NotContains(t, docker.GetCalls()[calls:], "CreateContainer")
+
+	// the container is restarted after it is killed
+	containerID := podStatus.ContainerStatuses[0].ID.ID
+	assert.NoError(t, docker.SignalContainer(containerID, "SIGKILL"))
+	podStatus, err = manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+	restarted, err := manager.GetRunningContainerID(pod.UID, "nginx")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", restarted)
+	assert.NotEqual(t, containerID, restarted)
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+	assert.Empty(t, docker.FindContainers(map[string]string{}))
+}
+
+func TestDockerRuntimeManagerPullFailure(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages(options.PauseImage)
+	docker.InjectError("PullImage", errors.New("registry unavailable"))
+	manager := cuberuntime.NewCubeRuntimeManagerWithDocker(docker,
+		func(sandboxID string) (net.IP, error) {
+			return net.ParseIP("10.32.0.3"), nil
+		},
+		func(pod object.Pod) (object.Pod, error) {
+			return pod, nil
+		})
+	defer manager.Close()
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "busybox", Namespace: "default", UID: "docker-pull-test-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:  "busybox",
+			Image: "busybox",
+		}}},
+	}
+	_ = manager.SyncPod(pod, &container.PodStatus{})
+
+	status, err := manager.InspectPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, object.PodPending, status.Phase)
+	assert.Len(t, status.ContainerStatuses, 1)
+	assert.NotNil(t, status.ContainerStatuses[0].State.Waiting)
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+}
+
+func TestDockerRuntimeManagerHostNetwork(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages(options.PauseImage, "nginx:1.21")
+	reported := make([]object.Pod, 0)
+	manager := cuberuntime.NewCubeRuntimeManagerWithDocker(docker,
+		func(sandboxID string) (net.IP, error) {
+			return nil, errors.New("not in weave network")
+		},
+		func(pod object.Pod) (object.Pod, error) {
+			reported = append(reported, pod)
+			return pod, nil
+		})
+	defer manager.Close()
+	manager.SetNodeIP(net.ParseIP("192.168.1.10"))
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "proxy", Namespace: "default", UID: "host-network-pod"},
+		Spec: object.PodSpec{
+			HostNetwork: true,
+			Containers: []object.Container{{
+				Name:            "proxy",
+				Image:           "nginx:1.21",
+				ImagePullPolicy: object.PullIfNotPresent,
+				Ports:           []object.ContainerPort{{ContainerPort: 8080, HostPort: 8080, Protocol: "TCP"}},
+			}},
+		},
+	}
+	podStatus, err := manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+
+	sandboxes := docker.FindContainers(map[string]string{cuberuntime.ContainerTypeLabel: cuberuntime.ContainerTypeSandbox})
+	assert.Len(t, sandboxes, 1)
+	assert.Equal(t, "host", string(sandboxes[0].HostConfig.NetworkMode))
+	assert.Empty(t, sandboxes[0].HostConfig.DNS)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, "192.168.1.10", reported[0].Status.IP.String())
+
+	// the sandbox without weave IP is kept
+	podStatus, err = manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.10", podStatus.PodNetWork.IP.String())
+	calls := len(docker.GetCalls())
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+	assert.NotContains(t, docker.GetCalls()[calls:], "CreateContainer")
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+}
+
+func TestDockerRuntimeManagerSandboxIPCache(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages(options.PauseImage, "nginx:1.21")
+	lookups := make([]string, 0)
+	manager := cuberuntime.NewCubeRuntimeManagerWithDocker(docker,
+		func(sandboxID string) (net.IP, error) {
+			lookups = append(lookups, sandboxID)
+			return net.ParseIP("10.32.0.4"), nil
+		},
+		func(pod object.Pod) (object.Pod, error) {
+			return pod, nil
+		})
+	defer manager.Close()
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "sandbox-ip-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name:            "nginx",
+			Image:           "nginx:1.21",
+			ImagePullPolicy: object.PullIfNotPresent,
+		}}},
+	}
+	podStatus, err := manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+	assert.Len(t, lookups, 1)
+
+	// the IP of the running sandbox is looked up once
+	for i := 0; i < 3; i++ {
+		podStatus, err = manager.GetPodStatus(pod.UID)
+		assert.NoError(t, err)
+		assert.Equal(t, "10.32.0.4", podStatus.SandboxStatuses[0].Ip)
+	}
+	assert.Len(t, lookups, 1)
+
+	// the exited sandbox is forgotten, and the IP of the new one is looked up
+	sandboxID := podStatus.SandboxStatuses[0].Id
+	assert.NoError(t, docker.SignalContainer(sandboxID, "SIGKILL"))
+	podStatus, err = manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", podStatus.SandboxStatuses[0].Ip)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+	podStatus, err = manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sandboxID, podStatus.SandboxStatuses[0].Id)
+	assert.Equal(t, "10.32.0.4", podStatus.SandboxStatuses[0].Ip)
+	assert.Len(t, lookups, 2)
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+}
+
+func TestDockerRuntimeManagerInspectPod(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	docker.SetFakeImages(options.PauseImage, "nginx:1.21")
+	manager := cuberuntime.NewCubeRuntimeManagerWithDocker(docker,
+		func(sandboxID string) (net.IP, error) {
+			return net.ParseIP("10.32.0.5"), nil
+		},
+		func(pod object.Pod) (object.Pod, error) {
+			return pod, nil
+		})
+	defer manager.Close()
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: "nginx", Namespace: "default", UID: "inspect-pod"},
+		Spec: object.PodSpec{
+			RestartPolicy: object.RestartPolicyNever,
+			Containers: []object.Container{{
+				Name:            "nginx",
+				Image:           "nginx:1.21",
+				ImagePullPolicy: object.PullIfNotPresent,
+			}},
+		},
+		Status: &object.PodStatus{},
+	}
+	object.SetPodCondition(pod.Status, object.PodCondition{Type: object.PodScheduled, Status: object.ConditionTrue})
+
+	podStatus, err := manager.GetPodStatus(pod.UID)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.SyncPod(pod, podStatus))
+
+	status, err := manager.InspectPod(pod)
+	assert.NoError(t, err)
+	assert.Equal(t, object.PodRunning, status.Phase)
+	assert.Len(t, status.ContainerStatuses, 1)
+	cs := status.ContainerStatuses[0]
+	assert.Equal(t, "nginx", cs.Name)
+	assert.Equal(t, "nginx:1.21", cs.Image)
+	assert.NotEqual(t, "", cs.ContainerID)
+	assert.Equal(t, int32(0), cs.RestartCount)
+	assert.NotNil(t, cs.State.Running)
+	assert.False(t, cs.State.Running.StartedAt.IsZero())
+
+	// conditions reported by scheduler are kept
+	scheduled := object.GetPodCondition(status, object.PodScheduled)
+	assert.NotNil(t, scheduled)
+	assert.Equal(t, object.ConditionTrue, scheduled.Status)
+	initialized := object.GetPodCondition(status, object.PodInitialized)
+	assert.NotNil(t, initialized)
+	assert.Equal(t, object.ConditionTrue, initialized.Status)
+
+	// a killed container is reported terminated with its exit code
+	assert.NoError(t, docker.SignalContainer(cs.ContainerID, "SIGKILL"))
+	status, err = manager.InspectPod(pod)
+	assert.NoError(t, err)
+	assert.Len(t, status.ContainerStatuses, 1)
+	terminated := status.ContainerStatuses[0].State.Terminated
+	assert.NotNil(t, terminated)
+	assert.Equal(t, int32(137), terminated.ExitCode)
+	assert.False(t, terminated.FinishedAt.IsZero())
+
+	assert.NoError(t, manager.KillPod(pod.UID))
+}
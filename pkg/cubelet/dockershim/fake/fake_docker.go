@@ -0,0 +1,644 @@
+package fake
+
+import (
+	"Cubernetes/pkg/cubelet/dockershim"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+)
+
+const (
+	StateCreated = "created"
+	StateRunning = "running"
+	StateExited  = "exited"
+
+	// FakeVersion is the docker version reported
+	FakeVersion = "20.10.0-fake"
+	// killedExitCode is the exit code of containers killed by SIGKILL
+	killedExitCode = 137
+)
+
+var ErrNotSupported = errors.New("not supported by fake docker runtime")
+
+// FakeContainer is a container simulated by FakeDockerRuntime
+type FakeContainer struct {
+	ID         string
+	Name       string
+	Config     *dockercontainer.Config
+	HostConfig *dockercontainer.HostConfig
+	// State is StateCreated, StateRunning or StateExited
+	State      string
+	ExitCode   int
+	OOMKilled  bool
+	Error      string
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Stats is returned by GetContainerStats while the container is running
+	Stats *dockertypes.StatsJSON
+	// Signals sent to the container
+	Signals []string
+}
+
+// FakeDockerRuntime simulates containers and images of docker in memory. Errors can be injected
+// for each method, and calls are recorded, so that tests can run without docker daemon and root
+type FakeDockerRuntime struct {
+	sync.Mutex
+
+	Called []string
+	Errors map[string][]error
+
+	// Containers is keyed by container ID
+	Containers map[string]*FakeContainer
+	// Images is keyed by image name with tag
+	Images map[string]*dockertypes.ImageInspect
+	// Logs of containers returned by ContainerLogs, keyed by container ID
+	Logs map[string]string
+	// ExecHandler runs commands of ExecInContainer, which exit with 0 and no output if it is nil
+	ExecHandler func(containerID string, cmd []string) (int, []byte, error)
+	// StopExitCode is the exit code of containers stopped, as processes handling SIGTERM exit with
+	StopExitCode int
+
+	nextID int
+}
+
+var _ dockershim.DockerRuntime = &FakeDockerRuntime{}
+
+func NewFakeDockerRuntime() *FakeDockerRuntime {
+	return &FakeDockerRuntime{
+		Called:     make([]string, 0),
+		Errors:     make(map[string][]error),
+		Containers: make(map[string]*FakeContainer),
+		Images:     make(map[string]*dockertypes.ImageInspect),
+		Logs:       make(map[string]string),
+	}
+}
+
+// InjectError makes the next call of method f fail with err, errors of f are returned in order
+func (r *FakeDockerRuntime) InjectError(f string, err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.Errors[f] = append(r.Errors[f], err)
+}
+
+// GetCalls returns names of methods called in order
+func (r *FakeDockerRuntime) GetCalls() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string{}, r.Called...)
+}
+
+// SetFakeImages makes images present, as if they were pulled
+func (r *FakeDockerRuntime) SetFakeImages(images ...string) {
+	r.Lock()
+	defer r.Unlock()
+	for _, image := range images {
+		r.addImage(image)
+	}
+}
+
+// ExitContainer makes the running container exit with exitCode, as if its process exited
+func (r *FakeDockerRuntime) ExitContainer(containerID string, exitCode int, oomKilled bool) error {
+	r.Lock()
+	defer r.Unlock()
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	if c.State != StateRunning {
+		return fmt.Errorf("container %s is not running", containerID)
+	}
+	r.exit(c, exitCode)
+	c.OOMKilled = oomKilled
+	return nil
+}
+
+// SetContainerStats sets resource usage of the container
+func (r *FakeDockerRuntime) SetContainerStats(containerID string, cpuTotalUsage, systemUsage, memoryUsage uint64) error {
+	r.Lock()
+	defer r.Unlock()
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	stats := &dockertypes.StatsJSON{}
+	stats.Read = time.Now()
+	stats.CPUStats.CPUUsage.TotalUsage = cpuTotalUsage
+	stats.CPUStats.SystemUsage = systemUsage
+	stats.MemoryStats.Usage = memoryUsage
+	c.Stats = stats
+	return nil
+}
+
+// GetContainer returns a copy of the container
+func (r *FakeDockerRuntime) GetContainer(containerID string) (FakeContainer, bool) {
+	r.Lock()
+	defer r.Unlock()
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return FakeContainer{}, false
+	}
+	return *c, true
+}
+
+// FindContainers returns copies of containers with all the labels, the earliest created first
+func (r *FakeDockerRuntime) FindContainers(labels map[string]string) []FakeContainer {
+	r.Lock()
+	defer r.Unlock()
+	result := make([]FakeContainer, 0)
+	for _, c := range r.sortedContainers() {
+		if matchLabels(c.Config.Labels, labels) {
+			result = append(result, *c)
+		}
+	}
+	return result
+}
+
+func (r *FakeDockerRuntime) CreateContainer(config *dockertypes.ContainerCreateConfig) (string, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("CreateContainer"); err != nil {
+		return "", err
+	}
+
+	if config.Config == nil {
+		return "", errors.New("config of container is not set")
+	}
+	if _, ok := r.Images[normalizeImage(config.Config.Image)]; !ok {
+		return "", fmt.Errorf("No such image: %s", config.Config.Image)
+	}
+	for _, c := range r.Containers {
+		if config.Name != "" && c.Name == config.Name {
+			return "", fmt.Errorf("Conflict. The container name \"/%s\" is already in use", config.Name)
+		}
+	}
+
+	r.nextID++
+	id := fmt.Sprintf("%064x", r.nextID)
+	name := config.Name
+	if name == "" {
+		name = "fake_" + id[len(id)-12:]
+	}
+	hostConfig := config.HostConfig
+	if hostConfig == nil {
+		hostConfig = &dockercontainer.HostConfig{}
+	}
+	r.Containers[id] = &FakeContainer{
+		ID:         id,
+		Name:       name,
+		Config:     config.Config,
+		HostConfig: hostConfig,
+		State:      StateCreated,
+		CreatedAt:  time.Now(),
+	}
+	return id, nil
+}
+
+func (r *FakeDockerRuntime) StartContainer(containerID string) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("StartContainer"); err != nil {
+		return err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	if c.State == StateRunning {
+		return nil
+	}
+	c.State = StateRunning
+	c.ExitCode = 0
+	c.OOMKilled = false
+	c.StartedAt = time.Now()
+	c.FinishedAt = time.Time{}
+	return nil
+}
+
+func (r *FakeDockerRuntime) StopContainer(containerID string) error {
+	return r.StopContainerWithGracePeriod(containerID, 0)
+}
+
+func (r *FakeDockerRuntime) StopContainerWithGracePeriod(containerID string, gracePeriod time.Duration) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("StopContainer"); err != nil {
+		return err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	if c.State == StateRunning {
+		r.exit(c, r.StopExitCode)
+	}
+	return nil
+}
+
+// ListContainers lists running containers only unless All is set or status is filtered, as docker does.
+// Filters of label, status, name and id are supported
+func (r *FakeDockerRuntime) ListContainers(opts dockertypes.ContainerListOptions) ([]dockertypes.Container, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("ListContainers"); err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	for _, selector := range opts.Filters.Get("label") {
+		key, value, _ := strings.Cut(selector, "=")
+		labels[key] = value
+	}
+	statuses := opts.Filters.Get("status")
+	all := opts.All || len(statuses) != 0
+
+	// the latest created first, as docker lists
+	sorted := r.sortedContainers()
+	result := make([]dockertypes.Container, 0)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		c := sorted[i]
+		if !all && c.State != StateRunning {
+			continue
+		}
+		if len(statuses) != 0 && !contains(statuses, c.State) {
+			continue
+		}
+		if !matchLabels(c.Config.Labels, labels) || !matchAny(opts.Filters.Get("name"), c.Name) ||
+			!matchAny(opts.Filters.Get("id"), c.ID) {
+			continue
+		}
+		result = append(result, dockertypes.Container{
+			ID:      c.ID,
+			Names:   []string{"/" + c.Name},
+			Image:   c.Config.Image,
+			ImageID: r.imageID(c.Config.Image),
+			Created: c.CreatedAt.Unix(),
+			Labels:  c.Config.Labels,
+			State:   c.State,
+			Status:  statusString(c),
+		})
+	}
+	return result, nil
+}
+
+func (r *FakeDockerRuntime) RemoveContainer(containerID string, force bool) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("RemoveContainer"); err != nil {
+		return err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	if c.State == StateRunning && !force {
+		return fmt.Errorf("You cannot remove a running container %s", containerID)
+	}
+	delete(r.Containers, containerID)
+	delete(r.Logs, containerID)
+	return nil
+}
+
+func (r *FakeDockerRuntime) InspectContainer(containerID string) (*dockertypes.ContainerJSON, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("InspectContainer"); err != nil {
+		return nil, err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return nil, noSuchContainer(containerID)
+	}
+
+	return &dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			ID:      c.ID,
+			Name:    "/" + c.Name,
+			Created: c.CreatedAt.Format(time.RFC3339Nano),
+			Image:   r.imageID(c.Config.Image),
+			State: &dockertypes.ContainerState{
+				Status:     c.State,
+				Running:    c.State == StateRunning,
+				OOMKilled:  c.OOMKilled,
+				ExitCode:   c.ExitCode,
+				Error:      c.Error,
+				StartedAt:  c.StartedAt.Format(time.RFC3339Nano),
+				FinishedAt: c.FinishedAt.Format(time.RFC3339Nano),
+			},
+			HostConfig: c.HostConfig,
+		},
+		Config: c.Config,
+	}, nil
+}
+
+func (r *FakeDockerRuntime) GetContainerStats(containerID string) (*dockertypes.StatsJSON, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("GetContainerStats"); err != nil {
+		return nil, err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return nil, noSuchContainer(containerID)
+	}
+	if c.State != StateRunning || c.Stats == nil {
+		return &dockertypes.StatsJSON{}, nil
+	}
+	stats := *c.Stats
+	return &stats, nil
+}
+
+// SignalContainer records the signal, SIGKILL kills the container
+func (r *FakeDockerRuntime) SignalContainer(containerID, signal string) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("SignalContainer"); err != nil {
+		return err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		return noSuchContainer(containerID)
+	}
+	if c.State != StateRunning {
+		return fmt.Errorf("container %s is not running", containerID)
+	}
+	c.Signals = append(c.Signals, signal)
+	if signal == "SIGKILL" || signal == "KILL" {
+		r.exit(c, killedExitCode)
+	}
+	return nil
+}
+
+func (r *FakeDockerRuntime) ExecInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error) {
+	r.Lock()
+	if err := r.called("ExecInContainer"); err != nil {
+		r.Unlock()
+		return 0, nil, err
+	}
+	c, ok := r.Containers[containerID]
+	if !ok {
+		r.Unlock()
+		return 0, nil, noSuchContainer(containerID)
+	}
+	if c.State != StateRunning {
+		r.Unlock()
+		return 0, nil, fmt.Errorf("container %s is not running", containerID)
+	}
+	handler := r.ExecHandler
+	r.Unlock()
+
+	// the handler may call the runtime, e.g. to make the container exit
+	if handler == nil {
+		return 0, nil, nil
+	}
+	return handler(containerID, cmd)
+}
+
+func (r *FakeDockerRuntime) ContainerLogs(containerID string, opts dockertypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("ContainerLogs"); err != nil {
+		return nil, err
+	}
+	if _, ok := r.Containers[containerID]; !ok {
+		return nil, noSuchContainer(containerID)
+	}
+	return io.NopCloser(strings.NewReader(r.Logs[containerID])), nil
+}
+
+func (r *FakeDockerRuntime) StartExecStream(containerID string, cmd []string, stdin, tty bool) (string, dockertypes.HijackedResponse, error) {
+	return "", dockertypes.HijackedResponse{}, ErrNotSupported
+}
+
+func (r *FakeDockerRuntime) ResizeExec(execID string, height, width uint) error {
+	return ErrNotSupported
+}
+
+func (r *FakeDockerRuntime) InspectExec(execID string) (*dockertypes.ContainerExecInspect, error) {
+	return nil, ErrNotSupported
+}
+
+func (r *FakeDockerRuntime) AttachContainer(containerID string, stdin bool) (dockertypes.HijackedResponse, error) {
+	return dockertypes.HijackedResponse{}, ErrNotSupported
+}
+
+func (r *FakeDockerRuntime) ResizeContainer(containerID string, height, width uint) error {
+	return ErrNotSupported
+}
+
+func (r *FakeDockerRuntime) PullImage(imageName string) error {
+	return r.PullImageWithAuth(imageName, nil, nil)
+}
+
+func (r *FakeDockerRuntime) PullImageWithAuth(imageName string, auth *dockertypes.AuthConfig, progress func(string)) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("PullImage"); err != nil {
+		return err
+	}
+	r.addImage(imageName)
+	if progress != nil {
+		progress("Download complete")
+	}
+	return nil
+}
+
+func (r *FakeDockerRuntime) InspectImage(imageName string) (*dockertypes.ImageInspect, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("InspectImage"); err != nil {
+		return nil, err
+	}
+	image, ok := r.Images[normalizeImage(imageName)]
+	if !ok {
+		return nil, fmt.Errorf("No such image: %s", imageName)
+	}
+	inspect := *image
+	return &inspect, nil
+}
+
+func (r *FakeDockerRuntime) RemoveImage(imageName string) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("RemoveImage"); err != nil {
+		return err
+	}
+	name := normalizeImage(imageName)
+	if _, ok := r.Images[name]; !ok {
+		return fmt.Errorf("No such image: %s", imageName)
+	}
+	delete(r.Images, name)
+	return nil
+}
+
+func (r *FakeDockerRuntime) RemoveImageByID(imageID string) error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("RemoveImage"); err != nil {
+		return err
+	}
+	for name, image := range r.Images {
+		if image.ID == imageID {
+			delete(r.Images, name)
+			return nil
+		}
+	}
+	return fmt.Errorf("No such image: %s", imageID)
+}
+
+func (r *FakeDockerRuntime) ListImages(all bool) ([]*dockertypes.ImageSummary, error) {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.called("ListImages"); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(r.Images))
+	for name := range r.Images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	summaries := make([]*dockertypes.ImageSummary, 0, len(names))
+	for _, name := range names {
+		image := r.Images[name]
+		created, _ := time.Parse(time.RFC3339Nano, image.Created)
+		summaries = append(summaries, &dockertypes.ImageSummary{
+			ID:       image.ID,
+			RepoTags: image.RepoTags,
+			Size:     image.Size,
+			Created:  created.Unix(),
+		})
+	}
+	return summaries, nil
+}
+
+func (r *FakeDockerRuntime) GetDockerRootDir() (string, error) {
+	return "/var/lib/docker", nil
+}
+
+func (r *FakeDockerRuntime) GetCgroupDriver() (string, error) {
+	return "cgroupfs", nil
+}
+
+func (r *FakeDockerRuntime) GetRuntimeVersion() (string, error) {
+	return FakeVersion, nil
+}
+
+func (r *FakeDockerRuntime) CloseConnection() {}
+
+// called records the call of f, and pops an error injected, it must be called with lock held
+func (r *FakeDockerRuntime) called(f string) error {
+	r.Called = append(r.Called, f)
+	errs := r.Errors[f]
+	if len(errs) == 0 {
+		return nil
+	}
+	r.Errors[f] = errs[1:]
+	return errs[0]
+}
+
+func (r *FakeDockerRuntime) exit(c *FakeContainer, exitCode int) {
+	c.State = StateExited
+	c.ExitCode = exitCode
+	c.FinishedAt = time.Now()
+}
+
+func (r *FakeDockerRuntime) addImage(imageName string) {
+	name := normalizeImage(imageName)
+	if _, ok := r.Images[name]; ok {
+		return
+	}
+	sum := sha256.Sum256([]byte(name))
+	r.Images[name] = &dockertypes.ImageInspect{
+		ID:       "sha256:" + hex.EncodeToString(sum[:]),
+		RepoTags: []string{name},
+		Created:  time.Now().Format(time.RFC3339Nano),
+		Size:     int64(len(name)) * 1024 * 1024,
+	}
+}
+
+func (r *FakeDockerRuntime) imageID(imageName string) string {
+	if image, ok := r.Images[normalizeImage(imageName)]; ok {
+		return image.ID
+	}
+	return ""
+}
+
+// sortedContainers returns containers the earliest created first
+func (r *FakeDockerRuntime) sortedContainers() []*FakeContainer {
+	containers := make([]*FakeContainer, 0, len(r.Containers))
+	for _, c := range r.Containers {
+		containers = append(containers, c)
+	}
+	// IDs are increasing, containers created at the same time are still ordered
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].ID < containers[j].ID
+	})
+	return containers
+}
+
+// normalizeImage adds the latest tag to image names without tag or digest
+func normalizeImage(image string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image
+	}
+	return image + ":latest"
+}
+
+func statusString(c *FakeContainer) string {
+	switch c.State {
+	case StateRunning:
+		return "Up " + time.Since(c.StartedAt).Round(time.Second).String()
+	case StateExited:
+		return "Exited (" + strconv.Itoa(c.ExitCode) + ") " + time.Since(c.FinishedAt).Round(time.Second).String() + " ago"
+	}
+	return "Created"
+}
+
+func matchLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := labels[key]; !ok || (value != "" && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny tells whether s contains any of patterns, or patterns is empty
+func matchAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func noSuchContainer(containerID string) error {
+	return fmt.Errorf("No such container: %s", containerID)
+}
@@ -0,0 +1,63 @@
+package dockershim
+
+import (
+	"Cubernetes/pkg/object"
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// WriteContainerLogs writes logs of the container to stdout and stderr, output of a TTY goes to stdout.
+// It returns when the logs end, or when ctx is done if they are followed
+func WriteContainerLogs(ctx context.Context, runtime DockerRuntime, containerID string, opts object.LogOptions,
+	stdout, stderr io.Writer) error {
+	inspect, err := runtime.InspectContainer(containerID)
+	if err != nil {
+		return err
+	}
+
+	dockerOpts := dockertypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+		Tail:       "all",
+	}
+	if opts.TailLines >= 0 {
+		dockerOpts.Tail = strconv.Itoa(opts.TailLines)
+	}
+	if opts.Since > 0 {
+		dockerOpts.Since = strconv.FormatInt(time.Now().Add(-opts.Since).Unix(), 10)
+	}
+
+	logs, err := runtime.ContainerLogs(inspect.ID, dockerOpts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logs.Close() }()
+
+	// a followed stream may stay idle for long, stop it as soon as ctx is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = logs.Close()
+		case <-done:
+		}
+	}()
+
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(stdout, logs)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, logs)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package dockershim
 
 import (
 	"Cubernetes/pkg/object"
+	"strconv"
 	"strings"
 )
 
@@ -20,12 +21,15 @@ func MakeSandboxName(pod *object.Pod) string {
 	}, nameDelimiter)
 }
 
-func MakeContainerName(pod *object.Pod, container *object.Container) string {
+// MakeContainerName makes names of restarted containers different by attempt,
+// so that the exited one can be kept
+func MakeContainerName(pod *object.Pod, container *object.Container, attempt int) string {
 	return strings.Join([]string{
 		cubePrefix,
 		pod.Name,
 		container.Name,
 		pod.UID,
+		strconv.Itoa(attempt),
 	}, nameDelimiter)
 }
 
@@ -2,6 +2,7 @@ package dockershim
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,16 +20,43 @@ type DockerRuntime interface {
 	CreateContainer(config *dockertypes.ContainerCreateConfig) (string, error)
 	StartContainer(containerID string) error
 	StopContainer(containerID string) error
+	// StopContainerWithGracePeriod sends SIGTERM, and SIGKILL if the container is still running after gracePeriod
+	StopContainerWithGracePeriod(containerID string, gracePeriod time.Duration) error
 	ListContainers(opts dockertypes.ContainerListOptions) ([]dockertypes.Container, error)
 	RemoveContainer(containerID string, force bool) error
 	InspectContainer(containerID string) (*dockertypes.ContainerJSON, error)
 	GetContainerStats(containerID string) (*dockertypes.StatsJSON, error)
 	SignalContainer(containerID, signal string) error
+	// ExecInContainer runs cmd in the container, and returns its exit code and output
+	ExecInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error)
+	// ContainerLogs returns the log stream of the container, stdout and stderr are multiplexed
+	// unless the container has a tty, the stream is cancelled when closed
+	ContainerLogs(containerID string, opts dockertypes.ContainerLogsOptions) (io.ReadCloser, error)
+	// StartExecStream starts cmd in the container, and returns the exec id and its hijacked stdio,
+	// stdout and stderr are multiplexed unless tty is set
+	StartExecStream(containerID string, cmd []string, stdin, tty bool) (string, dockertypes.HijackedResponse, error)
+	ResizeExec(execID string, height, width uint) error
+	InspectExec(execID string) (*dockertypes.ContainerExecInspect, error)
+	// AttachContainer returns the hijacked stdio of the main process of the container
+	AttachContainer(containerID string, stdin bool) (dockertypes.HijackedResponse, error)
+	ResizeContainer(containerID string, height, width uint) error
 
 	// PullImage Image Service
 	PullImage(imageName string) error
+	// PullImageWithAuth pulls with registry credentials if auth is not nil,
+	// and reports progress messages to progress if it is not nil
+	PullImageWithAuth(imageName string, auth *dockertypes.AuthConfig, progress func(string)) error
+	// InspectImage returns nil if the image is not present
+	InspectImage(imageName string) (*dockertypes.ImageInspect, error)
 	RemoveImage(imageName string) error
+	RemoveImageByID(imageID string) error
 	ListImages(all bool) ([]*dockertypes.ImageSummary, error)
+	// GetDockerRootDir is where images and containers are stored
+	GetDockerRootDir() (string, error)
+	// GetCgroupDriver returns how docker manages cgroups of containers, cgroupfs or systemd
+	GetCgroupDriver() (string, error)
+	// GetRuntimeVersion returns version of the docker daemon
+	GetRuntimeVersion() (string, error)
 	// GetImageName(imageID string) (string, error)
 
 	// CloseConnection Closer
@@ -46,15 +74,21 @@ func NewDockerRuntime() (DockerRuntime, error) {
 		client:            client,
 		timeout:           time.Minute * 2,
 		imagePullDeadline: time.Minute,
+		stopGracePeriod:   time.Second * 30,
 	}
 
 	return cubeDockerClient, nil
 }
 
+// maxExecOutputSize limits output read from ExecInContainer
+const maxExecOutputSize = 10 * 1024
+
 type dockerClient struct {
 	timeout           time.Duration
 	imagePullDeadline time.Duration
-	client            *dockerapi.Client
+	// docker sends SIGTERM on stop, and SIGKILL after the grace period
+	stopGracePeriod time.Duration
+	client          *dockerapi.Client
 }
 
 func (c *dockerClient) CreateContainer(config *dockertypes.ContainerCreateConfig) (string, error) {
@@ -92,10 +126,15 @@ func (c *dockerClient) StartContainer(containerID string) error {
 }
 
 func (c *dockerClient) StopContainer(containerID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	return c.StopContainerWithGracePeriod(containerID, c.stopGracePeriod)
+}
+
+func (c *dockerClient) StopContainerWithGracePeriod(containerID string, gracePeriod time.Duration) error {
+	// wait for SIGKILL after the grace period
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout+gracePeriod)
 	defer cancel()
 
-	if err := c.client.ContainerStop(ctx, containerID, &c.timeout); err != nil {
+	if err := c.client.ContainerStop(ctx, containerID, &gracePeriod); err != nil {
 		log.Printf("fail to stop container %s : %v\n", containerID, err)
 		return err
 	}
@@ -177,11 +216,141 @@ func (c *dockerClient) SignalContainer(containerID, signal string) error {
 	return nil
 }
 
+func (c *dockerClient) ExecInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	exec, err := c.client.ContainerExecCreate(ctx, containerID, dockertypes.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		log.Printf("fail to create exec in container %s : %v\n", containerID, err)
+		return -1, nil, err
+	}
+
+	resp, err := c.client.ContainerExecAttach(ctx, exec.ID, dockertypes.ExecStartCheck{})
+	if err != nil {
+		log.Printf("fail to attach exec in container %s : %v\n", containerID, err)
+		return -1, nil, err
+	}
+	defer resp.Close()
+
+	// stdout and stderr are multiplexed with headers, kept as it is
+	output, err := io.ReadAll(io.LimitReader(resp.Reader, maxExecOutputSize))
+	if err != nil {
+		return -1, output, err
+	}
+
+	inspect, err := c.client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		log.Printf("fail to inspect exec in container %s : %v\n", containerID, err)
+		return -1, output, err
+	}
+	if inspect.Running {
+		return -1, output, fmt.Errorf("exec in container %s is still running", containerID)
+	}
+	return inspect.ExitCode, output, nil
+}
+
+func (c *dockerClient) ContainerLogs(containerID string, opts dockertypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	// no timeout, followed logs last until the caller closes the stream
+	logs, err := c.client.ContainerLogs(context.Background(), containerID, opts)
+	if err != nil {
+		log.Printf("fail to get logs of container %s : %v\n", containerID, err)
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (c *dockerClient) StartExecStream(containerID string, cmd []string, stdin, tty bool) (string, dockertypes.HijackedResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	exec, err := c.client.ContainerExecCreate(ctx, containerID, dockertypes.ExecConfig{
+		AttachStdin:  stdin,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		log.Printf("fail to create exec in container %s : %v\n", containerID, err)
+		return "", dockertypes.HijackedResponse{}, err
+	}
+
+	// no timeout, the stream lasts until cmd exits or the caller closes it
+	resp, err := c.client.ContainerExecAttach(context.Background(), exec.ID, dockertypes.ExecStartCheck{Tty: tty})
+	if err != nil {
+		log.Printf("fail to attach exec in container %s : %v\n", containerID, err)
+		return "", dockertypes.HijackedResponse{}, err
+	}
+
+	return exec.ID, resp, nil
+}
+
+func (c *dockerClient) ResizeExec(execID string, height, width uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	return c.client.ContainerExecResize(ctx, execID, dockertypes.ResizeOptions{Height: height, Width: width})
+}
+
+func (c *dockerClient) InspectExec(execID string) (*dockertypes.ContainerExecInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	inspect, err := c.client.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		log.Printf("fail to inspect exec %s : %v\n", execID, err)
+		return nil, err
+	}
+
+	return &inspect, nil
+}
+
+func (c *dockerClient) AttachContainer(containerID string, stdin bool) (dockertypes.HijackedResponse, error) {
+	resp, err := c.client.ContainerAttach(context.Background(), containerID, dockertypes.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  stdin,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		log.Printf("fail to attach container %s : %v\n", containerID, err)
+		return dockertypes.HijackedResponse{}, err
+	}
+
+	return resp, nil
+}
+
+func (c *dockerClient) ResizeContainer(containerID string, height, width uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	return c.client.ContainerResize(ctx, containerID, dockertypes.ResizeOptions{Height: height, Width: width})
+}
+
 func (c *dockerClient) PullImage(imageName string) error {
+	return c.PullImageWithAuth(imageName, nil, nil)
+}
+
+func (c *dockerClient) PullImageWithAuth(imageName string, auth *dockertypes.AuthConfig, progress func(string)) error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout*2)
 	defer cancel()
 
-	out, err := c.client.ImagePull(ctx, imageName, dockertypes.ImagePullOptions{})
+	opts := dockertypes.ImagePullOptions{}
+	if auth != nil {
+		buf, err := json.Marshal(auth)
+		if err != nil {
+			return err
+		}
+		opts.RegistryAuth = base64.URLEncoding.EncodeToString(buf)
+	}
+
+	out, err := c.client.ImagePull(ctx, imageName, opts)
 	if err != nil {
 		log.Printf("fail to pull image %s : %v\n", imageName, err)
 		return err
@@ -209,10 +378,74 @@ func (c *dockerClient) PullImage(imageName string) error {
 		if pullMessageFilter(msg.Status) {
 			log.Println(msg.Status)
 		}
+		if progress != nil && msg.Progress != nil {
+			progress(strings.TrimSpace(msg.Status + " " + msg.Progress.String()))
+		}
 	}
 	return nil
 }
 
+func (c *dockerClient) InspectImage(imageName string) (*dockertypes.ImageInspect, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	image, _, err := c.client.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		if dockerapi.IsErrNotFound(err) {
+			return nil, nil
+		}
+		log.Printf("fail to inspect image %s : %v\n", imageName, err)
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (c *dockerClient) RemoveImageByID(imageID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	// images of containers are not removed without force
+	_, err := c.client.ImageRemove(ctx, imageID, dockertypes.ImageRemoveOptions{PruneChildren: true})
+	if err != nil {
+		log.Printf("fail to remove image %s : %v\n", imageID, err)
+		return err
+	}
+	return nil
+}
+
+func (c *dockerClient) GetDockerRootDir() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	info, err := c.client.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.DockerRootDir, nil
+}
+
+func (c *dockerClient) GetCgroupDriver() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	info, err := c.client.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	return info.CgroupDriver, nil
+}
+
+func (c *dockerClient) GetRuntimeVersion() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	version, err := c.client.ServerVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	return version.Version, nil
+}
+
 func (c *dockerClient) ListImages(all bool) ([]*dockertypes.ImageSummary, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
@@ -224,8 +457,8 @@ func (c *dockerClient) ListImages(all bool) ([]*dockertypes.ImageSummary, error)
 	}
 
 	imageRefs := []*dockertypes.ImageSummary{}
-	for _, image := range images {
-		imageRefs = append(imageRefs, &image)
+	for idx := range images {
+		imageRefs = append(imageRefs, &images[idx])
 	}
 
 	return imageRefs, nil
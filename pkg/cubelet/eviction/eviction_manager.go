@@ -0,0 +1,126 @@
+package eviction
+
+import (
+	"Cubernetes/pkg/cubelet/images"
+	"Cubernetes/pkg/object"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Manager evicts pods when the node is low on memory or disk. Disk is reclaimed by removing
+// unused images first, then one pod is evicted on each sync until no threshold is met
+type Manager interface {
+	// Synchronize observes signals and updates pressure conditions, then returns the pod to evict
+	// and why, or nil if no threshold is met
+	Synchronize(pods []object.Pod) (*object.Pod, string)
+	// UpdateNodeCondition sets MemoryPressure, DiskPressure and OutOfDisk of the condition
+	UpdateNodeCondition(condition *object.NodeCondition)
+	// Admit tells if a new pod can run on the node, BestEffort pods are rejected under memory
+	// pressure, and all pods under disk pressure
+	Admit(pod *object.Pod) (bool, string)
+}
+
+func NewManager(config Config, imageGC images.ImageGCManager) Manager {
+	return &manager{config: config, imageGC: imageGC}
+}
+
+type manager struct {
+	config  Config
+	imageGC images.ImageGCManager
+
+	lock sync.Mutex
+	// last time memory or disk thresholds are met
+	memoryPressureAt time.Time
+	diskPressureAt   time.Time
+	outOfDisk        bool
+}
+
+func (m *manager) Synchronize(pods []object.Pod) (*object.Pod, string) {
+	observations, err := observe(m.config.NodeFsPath, m.config.ImageFsPath)
+	if err != nil {
+		log.Printf("[Error]: fail to observe eviction signals: %v\n", err)
+		return nil, ""
+	}
+	met := ThresholdsMet(m.config.Thresholds, observations)
+
+	if m.imageGC != nil && hasDiskThreshold(met) {
+		freed, err := m.imageGC.DeleteUnusedImages()
+		if err != nil {
+			log.Printf("[Error]: fail to remove unused images: %v\n", err)
+		}
+		if freed > 0 {
+			log.Printf("[INFO]: %d bytes of unused images removed under disk pressure\n", freed)
+			if observations, err = observe(m.config.NodeFsPath, m.config.ImageFsPath); err != nil {
+				log.Printf("[Error]: fail to observe eviction signals: %v\n", err)
+				return nil, ""
+			}
+			met = ThresholdsMet(m.config.Thresholds, observations)
+		}
+	}
+
+	m.lock.Lock()
+	now := time.Now()
+	for _, threshold := range met {
+		if isMemorySignal(threshold.Signal) {
+			m.memoryPressureAt = now
+		} else {
+			m.diskPressureAt = now
+		}
+	}
+	m.outOfDisk = isOutOfDisk(observations)
+	m.lock.Unlock()
+
+	if len(met) == 0 {
+		return nil, ""
+	}
+
+	threshold := met[0]
+	log.Printf("[WARNING]: eviction threshold %s met, %d available\n",
+		threshold.Signal, observations[threshold.Signal].Available)
+	ranked := RankPodsForEviction(pods)
+	if len(ranked) == 0 {
+		log.Printf("[WARNING]: no pod to evict for %s\n", threshold.Signal)
+		return nil, ""
+	}
+	resource := "memory"
+	if !isMemorySignal(threshold.Signal) {
+		resource = "ephemeral-storage"
+	}
+	return ranked[0], fmt.Sprintf("The node was low on resource: %s. Signal %s is %d, below the threshold",
+		resource, threshold.Signal, observations[threshold.Signal].Available)
+}
+
+func (m *manager) UpdateNodeCondition(condition *object.NodeCondition) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	condition.MemoryPressure = m.underPressure(m.memoryPressureAt)
+	condition.DiskPressure = m.underPressure(m.diskPressureAt)
+	condition.OutOfDisk = m.outOfDisk
+}
+
+func (m *manager) Admit(pod *object.Pod) (bool, string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.underPressure(m.diskPressureAt) {
+		return false, "The node had condition: DiskPressure"
+	}
+	if m.underPressure(m.memoryPressureAt) && object.GetPodQOS(pod) == object.PodQOSBestEffort {
+		return false, "The node had condition: MemoryPressure"
+	}
+	return true, ""
+}
+
+func (m *manager) underPressure(lastMet time.Time) bool {
+	return !lastMet.IsZero() && time.Since(lastMet) < m.config.PressureTransitionPeriod
+}
+
+func hasDiskThreshold(thresholds []Threshold) bool {
+	for _, threshold := range thresholds {
+		if !isMemorySignal(threshold.Signal) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package eviction
+
+import (
+	"Cubernetes/pkg/object"
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// outOfDiskQuantity is the free space below which the node is out of disk
+const outOfDiskQuantity = 256 * 1024 * 1024
+
+// ThresholdsMet returns thresholds whose signals are observed below them
+func ThresholdsMet(thresholds []Threshold, observations Observations) []Threshold {
+	met := make([]Threshold, 0)
+	for _, threshold := range thresholds {
+		observed, ok := observations[threshold.Signal]
+		if !ok {
+			continue
+		}
+		quantity := threshold.Quantity
+		if quantity == 0 {
+			quantity = int64(float64(observed.Capacity) * threshold.Percentage)
+		}
+		if observed.Available < quantity {
+			met = append(met, threshold)
+		}
+	}
+	return met
+}
+
+func isMemorySignal(signal Signal) bool {
+	return signal == SignalMemoryAvailable
+}
+
+func isOutOfDisk(observations Observations) bool {
+	for _, signal := range []Signal{SignalNodeFsAvailable, SignalImageFsAvailable} {
+		if observed, ok := observations[signal]; ok && observed.Available < outOfDiskQuantity {
+			return true
+		}
+	}
+	for _, signal := range []Signal{SignalNodeFsInodesFree, SignalImageFsInodesFree} {
+		if observed, ok := observations[signal]; ok && observed.Available == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var qosRanks = map[object.PodQOSClass]int{
+	object.PodQOSBestEffort: 0,
+	object.PodQOSBurstable:  1,
+	object.PodQOSGuaranteed: 2,
+}
+
+// RankPodsForEviction sorts pods to evict first to the front: BestEffort pods, then Burstable
+// and Guaranteed ones, pods of lower priority first in each class, then pods using more memory
+// beyond their requests
+func RankPodsForEviction(pods []object.Pod) []*object.Pod {
+	ranked := make([]*object.Pod, len(pods))
+	for idx := range pods {
+		ranked[idx] = &pods[idx]
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if qa, qb := qosRanks[object.GetPodQOS(a)], qosRanks[object.GetPodQOS(b)]; qa != qb {
+			return qa < qb
+		}
+		if pa, pb := object.GetPodPriority(a), object.GetPodPriority(b); pa != pb {
+			return pa < pb
+		}
+		return memoryAboveRequests(a) > memoryAboveRequests(b)
+	})
+	return ranked
+}
+
+func memoryAboveRequests(pod *object.Pod) int64 {
+	if pod.Status == nil || pod.Status.ActualResourceUsage == nil {
+		return 0
+	}
+	_, requests := object.GetPodRequests(pod)
+	return pod.Status.ActualResourceUsage.ActualMemoryUsage - requests
+}
+
+// observe reads available memory from /proc/meminfo, and space and inodes of filesystems
+func observe(nodeFsPath, imageFsPath string) (Observations, error) {
+	observations := make(Observations)
+	memory, err := observeMemory()
+	if err != nil {
+		return nil, err
+	}
+	observations[SignalMemoryAvailable] = memory
+
+	fsPaths := map[string][2]Signal{
+		nodeFsPath:  {SignalNodeFsAvailable, SignalNodeFsInodesFree},
+		imageFsPath: {SignalImageFsAvailable, SignalImageFsInodesFree},
+	}
+	for path, signals := range fsPaths {
+		if path == "" {
+			continue
+		}
+		var stat syscall.Statfs_t
+		if err = syscall.Statfs(path, &stat); err != nil {
+			return nil, err
+		}
+		observations[signals[0]] = Observation{
+			Available: int64(stat.Bavail) * stat.Bsize,
+			Capacity:  int64(stat.Blocks) * stat.Bsize,
+		}
+		// some filesystems, e.g. btrfs, don't limit inodes
+		if stat.Files > 0 {
+			observations[signals[1]] = Observation{Available: int64(stat.Ffree), Capacity: int64(stat.Files)}
+		}
+	}
+	return observations, nil
+}
+
+func observeMemory() (Observation, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return Observation{}, err
+	}
+	defer file.Close()
+
+	values := make(map[string]int64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// MemAvailable:    1234 kB
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value * 1024
+	}
+	if err = scanner.Err(); err != nil {
+		return Observation{}, err
+	}
+
+	total, ok := values["MemTotal"]
+	if !ok {
+		return Observation{}, fmt.Errorf("MemTotal not found in /proc/meminfo")
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	return Observation{Available: available, Capacity: total}, nil
+}
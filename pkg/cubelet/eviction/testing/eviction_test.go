@@ -0,0 +1,50 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/eviction"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildPod(name string, priority int32, resources *object.ResourceRequirements, usage int64) object.Pod {
+	return object.Pod{
+		ObjectMeta: object.ObjectMeta{Name: name},
+		Spec: object.PodSpec{
+			Priority:   &priority,
+			Containers: []object.Container{{Name: name, Resources: resources}},
+		},
+		Status: &object.PodStatus{ActualResourceUsage: &object.ResourceUsage{ActualMemoryUsage: usage}},
+	}
+}
+
+func TestThresholdsMet(t *testing.T) {
+	observations := eviction.Observations{
+		eviction.SignalMemoryAvailable:  {Available: 50 * 1024 * 1024, Capacity: 1024 * 1024 * 1024},
+		eviction.SignalNodeFsAvailable:  {Available: 200, Capacity: 1000},
+		eviction.SignalNodeFsInodesFree: {Available: 40, Capacity: 1000},
+	}
+	met := eviction.ThresholdsMet(eviction.DefaultThresholds, observations)
+	assert.Equal(t, 2, len(met))
+	assert.Equal(t, eviction.SignalMemoryAvailable, met[0].Signal)
+	assert.Equal(t, eviction.SignalNodeFsInodesFree, met[1].Signal)
+}
+
+func TestRankPodsForEviction(t *testing.T) {
+	limited := &object.ResourceRequirements{Cpus: 1, Memory: 100}
+	burstable := &object.ResourceRequirements{Memory: 200, Requests: &object.ResourceList{Memory: 100}}
+	pods := []object.Pod{
+		buildPod("guaranteed", 0, limited, 100),
+		buildPod("burstable-high", 1000, burstable, 300),
+		buildPod("burstable-small", 0, burstable, 120),
+		buildPod("burstable-large", 0, burstable, 190),
+		buildPod("best-effort", 1000, nil, 10),
+	}
+
+	ranked := eviction.RankPodsForEviction(pods)
+	names := make([]string, len(ranked))
+	for idx, pod := range ranked {
+		names[idx] = pod.Name
+	}
+	assert.Equal(t, []string{"best-effort", "burstable-large", "burstable-small", "burstable-high", "guaranteed"}, names)
+}
@@ -0,0 +1,50 @@
+package eviction
+
+import "time"
+
+// Signal is a resource of the node observed by the eviction manager
+type Signal string
+
+const (
+	SignalMemoryAvailable   Signal = "memory.available"
+	SignalNodeFsAvailable   Signal = "nodefs.available"
+	SignalNodeFsInodesFree  Signal = "nodefs.inodesFree"
+	SignalImageFsAvailable  Signal = "imagefs.available"
+	SignalImageFsInodesFree Signal = "imagefs.inodesFree"
+)
+
+// Threshold is met when the signal is below Quantity, or below Percentage of its capacity
+// if Quantity is zero
+type Threshold struct {
+	Signal   Signal
+	Quantity int64
+	// Percentage is in (0, 1]
+	Percentage float64
+}
+
+// DefaultThresholds are the hard eviction thresholds of kubelet
+var DefaultThresholds = []Threshold{
+	{Signal: SignalMemoryAvailable, Quantity: 100 * 1024 * 1024},
+	{Signal: SignalNodeFsAvailable, Percentage: 0.1},
+	{Signal: SignalNodeFsInodesFree, Percentage: 0.05},
+	{Signal: SignalImageFsAvailable, Percentage: 0.15},
+}
+
+type Config struct {
+	Thresholds []Threshold
+	// PressureTransitionPeriod is how long a pressure condition stays after its thresholds
+	// are no longer met, so that the condition doesn't flap
+	PressureTransitionPeriod time.Duration
+	// NodeFsPath is on the filesystem of cubelet volumes and logs
+	NodeFsPath string
+	// ImageFsPath is on the filesystem of images and writable layers of containers
+	ImageFsPath string
+}
+
+// Observation is the available amount and capacity of a signal
+type Observation struct {
+	Available int64
+	Capacity  int64
+}
+
+type Observations map[Signal]Observation
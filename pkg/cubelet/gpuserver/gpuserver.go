@@ -32,6 +32,11 @@ func NewJobRuntime() JobRuntime {
 		log.Println("[Error]: Init docker runtime error")
 	}
 
+	return NewJobRuntimeWithDocker(dockerInstance)
+}
+
+// NewJobRuntimeWithDocker creates the job runtime on dockerInstance, e.g. a fake one in tests
+func NewJobRuntimeWithDocker(dockerInstance dockershim.DockerRuntime) JobRuntime {
 	return &jobRuntimeManager{
 		jobMap:         make(map[string]string),
 		dockerInstance: dockerInstance,
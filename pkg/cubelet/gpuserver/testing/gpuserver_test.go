@@ -1,8 +1,13 @@
 package testing
 
 import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/cubelet/dockershim/fake"
 	"Cubernetes/pkg/cubelet/gpuserver"
+	"Cubernetes/pkg/cubelet/gpuserver/options"
 	"Cubernetes/pkg/object"
+	"errors"
+	"github.com/docker/docker/api/types/strslice"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -24,3 +29,24 @@ func TestGPUServer(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestGPUServerWithFakeDocker(t *testing.T) {
+	docker := fake.NewFakeDockerRuntime()
+	jr := gpuserver.NewJobRuntimeWithDocker(docker)
+
+	job := object.GpuJob{ObjectMeta: object.ObjectMeta{Name: "test-gpu", UID: "gpu-job-uid"}}
+	assert.NoError(t, jr.AddGPUJob(&job))
+
+	containers := docker.FindContainers(map[string]string{})
+	assert.Len(t, containers, 1)
+	assert.Equal(t, gpuserver.GetJobDockerName(job.UID), containers[0].Name)
+	assert.Equal(t, options.GpuServerImageName, containers[0].Config.Image)
+	assert.Equal(t, strslice.StrSlice{job.UID, cubeconfig.APIServerIp}, containers[0].Config.Cmd)
+	assert.Equal(t, fake.StateRunning, containers[0].State)
+
+	// no container is created if the image fails to be pulled
+	docker.InjectError("PullImage", errors.New("registry unavailable"))
+	failed := object.GpuJob{ObjectMeta: object.ObjectMeta{Name: "test-gpu-2", UID: "gpu-job-uid-2"}}
+	assert.Error(t, jr.AddGPUJob(&failed))
+	assert.Len(t, docker.FindContainers(map[string]string{}), 1)
+}
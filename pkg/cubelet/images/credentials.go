@@ -0,0 +1,96 @@
+package images
+
+import (
+	"Cubernetes/pkg/object"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	dockertypes "github.com/docker/docker/api/types"
+)
+
+const defaultRegistry = "docker.io"
+
+// DockerConfigJson is the value of .dockerconfigjson in secrets of type cubernetes.io/dockerconfigjson
+type DockerConfigJson struct {
+	// registry -> credentials
+	Auths map[string]DockerConfigEntry `json:"auths"`
+}
+
+type DockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Auth is base64 encoded username:password, used if Username is not set
+	Auth  string `json:"auth,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// ParseImageRegistry returns the registry host of image, docker.io if it is not specified
+func ParseImageRegistry(image string) string {
+	idx := strings.Index(image, "/")
+	if idx == -1 {
+		return defaultRegistry
+	}
+	host := image[:idx]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		// user name on docker hub
+		return defaultRegistry
+	}
+	return normalizeRegistry(host)
+}
+
+// normalizeRegistry strips scheme and path of registry in docker config,
+// e.g. https://index.docker.io/v1/ is docker.io
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	if idx := strings.Index(registry, "/"); idx != -1 {
+		registry = registry[:idx]
+	}
+	if registry == "index.docker.io" || registry == "registry-1.docker.io" {
+		return defaultRegistry
+	}
+	return registry
+}
+
+// LookupAuth returns credentials for the registry of image from secrets,
+// nil if no secret matches, and the first matched is used
+func LookupAuth(image string, secrets []*object.Secret) *dockertypes.AuthConfig {
+	registry := ParseImageRegistry(image)
+	for _, secret := range secrets {
+		if secret.Type != object.SecretTypeDockerConfigJson {
+			continue
+		}
+		value, ok := secret.GetValue(object.DockerConfigJsonKey)
+		if !ok {
+			continue
+		}
+		var config DockerConfigJson
+		if err := json.Unmarshal([]byte(value), &config); err != nil {
+			continue
+		}
+		for server, entry := range config.Auths {
+			if normalizeRegistry(server) == registry {
+				return toAuthConfig(server, &entry)
+			}
+		}
+	}
+	return nil
+}
+
+func toAuthConfig(server string, entry *DockerConfigEntry) *dockertypes.AuthConfig {
+	auth := &dockertypes.AuthConfig{
+		Username:      entry.Username,
+		Password:      entry.Password,
+		Email:         entry.Email,
+		ServerAddress: server,
+	}
+	if auth.Username == "" && entry.Auth != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil {
+			if pair := strings.SplitN(string(decoded), ":", 2); len(pair) == 2 {
+				auth.Username, auth.Password = pair[0], pair[1]
+			}
+		}
+	}
+	return auth
+}
@@ -0,0 +1,185 @@
+package images
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ImageGCPolicy removes unused images when disk usage of the image filesystem
+// exceeds HighThresholdPercent, until it is below LowThresholdPercent
+type ImageGCPolicy struct {
+	HighThresholdPercent int
+	LowThresholdPercent  int
+	// MinAge is how long an image stays after it is found, so that images just pulled are kept
+	MinAge time.Duration
+	// PinnedImages are never removed, e.g. the pause image of sandboxes
+	PinnedImages []string
+}
+
+type ImageGCManager interface {
+	// GarbageCollect removes unused images by policy, least recently used first
+	GarbageCollect() error
+	// DeleteUnusedImages removes all unused images older than MinAge regardless of disk usage,
+	// it is called to reclaim disk under disk pressure, and returns bytes freed
+	DeleteUnusedImages() (int64, error)
+}
+
+type imageRecord struct {
+	firstDetected time.Time
+	lastUsed      time.Time
+	size          int64
+}
+
+type imageGCManager struct {
+	runtime cubecontainer.ImageService
+	policy  ImageGCPolicy
+
+	lock sync.Mutex
+	// image ID -> record
+	imageRecords map[string]*imageRecord
+}
+
+func NewImageGCManager(runtime cubecontainer.ImageService, policy ImageGCPolicy) (ImageGCManager, error) {
+	if policy.HighThresholdPercent <= 0 || policy.HighThresholdPercent > 100 {
+		return nil, fmt.Errorf("invalid HighThresholdPercent %d", policy.HighThresholdPercent)
+	}
+	if policy.LowThresholdPercent < 0 || policy.LowThresholdPercent > policy.HighThresholdPercent {
+		return nil, fmt.Errorf("invalid LowThresholdPercent %d", policy.LowThresholdPercent)
+	}
+	return &imageGCManager{
+		runtime:      runtime,
+		policy:       policy,
+		imageRecords: make(map[string]*imageRecord),
+	}, nil
+}
+
+func (m *imageGCManager) GarbageCollect() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	images, err := m.detectImages(time.Now())
+	if err != nil {
+		return err
+	}
+
+	rootDir, err := m.runtime.ImageFsPath()
+	if err != nil {
+		return err
+	}
+	var stat syscall.Statfs_t
+	if err = syscall.Statfs(rootDir, &stat); err != nil {
+		return err
+	}
+	capacity := int64(stat.Blocks) * stat.Bsize
+	available := int64(stat.Bavail) * stat.Bsize
+	if capacity <= 0 {
+		return fmt.Errorf("invalid capacity %d of %s", capacity, rootDir)
+	}
+
+	usagePercent := int((capacity - available) * 100 / capacity)
+	if usagePercent < m.policy.HighThresholdPercent {
+		return nil
+	}
+
+	amountToFree := capacity - available - capacity*int64(m.policy.LowThresholdPercent)/100
+	log.Printf("[INFO]: disk usage of images is %d%%, over %d%%, freeing %d bytes\n",
+		usagePercent, m.policy.HighThresholdPercent, amountToFree)
+	freed := m.freeSpace(images, amountToFree, time.Now())
+	if freed < amountToFree {
+		return fmt.Errorf("freed %d bytes of images, %d bytes expected", freed, amountToFree)
+	}
+	return nil
+}
+
+func (m *imageGCManager) DeleteUnusedImages() (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	images, err := m.detectImages(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return m.freeSpace(images, math.MaxInt64, time.Now()), nil
+}
+
+// detectImages updates records of images, and returns IDs of images not in use
+func (m *imageGCManager) detectImages(now time.Time) ([]string, error) {
+	images, err := m.runtime.ListImages()
+	if err != nil {
+		return nil, err
+	}
+	// containers of all states keep their images
+	inUse, err := m.runtime.ListImagesInUse()
+	if err != nil {
+		return nil, err
+	}
+	pinned := make(map[string]bool)
+	for _, name := range m.policy.PinnedImages {
+		pinned[name] = true
+	}
+
+	unused := make([]string, 0)
+	current := make(map[string]bool)
+	for _, image := range images {
+		current[image.ID] = true
+		record, ok := m.imageRecords[image.ID]
+		if !ok {
+			record = &imageRecord{firstDetected: now}
+			m.imageRecords[image.ID] = record
+		}
+		record.size = image.Size
+
+		isPinned := false
+		for _, tag := range image.RepoTags {
+			isPinned = isPinned || pinned[tag]
+		}
+		if inUse[image.ID] || isPinned {
+			record.lastUsed = now
+			continue
+		}
+		unused = append(unused, image.ID)
+	}
+
+	// forget images removed
+	for id := range m.imageRecords {
+		if !current[id] {
+			delete(m.imageRecords, id)
+		}
+	}
+	return unused, nil
+}
+
+// freeSpace removes unused images, least recently used first, and returns bytes freed
+func (m *imageGCManager) freeSpace(unused []string, amountToFree int64, now time.Time) int64 {
+	sort.Slice(unused, func(i, j int) bool {
+		a, b := m.imageRecords[unused[i]], m.imageRecords[unused[j]]
+		if !a.lastUsed.Equal(b.lastUsed) {
+			return a.lastUsed.Before(b.lastUsed)
+		}
+		return a.firstDetected.Before(b.firstDetected)
+	})
+
+	freed := int64(0)
+	for _, id := range unused {
+		if freed >= amountToFree {
+			break
+		}
+		record := m.imageRecords[id]
+		if now.Sub(record.firstDetected) < m.policy.MinAge {
+			continue
+		}
+		log.Printf("[INFO]: removing image %s to free %d bytes\n", id, record.size)
+		if err := m.runtime.RemoveImage(id); err != nil {
+			continue
+		}
+		delete(m.imageRecords, id)
+		freed += record.size
+	}
+	return freed
+}
@@ -0,0 +1,49 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/images"
+	"Cubernetes/pkg/object"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseImageRegistry(t *testing.T) {
+	assert.Equal(t, "docker.io", images.ParseImageRegistry("nginx"))
+	assert.Equal(t, "docker.io", images.ParseImageRegistry("yiyanleee/gpuserver:v2"))
+	assert.Equal(t, "registry.example.com", images.ParseImageRegistry("registry.example.com/team/app:1.0"))
+	assert.Equal(t, "localhost:5000", images.ParseImageRegistry("localhost:5000/app"))
+}
+
+func TestLookupAuth(t *testing.T) {
+	config := `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("hub:secret")) + `"},
+		"registry.example.com": {"username": "user", "password": "pass"}
+	}}`
+	secret := &object.Secret{
+		Type: object.SecretTypeDockerConfigJson,
+		Data: map[string]string{object.DockerConfigJsonKey: base64.StdEncoding.EncodeToString([]byte(config))},
+	}
+	opaque := &object.Secret{Type: object.SecretTypeOpaque, Data: secret.Data}
+
+	auth := images.LookupAuth("registry.example.com/app", []*object.Secret{opaque, secret})
+	assert.NotNil(t, auth)
+	assert.Equal(t, "user", auth.Username)
+	assert.Equal(t, "pass", auth.Password)
+
+	auth = images.LookupAuth("nginx:1.21", []*object.Secret{secret})
+	assert.NotNil(t, auth)
+	assert.Equal(t, "hub", auth.Username)
+	assert.Equal(t, "secret", auth.Password)
+
+	assert.Nil(t, images.LookupAuth("quay.io/app", []*object.Secret{secret}))
+	assert.Nil(t, images.LookupAuth("registry.example.com/app", []*object.Secret{opaque}))
+}
+
+func TestDefaultImagePullPolicy(t *testing.T) {
+	assert.Equal(t, object.PullAlways, object.DefaultImagePullPolicy("nginx"))
+	assert.Equal(t, object.PullAlways, object.DefaultImagePullPolicy("nginx:latest"))
+	assert.Equal(t, object.PullAlways, object.DefaultImagePullPolicy("localhost:5000/app"))
+	assert.Equal(t, object.PullIfNotPresent, object.DefaultImagePullPolicy("localhost:5000/app:1.0"))
+	assert.Equal(t, object.PullIfNotPresent, object.DefaultImagePullPolicy("nginx@sha256:0123"))
+}
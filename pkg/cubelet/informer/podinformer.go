@@ -3,6 +3,7 @@ package informer
 import (
 	"Cubernetes/pkg/apiserver/crudobj"
 	"Cubernetes/pkg/apiserver/watchobj"
+	"Cubernetes/pkg/cubelet/checkpoint"
 	"Cubernetes/pkg/cubelet/informer/types"
 	"Cubernetes/pkg/object"
 	"log"
@@ -15,15 +16,21 @@ type PodInformer interface {
 	WatchPodEvent() <-chan types.PodEvent
 	SetNodeUID(uid string)
 	ListPods() []object.Pod
+	GetPod(uid string) (object.Pod, bool)
 	ForceRemove(uid string)
+	// HasSynced tells whether pods have been listed from apiserver, before that
+	// pods in cache are restored from checkpoint, and pods removed from apiserver may be missed
+	HasSynced() bool
 }
 
 const WatchRetryIntervalSec = 10
 
-func NewPodInformer() (PodInformer, error) {
+// NewPodInformer creates the informer, pods in cache are saved into podCheckpoint
+func NewPodInformer(podCheckpoint checkpoint.PodCheckpoint) (PodInformer, error) {
 	return &cubePodInformer{
-		podEvent: make(chan types.PodEvent),
-		podCache: make(map[string]object.Pod),
+		podEvent:      make(chan types.PodEvent),
+		podCache:      make(map[string]object.Pod),
+		podCheckpoint: podCheckpoint,
 	}, nil
 }
 
@@ -32,6 +39,9 @@ type cubePodInformer struct {
 	podEvent chan types.PodEvent
 	podCache map[string]object.Pod
 	lock     sync.Mutex
+
+	podCheckpoint checkpoint.PodCheckpoint
+	synced        bool
 }
 
 func (i *cubePodInformer) ListAndWatchPodsWithRetry() {
@@ -51,16 +61,9 @@ func (i *cubePodInformer) tryListAndWatchPods() {
 		log.Printf("[INFO]: will retry after %d seconds...\n", WatchRetryIntervalSec)
 		return
 	} else {
-		// put all pods to pod cache
-		for _, pod := range allPods {
-			if pod.Status != nil && pod.Status.NodeUID == i.nodeUID {
-				// simply initialize cache without inform
-				// informer could lead to Create event because cache is empty
-				// we assume that no new pod will be bound since apiserver lost connection with this Node
-				// much simplified :)
-				i.podCache[pod.UID] = pod
-			}
-		}
+		// pods bound, changed or removed since apiserver lost connection, or cubelet restarted,
+		// are informed as if they were watched
+		i.relistPods(allPods)
 	}
 
 	// then watch pod status change
@@ -103,11 +106,53 @@ func (i *cubePodInformer) tryListAndWatchPods() {
 	}
 }
 
+// SetNodeUID restores pods of the node from checkpoint, they are kept running before apiserver is connected
 func (i *cubePodInformer) SetNodeUID(uid string) {
 	if i.nodeUID != "" {
 		log.Printf("[FATAL]: Node ID already set!\n")
 	}
 	i.nodeUID = uid
+
+	pods, err := i.podCheckpoint.LoadPods()
+	if err != nil {
+		log.Printf("[Error]: fail to load pods from checkpoint: %v\n", err)
+		return
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	for _, pod := range pods {
+		if pod.Status != nil && pod.Status.NodeUID == uid {
+			i.podCache[pod.UID] = pod
+		}
+	}
+	log.Printf("[INFO]: %d pods restored from checkpoint\n", len(i.podCache))
+}
+
+// relistPods informs differences between pods listed and those in cache
+func (i *cubePodInformer) relistPods(allPods []object.Pod) {
+	listed := make(map[string]bool)
+	for _, pod := range allPods {
+		if pod.Status != nil && pod.Status.NodeUID == i.nodeUID {
+			listed[pod.UID] = true
+			i.informPod(pod, watchobj.EVENT_PUT)
+		}
+	}
+	for _, pod := range i.ListPods() {
+		if !listed[pod.UID] {
+			log.Printf("[INFO]: pod %s is removed while watch is lost\n", pod.Name)
+			i.informPod(pod, watchobj.EVENT_DELETE)
+		}
+	}
+
+	i.lock.Lock()
+	i.synced = true
+	i.lock.Unlock()
+}
+
+func (i *cubePodInformer) HasSynced() bool {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.synced
 }
 
 func (i *cubePodInformer) WatchPodEvent() <-chan types.PodEvent {
@@ -115,6 +160,8 @@ func (i *cubePodInformer) WatchPodEvent() <-chan types.PodEvent {
 }
 
 func (i *cubePodInformer) ListPods() []object.Pod {
+	i.lock.Lock()
+	defer i.lock.Unlock()
 	pods := make([]object.Pod, len(i.podCache))
 	idx := 0
 	for _, pod := range i.podCache {
@@ -125,21 +172,46 @@ func (i *cubePodInformer) ListPods() []object.Pod {
 	return pods
 }
 
+func (i *cubePodInformer) GetPod(uid string) (object.Pod, bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	pod, ok := i.podCache[uid]
+	return pod, ok
+}
+
 func (i *cubePodInformer) ForceRemove(uid string) {
 	i.lock.Lock()
 	defer i.lock.Unlock()
 	delete(i.podCache, uid)
+	i.saveCheckpoint()
 }
 
+// saveCheckpoint must be called with lock held
+func (i *cubePodInformer) saveCheckpoint() {
+	pods := make([]object.Pod, 0, len(i.podCache))
+	for _, pod := range i.podCache {
+		pods = append(pods, pod)
+	}
+	if err := i.podCheckpoint.SavePods(pods); err != nil {
+		log.Printf("[Error]: fail to save pods into checkpoint: %v\n", err)
+	}
+}
+
+// informPod updates cache and checkpoint before the event is sent, lock is not held while sending,
+// so that handlers of events can get pods in cache. Checkpoint is not saved on changes of status
 func (i *cubePodInformer) informPod(newPod object.Pod, eType watchobj.EventType) error {
+	i.lock.Lock()
 	oldPod, exist := i.podCache[newPod.UID]
 
 	if eType == watchobj.EVENT_DELETE {
 		if exist {
 			delete(i.podCache, newPod.UID)
+			i.saveCheckpoint()
+			i.lock.Unlock()
 			i.podEvent <- types.PodEvent{
 				Type: types.Remove,
 				Pod:  newPod}
+			return nil
 		} else {
 			log.Printf("pod %s not exist, DELETE do nothing\n", newPod.Name)
 		}
@@ -149,23 +221,34 @@ func (i *cubePodInformer) informPod(newPod object.Pod, eType watchobj.EventType)
 		// update podCache anyway
 		i.podCache[newPod.UID] = newPod
 		if !exist {
+			i.saveCheckpoint()
+			i.lock.Unlock()
 			// UID never seen -> create new Pod
 			i.podEvent <- types.PodEvent{
 				Type: types.Create,
 				Pod:  newPod}
+			return nil
 		} else {
 			// compute pod change: Name / Label / Spec
 			if object.ComputeObjectMetaChange(&newPod.ObjectMeta, &oldPod.ObjectMeta) ||
 				object.ComputePodSpecChange(&newPod.Spec, &oldPod.Spec) {
 				log.Printf("pod %s spec configured\n", newPod.Name)
+				i.saveCheckpoint()
+				i.lock.Unlock()
 				i.podEvent <- types.PodEvent{
 					Type: types.Update,
 					Pod:  newPod}
+				return nil
 			} else {
 				log.Printf("pod %s spec not change\n", newPod.Name)
+				if object.IsPodEvicted(&newPod) != object.IsPodEvicted(&oldPod) {
+					// evicted pods are not restarted after cubelet restarts
+					i.saveCheckpoint()
+				}
 			}
 		}
 	}
 
+	i.lock.Unlock()
 	return nil
 }
@@ -0,0 +1,192 @@
+package dns
+
+import (
+	"Cubernetes/pkg/object"
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// HostResolvConf is the resolv.conf of the node, used by pods with Default DNS policy
+const HostResolvConf = "/etc/resolv.conf"
+
+// defaultNdots lets names with less than 5 dots, e.g. <svc>.<ns>.svc, try search paths first
+const defaultNdots = "ndots:5"
+
+// Config is what is written into resolv.conf of containers
+type Config struct {
+	Servers  []string
+	Searches []string
+	Options  []string
+}
+
+// ResolvConf formats the config as resolv.conf
+func (c *Config) ResolvConf() []byte {
+	var buf bytes.Buffer
+	for _, server := range c.Servers {
+		fmt.Fprintf(&buf, "nameserver %s\n", server)
+	}
+	if len(c.Searches) != 0 {
+		fmt.Fprintf(&buf, "search %s\n", strings.Join(c.Searches, " "))
+	}
+	if len(c.Options) != 0 {
+		fmt.Fprintf(&buf, "options %s\n", strings.Join(c.Options, " "))
+	}
+	return buf.Bytes()
+}
+
+// Configurer makes DNS config of pods by their DNS policy
+type Configurer struct {
+	clusterDNS    []string
+	clusterDomain string
+	// extraSearches are searched after the cluster domain by ClusterFirst pods
+	extraSearches []string
+	// hostResolvConf is read for Default DNS policy
+	hostResolvConf string
+}
+
+// NewConfigurer creates the configurer, ClusterFirst pods use clusterDNS as nameservers, and search
+// in clusterDomain first, then in extraSearches. clusterDomain may be empty if clusterDNS doesn't resolve it,
+// so that lookups of other names don't try the cluster search paths first
+func NewConfigurer(clusterDNS []string, clusterDomain string, extraSearches []string, hostResolvConf string) *Configurer {
+	return &Configurer{
+		clusterDNS:     clusterDNS,
+		clusterDomain:  strings.Trim(clusterDomain, "."),
+		extraSearches:  extraSearches,
+		hostResolvConf: hostResolvConf,
+	}
+}
+
+// GetPodDNS returns DNS config of containers in the pod, dnsConfig of the pod is merged
+// into the one made by its policy
+func (c *Configurer) GetPodDNS(pod *object.Pod) *Config {
+	policy := pod.Spec.DNSPolicy
+	if policy == "" {
+		policy = object.DNSClusterFirst
+	}
+	if policy == object.DNSClusterFirst && (pod.Spec.HostNetwork || len(c.clusterDNS) == 0) {
+		// names in the node network are not resolved by cluster DNS
+		policy = object.DNSDefault
+	}
+
+	config := &Config{}
+	switch policy {
+	case object.DNSClusterFirst:
+		config.Servers = append(config.Servers, c.clusterDNS...)
+		namespace := pod.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		if c.clusterDomain != "" {
+			config.Searches = append(config.Searches,
+				fmt.Sprintf("%s.svc.%s", namespace, c.clusterDomain),
+				fmt.Sprintf("svc.%s", c.clusterDomain),
+				c.clusterDomain)
+			config.Options = []string{defaultNdots}
+		}
+		config.Searches = appendUnique(config.Searches, c.extraSearches...)
+	case object.DNSDefault:
+		hostConfig, err := parseResolvConf(c.hostResolvConf)
+		if err != nil {
+			log.Printf("[Error]: fail to read resolv.conf of the node: %v\n", err)
+		} else {
+			config = hostConfig
+		}
+	}
+
+	if dnsConfig := pod.Spec.DNSConfig; dnsConfig != nil {
+		config.Servers = appendUnique(config.Servers, dnsConfig.Nameservers...)
+		config.Searches = appendUnique(config.Searches, dnsConfig.Searches...)
+		config.Options = mergeOptions(config.Options, dnsConfig.Options)
+	}
+
+	if len(config.Servers) > object.MaxDNSNameservers {
+		log.Printf("[WARNING]: nameservers of pod %s exceed the limit %d, the others are omitted\n",
+			pod.Name, object.MaxDNSNameservers)
+		config.Servers = config.Servers[:object.MaxDNSNameservers]
+	}
+	if len(config.Searches) > object.MaxDNSSearches {
+		log.Printf("[WARNING]: searches of pod %s exceed the limit %d, the others are omitted\n",
+			pod.Name, object.MaxDNSSearches)
+		config.Searches = config.Searches[:object.MaxDNSSearches]
+	}
+	return config
+}
+
+// parseResolvConf reads nameservers, searches and options of resolv.conf, the last search line wins
+func parseResolvConf(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config := &Config{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexAny(line, "#;"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			config.Servers = append(config.Servers, fields[1])
+		case "search", "domain":
+			config.Searches = fields[1:]
+		case "options":
+			config.Options = append(config.Options, fields[1:]...)
+		}
+	}
+	return config, scanner.Err()
+}
+
+func appendUnique(values []string, added ...string) []string {
+	for _, value := range added {
+		exists := false
+		for _, v := range values {
+			if v == value {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// mergeOptions replaces options with the same name, e.g. ndots:2 replaces ndots:5
+func mergeOptions(options []string, added []object.PodDNSConfigOption) []string {
+	merged := make([]string, 0, len(options)+len(added))
+	index := make(map[string]int)
+	for _, option := range options {
+		name := strings.SplitN(option, ":", 2)[0]
+		if idx, ok := index[name]; ok {
+			merged[idx] = option
+			continue
+		}
+		index[name] = len(merged)
+		merged = append(merged, option)
+	}
+	for _, option := range added {
+		value := option.Name
+		if option.Value != nil {
+			value += ":" + *option.Value
+		}
+		if idx, ok := index[option.Name]; ok {
+			merged[idx] = value
+			continue
+		}
+		index[option.Name] = len(merged)
+		merged = append(merged, value)
+	}
+	return merged
+}
@@ -0,0 +1,66 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/network/dns"
+	"Cubernetes/pkg/object"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const hostResolvConf = `# generated by resolvconf
+nameserver 192.168.1.1
+search example.com
+options timeout:2
+`
+
+func TestGetPodDNS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(hostResolvConf), 0644))
+	configurer := dns.NewConfigurer([]string{"172.17.0.1"}, "cluster.local.", []string{"weave.local"}, path)
+
+	pod := &object.Pod{ObjectMeta: object.ObjectMeta{Name: "web", Namespace: "prod"}}
+	config := configurer.GetPodDNS(pod)
+	assert.Equal(t, []string{"172.17.0.1"}, config.Servers)
+	assert.Equal(t, []string{"prod.svc.cluster.local", "svc.cluster.local", "cluster.local", "weave.local"},
+		config.Searches)
+	assert.Equal(t, "nameserver 172.17.0.1\n"+
+		"search prod.svc.cluster.local svc.cluster.local cluster.local weave.local\n"+
+		"options ndots:5\n", string(config.ResolvConf()))
+
+	// dnsConfig is merged, options with the same name are replaced
+	ndots := "2"
+	pod.Spec.DNSConfig = &object.PodDNSConfig{
+		Nameservers: []string{"8.8.8.8", "172.17.0.1"},
+		Searches:    []string{"corp.internal"},
+		Options:     []object.PodDNSConfigOption{{Name: "ndots", Value: &ndots}, {Name: "edns0"}},
+	}
+	config = configurer.GetPodDNS(pod)
+	assert.Equal(t, []string{"172.17.0.1", "8.8.8.8"}, config.Servers)
+	assert.Len(t, config.Searches, 5)
+	assert.Equal(t, []string{"ndots:2", "edns0"}, config.Options)
+
+	// hostNetwork pods resolve names as the node does
+	pod.Spec.DNSConfig = nil
+	pod.Spec.HostNetwork = true
+	config = configurer.GetPodDNS(pod)
+	assert.Equal(t, []string{"192.168.1.1"}, config.Servers)
+	assert.Equal(t, []string{"example.com"}, config.Searches)
+	assert.Equal(t, []string{"timeout:2"}, config.Options)
+
+	// without cluster domain, only extra searches are tried
+	pod.Spec.HostNetwork = false
+	config = dns.NewConfigurer([]string{"172.17.0.1"}, "", []string{"weave.local"}, path).GetPodDNS(pod)
+	assert.Equal(t, "nameserver 172.17.0.1\nsearch weave.local\n", string(config.ResolvConf()))
+
+	// the cluster domain served by weave DNS is searched once
+	config = dns.NewConfigurer([]string{"172.17.0.1"}, "cluster.local", []string{"cluster.local"}, path).GetPodDNS(pod)
+	assert.Equal(t, []string{"prod.svc.cluster.local", "svc.cluster.local", "cluster.local"}, config.Searches)
+
+	pod.Spec.DNSPolicy = object.DNSNone
+	pod.Spec.DNSConfig = &object.PodDNSConfig{Nameservers: []string{"1.1.1.1"}}
+	config = configurer.GetPodDNS(pod)
+	assert.Equal(t, "nameserver 1.1.1.1\n", string(config.ResolvConf()))
+}
@@ -0,0 +1,198 @@
+package hostport
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/staging/pkg/cubelet/network"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+)
+
+const (
+	natTable         = "nat"
+	preRoutingChain  = "PREROUTING"
+	outputChain      = "OUTPUT"
+	hostportsChain   = "CUBE-HOSTPORTS"
+	podChainPrefix   = "CUBE-HP-"
+	iptablesTimeout  = 3
+	podChainHashSize = 16
+)
+
+// Manager forwards host ports of pods to their IPs in weave network by DNAT rules,
+// since docker doesn't publish ports of containers in weave network.
+// Traffic to local addresses of the node goes through CUBE-HOSTPORTS, which jumps to a chain per pod
+type Manager interface {
+	// Add forwards host ports of the pod to podIP, rules added before for the pod are replaced
+	Add(pod *object.Pod, podIP net.IP) error
+	// Remove deletes rules of the pod, nothing is done if it has none
+	Remove(podUID string) error
+}
+
+type hostportManager struct {
+	lock sync.Mutex
+	// ipt is nil if iptables is not found, cubelet still runs as long as no pod has host ports
+	ipt *iptables.IPTables
+	// pods whose rules are in CUBE-HOSTPORTS, rules of other pods are never touched
+	pods map[string]bool
+}
+
+// NewManager restores pods with rules added before cubelet restarts, so that their rules are removed
+// when they are killed
+func NewManager() Manager {
+	m := &hostportManager{pods: make(map[string]bool)}
+	ipt, err := iptables.New(iptables.Timeout(iptablesTimeout))
+	if err != nil {
+		log.Printf("[WARNING]: iptables is not found, host ports of pods are not forwarded: %v\n", err)
+		return m
+	}
+	m.ipt = ipt
+	if err = m.restorePods(); err != nil {
+		log.Printf("[WARNING]: fail to restore host ports of pods: %v\n", err)
+	}
+	return m
+}
+
+func (m *hostportManager) Add(pod *object.Pod, podIP net.IP) error {
+	mapping := network.ConstructPodPortMapping(pod, podIP)
+	portMappings := make([]*network.PortMapping, 0, len(mapping.PortMappings))
+	for _, pm := range mapping.PortMappings {
+		if pm.HostPort != 0 {
+			portMappings = append(portMappings, pm)
+		}
+	}
+	if len(portMappings) == 0 {
+		return m.Remove(pod.UID)
+	}
+	if podIP == nil {
+		return fmt.Errorf("pod %s has no IP for host ports", pod.Name)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ipt, err := m.ensureChains()
+	if err != nil {
+		return err
+	}
+	m.pods[pod.UID] = true
+
+	podChain := podChainName(pod.UID)
+	// ClearChain creates the chain if it doesn't exist
+	if err = ipt.ClearChain(natTable, podChain); err != nil {
+		return err
+	}
+	for _, pm := range portMappings {
+		rule := []string{"-p", toProtocol(pm.Protocol), "--dport", strconv.Itoa(int(pm.HostPort))}
+		if pm.HostIP != "" && pm.HostIP != "0.0.0.0" {
+			rule = append(rule, "-d", pm.HostIP)
+		}
+		rule = append(rule, "-j", "DNAT",
+			"--to-destination", net.JoinHostPort(podIP.String(), strconv.Itoa(int(pm.ContainerPort))))
+		if err = ipt.Append(natTable, podChain, rule...); err != nil {
+			return err
+		}
+	}
+	if err = ipt.AppendUnique(natTable, hostportsChain, podJumpRule(pod.UID)...); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO]: %d host ports of pod %s forwarded to %s\n", len(portMappings), pod.Name, podIP.String())
+	return nil
+}
+
+func (m *hostportManager) Remove(podUID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if !m.pods[podUID] {
+		return nil
+	}
+	ipt := m.ipt
+
+	if err := ipt.DeleteIfExists(natTable, hostportsChain, podJumpRule(podUID)...); err != nil {
+		return err
+	}
+	podChain := podChainName(podUID)
+	if exists, err := ipt.ChainExists(natTable, podChain); err != nil {
+		return err
+	} else if exists {
+		if err = ipt.ClearAndDeleteChain(natTable, podChain); err != nil {
+			return err
+		}
+	}
+	delete(m.pods, podUID)
+	log.Printf("[INFO]: remove host ports of pod %s\n", podUID)
+	return nil
+}
+
+// restorePods finds pods by comments of jump rules in CUBE-HOSTPORTS
+func (m *hostportManager) restorePods() error {
+	if exists, err := m.ipt.ChainExists(natTable, hostportsChain); err != nil || !exists {
+		return err
+	}
+	rules, err := m.ipt.List(natTable, hostportsChain)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		fields := strings.Fields(rule)
+		for idx := 0; idx+1 < len(fields); idx++ {
+			if fields[idx] == "--comment" {
+				m.pods[strings.Trim(fields[idx+1], "\"")] = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// ensureChains creates CUBE-HOSTPORTS, and jumps to it for traffic from outside and from the node itself
+func (m *hostportManager) ensureChains() (*iptables.IPTables, error) {
+	ipt := m.ipt
+	if ipt == nil {
+		return nil, fmt.Errorf("iptables is not found")
+	}
+	if exists, err := ipt.ChainExists(natTable, hostportsChain); err != nil {
+		return nil, err
+	} else if !exists {
+		if err = ipt.NewChain(natTable, hostportsChain); err != nil {
+			return nil, err
+		}
+	}
+
+	jump := []string{"-m", "addrtype", "--dst-type", "LOCAL", "-j", hostportsChain}
+	for _, chain := range []string{preRoutingChain, outputChain} {
+		exists, err := ipt.Exists(natTable, chain, jump...)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if err = ipt.Insert(natTable, chain, 1, jump...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ipt, nil
+}
+
+// podChainName hashes the pod UID, since chain names are limited to 28 characters
+func podChainName(podUID string) string {
+	hash := sha256.Sum256([]byte(podUID))
+	return podChainPrefix + strings.ToUpper(hex.EncodeToString(hash[:]))[:podChainHashSize]
+}
+
+func podJumpRule(podUID string) []string {
+	return []string{"-m", "comment", "--comment", podUID, "-j", podChainName(podUID)}
+}
+
+func toProtocol(protocol string) string {
+	if protocol == "" {
+		return "tcp"
+	}
+	return strings.ToLower(protocol)
+}
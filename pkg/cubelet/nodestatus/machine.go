@@ -0,0 +1,72 @@
+package nodestatus
+
+import (
+	"Cubernetes/pkg/object"
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const osReleaseFile = "/etc/os-release"
+
+// detectCapacity returns cpus, memory and ephemeral storage of the node,
+// ephemeral storage is the size of the filesystem of rootDir
+func detectCapacity(rootDir string) (object.NodeResources, error) {
+	capacity := object.NodeResources{Cpus: float64(runtime.NumCPU())}
+
+	var info unix.Sysinfo_t
+	if err := unix.Sysinfo(&info); err != nil {
+		return capacity, err
+	}
+	capacity.Memory = int64(info.Totalram) * int64(info.Unit)
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(rootDir, &stat); err != nil {
+		return capacity, err
+	}
+	capacity.EphemeralStorage = int64(stat.Blocks) * stat.Bsize
+	return capacity, nil
+}
+
+// detectSystemInfo returns kernel, OS and architecture of the node
+func detectSystemInfo() object.NodeSystemInfo {
+	info := object.NodeSystemInfo{
+		OSImage:         readOSImage(),
+		OperatingSystem: runtime.GOOS,
+		Architecture:    runtime.GOARCH,
+	}
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err == nil {
+		info.KernelVersion = unix.ByteSliceToString(uname.Release[:])
+	}
+	return info
+}
+
+// readOSImage returns PRETTY_NAME of os-release, or "Unknown"
+func readOSImage() string {
+	file, err := os.Open(osReleaseFile)
+	if err != nil {
+		return "Unknown"
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// PRETTY_NAME="Ubuntu 20.04.4 LTS"
+		if value, ok := cutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return "Unknown"
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,184 @@
+package nodestatus
+
+import (
+	cubeconfig "Cubernetes/config"
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/eviction"
+	"Cubernetes/pkg/object"
+	"log"
+	"sort"
+	"sync"
+)
+
+// maxImages is the number of images reported in node status, the largest ones are kept
+const maxImages = 50
+
+type Config struct {
+	// RootDir is on the filesystem whose size is the ephemeral storage capacity
+	RootDir string
+	// SystemReserved is kept for system daemons, it is not allocatable to pods
+	SystemReserved object.NodeResources
+	// memory and nodefs thresholds are not allocatable either, or pods would be evicted
+	// before they use up their requests
+	EvictionThresholds []eviction.Threshold
+	MaxPods            int
+	CubeletPort        int
+}
+
+// Manager detects resources, system info and images of the node, which are reported in node status
+type Manager interface {
+	// Update detects the node again, capacity and system info are detected once
+	Update()
+	// SetNodeStatus fills capacity, allocatable, system info, images and daemon endpoints
+	// of the status by the last Update
+	SetNodeStatus(status *object.NodeStatus)
+}
+
+func NewManager(runtime cubecontainer.ImageService, config Config) Manager {
+	return &manager{runtime: runtime, config: config}
+}
+
+type manager struct {
+	runtime cubecontainer.ImageService
+	config  Config
+
+	lock        sync.Mutex
+	capacity    *object.NodeResources
+	allocatable *object.NodeResources
+	nodeInfo    *object.NodeSystemInfo
+	images      []object.ContainerImage
+}
+
+func (m *manager) Update() {
+	m.lock.Lock()
+	detected := m.capacity != nil
+	m.lock.Unlock()
+
+	var capacity, allocatable *object.NodeResources
+	var nodeInfo *object.NodeSystemInfo
+	if !detected {
+		c, err := detectCapacity(m.config.RootDir)
+		if err != nil {
+			log.Printf("[Error]: fail to detect node capacity: %v\n", err)
+		} else {
+			c.Pods = m.config.MaxPods
+			a := m.computeAllocatable(c)
+			capacity, allocatable = &c, &a
+		}
+		info := detectSystemInfo()
+		info.CubeletVersion = cubeconfig.CubeVersion
+		nodeInfo = &info
+	}
+
+	version, err := m.runtime.RuntimeVersion()
+	if err != nil {
+		log.Printf("[Error]: fail to get container runtime version: %v\n", err)
+	}
+	images, err := m.listImages()
+	if err != nil {
+		log.Printf("[Error]: fail to list images: %v\n", err)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if capacity != nil {
+		m.capacity, m.allocatable = capacity, allocatable
+	}
+	if nodeInfo != nil {
+		m.nodeInfo = nodeInfo
+	}
+	if m.nodeInfo != nil && version != "" {
+		m.nodeInfo.ContainerRuntimeVersion = version
+	}
+	if images != nil {
+		m.images = images
+	}
+}
+
+func (m *manager) SetNodeStatus(status *object.NodeStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.capacity != nil {
+		capacity, allocatable := *m.capacity, *m.allocatable
+		status.Capacity, status.Allocatable = &capacity, &allocatable
+	}
+	if m.nodeInfo != nil {
+		info := *m.nodeInfo
+		status.NodeInfo = &info
+	}
+	status.Images = m.images
+	status.DaemonEndpoints = &object.NodeDaemonEndpoints{CubeletPort: m.config.CubeletPort}
+}
+
+// computeAllocatable subtracts system reservations and eviction thresholds from capacity
+func (m *manager) computeAllocatable(capacity object.NodeResources) object.NodeResources {
+	reserved := m.config.SystemReserved
+	allocatable := object.NodeResources{
+		Cpus:             capacity.Cpus - reserved.Cpus,
+		Memory:           capacity.Memory - reserved.Memory,
+		EphemeralStorage: capacity.EphemeralStorage - reserved.EphemeralStorage,
+		Pods:             capacity.Pods,
+	}
+	for _, threshold := range m.config.EvictionThresholds {
+		switch threshold.Signal {
+		case eviction.SignalMemoryAvailable:
+			allocatable.Memory -= thresholdQuantity(threshold, capacity.Memory)
+		case eviction.SignalNodeFsAvailable:
+			allocatable.EphemeralStorage -= thresholdQuantity(threshold, capacity.EphemeralStorage)
+		}
+	}
+
+	if allocatable.Cpus < 0 {
+		allocatable.Cpus = 0
+	}
+	if allocatable.Memory < 0 {
+		allocatable.Memory = 0
+	}
+	if allocatable.EphemeralStorage < 0 {
+		allocatable.EphemeralStorage = 0
+	}
+	return allocatable
+}
+
+func thresholdQuantity(threshold eviction.Threshold, capacity int64) int64 {
+	if threshold.Quantity != 0 {
+		return threshold.Quantity
+	}
+	return int64(float64(capacity) * threshold.Percentage)
+}
+
+// listImages returns images on the node by name, the largest maxImages ones
+func (m *manager) listImages() ([]object.ContainerImage, error) {
+	summaries, err := m.runtime.ListImages()
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]object.ContainerImage, 0, len(summaries))
+	for _, summary := range summaries {
+		names := make([]string, 0, len(summary.RepoTags)+len(summary.RepoDigests))
+		for _, tag := range summary.RepoTags {
+			if tag != "<none>:<none>" {
+				names = append(names, tag)
+			}
+		}
+		for _, digest := range summary.RepoDigests {
+			if digest != "<none>@<none>" {
+				names = append(names, digest)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		images = append(images, object.ContainerImage{Names: names, SizeBytes: summary.Size})
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].SizeBytes > images[j].SizeBytes
+	})
+	if len(images) > maxImages {
+		images = images[:maxImages]
+	}
+	return images, nil
+}
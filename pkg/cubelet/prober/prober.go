@@ -0,0 +1,154 @@
+package prober
+
+import (
+	"Cubernetes/pkg/object"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProbeType string
+
+const (
+	Liveness  ProbeType = "Liveness"
+	Readiness ProbeType = "Readiness"
+	Startup   ProbeType = "Startup"
+)
+
+type Result int
+
+const (
+	Unknown Result = iota
+	Success
+	Failure
+)
+
+func (r Result) String() string {
+	switch r {
+	case Success:
+		return "Success"
+	case Failure:
+		return "Failure"
+	}
+	return "Unknown"
+}
+
+const (
+	defaultTimeoutSeconds   = 1
+	defaultPeriodSeconds    = 10
+	defaultSuccessThreshold = 1
+	defaultFailureThreshold = 3
+	// response body read by http probe is limited
+	maxRespBodyLength = 10 * 1024
+)
+
+// ContainerRuntime is what prober needs from container runtime
+type ContainerRuntime interface {
+	// GetRunningContainerID returns "" if the container of the pod is not running
+	GetRunningContainerID(podUID, containerName string) (string, error)
+	// RunInContainer runs cmd in the container, and returns its exit code and output
+	RunInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error)
+}
+
+func withDefault(value int32, defaultValue int32) int32 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func timeoutOf(probe *object.Probe) time.Duration {
+	return time.Duration(withDefault(probe.TimeoutSeconds, defaultTimeoutSeconds)) * time.Second
+}
+
+func periodOf(probe *object.Probe) time.Duration {
+	return time.Duration(withDefault(probe.PeriodSeconds, defaultPeriodSeconds)) * time.Second
+}
+
+// runProbe returns the result with a message explaining failure
+func runProbe(runtime ContainerRuntime, probe *object.Probe, podIP string, containerID string) (Result, string) {
+	timeout := timeoutOf(probe)
+
+	switch {
+	case probe.Exec != nil:
+		exitCode, output, err := runtime.RunInContainer(containerID, probe.Exec.Command, timeout)
+		if err != nil {
+			return Failure, fmt.Sprintf("exec failed: %v", err)
+		}
+		if exitCode != 0 {
+			return Failure, fmt.Sprintf("exec exited with %d: %s", exitCode, strings.TrimSpace(string(output)))
+		}
+		return Success, ""
+
+	case probe.HTTPGet != nil:
+		return probeHTTP(probe.HTTPGet, podIP, timeout)
+
+	case probe.TCPSocket != nil:
+		host := probe.TCPSocket.Host
+		if host == "" {
+			host = podIP
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(int(probe.TCPSocket.Port))), timeout)
+		if err != nil {
+			return Failure, fmt.Sprintf("tcp probe failed: %v", err)
+		}
+		_ = conn.Close()
+		return Success, ""
+	}
+
+	return Unknown, "no action in probe"
+}
+
+func probeHTTP(action *object.HTTPGetAction, podIP string, timeout time.Duration) (Result, string) {
+	host := action.Host
+	if host == "" {
+		host = podIP
+	}
+	scheme := strings.ToLower(action.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := action.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(int(action.Port))),
+	}
+	target := u.String() + path
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return Failure, fmt.Sprintf("bad http probe %s: %v", target, err)
+	}
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		// certificates of pods are not verified, same as kubernetes
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Failure, fmt.Sprintf("http probe failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRespBodyLength))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return Failure, fmt.Sprintf("http probe failed with statuscode %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return Success, ""
+}
@@ -0,0 +1,240 @@
+package prober
+
+import (
+	"Cubernetes/pkg/object"
+	"fmt"
+	"sync"
+)
+
+// ContainerFailure is sent when liveness or startup probe of a container fails,
+// and the container should be restarted
+type ContainerFailure struct {
+	PodUID        string
+	ContainerName string
+	ContainerID   string
+	ProbeType     ProbeType
+	Message       string
+}
+
+// Manager runs probes of containers of pods on this node
+type Manager interface {
+	// AddPod starts probing containers of a new pod,
+	// or updates IP of a known pod
+	AddPod(pod *object.Pod)
+	RemovePod(UID string)
+	// UpdatePodStatus sets ContainersReady and Ready conditions of the pod by readiness and startup probes,
+	// sidecars have to be ready as well
+	UpdatePodStatus(podUID string, status *object.PodStatus)
+	// ContainerFailures is where failed liveness and startup probes are reported
+	ContainerFailures() <-chan ContainerFailure
+}
+
+type probeKey struct {
+	podUID        string
+	containerName string
+	probeType     ProbeType
+}
+
+type probedPod struct {
+	pod     object.Pod
+	workers map[probeKey]*worker
+}
+
+type manager struct {
+	runtime ContainerRuntime
+
+	lock    sync.Mutex
+	pods    map[string]*probedPod
+	results map[probeKey]Result
+
+	failures chan ContainerFailure
+}
+
+func NewManager(runtime ContainerRuntime) Manager {
+	return &manager{
+		runtime:  runtime,
+		pods:     make(map[string]*probedPod),
+		results:  make(map[probeKey]Result),
+		failures: make(chan ContainerFailure, 100),
+	}
+}
+
+func (m *manager) AddPod(pod *object.Pod) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if probed, ok := m.pods[pod.UID]; ok {
+		if pod.Status != nil && pod.Status.IP != nil {
+			probed.pod.Status = pod.Status
+		}
+		return
+	}
+
+	probed := &probedPod{
+		pod:     *pod,
+		workers: make(map[probeKey]*worker),
+	}
+	for _, container := range probedContainers(pod) {
+		probes := map[ProbeType]*object.Probe{
+			Liveness:  container.LivenessProbe,
+			Readiness: container.ReadinessProbe,
+			Startup:   container.StartupProbe,
+		}
+		for probeType, probe := range probes {
+			if probe == nil {
+				continue
+			}
+			key := probeKey{podUID: pod.UID, containerName: container.Name, probeType: probeType}
+			w := newWorker(m, key, probe)
+			probed.workers[key] = w
+			go w.run()
+		}
+	}
+	m.pods[pod.UID] = probed
+}
+
+func (m *manager) RemovePod(UID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	probed, ok := m.pods[UID]
+	if !ok {
+		return
+	}
+	for key, w := range probed.workers {
+		close(w.stop)
+		delete(m.results, key)
+	}
+	delete(m.pods, UID)
+}
+
+func (m *manager) UpdatePodStatus(podUID string, status *object.PodStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ready, message := status.Phase == object.PodRunning, ""
+	if !ready {
+		message = fmt.Sprintf("pod is %s", status.Phase)
+	}
+
+	sidecars := make(map[string]bool)
+	if probed, ok := m.pods[podUID]; ok {
+		for _, container := range probed.pod.Spec.InitContainers {
+			sidecars[container.Name] = object.IsSidecarContainer(&container)
+		}
+	}
+	for idx := range status.InitContainerStatuses {
+		containerStatus := &status.InitContainerStatuses[idx]
+		if !sidecars[containerStatus.Name] {
+			// other init containers are ready once completed
+			terminated := containerStatus.State.Terminated
+			containerStatus.Ready = terminated != nil && terminated.ExitCode == 0
+			continue
+		}
+		containerStatus.Ready = containerStatus.State.Running != nil &&
+			m.isContainerReady(podUID, containerStatus.Name)
+		if ready && !containerStatus.Ready {
+			ready, message = false, fmt.Sprintf("sidecar %s is not ready", containerStatus.Name)
+		}
+	}
+
+	for idx := range status.ContainerStatuses {
+		containerStatus := &status.ContainerStatuses[idx]
+		containerStatus.Ready = containerStatus.State.Running != nil &&
+			m.isContainerReady(podUID, containerStatus.Name)
+		if ready && !containerStatus.Ready {
+			ready, message = false, fmt.Sprintf("container %s is not ready", containerStatus.Name)
+		}
+	}
+
+	// there are no readiness gates, so the pod is ready when its containers are
+	for _, conditionType := range []object.PodConditionType{object.ContainersReady, object.PodReady} {
+		condition := object.PodCondition{Type: conditionType, Status: object.ConditionTrue}
+		if !ready {
+			condition.Status = object.ConditionFalse
+			condition.Message = message
+		}
+		object.SetPodCondition(status, condition)
+	}
+}
+
+// probedContainers are containers and sidecars of the pod
+func probedContainers(pod *object.Pod) []object.Container {
+	containers := make([]object.Container, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.InitContainers {
+		if object.IsSidecarContainer(&container) {
+			containers = append(containers, container)
+		}
+	}
+	return append(containers, pod.Spec.Containers...)
+}
+
+// isContainerReady checks startup and readiness probes of a running container
+func (m *manager) isContainerReady(podUID string, containerName string) bool {
+	probed, ok := m.pods[podUID]
+	if !ok {
+		return true
+	}
+	for _, probeType := range []ProbeType{Startup, Readiness} {
+		key := probeKey{podUID, containerName, probeType}
+		if _, ok := probed.workers[key]; ok && m.results[key] != Success {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *manager) ContainerFailures() <-chan ContainerFailure {
+	return m.failures
+}
+
+func (m *manager) setResult(key probeKey, result Result) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.pods[key.podUID]; ok {
+		m.results[key] = result
+	}
+}
+
+func (m *manager) getResult(key probeKey) Result {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.results[key]
+}
+
+// isStarted is true if the container has no startup probe, or it succeeded
+func (m *manager) isStarted(podUID, containerName string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	probed, ok := m.pods[podUID]
+	if !ok {
+		return false
+	}
+	key := probeKey{podUID, containerName, Startup}
+	if _, ok := probed.workers[key]; !ok {
+		return true
+	}
+	return m.results[key] == Success
+}
+
+func (m *manager) getPodIP(podUID string) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	probed, ok := m.pods[podUID]
+	if !ok || probed.pod.Status == nil || probed.pod.Status.IP == nil {
+		return ""
+	}
+	return probed.pod.Status.IP.String()
+}
+
+// containerFailed returns false if the failure is not reported since the channel is full
+func (m *manager) containerFailed(failure ContainerFailure) bool {
+	select {
+	case m.failures <- failure:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,124 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/prober"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeRuntime struct {
+	lock     sync.Mutex
+	exitCode int
+}
+
+func (r *fakeRuntime) GetRunningContainerID(podUID, containerName string) (string, error) {
+	return podUID + "-" + containerName, nil
+}
+
+func (r *fakeRuntime) RunInContainer(containerID string, cmd []string, timeout time.Duration) (int, []byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.exitCode, nil, nil
+}
+
+func readyStatus(m prober.Manager, pod *object.Pod) object.ConditionStatus {
+	status := &object.PodStatus{Phase: object.PodRunning}
+	for _, c := range pod.Spec.Containers {
+		status.ContainerStatuses = append(status.ContainerStatuses, object.ContainerStatus{
+			Name:  c.Name,
+			State: object.ContainerState{Running: &object.ContainerStateRunning{}},
+		})
+	}
+	m.UpdatePodStatus(pod.UID, status)
+	// there are no readiness gates, so ContainersReady always agrees with Ready
+	if object.GetPodCondition(status, object.ContainersReady).Status != object.GetPodCondition(status, object.PodReady).Status {
+		return object.ConditionUnknown
+	}
+	return object.GetPodCondition(status, object.PodReady).Status
+}
+
+func TestReadinessProbe(t *testing.T) {
+	healthy := true
+	lock := sync.Mutex{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: "ready-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name: "web",
+			ReadinessProbe: &object.Probe{
+				HTTPGet:          &object.HTTPGetAction{Path: "/healthz", Port: int32(port)},
+				PeriodSeconds:    1,
+				FailureThreshold: 1,
+			},
+		}}},
+		Status: &object.PodStatus{IP: net.ParseIP(host)},
+	}
+
+	m := prober.NewManager(&fakeRuntime{})
+	m.AddPod(pod)
+	defer m.RemovePod(pod.UID)
+
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, object.ConditionTrue, readyStatus(m, pod))
+
+	lock.Lock()
+	healthy = false
+	lock.Unlock()
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, object.ConditionFalse, readyStatus(m, pod))
+
+	// pods not running are never ready
+	status := &object.PodStatus{Phase: object.PodPending}
+	m.UpdatePodStatus(pod.UID, status)
+	assert.Equal(t, object.ConditionFalse, object.GetPodCondition(status, object.PodReady).Status)
+	assert.Equal(t, object.ConditionFalse, object.GetPodCondition(status, object.ContainersReady).Status)
+}
+
+func TestLivenessProbe(t *testing.T) {
+	runtime := &fakeRuntime{exitCode: 1}
+	pod := &object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: "live-pod"},
+		Spec: object.PodSpec{Containers: []object.Container{{
+			Name: "app",
+			LivenessProbe: &object.Probe{
+				Exec:             &object.ExecAction{Command: []string{"cat", "/tmp/healthy"}},
+				PeriodSeconds:    1,
+				FailureThreshold: 2,
+			},
+		}}},
+	}
+
+	m := prober.NewManager(runtime)
+	m.AddPod(pod)
+	defer m.RemovePod(pod.UID)
+
+	select {
+	case failure := <-m.ContainerFailures():
+		assert.Equal(t, "live-pod-app", failure.ContainerID)
+		assert.Equal(t, prober.Liveness, failure.ProbeType)
+	case <-time.After(3 * time.Second):
+		t.Fatal("liveness failure not reported")
+	}
+
+	// liveness probe doesn't affect readiness
+	assert.Equal(t, object.ConditionTrue, readyStatus(m, pod))
+}
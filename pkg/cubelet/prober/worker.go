@@ -0,0 +1,119 @@
+package prober
+
+import (
+	"Cubernetes/pkg/object"
+	"log"
+	"time"
+)
+
+// worker runs one probe of one container periodically, until the pod is removed
+type worker struct {
+	manager *manager
+	key     probeKey
+	probe   *object.Probe
+
+	stop chan struct{}
+
+	// container probed currently, results are reset when it is re-created
+	containerID string
+	firstSeen   time.Time
+	// set after liveness or startup probe failed, until the container is re-created
+	onHold bool
+
+	lastResult Result
+	resultRun  int32
+}
+
+func newWorker(m *manager, key probeKey, probe *object.Probe) *worker {
+	return &worker{
+		manager: m,
+		key:     key,
+		probe:   probe,
+		stop:    make(chan struct{}),
+	}
+}
+
+func (w *worker) run() {
+	ticker := time.NewTicker(periodOf(w.probe))
+	defer ticker.Stop()
+
+	for {
+		w.doProbe()
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *worker) doProbe() {
+	containerID, err := w.manager.runtime.GetRunningContainerID(w.key.podUID, w.key.containerName)
+	if err != nil {
+		log.Printf("[Error]: fail to get container %s of pod %s: %v\n", w.key.containerName, w.key.podUID, err)
+		return
+	}
+	if containerID == "" {
+		// not running, it is not ready until probed again after restart
+		w.containerID = ""
+		w.manager.setResult(w.key, Unknown)
+		return
+	}
+
+	if containerID != w.containerID {
+		w.containerID = containerID
+		w.firstSeen = time.Now()
+		w.onHold = false
+		w.lastResult = Unknown
+		w.resultRun = 0
+		w.manager.setResult(w.key, Unknown)
+	}
+
+	if w.onHold {
+		return
+	}
+	if w.key.probeType == Startup && w.manager.getResult(w.key) == Success {
+		// startup probe stops once it succeeds
+		return
+	}
+	if w.key.probeType != Startup && !w.manager.isStarted(w.key.podUID, w.key.containerName) {
+		return
+	}
+	if time.Since(w.firstSeen) < time.Duration(w.probe.InitialDelaySeconds)*time.Second {
+		return
+	}
+
+	podIP := w.manager.getPodIP(w.key.podUID)
+	result, message := runProbe(w.manager.runtime, w.probe, podIP, containerID)
+	if result == Unknown {
+		return
+	}
+	if result == w.lastResult {
+		w.resultRun++
+	} else {
+		w.lastResult = result
+		w.resultRun = 1
+	}
+
+	if result == Failure && w.resultRun < withDefault(w.probe.FailureThreshold, defaultFailureThreshold) ||
+		result == Success && w.resultRun < withDefault(w.probe.SuccessThreshold, defaultSuccessThreshold) {
+		return
+	}
+
+	if w.manager.getResult(w.key) != result {
+		log.Printf("[INFO]: %s probe of container %s in pod %s: %s %s\n",
+			w.key.probeType, w.key.containerName, w.key.podUID, result, message)
+	}
+	w.manager.setResult(w.key, result)
+
+	if result == Failure && w.key.probeType != Readiness {
+		// wait for the container to be re-created, or report again next time
+		w.onHold = w.manager.containerFailed(ContainerFailure{
+			PodUID:        w.key.podUID,
+			ContainerName: w.key.containerName,
+			ContainerID:   containerID,
+			ProbeType:     w.key.probeType,
+			Message:       message,
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	actruntime "Cubernetes/pkg/cubelet/actorruntime"
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/cubelet/gpuserver"
+	"Cubernetes/pkg/object"
+	"errors"
+	"fmt"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerapi "github.com/docker/docker/client"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+)
+
+func (s *Server) getPodLogs(ctx *gin.Context) {
+	opts, ok := parseLogOptions(ctx)
+	if !ok {
+		return
+	}
+	if opts.Container == "" {
+		ctx.String(http.StatusBadRequest, "container name is required")
+		return
+	}
+
+	writeLogs(ctx, func(stdout, stderr io.Writer) error {
+		return s.podRuntime.GetContainerLogs(ctx.Request.Context(), ctx.Param("uid"), opts, stdout, stderr)
+	})
+}
+
+func (s *Server) getActorLogs(ctx *gin.Context) {
+	opts, ok := parseLogOptions(ctx)
+	if !ok {
+		return
+	}
+	if opts.Previous {
+		ctx.String(http.StatusBadRequest, "actors keep no previous instance")
+		return
+	}
+
+	containers, err := s.dockerRuntime.ListContainers(dockertypes.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", actruntime.ContainerTypeLabel+"="+actruntime.ContainerTypeContainer),
+			filters.Arg("label", actruntime.ActorUIDLabel+"="+ctx.Param("uid")),
+		),
+	})
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "fail to list containers")
+		return
+	}
+	if len(containers) == 0 {
+		ctx.String(http.StatusNotFound, "container of actor is not created yet")
+		return
+	}
+
+	writeLogs(ctx, func(stdout, stderr io.Writer) error {
+		return dockershim.WriteContainerLogs(ctx.Request.Context(), s.dockerRuntime, containers[0].ID, opts, stdout, stderr)
+	})
+}
+
+func (s *Server) getGpuJobLogs(ctx *gin.Context) {
+	opts, ok := parseLogOptions(ctx)
+	if !ok {
+		return
+	}
+	if opts.Previous {
+		ctx.String(http.StatusBadRequest, "gpu jobs keep no previous instance")
+		return
+	}
+
+	// docker resolves container names as well as ids
+	writeLogs(ctx, func(stdout, stderr io.Writer) error {
+		return dockershim.WriteContainerLogs(ctx.Request.Context(), s.dockerRuntime,
+			gpuserver.GetJobDockerName(ctx.Param("uid")), opts, stdout, stderr)
+	})
+}
+
+func parseLogOptions(ctx *gin.Context) (object.LogOptions, bool) {
+	opts, err := object.ParseLogOptions(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return opts, false
+	}
+	return opts, true
+}
+
+// writeLogs replies logs written by write, errors before any log is written are replied with status codes
+func writeLogs(ctx *gin.Context, write func(stdout, stderr io.Writer) error) {
+	ctx.Header("Content-Type", "text/plain; charset=utf-8")
+	writer := &flushWriter{writer: ctx.Writer}
+	err := write(writer, writer)
+
+	switch {
+	case err == nil:
+		if !ctx.Writer.Written() {
+			ctx.Status(http.StatusOK)
+			ctx.Writer.WriteHeaderNow()
+		}
+	case ctx.Writer.Written():
+		if ctx.Request.Context().Err() == nil {
+			log.Printf("[Error]: fail to stream logs: %v\n", err)
+			_, _ = fmt.Fprintf(writer, "\nerror streaming logs: %v\n", err)
+		}
+	case errors.Is(err, cubecontainer.ErrContainerNotFound) || dockerapi.IsErrNotFound(err):
+		ctx.String(http.StatusNotFound, cubecontainer.ErrContainerNotFound.Error())
+	case errors.Is(err, cubecontainer.ErrPreviousContainerNotFound):
+		ctx.String(http.StatusBadRequest, err.Error())
+	default:
+		ctx.String(http.StatusInternalServerError, "fail to read container logs: %v", err)
+	}
+}
+
+// flushWriter sends every chunk to the client right away
+type flushWriter struct {
+	writer gin.ResponseWriter
+}
+
+func (w *flushWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.writer.Flush()
+	return n, err
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	cubeconfig "Cubernetes/config"
+	"Cubernetes/pkg/cubelet/cuberuntime"
+	"Cubernetes/pkg/cubelet/dockershim"
+	"Cubernetes/pkg/utils/localstorage"
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Server serves container logs, exec, attach and port-forward requests proxied by apiserver,
+// every request must carry the cubelet token
+type Server struct {
+	// podRuntime serves logs and streams of pods, on docker or a CRI runtime
+	podRuntime cuberuntime.CubeRuntime
+	// actors and gpu jobs always run on docker
+	dockerRuntime dockershim.DockerRuntime
+	token         string
+}
+
+func NewServer(podRuntime cuberuntime.CubeRuntime, dockerRuntime dockershim.DockerRuntime) *Server {
+	token, err := localstorage.LoadCubeletToken()
+	if err != nil {
+		log.Println("[Error]: fail to load cubelet token,", err)
+	}
+	return &Server{
+		podRuntime:    podRuntime,
+		dockerRuntime: dockerRuntime,
+		token:         token,
+	}
+}
+
+func (s *Server) Run() {
+	// an empty token would accept unauthenticated requests, join the cluster with cuberoot to get one
+	if s.token == "" {
+		log.Printf("[Error]: no cubelet token in %s, refuse to start cubelet http server\n", cubeconfig.CubeletTokenFile)
+		return
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.Default()
+	router.Use(s.authenticate())
+
+	router.GET("/containerLogs/pod/:uid", s.getPodLogs)
+	router.GET("/containerLogs/actor/:uid", s.getActorLogs)
+	router.GET("/containerLogs/gpuJob/:uid", s.getGpuJobLogs)
+
+	router.GET("/exec/pod/:uid", s.execInPod)
+	router.GET("/attach/pod/:uid", s.attachToPod)
+	router.GET("/portForward/pod/:uid", s.portForwardPod)
+
+	err := router.Run(":" + strconv.Itoa(cubeconfig.CubeletPort))
+	if err != nil {
+		log.Println("[Error]: failure when running cubelet http server,", err)
+	}
+}
+
+func (s *Server) authenticate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.GetHeader(cubeconfig.CubeletTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			ctx.String(http.StatusUnauthorized, "unauthorized")
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
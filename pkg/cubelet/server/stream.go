@@ -0,0 +1,191 @@
+package server
+
+import (
+	cubecontainer "Cubernetes/pkg/cubelet/container"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/remotecommand"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+func (s *Server) execInPod(ctx *gin.Context) {
+	opts, ok := parseStreamRequest(ctx)
+	if !ok {
+		return
+	}
+	if len(opts.Command) == 0 {
+		ctx.String(http.StatusBadRequest, "command is required")
+		return
+	}
+	containerID, ok := s.getRunningContainer(ctx, ctx.Param("uid"), opts.Container)
+	if !ok {
+		return
+	}
+
+	conn, err := remotecommand.Upgrade(ctx.Writer, ctx.Request)
+	if err != nil {
+		log.Printf("[Error]: fail to upgrade exec request: %v\n", err)
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	status := serveStreams(conn, opts.Stdin, opts.TTY, func(streamCtx context.Context, streams cubecontainer.Streams) (int, error) {
+		return s.podRuntime.ExecInContainer(streamCtx, containerID, opts.Command, streams)
+	})
+	_ = remotecommand.WriteJSONFrame(conn, remotecommand.ChannelStatus, status)
+}
+
+func (s *Server) attachToPod(ctx *gin.Context) {
+	opts, ok := parseStreamRequest(ctx)
+	if !ok {
+		return
+	}
+	containerID, ok := s.getRunningContainer(ctx, ctx.Param("uid"), opts.Container)
+	if !ok {
+		return
+	}
+
+	conn, err := remotecommand.Upgrade(ctx.Writer, ctx.Request)
+	if err != nil {
+		log.Printf("[Error]: fail to upgrade attach request: %v\n", err)
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	status := serveStreams(conn, opts.Stdin, opts.TTY, func(streamCtx context.Context, streams cubecontainer.Streams) (int, error) {
+		return s.podRuntime.AttachContainer(streamCtx, containerID, streams)
+	})
+	_ = remotecommand.WriteJSONFrame(conn, remotecommand.ChannelStatus, status)
+}
+
+func (s *Server) portForwardPod(ctx *gin.Context) {
+	port, err := object.ParsePortForwardPort(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if !remotecommand.IsUpgradeRequest(ctx.Request) {
+		ctx.String(http.StatusBadRequest, "upgrade to %s is required", remotecommand.StreamProtocol)
+		return
+	}
+
+	target, err := s.podRuntime.PortForward(ctx.Param("uid"), port)
+	if errors.Is(err, cubecontainer.ErrSandboxNotRunning) {
+		ctx.String(http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		ctx.String(http.StatusBadGateway, "fail to connect to port %d in pod: %v", port, err)
+		return
+	}
+
+	conn, err := remotecommand.Upgrade(ctx.Writer, ctx.Request)
+	if err != nil {
+		log.Printf("[Error]: fail to upgrade port-forward request: %v\n", err)
+		_ = target.Close()
+		return
+	}
+	remotecommand.Pipe(conn, target)
+}
+
+func parseStreamRequest(ctx *gin.Context) (object.ExecOptions, bool) {
+	opts, err := object.ParseExecOptions(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return opts, false
+	}
+	if opts.Container == "" {
+		ctx.String(http.StatusBadRequest, "container name is required")
+		return opts, false
+	}
+	if !remotecommand.IsUpgradeRequest(ctx.Request) {
+		ctx.String(http.StatusBadRequest, "upgrade to %s is required", remotecommand.StreamProtocol)
+		return opts, false
+	}
+	return opts, true
+}
+
+func (s *Server) getRunningContainer(ctx *gin.Context, podUID, name string) (string, bool) {
+	containerID, err := s.podRuntime.GetRunningContainerID(podUID, name)
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, "fail to list containers")
+		return "", false
+	}
+	if containerID == "" {
+		ctx.String(http.StatusBadRequest, "container %s is not running", name)
+		return "", false
+	}
+	return containerID, true
+}
+
+// serveStreams runs the process with its output sent to the client in frames, and dispatches stdin
+// and resize frames from the client. The process is stopped once the client is gone
+func serveStreams(conn io.ReadWriter, stdin, tty bool,
+	run func(ctx context.Context, streams cubecontainer.Streams) (int, error)) remotecommand.Status {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stdinReader, stdinWriter := io.Pipe()
+	// stdin frames are dropped once the process ends
+	defer func() { _ = stdinReader.Close() }()
+	resize := make(chan remotecommand.TerminalSize)
+
+	go func() {
+		defer close(resize)
+		for {
+			channel, payload, err := remotecommand.ReadFrame(conn)
+			if err != nil {
+				cancel()
+				_ = stdinWriter.CloseWithError(err)
+				return
+			}
+
+			switch channel {
+			case remotecommand.ChannelStdin:
+				if !stdin {
+					continue
+				}
+				// an empty frame is EOF of stdin
+				if len(payload) == 0 {
+					_ = stdinWriter.Close()
+					continue
+				}
+				if _, err = stdinWriter.Write(payload); err != nil && ctx.Err() == nil {
+					log.Printf("[Error]: fail to write stdin: %v\n", err)
+				}
+			case remotecommand.ChannelResize:
+				var size remotecommand.TerminalSize
+				if json.Unmarshal(payload, &size) != nil {
+					continue
+				}
+				select {
+				case resize <- size:
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	lock := &sync.Mutex{}
+	streams := cubecontainer.Streams{
+		Stdout: remotecommand.NewFrameWriter(lock, conn, remotecommand.ChannelStdout),
+		Stderr: remotecommand.NewFrameWriter(lock, conn, remotecommand.ChannelStderr),
+		TTY:    tty,
+		Resize: resize,
+	}
+	if stdin {
+		streams.Stdin = stdinReader
+	}
+
+	code, err := run(ctx, streams)
+	if err != nil {
+		return remotecommand.Status{ExitCode: -1, Message: err.Error()}
+	}
+	return remotecommand.Status{ExitCode: code}
+}
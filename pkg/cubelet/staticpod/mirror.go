@@ -0,0 +1,51 @@
+package staticpod
+
+import (
+	"Cubernetes/pkg/object"
+)
+
+// MakeMirrorPod makes the mirror pod of a static pod, bound to the node. UID of the mirror pod
+// is given by apiserver, the static pod is referred by its annotation
+func MakeMirrorPod(pod *object.Pod, nodeUID string) object.Pod {
+	mirror := *pod
+	mirror.UID = ""
+	mirror.Annotations = make(map[string]string, len(pod.Annotations)+1)
+	for k, v := range pod.Annotations {
+		mirror.Annotations[k] = v
+	}
+	mirror.Annotations[object.ConfigMirrorAnnotationKey] = pod.UID
+	mirror.Status = &object.PodStatus{
+		NodeUID: nodeUID,
+		Phase:   object.PodPending,
+	}
+	return mirror
+}
+
+// ComputeMirrorPodChanges returns static pods whose mirror pods are to be created, and mirror pods
+// to be deleted, which mirror no static pod, or are being deleted by users. Deleting a mirror pod
+// doesn't stop its static pod, the mirror pod is created again
+func ComputeMirrorPodChanges(staticPods, mirrorPods []object.Pod) (toCreate []object.Pod, toDelete []object.Pod) {
+	static := make(map[string]bool, len(staticPods))
+	for _, pod := range staticPods {
+		static[pod.UID] = true
+	}
+
+	mirrored := make(map[string]bool, len(mirrorPods))
+	toDelete = make([]object.Pod, 0)
+	for _, mirror := range mirrorPods {
+		uid := mirror.Annotations[object.ConfigMirrorAnnotationKey]
+		if !static[uid] || object.IsPodTerminating(&mirror) || mirrored[uid] {
+			toDelete = append(toDelete, mirror)
+			continue
+		}
+		mirrored[uid] = true
+	}
+
+	toCreate = make([]object.Pod, 0)
+	for _, pod := range staticPods {
+		if !mirrored[pod.UID] {
+			toCreate = append(toCreate, pod)
+		}
+	}
+	return toCreate, toDelete
+}
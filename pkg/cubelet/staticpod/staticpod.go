@@ -0,0 +1,200 @@
+package staticpod
+
+import (
+	"Cubernetes/pkg/object"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultManifestPath is the directory cubelet reads static pods from by default
+const DefaultManifestPath = "/etc/cubernetes/manifests"
+
+// Manager keeps static pods read from manifest files in a directory, they are run by cubelet
+// without apiserver, and shown in apiserver by mirror pods
+type Manager interface {
+	// Update reads manifests again, and returns static pods added and removed since the last update.
+	// A pod changed in its manifest gets a new UID, so it is removed and added again
+	Update() (added []object.Pod, removed []object.Pod)
+	ListPods() []object.Pod
+	GetPod(uid string) (object.Pod, bool)
+}
+
+type manager struct {
+	manifestPath string
+	nodeName     string
+
+	lock sync.Mutex
+	// static pods keyed by UID
+	pods map[string]object.Pod
+}
+
+// NewManager reads static pods from manifestPath, names of the pods are suffixed by nodeName.
+// No static pod is read if manifestPath is empty
+func NewManager(manifestPath, nodeName string) Manager {
+	return &manager{
+		manifestPath: manifestPath,
+		nodeName:     nodeName,
+		pods:         make(map[string]object.Pod),
+	}
+}
+
+func (m *manager) Update() ([]object.Pod, []object.Pod) {
+	if m.manifestPath == "" {
+		return nil, nil
+	}
+	pods, err := ReadManifests(m.manifestPath, m.nodeName)
+	if err != nil {
+		log.Printf("[Error]: fail to read static pods in %s: %v\n", m.manifestPath, err)
+		return nil, nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	current := make(map[string]object.Pod, len(pods))
+	added := make([]object.Pod, 0)
+	for _, pod := range pods {
+		current[pod.UID] = pod
+		if _, ok := m.pods[pod.UID]; !ok {
+			log.Printf("[INFO]: static pod %s added from %s\n", pod.Name, pod.Annotations[object.ConfigSourceAnnotationKey])
+			added = append(added, pod)
+		}
+	}
+	removed := make([]object.Pod, 0)
+	for uid, pod := range m.pods {
+		if _, ok := current[uid]; !ok {
+			log.Printf("[INFO]: static pod %s removed\n", pod.Name)
+			removed = append(removed, pod)
+		}
+	}
+	m.pods = current
+	return added, removed
+}
+
+func (m *manager) ListPods() []object.Pod {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	pods := make([]object.Pod, 0, len(m.pods))
+	for _, pod := range m.pods {
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods
+}
+
+func (m *manager) GetPod(uid string) (object.Pod, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	pod, ok := m.pods[uid]
+	return pod, ok
+}
+
+// ReadManifests reads pods in yaml or json files of the directory, files not parsed are skipped.
+// No pod is read if the directory doesn't exist
+func ReadManifests(manifestPath, nodeName string) ([]object.Pod, error) {
+	entries, err := ioutil.ReadDir(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	pods := make([]object.Pod, 0)
+	names := make(map[string]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		path := filepath.Join(manifestPath, entry.Name())
+		pod, err := ReadManifest(path, nodeName)
+		if err != nil {
+			log.Printf("[WARNING]: skip manifest %s: %v\n", path, err)
+			continue
+		}
+		key := pod.Namespace + "/" + pod.Name
+		if other, ok := names[key]; ok {
+			log.Printf("[WARNING]: skip manifest %s: pod %s is already defined in %s\n", path, key, other)
+			continue
+		}
+		names[key] = path
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// ReadManifest reads a static pod from the file, its name is suffixed by nodeName, and its UID
+// is derived from nodeName, path and content of the file, so that it is kept across restarts
+func ReadManifest(path, nodeName string) (object.Pod, error) {
+	var pod object.Pod
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return pod, err
+	}
+	if err = yaml.Unmarshal(buf, &pod); err != nil {
+		return pod, err
+	}
+	if pod.Kind != "" && pod.Kind != object.KindPod {
+		return pod, fmt.Errorf("kind %s is not Pod", pod.Kind)
+	}
+	if pod.Name == "" {
+		return pod, fmt.Errorf("name of pod is empty")
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return pod, fmt.Errorf("pod %s has no container", pod.Name)
+	}
+
+	pod.Kind = object.KindPod
+	pod.APIVersion = "v1"
+	pod.Name = pod.Name + "-" + nodeName
+	if pod.Namespace == "" {
+		pod.Namespace = "default"
+	}
+	pod.UID = uuid.NewSHA1(uuid.NameSpaceURL, append([]byte(nodeName+":"+path+":"), buf...)).String()
+	annotations := map[string]string{
+		object.ConfigSourceAnnotationKey: path,
+		object.ConfigHashAnnotationKey:   pod.UID,
+	}
+	for k, v := range pod.Annotations {
+		if k != object.ConfigMirrorAnnotationKey {
+			annotations[k] = v
+		}
+	}
+	pod.Annotations = annotations
+	pod.DeletionTimestamp = nil
+	pod.DeletionGracePeriodSeconds = nil
+	pod.Status = nil
+	setDefaults(&pod.Spec)
+	return pod, nil
+}
+
+// setDefaults sets default values of the spec as apiserver does
+func setDefaults(spec *object.PodSpec) {
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = object.RestartPolicyAlways
+	}
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = object.DNSClusterFirst
+	}
+	for idx := range spec.InitContainers {
+		if spec.InitContainers[idx].ImagePullPolicy == "" {
+			spec.InitContainers[idx].ImagePullPolicy = object.DefaultImagePullPolicy(spec.InitContainers[idx].Image)
+		}
+	}
+	for idx := range spec.Containers {
+		if spec.Containers[idx].ImagePullPolicy == "" {
+			spec.Containers[idx].ImagePullPolicy = object.DefaultImagePullPolicy(spec.Containers[idx].Image)
+		}
+	}
+}
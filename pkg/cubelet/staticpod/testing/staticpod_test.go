@@ -0,0 +1,103 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/staticpod"
+	"Cubernetes/pkg/object"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const manifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: proxy
+  labels:
+    app: proxy
+spec:
+  containers:
+    - name: proxy
+      image: nginx:1.21
+`
+
+func TestStaticPodManager(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proxy.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(manifest), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a manifest"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("kind: Pod\nspec: ["), 0644))
+
+	manager := staticpod.NewManager(dir, "node1")
+	added, removed := manager.Update()
+	assert.Len(t, added, 1)
+	assert.Empty(t, removed)
+
+	pod := added[0]
+	assert.Equal(t, "proxy-node1", pod.Name)
+	assert.Equal(t, "default", pod.Namespace)
+	assert.NotEmpty(t, pod.UID)
+	assert.Equal(t, path, pod.Annotations[object.ConfigSourceAnnotationKey])
+	assert.Equal(t, object.RestartPolicyAlways, pod.Spec.RestartPolicy)
+	assert.Equal(t, object.PullIfNotPresent, pod.Spec.Containers[0].ImagePullPolicy)
+	assert.True(t, object.IsStaticPod(&pod))
+
+	// UID is kept if the manifest doesn't change
+	added, removed = manager.Update()
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	same, ok := manager.GetPod(pod.UID)
+	assert.True(t, ok)
+	assert.Equal(t, pod.Name, same.Name)
+
+	// a changed manifest replaces the pod
+	changed := manifest + "      imagePullPolicy: Always\n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte(changed), 0644))
+	added, removed = manager.Update()
+	assert.Len(t, added, 1)
+	assert.Len(t, removed, 1)
+	assert.Equal(t, pod.UID, removed[0].UID)
+	assert.NotEqual(t, pod.UID, added[0].UID)
+	assert.Len(t, manager.ListPods(), 1)
+}
+
+func TestMirrorPods(t *testing.T) {
+	pod := object.Pod{ObjectMeta: object.ObjectMeta{
+		Name: "proxy-node1",
+		UID:  "static-uid",
+		Annotations: map[string]string{
+			object.ConfigSourceAnnotationKey: "/etc/cubernetes/manifests/proxy.yaml",
+			object.ConfigHashAnnotationKey:   "static-uid",
+		},
+	}}
+	mirror := staticpod.MakeMirrorPod(&pod, "node-uid")
+	assert.Empty(t, mirror.UID)
+	assert.True(t, object.IsMirrorPod(&mirror))
+	assert.False(t, object.IsStaticPod(&mirror))
+	assert.Equal(t, "node-uid", mirror.Status.NodeUID)
+	assert.False(t, object.IsMirrorPod(&pod))
+
+	toCreate, toDelete := staticpod.ComputeMirrorPodChanges([]object.Pod{pod}, nil)
+	assert.Len(t, toCreate, 1)
+	assert.Empty(t, toDelete)
+
+	mirror.UID = "mirror-uid"
+	toCreate, toDelete = staticpod.ComputeMirrorPodChanges([]object.Pod{pod}, []object.Pod{mirror})
+	assert.Empty(t, toCreate)
+	assert.Empty(t, toDelete)
+
+	// mirror pods deleted by users are created again
+	deleting := mirror
+	now := time.Now()
+	deleting.DeletionTimestamp = &now
+	toCreate, toDelete = staticpod.ComputeMirrorPodChanges([]object.Pod{pod}, []object.Pod{deleting})
+	assert.Len(t, toCreate, 1)
+	assert.Len(t, toDelete, 1)
+
+	// mirror pods of static pods removed are deleted
+	toCreate, toDelete = staticpod.ComputeMirrorPodChanges(nil, []object.Pod{mirror})
+	assert.Empty(t, toCreate)
+	assert.Equal(t, "mirror-uid", toDelete[0].UID)
+}
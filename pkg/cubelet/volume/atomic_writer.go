@@ -0,0 +1,175 @@
+package volume
+
+import (
+	"Cubernetes/pkg/object"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// dataDirName links to the timestamped directory holding current files of the volume
+	dataDirName    = "..data"
+	newDataDirName = "..data_tmp"
+)
+
+type FileProjection struct {
+	Data []byte
+	Mode int32
+}
+
+// AtomicWriter updates all files of a volume in one step, so that containers never see
+// a partial update: files are written into a new timestamped directory, which ..data is
+// switched to by renaming a symlink, and files in the volume are symlinks into ..data
+type AtomicWriter struct {
+	targetDir string
+}
+
+func NewAtomicWriter(targetDir string) *AtomicWriter {
+	return &AtomicWriter{targetDir: targetDir}
+}
+
+// Write replaces files of the volume with payload, keyed by relative paths.
+// Nothing is done if the payload is the same as current files
+func (w *AtomicWriter) Write(payload map[string]FileProjection) error {
+	for path := range payload {
+		if err := object.CheckVolumeFilePath(path); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(w.targetDir, 0755); err != nil {
+		return err
+	}
+
+	dataDirPath := filepath.Join(w.targetDir, dataDirName)
+	oldTsDir, err := os.Readlink(dataDirPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var oldTsPath string
+	if oldTsDir != "" {
+		oldTsPath = filepath.Join(w.targetDir, oldTsDir)
+		if !payloadChanged(oldTsPath, payload) {
+			return nil
+		}
+	}
+
+	tsPath, err := os.MkdirTemp(w.targetDir, time.Now().Format("..2006_01_02_15_04_05."))
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(tsPath, 0755); err != nil {
+		_ = os.RemoveAll(tsPath)
+		return err
+	}
+	if err = writePayload(tsPath, payload); err != nil {
+		_ = os.RemoveAll(tsPath)
+		return err
+	}
+
+	// rename replaces the old symlink atomically
+	newDataDirPath := filepath.Join(w.targetDir, newDataDirName)
+	_ = os.Remove(newDataDirPath)
+	if err = os.Symlink(filepath.Base(tsPath), newDataDirPath); err != nil {
+		_ = os.RemoveAll(tsPath)
+		return err
+	}
+	if err = os.Rename(newDataDirPath, dataDirPath); err != nil {
+		_ = os.Remove(newDataDirPath)
+		_ = os.RemoveAll(tsPath)
+		return err
+	}
+
+	if err = w.updateUserVisiblePaths(payload); err != nil {
+		return err
+	}
+	if oldTsPath != "" {
+		return os.RemoveAll(oldTsPath)
+	}
+	return nil
+}
+
+// updateUserVisiblePaths links each top level entry of payload into ..data,
+// and removes links of entries no longer in payload
+func (w *AtomicWriter) updateUserVisiblePaths(payload map[string]FileProjection) error {
+	visible := make(map[string]bool)
+	for path := range payload {
+		visible[strings.SplitN(path, "/", 2)[0]] = true
+	}
+
+	for name := range visible {
+		link := filepath.Join(w.targetDir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.Symlink(filepath.Join(dataDirName, name), link); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(w.targetDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") || visible[entry.Name()] || entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		if err = os.Remove(filepath.Join(w.targetDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writePayload(dir string, payload map[string]FileProjection) error {
+	for path, file := range payload {
+		if err := object.CheckVolumeFilePath(path); err != nil {
+			return err
+		}
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, file.Data, os.FileMode(file.Mode)); err != nil {
+			return err
+		}
+		// mode given to WriteFile is masked by umask
+		if err := os.Chmod(fullPath, os.FileMode(file.Mode)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// payloadChanged compares payload with files in dir
+func payloadChanged(dir string, payload map[string]FileProjection) bool {
+	count := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		count++
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, ok := payload[rel]
+		if !ok || info.Mode().Perm() != os.FileMode(file.Mode).Perm() {
+			return os.ErrNotExist
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(data, file.Data) {
+			return os.ErrNotExist
+		}
+		return nil
+	})
+	return err != nil || count != len(payload)
+}
@@ -0,0 +1,337 @@
+package volume
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/utils/mount"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// volumes of a pod are kept in podsRootDirectory/<pod UID>/volumes/<volume name>
+const podsRootDirectory = "/var/lib/cubelet/pods"
+
+type Manager interface {
+	// SetUpPodVolumes prepares volumes of the pod, it is called on every sync of the pod,
+	// files of configMap, secret and downwardAPI volumes are updated if they change
+	SetUpPodVolumes(pod *object.Pod) error
+	// GetVolumeHostPath returns the path on the node to mount for the volume,
+	// and whether it must be mounted read-only due to its persistent volume
+	GetVolumeHostPath(pod *object.Pod, volume *object.Volume) (string, bool)
+	// CleanupPodVolumes unmounts and removes volumes of the pod
+	CleanupPodVolumes(podUID string) error
+	// ListPodsWithVolumes returns UID of pods whose volume directories exist
+	ListPodsWithVolumes() ([]string, error)
+	// CleanupProvisionedLocalVolumes removes directories of local volumes provisioned on the node,
+	// whose persistent volumes are not in volumeNames any more
+	CleanupProvisionedLocalVolumes(volumeNames map[string]bool) error
+}
+
+func NewManager() Manager {
+	return &volumeManager{
+		rootDir:      podsRootDirectory,
+		claimVolumes: make(map[string]map[string]claimVolume),
+		localRootDir: object.LocalProvisionerBaseDir,
+	}
+}
+
+// claimVolume is where the volume bound to a claim is on the node
+type claimVolume struct {
+	hostPath string
+	readOnly bool
+}
+
+type volumeManager struct {
+	rootDir      string
+	localRootDir string
+	// pod UID -> volume name -> volume bound to the claim
+	claimVolumes map[string]map[string]claimVolume
+	lock         sync.Mutex
+}
+
+func (m *volumeManager) podDir(podUID string) string {
+	return filepath.Join(m.rootDir, podUID)
+}
+
+func (m *volumeManager) volumeDir(podUID, name string) string {
+	return filepath.Join(m.podDir(podUID), "volumes", name)
+}
+
+func (m *volumeManager) GetVolumeHostPath(pod *object.Pod, volume *object.Volume) (string, bool) {
+	if !volume.IsManaged() {
+		return volume.HostPath, false
+	}
+	if volume.PersistentVolumeClaim != nil {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		cv := m.claimVolumes[pod.UID][volume.Name]
+		return cv.hostPath, cv.readOnly
+	}
+	return m.volumeDir(pod.UID, volume.Name), false
+}
+
+func (m *volumeManager) SetUpPodVolumes(pod *object.Pod) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	configMaps, secrets, err := getVolumeObjects(pod)
+	if err != nil {
+		return err
+	}
+	claims, volumes, err := getClaimObjects(pod)
+	if err != nil {
+		return err
+	}
+
+	for idx := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[idx]
+		if !volume.IsManaged() {
+			continue
+		}
+
+		dir := m.volumeDir(pod.UID, volume.Name)
+		if volume.EmptyDir != nil {
+			err = setUpEmptyDir(dir, volume.EmptyDir)
+		} else if volume.PersistentVolumeClaim != nil {
+			var cv claimVolume
+			if cv, err = setUpClaimVolume(dir, volume.PersistentVolumeClaim, claims, volumes); err == nil {
+				if m.claimVolumes[pod.UID] == nil {
+					m.claimVolumes[pod.UID] = make(map[string]claimVolume)
+				}
+				m.claimVolumes[pod.UID][volume.Name] = cv
+			}
+		} else {
+			var payload map[string]FileProjection
+			if payload, err = ProjectVolumeFiles(pod, volume, configMaps, secrets); err == nil {
+				err = NewAtomicWriter(dir).Write(payload)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("fail to set up volume %s: %v", volume.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *volumeManager) CleanupPodVolumes(podUID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	podDir := m.podDir(podUID)
+	entries, err := os.ReadDir(filepath.Join(podDir, "volumes"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(podDir, "volumes", entry.Name())
+		mounted, err := isMountPoint(dir)
+		if err != nil {
+			return err
+		}
+		if mounted {
+			if err = unix.Unmount(dir, 0); err != nil {
+				return fmt.Errorf("fail to unmount %s: %v", dir, err)
+			}
+		}
+	}
+
+	log.Printf("[INFO]: remove volumes of pod %s\n", podUID)
+	delete(m.claimVolumes, podUID)
+	return os.RemoveAll(podDir)
+}
+
+func (m *volumeManager) ListPodsWithVolumes() ([]string, error) {
+	entries, err := os.ReadDir(m.rootDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			uids = append(uids, entry.Name())
+		}
+	}
+	return uids, nil
+}
+
+func (m *volumeManager) CleanupProvisionedLocalVolumes(volumeNames map[string]bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	entries, err := os.ReadDir(m.localRootDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || volumeNames[entry.Name()] {
+			continue
+		}
+		log.Printf("[INFO]: remove local volume %s, whose persistent volume is deleted\n", entry.Name())
+		if err = os.RemoveAll(filepath.Join(m.localRootDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getVolumeObjects fetches configMaps and secrets keyed by name, only if volumes of the pod refer to them
+func getVolumeObjects(pod *object.Pod) (map[string]*object.ConfigMap, map[string]*object.Secret, error) {
+	needConfigMaps, needSecrets := false, false
+	for _, volume := range pod.Spec.Volumes {
+		needConfigMaps = needConfigMaps || volume.ConfigMap != nil
+		needSecrets = needSecrets || volume.Secret != nil
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				needConfigMaps = needConfigMaps || source.ConfigMap != nil
+				needSecrets = needSecrets || source.Secret != nil
+			}
+		}
+	}
+
+	configMaps := make(map[string]*object.ConfigMap)
+	secrets := make(map[string]*object.Secret)
+	if needConfigMaps {
+		all, err := crudobj.GetConfigMaps()
+		if err != nil {
+			return nil, nil, err
+		}
+		for idx := range all {
+			configMaps[all[idx].Name] = &all[idx]
+		}
+	}
+	if needSecrets {
+		all, err := crudobj.GetSecrets()
+		if err != nil {
+			return nil, nil, err
+		}
+		for idx := range all {
+			secrets[all[idx].Name] = &all[idx]
+		}
+	}
+	return configMaps, secrets, nil
+}
+
+// getClaimObjects fetches claims and persistent volumes keyed by name, only if volumes of the pod use claims
+func getClaimObjects(pod *object.Pod) (map[string]*object.PersistentVolumeClaim, map[string]*object.PersistentVolume, error) {
+	claims := make(map[string]*object.PersistentVolumeClaim)
+	volumes := make(map[string]*object.PersistentVolume)
+	if len(object.GetPodClaimNames(pod)) == 0 {
+		return claims, volumes, nil
+	}
+
+	pvcs, err := crudobj.GetPersistentVolumeClaims()
+	if err != nil {
+		return nil, nil, err
+	}
+	for idx := range pvcs {
+		claims[pvcs[idx].Name] = &pvcs[idx]
+	}
+	pvs, err := crudobj.GetPersistentVolumes()
+	if err != nil {
+		return nil, nil, err
+	}
+	for idx := range pvs {
+		volumes[pvs[idx].Name] = &pvs[idx]
+	}
+	return claims, volumes, nil
+}
+
+// setUpClaimVolume prepares the volume bound to the claim. A local volume is used in place,
+// its directory is created if it is provisioned; an NFS volume is mounted on dir
+func setUpClaimVolume(dir string, source *object.PersistentVolumeClaimVolumeSource,
+	claims map[string]*object.PersistentVolumeClaim, volumes map[string]*object.PersistentVolume) (claimVolume, error) {
+
+	pvc, ok := claims[source.ClaimName]
+	if !ok {
+		return claimVolume{}, fmt.Errorf("claim %s not found", source.ClaimName)
+	}
+	if pvc.Status == nil || pvc.Status.Phase != object.ClaimBound || pvc.Spec.VolumeName == "" {
+		return claimVolume{}, fmt.Errorf("claim %s is not bound", source.ClaimName)
+	}
+	pv, ok := volumes[pvc.Spec.VolumeName]
+	if !ok {
+		return claimVolume{}, fmt.Errorf("volume %s of claim %s not found", pvc.Spec.VolumeName, pvc.Name)
+	}
+	readOnly := object.IsReadOnlyVolume(pv)
+
+	switch {
+	case pv.Spec.Local != nil:
+		if pv.Annotations[object.AnnProvisionedBy] == object.LocalProvisioner {
+			if err := os.MkdirAll(pv.Spec.Local.Path, 0777); err != nil {
+				return claimVolume{}, err
+			}
+			if err := os.Chmod(pv.Spec.Local.Path, 0777); err != nil {
+				return claimVolume{}, err
+			}
+		} else if _, err := os.Stat(pv.Spec.Local.Path); err != nil {
+			return claimVolume{}, fmt.Errorf("local path of volume %s: %v", pv.Name, err)
+		}
+		return claimVolume{hostPath: pv.Spec.Local.Path, readOnly: readOnly}, nil
+	case pv.Spec.NFS != nil:
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return claimVolume{}, err
+		}
+		mounted, err := isMountPoint(dir)
+		if err != nil {
+			return claimVolume{}, err
+		}
+		if !mounted {
+			if err = mount.MountNFS(pv.Spec.NFS.Server, pv.Spec.NFS.Path, dir, readOnly); err != nil {
+				return claimVolume{}, err
+			}
+		}
+		return claimVolume{hostPath: dir, readOnly: readOnly}, nil
+	}
+	return claimVolume{}, fmt.Errorf("volume %s has no source", pv.Name)
+}
+
+// setUpEmptyDir creates the directory, it is writable by any user in containers;
+// Memory medium mounts a tmpfs on it, whose size is SizeLimit if set
+func setUpEmptyDir(dir string, source *object.EmptyDirVolumeSource) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if source.Medium != object.StorageMediumMemory {
+		return os.Chmod(dir, 0777)
+	}
+
+	mounted, err := isMountPoint(dir)
+	if err != nil || mounted {
+		return err
+	}
+	options := "mode=0777"
+	if source.SizeLimit > 0 {
+		options += ",size=" + strconv.FormatInt(source.SizeLimit, 10)
+	}
+	if err = unix.Mount("tmpfs", dir, "tmpfs", 0, options); err != nil {
+		return fmt.Errorf("fail to mount tmpfs on %s: %v", dir, err)
+	}
+	return nil
+}
+
+// isMountPoint tells if dir is on another device than its parent
+func isMountPoint(dir string) (bool, error) {
+	var stat, parentStat unix.Stat_t
+	if err := unix.Lstat(dir, &stat); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := unix.Lstat(filepath.Dir(dir), &parentStat); err != nil {
+		return false, err
+	}
+	return stat.Dev != parentStat.Dev, nil
+}
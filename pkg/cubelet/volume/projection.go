@@ -0,0 +1,190 @@
+package volume
+
+import (
+	"Cubernetes/pkg/object"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProjectVolumeFiles returns files of a configMap, secret, downwardAPI or projected volume,
+// configMaps and secrets are keyed by name
+func ProjectVolumeFiles(pod *object.Pod, volume *object.Volume,
+	configMaps map[string]*object.ConfigMap, secrets map[string]*object.Secret) (map[string]FileProjection, error) {
+
+	payload := make(map[string]FileProjection)
+	var err error
+	switch {
+	case volume.ConfigMap != nil:
+		source := volume.ConfigMap
+		err = projectConfigMap(payload, configMaps[source.Name], source.Name, source.Items,
+			fileMode(source.DefaultMode), source.Optional)
+	case volume.Secret != nil:
+		source := volume.Secret
+		err = projectSecret(payload, secrets[source.SecretName], source.SecretName, source.Items,
+			fileMode(source.DefaultMode), source.Optional)
+	case volume.DownwardAPI != nil:
+		err = projectDownwardAPI(payload, pod, volume.DownwardAPI.Items, fileMode(volume.DownwardAPI.DefaultMode))
+	case volume.Projected != nil:
+		defaultMode := fileMode(volume.Projected.DefaultMode)
+		for _, source := range volume.Projected.Sources {
+			// paths of sources must not overlap
+			files := make(map[string]FileProjection)
+			switch {
+			case source.ConfigMap != nil:
+				err = projectConfigMap(files, configMaps[source.ConfigMap.Name], source.ConfigMap.Name,
+					source.ConfigMap.Items, defaultMode, source.ConfigMap.Optional)
+			case source.Secret != nil:
+				err = projectSecret(files, secrets[source.Secret.Name], source.Secret.Name,
+					source.Secret.Items, defaultMode, source.Secret.Optional)
+			case source.DownwardAPI != nil:
+				err = projectDownwardAPI(files, pod, source.DownwardAPI.Items, defaultMode)
+			}
+			if err != nil {
+				break
+			}
+			for path, file := range files {
+				if _, ok := payload[path]; ok {
+					return nil, fmt.Errorf("path %s is projected more than once", path)
+				}
+				payload[path] = file
+			}
+		}
+	default:
+		return nil, fmt.Errorf("volume %s has no files to project", volume.Name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func projectConfigMap(payload map[string]FileProjection, configMap *object.ConfigMap, name string,
+	items []object.KeyToPath, defaultMode int32, optional bool) error {
+
+	if configMap == nil {
+		if optional {
+			return nil
+		}
+		return fmt.Errorf("configMap %s not found", name)
+	}
+	return projectKeys(payload, items, defaultMode, optional, "configMap "+name, keysOf(configMap.Data),
+		func(key string) (string, bool) {
+			value, ok := configMap.Data[key]
+			return value, ok
+		})
+}
+
+func projectSecret(payload map[string]FileProjection, secret *object.Secret, name string,
+	items []object.KeyToPath, defaultMode int32, optional bool) error {
+
+	if secret == nil {
+		if optional {
+			return nil
+		}
+		return fmt.Errorf("secret %s not found", name)
+	}
+	return projectKeys(payload, items, defaultMode, optional, "secret "+name, keysOf(secret.Data), secret.GetValue)
+}
+
+// projectKeys writes every key to a file named by it, or only keys in items
+func projectKeys(payload map[string]FileProjection, items []object.KeyToPath, defaultMode int32, optional bool,
+	source string, keys []string, getValue func(key string) (string, bool)) error {
+
+	if len(items) == 0 {
+		for _, key := range keys {
+			value, ok := getValue(key)
+			if !ok {
+				return fmt.Errorf("key %s of %s is invalid", key, source)
+			}
+			// keys which are not valid paths are skipped, e.g. ..x, while .dockerconfigjson is fine
+			if object.CheckVolumeFilePath(key) != nil {
+				continue
+			}
+			payload[key] = FileProjection{Data: []byte(value), Mode: defaultMode}
+		}
+		return nil
+	}
+
+	for _, item := range items {
+		// paths are checked by apiserver, but files must never be written out of the volume
+		if err := object.CheckVolumeFilePath(item.Path); err != nil {
+			return fmt.Errorf("key %s of %s: %v", item.Key, source, err)
+		}
+		value, ok := getValue(item.Key)
+		if !ok {
+			if optional {
+				continue
+			}
+			return fmt.Errorf("key %s of %s not found", item.Key, source)
+		}
+		mode := defaultMode
+		if item.Mode != nil {
+			mode = *item.Mode
+		}
+		payload[item.Path] = FileProjection{Data: []byte(value), Mode: mode}
+	}
+	return nil
+}
+
+func projectDownwardAPI(payload map[string]FileProjection, pod *object.Pod,
+	items []object.DownwardAPIVolumeFile, defaultMode int32) error {
+
+	for _, item := range items {
+		if err := object.CheckVolumeFilePath(item.Path); err != nil {
+			return err
+		}
+		if item.FieldRef == nil {
+			return fmt.Errorf("fieldRef of %s is required", item.Path)
+		}
+		var value string
+		switch item.FieldRef.FieldPath {
+		case object.FieldPathPodName:
+			value = pod.Name
+		case object.FieldPathPodUID:
+			value = pod.UID
+		case object.FieldPathPodNamespace:
+			value = pod.Namespace
+		case object.FieldPathPodLabels:
+			value = FormatMap(pod.Labels)
+		case object.FieldPathPodAnnotations:
+			value = FormatMap(pod.Annotations)
+		default:
+			return fmt.Errorf("field %s is not supported by downward API volumes", item.FieldRef.FieldPath)
+		}
+
+		mode := defaultMode
+		if item.Mode != nil {
+			mode = *item.Mode
+		}
+		payload[item.Path] = FileProjection{Data: []byte(value), Mode: mode}
+	}
+	return nil
+}
+
+// FormatMap formats labels or annotations one per line as key="value", sorted by key
+func FormatMap(m map[string]string) string {
+	lines := make([]string, 0, len(m))
+	for _, key := range keysOf(m) {
+		lines = append(lines, key+"="+strconv.Quote(m[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func keysOf(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fileMode(mode *int32) int32 {
+	if mode == nil {
+		return object.DefaultVolumeFileMode
+	}
+	return *mode
+}
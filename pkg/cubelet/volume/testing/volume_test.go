@@ -0,0 +1,94 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubelet/volume"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAtomicWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer := volume.NewAtomicWriter(dir)
+
+	err := writer.Write(map[string]volume.FileProjection{
+		"a":     {Data: []byte("1"), Mode: 0644},
+		"sub/b": {Data: []byte("2"), Mode: 0400},
+	})
+	assert.Nil(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "sub", "b"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", string(data))
+	info, err := os.Stat(filepath.Join(dir, "sub", "b"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0400), info.Mode().Perm())
+
+	// the same payload keeps the data directory
+	oldData, _ := os.Readlink(filepath.Join(dir, "..data"))
+	assert.Nil(t, writer.Write(map[string]volume.FileProjection{
+		"a":     {Data: []byte("1"), Mode: 0644},
+		"sub/b": {Data: []byte("2"), Mode: 0400},
+	}))
+	newData, _ := os.Readlink(filepath.Join(dir, "..data"))
+	assert.Equal(t, oldData, newData)
+
+	// removed files disappear, along with the old data directory
+	assert.Nil(t, writer.Write(map[string]volume.FileProjection{"a": {Data: []byte("3"), Mode: 0644}}))
+	data, err = os.ReadFile(filepath.Join(dir, "a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "3", string(data))
+	_, err = os.Lstat(filepath.Join(dir, "sub"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, oldData))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NotNil(t, writer.Write(map[string]volume.FileProjection{"../escape": {Data: []byte("x")}}))
+}
+
+func TestProjectVolumeFiles(t *testing.T) {
+	pod := &object.Pod{ObjectMeta: object.ObjectMeta{
+		Name:   "test",
+		Labels: map[string]string{"b": "2", "a": "1"},
+	}}
+	configMaps := map[string]*object.ConfigMap{
+		"cm": {Data: map[string]string{"k1": "v1", "k2": "v2"}},
+	}
+	mode := int32(0600)
+
+	files, err := volume.ProjectVolumeFiles(pod, &object.Volume{Name: "v", Projected: &object.ProjectedVolumeSource{
+		Sources: []object.VolumeProjection{
+			{ConfigMap: &object.ConfigMapProjection{Name: "cm", Items: []object.KeyToPath{{Key: "k2", Path: "conf/k2"}}}},
+			{DownwardAPI: &object.DownwardAPIProjection{Items: []object.DownwardAPIVolumeFile{
+				{Path: "labels", FieldRef: &object.ObjectFieldSelector{FieldPath: object.FieldPathPodLabels}},
+			}}},
+		},
+		DefaultMode: &mode,
+	}}, configMaps, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(files))
+	assert.Equal(t, "v2", string(files["conf/k2"].Data))
+	assert.Equal(t, mode, files["conf/k2"].Mode)
+	assert.Equal(t, "a=\"1\"\nb=\"2\"", string(files["labels"].Data))
+
+	_, err = volume.ProjectVolumeFiles(pod, &object.Volume{Name: "v",
+		Secret: &object.SecretVolumeSource{SecretName: "missing"}}, configMaps, nil)
+	assert.NotNil(t, err)
+	files, err = volume.ProjectVolumeFiles(pod, &object.Volume{Name: "v",
+		Secret: &object.SecretVolumeSource{SecretName: "missing", Optional: true}}, configMaps, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(files))
+
+	// files are never projected out of the volume
+	_, err = volume.ProjectVolumeFiles(pod, &object.Volume{Name: "v", ConfigMap: &object.ConfigMapVolumeSource{
+		Name: "cm", Items: []object.KeyToPath{{Key: "k1", Path: "../../etc/k1"}},
+	}}, configMaps, nil)
+	assert.NotNil(t, err)
+	_, err = volume.ProjectVolumeFiles(pod, &object.Volume{Name: "v", DownwardAPI: &object.DownwardAPIVolumeSource{
+		Items: []object.DownwardAPIVolumeFile{
+			{Path: "/etc/labels", FieldRef: &object.ObjectFieldSelector{FieldPath: object.FieldPathPodLabels}},
+		},
+	}}, configMaps, nil)
+	assert.NotNil(t, err)
+}
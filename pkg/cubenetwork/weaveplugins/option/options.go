@@ -6,9 +6,13 @@ const (
 )
 
 const (
-	DnsAdd        = "dns-add"
-	DnsRemove     = "dns-remove"
-	DefaultSuffix = ".weave.local"
+	DnsAdd    = "dns-add"
+	DnsRemove = "dns-remove"
+	// DNSDomain is served by weave DNS instead of weave.local,
+	// it is the cluster domain searched by pods for <svc>.<ns>.svc.<domain>
+	DNSDomain     = "cluster.local"
+	DNSDomainFlag = "--dns-domain=" + DNSDomain + "."
+	DefaultSuffix = "." + DNSDomain
 )
 
 const (
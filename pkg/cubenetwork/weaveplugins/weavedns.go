@@ -43,6 +43,24 @@ func DeleteDNSEntry(containerID string) error {
 	return nil
 }
 
+// DeleteDNSRecord removes the record of ip added by AddDNSEntry
+func DeleteDNSRecord(hostname string, ip string) error {
+	path, err := osexec.LookPath(option.WeaveName)
+	if err != nil {
+		log.Println("[Error]: Weave Not found.")
+		return err
+	}
+
+	cmd := osexec.Command(path, option.DnsRemove, ip, "-h", GetDNSHost(hostname))
+	output, err := cmd.CombinedOutput()
+	log.Println(string(output))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func GetDNSHost(hostname string) string {
 	// add default name
 	var str strings.Builder
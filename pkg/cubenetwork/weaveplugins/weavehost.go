@@ -80,7 +80,7 @@ func InitWeave() error {
 		return err
 	}
 
-	cmd = osexec.Command(path, option.Launch)
+	cmd = osexec.Command(path, option.Launch, option.DNSDomainFlag)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		log.Panicf("Weave add node error: %s, %s\n", err, string(output))
@@ -120,7 +120,7 @@ func AddNode(newHost Host, apiServerHost Host) error {
 	}
 
 	log.Println("Connecting to peers...")
-	cmd = osexec.Command(path, option.Launch, newHost.IP.String(), apiServerHost.IP.String())
+	cmd = osexec.Command(path, option.Launch, option.DNSDomainFlag, newHost.IP.String(), apiServerHost.IP.String())
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		log.Panicf("Weave add node error: %s, %v\n", err, string(output))
@@ -100,6 +100,7 @@ func (cp *Cubeproxy) syncService() {
 				log.Printf("[Error]: Add service error: %v", err.Error())
 				return
 			}
+			cp.Runtime.AddServiceDNS(&service)
 		case types.Update:
 			// critical update: simply delete and rebuild
 			log.Printf("[INFO]: update service %s\n", service.UID)
@@ -114,6 +115,7 @@ func (cp *Cubeproxy) syncService() {
 				log.Printf("[Fatal]: Add service error: %v", err.Error())
 				return
 			}
+			cp.Runtime.AddServiceDNS(&service)
 
 		case types.Remove:
 			log.Printf("[INFO]: delete service %s\n", service.UID)
@@ -122,6 +124,7 @@ func (cp *Cubeproxy) syncService() {
 				log.Printf("[Fatal]: Delete service error: %v", err.Error())
 				return
 			}
+			cp.Runtime.DeleteServiceDNS(service.UID)
 		}
 
 		cp.lock.Unlock()
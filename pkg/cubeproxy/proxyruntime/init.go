@@ -26,6 +26,7 @@ func InitProxyRuntime() (*ProxyRuntime, error) {
 
 		ServiceChainMap: make(map[string]ServiceChainElement),
 		DNSMap:          make(map[string]DNSElement),
+		ServiceDNSMap:   make(map[string]ServiceDNSRecord),
 	}
 
 	err = pr.InitObject()
@@ -131,6 +132,7 @@ func (pr *ProxyRuntime) AddAllExistService() error {
 		if err != nil {
 			log.Printf("[INFO]: Add exist service %v failed", service.UID)
 		}
+		pr.AddServiceDNS(&service)
 	}
 
 	return nil
@@ -154,7 +156,7 @@ func (pr *ProxyRuntime) AddExistService(service *object.Service) error {
 	pods := pr.PodInformer.ListPods()
 	for _, pod := range pods {
 		if object.MatchLabelSelector(service.Spec.Selector, pod.Labels) {
-			if pod.Status != nil && pod.Status.IP != nil {
+			if object.IsPodReady(&pod) {
 				service.Status.Endpoints = append(service.Status.Endpoints, pod.Status.IP)
 			}
 		}
@@ -0,0 +1,47 @@
+package proxyruntime
+
+import (
+	"Cubernetes/pkg/cubenetwork/weaveplugins"
+	"Cubernetes/pkg/cubeproxy/utils"
+	"Cubernetes/pkg/object"
+	"log"
+)
+
+// AddServiceDNS adds <svc>.<ns>.svc.<domain> of the cluster IP to weave DNS,
+// so that ClusterFirst pods resolve services by name. The old record is replaced
+// if the name or cluster IP of the service changed
+func (pr *ProxyRuntime) AddServiceDNS(service *object.Service) {
+	if service.Spec.ClusterIP == "" {
+		return
+	}
+
+	record := ServiceDNSRecord{Hostname: utils.ServiceDNSName(service), IP: service.Spec.ClusterIP}
+	if old, ok := pr.ServiceDNSMap[service.UID]; ok {
+		if old == record {
+			return
+		}
+		pr.DeleteServiceDNS(service.UID)
+	}
+
+	err := weaveplugins.AddDNSEntry(record.Hostname, record.IP)
+	if err != nil {
+		log.Printf("[Error]: fail to add DNS record %s of service %s: %v\n", record.Hostname, service.UID, err)
+		return
+	}
+	log.Printf("[INFO]: DNS record %s -> %s added\n", record.Hostname, record.IP)
+	pr.ServiceDNSMap[service.UID] = record
+}
+
+// DeleteServiceDNS removes the record added for the service, delete events carry only UID
+func (pr *ProxyRuntime) DeleteServiceDNS(UID string) {
+	record, ok := pr.ServiceDNSMap[UID]
+	if !ok {
+		return
+	}
+
+	err := weaveplugins.DeleteDNSRecord(record.Hostname, record.IP)
+	if err != nil {
+		log.Printf("[Error]: fail to remove DNS record %s of service %s: %v\n", record.Hostname, UID, err)
+	}
+	delete(pr.ServiceDNSMap, UID)
+}
@@ -39,13 +39,13 @@ func (pr *ProxyRuntime) AddService(service *object.Service) error {
 		return err
 	}
 
-	// if pod's ip not filled in, discard it
+	// if pod's ip not filled in or pod is not ready, discard it
 	var pods []object.Pod
 	for idx, pod := range alternativePods {
-		if pod.Status != nil && pod.Status.IP != nil && pod.Status.Phase == object.PodRunning {
+		if object.IsPodReady(&alternativePods[idx]) {
 			pods = append(pods, alternativePods[idx])
 		} else {
-			log.Printf("[INFO]: Pod %v can't act as endpoint because no IP allocated", pod.UID)
+			log.Printf("[INFO]: Pod %v can't act as endpoint because it is not ready", pod.UID)
 		}
 	}
 
@@ -120,7 +120,8 @@ func (pr *ProxyRuntime) AddService(service *object.Service) error {
 		}
 	}
 
-	// Write back endpoints
+	// Write back endpoints, only ready pods are kept
+	service.Status.Endpoints = []net.IP{}
 	for _, pod := range pods {
 		service.Status.Endpoints = append(service.Status.Endpoints, pod.Status.IP)
 	}
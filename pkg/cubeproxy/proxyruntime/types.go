@@ -75,6 +75,12 @@ type DNSElement struct {
 	ContainerID string
 }
 
+// ServiceDNSRecord is the name of a service added to weave DNS, with its cluster IP
+type ServiceDNSRecord struct {
+	Hostname string
+	IP       string
+}
+
 type ProxyRuntime struct {
 	Ipt            *iptables.IPTables
 	DockerInstance dockershim.DockerRuntime
@@ -85,4 +91,5 @@ type ProxyRuntime struct {
 
 	ServiceChainMap map[string]ServiceChainElement
 	DNSMap          map[string]DNSElement
+	ServiceDNSMap   map[string]ServiceDNSRecord
 }
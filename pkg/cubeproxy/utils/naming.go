@@ -1,7 +1,9 @@
 package utils
 
 import (
+	weaveoption "Cubernetes/pkg/cubenetwork/weaveplugins/option"
 	"Cubernetes/pkg/cubeproxy/utils/options"
+	"Cubernetes/pkg/object"
 	"log"
 	"strings"
 )
@@ -25,3 +27,12 @@ func NginxConfigFileLocation(hostname string) string {
 	str := options.NginxFile + hostname + "/"
 	return str
 }
+
+// ServiceDNSName is the name of the service in the cluster DNS, <svc>.<ns>.svc.<domain>
+func ServiceDNSName(service *object.Service) string {
+	namespace := service.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return service.Name + "." + namespace + ".svc." + weaveoption.DNSDomain
+}
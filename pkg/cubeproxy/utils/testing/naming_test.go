@@ -0,0 +1,16 @@
+package testing
+
+import (
+	"Cubernetes/pkg/cubeproxy/utils"
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServiceDNSName(t *testing.T) {
+	service := &object.Service{ObjectMeta: object.ObjectMeta{Name: "web", Namespace: "prod"}}
+	assert.Equal(t, "web.prod.svc.cluster.local", utils.ServiceDNSName(service))
+
+	service.Namespace = ""
+	assert.Equal(t, "web.default.svc.cluster.local", utils.ServiceDNSName(service))
+}
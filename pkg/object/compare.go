@@ -1,6 +1,9 @@
 package object
 
-import "log"
+import (
+	"log"
+	"reflect"
+)
 
 func ComputeObjectMetaChange(new *ObjectMeta, old *ObjectMeta) bool {
 	if new.UID != old.UID {
@@ -12,6 +15,12 @@ func ComputeObjectMetaChange(new *ObjectMeta, old *ObjectMeta) bool {
 		return true
 	}
 
+	// deletion requested, or its grace period shortened?
+	if (new.DeletionTimestamp == nil) != (old.DeletionTimestamp == nil) ||
+		(new.DeletionTimestamp != nil && !new.DeletionTimestamp.Equal(*old.DeletionTimestamp)) {
+		return true
+	}
+
 	// label change?
 	if len(new.Labels) != len(old.Labels) {
 		return true
@@ -38,14 +47,20 @@ func ComputePodSpecChange(new *PodSpec, old *PodSpec) bool {
 		}
 	}
 
-	if len(new.Volumes) != len(old.Volumes) {
+	if !reflect.DeepEqual(new.Volumes, old.Volumes) {
 		return true
 	}
-	for i, oldV := range old.Volumes {
-		newV := new.Volumes[i]
-		if newV.HostPath != oldV.HostPath || newV.Name != oldV.Name {
-			return true
-		}
+
+	if !reflect.DeepEqual(new.SecurityContext, old.SecurityContext) {
+		return true
+	}
+
+	if new.HostNetwork != old.HostNetwork {
+		return true
+	}
+
+	if new.DNSPolicy != old.DNSPolicy || !reflect.DeepEqual(new.DNSConfig, old.DNSConfig) {
+		return true
 	}
 
 	return false
@@ -143,10 +158,18 @@ func ComputeContainerSpecChange(new *Container, old *Container) bool {
 		}
 	}
 
+	if new.WorkingDir != old.WorkingDir || new.Stdin != old.Stdin || new.TTY != old.TTY {
+		return true
+	}
+	if !reflect.DeepEqual(new.SecurityContext, old.SecurityContext) {
+		return true
+	}
+
 	// check Resource limits
 	if new.Resources != nil && old.Resources != nil {
 		if new.Resources.Cpus != old.Resources.Cpus ||
-			new.Resources.Memory != old.Resources.Memory {
+			new.Resources.Memory != old.Resources.Memory ||
+			!reflect.DeepEqual(new.Resources.Requests, old.Resources.Requests) {
 			return true
 		}
 	} else if new.Resources != nil || old.Resources != nil {
@@ -159,7 +182,7 @@ func ComputeContainerSpecChange(new *Container, old *Container) bool {
 	}
 	for i, oldM := range old.VolumeMounts {
 		newM := new.VolumeMounts[i]
-		if newM.Name != oldM.Name || newM.MountPath != oldM.MountPath {
+		if newM.Name != oldM.Name || newM.MountPath != oldM.MountPath || newM.ReadOnly != oldM.ReadOnly {
 			return true
 		}
 	}
@@ -214,6 +237,11 @@ func ComputePodNetworkChange(new *Pod, old *Pod) bool {
 		return true
 	}
 
+	if IsPodReady(old) != IsPodReady(new) {
+		log.Println("Some pod readiness changed, reset service")
+		return true
+	}
+
 	return false
 }
 
@@ -0,0 +1,11 @@
+package object
+
+const ConfigMapEtcdPrefix = "/apis/configMap/"
+
+// ConfigMap holds configuration data for pods to consume,
+// pods refer to it by name in env, envFrom or volumes
+type ConfigMap struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	Data       map[string]string `json:"data,omitempty" yaml:"data,omitempty"`
+}
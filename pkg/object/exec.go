@@ -0,0 +1,68 @@
+package object
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ExecOptions is passed from cubectl through apiserver to cubelet as url query of exec and attach
+type ExecOptions struct {
+	// Container is required if the pod has more than one container
+	Container string
+	// Command is ignored by attach
+	Command []string
+	Stdin   bool
+	TTY     bool
+}
+
+const (
+	ExecQueryContainer = LogQueryContainer
+	ExecQueryCommand   = "command"
+	ExecQueryStdin     = "stdin"
+	ExecQueryTTY       = "tty"
+
+	PortForwardQueryPort = "port"
+)
+
+func (opts *ExecOptions) Query() url.Values {
+	query := url.Values{}
+	if opts.Container != "" {
+		query.Set(ExecQueryContainer, opts.Container)
+	}
+	for _, arg := range opts.Command {
+		query.Add(ExecQueryCommand, arg)
+	}
+	if opts.Stdin {
+		query.Set(ExecQueryStdin, "true")
+	}
+	if opts.TTY {
+		query.Set(ExecQueryTTY, "true")
+	}
+	return query
+}
+
+// ParseExecOptions is the reverse of ExecOptions.Query
+func ParseExecOptions(query url.Values) (ExecOptions, error) {
+	opts := ExecOptions{
+		Container: query.Get(ExecQueryContainer),
+		Command:   query[ExecQueryCommand],
+	}
+
+	var err error
+	if opts.Stdin, err = parseBoolQuery(query, ExecQueryStdin); err != nil {
+		return opts, err
+	}
+	if opts.TTY, err = parseBoolQuery(query, ExecQueryTTY); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func ParsePortForwardPort(query url.Values) (int, error) {
+	port, err := strconv.Atoi(query.Get(PortForwardQueryPort))
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, errors.New("port must be an integer between 1 and 65535")
+	}
+	return port, nil
+}
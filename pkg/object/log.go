@@ -0,0 +1,106 @@
+package object
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LogOptions selects the part of container logs to fetch,
+// it is passed from cubectl through apiserver to cubelet as url query
+type LogOptions struct {
+	// Container is required if the pod has more than one container
+	Container string
+	// Follow keeps streaming until the container exits or the client disconnects
+	Follow bool
+	// TailLines is the number of lines from the end to show, all lines if negative
+	TailLines int
+	// Since only shows logs newer than a relative duration, all logs if zero
+	Since time.Duration
+	// Timestamps prefixes each line with its RFC3339 timestamp
+	Timestamps bool
+	// Previous shows logs of the last terminated instance of the container
+	Previous bool
+}
+
+const (
+	LogQueryContainer  = "container"
+	LogQueryFollow     = "follow"
+	LogQueryTailLines  = "tailLines"
+	LogQuerySince      = "since"
+	LogQueryTimestamps = "timestamps"
+	LogQueryPrevious   = "previous"
+)
+
+func (opts *LogOptions) Query() url.Values {
+	query := url.Values{}
+	if opts.Container != "" {
+		query.Set(LogQueryContainer, opts.Container)
+	}
+	if opts.Follow {
+		query.Set(LogQueryFollow, "true")
+	}
+	if opts.TailLines >= 0 {
+		query.Set(LogQueryTailLines, strconv.Itoa(opts.TailLines))
+	}
+	if opts.Since > 0 {
+		query.Set(LogQuerySince, opts.Since.String())
+	}
+	if opts.Timestamps {
+		query.Set(LogQueryTimestamps, "true")
+	}
+	if opts.Previous {
+		query.Set(LogQueryPrevious, "true")
+	}
+	return query
+}
+
+// ParseLogOptions is the reverse of LogOptions.Query
+func ParseLogOptions(query url.Values) (LogOptions, error) {
+	opts := LogOptions{
+		Container: query.Get(LogQueryContainer),
+		TailLines: -1,
+	}
+
+	var err error
+	if opts.Follow, err = parseBoolQuery(query, LogQueryFollow); err != nil {
+		return opts, err
+	}
+	if opts.Timestamps, err = parseBoolQuery(query, LogQueryTimestamps); err != nil {
+		return opts, err
+	}
+	if opts.Previous, err = parseBoolQuery(query, LogQueryPrevious); err != nil {
+		return opts, err
+	}
+
+	if tail := query.Get(LogQueryTailLines); tail != "" {
+		opts.TailLines, err = strconv.Atoi(tail)
+		if err != nil || opts.TailLines < 0 {
+			return opts, errors.New("tailLines must be a non-negative integer")
+		}
+	}
+	if since := query.Get(LogQuerySince); since != "" {
+		opts.Since, err = time.ParseDuration(since)
+		if err != nil || opts.Since <= 0 {
+			return opts, errors.New("since must be a positive duration")
+		}
+	}
+
+	if opts.Follow && opts.Previous {
+		return opts, errors.New("previous instance logs can't be followed")
+	}
+	return opts, nil
+}
+
+func parseBoolQuery(query url.Values, key string) (bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New(key + " must be a boolean")
+	}
+	return b, nil
+}
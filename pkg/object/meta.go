@@ -1,5 +1,7 @@
 package object
 
+import "time"
+
 const (
 	KindPod        = "Pod"
 	KindService    = "Service"
@@ -11,6 +13,15 @@ const (
 	KindAction     = "Action"
 	KindActor      = "Actor"
 	KindIngress    = "Ingress"
+
+	KindPriorityClass = "PriorityClass"
+	KindPodGroup      = "PodGroup"
+	KindConfigMap     = "ConfigMap"
+	KindSecret        = "Secret"
+
+	KindPersistentVolume      = "PersistentVolume"
+	KindPersistentVolumeClaim = "PersistentVolumeClaim"
+	KindStorageClass          = "StorageClass"
 )
 
 type TypeMeta struct {
@@ -24,4 +35,8 @@ type ObjectMeta struct {
 	UID         string            `json:"uid,omitempty" yaml:"uid,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// DeletionTimestamp is set when deletion of a pod is requested, it is when the grace period ends;
+	// the object is removed once its containers are terminated
+	DeletionTimestamp          *time.Time `json:"deletionTimestamp,omitempty" yaml:"deletionTimestamp,omitempty"`
+	DeletionGracePeriodSeconds *int64     `json:"deletionGracePeriodSeconds,omitempty" yaml:"deletionGracePeriodSeconds,omitempty"`
 }
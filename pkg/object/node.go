@@ -20,11 +20,42 @@ type NodeSpec struct {
 	Type     NodeType     `json:"types" yaml:"types"`
 	Capacity NodeCapacity `json:"capacity,omitempty" yaml:"capacity,omitempty"`
 	Info     NodeInfo     `json:"info,omitempty" yaml:"info,omitempty"`
+	// Unschedulable is set by cordon, scheduler won't put new pods on this node
+	Unschedulable bool    `json:"unschedulable,omitempty" yaml:"unschedulable,omitempty"`
+	Taints        []Taint `json:"taints,omitempty" yaml:"taints,omitempty"`
+}
+
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule means no new pod will be scheduled onto the node
+	// unless it tolerates the taint, but running pods are not affected.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule means scheduler tries not to place a pod
+	// that does not tolerate the taint on the node, but it is not required.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute means running pods that do not tolerate the taint
+	// are evicted from the node, and new ones won't be scheduled onto it.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+type Taint struct {
+	Key    string      `json:"key" yaml:"key"`
+	Value  string      `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect TaintEffect `json:"effect" yaml:"effect"`
 }
 
 type NodeStatus struct {
 	Addresses NodeAddresses `json:"addresses,omitempty" yaml:"addresses,omitempty"`
 	Condition NodeCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// Capacity is detected by cubelet, Allocatable is what pods can request,
+	// i.e. Capacity minus system reservations and eviction thresholds
+	Capacity    *NodeResources  `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+	Allocatable *NodeResources  `json:"allocatable,omitempty" yaml:"allocatable,omitempty"`
+	NodeInfo    *NodeSystemInfo `json:"nodeInfo,omitempty" yaml:"nodeInfo,omitempty"`
+	// Images cached on the node, the largest ones first
+	Images          []ContainerImage     `json:"images,omitempty" yaml:"images,omitempty"`
+	DaemonEndpoints *NodeDaemonEndpoints `json:"daemonEndpoints,omitempty" yaml:"daemonEndpoints,omitempty"`
 }
 
 type NodeAddresses struct {
@@ -40,6 +71,36 @@ type NodeCondition struct {
 	DiskPressure   bool `json:"diskPressure" yaml:"diskPressure"`
 }
 
+type NodeResources struct {
+	Cpus float64 `json:"cpus" yaml:"cpus"`
+	// Memory and EphemeralStorage in bytes
+	Memory           int64 `json:"memory" yaml:"memory"`
+	EphemeralStorage int64 `json:"ephemeralStorage" yaml:"ephemeralStorage"`
+	Pods             int   `json:"pods" yaml:"pods"`
+}
+
+type NodeSystemInfo struct {
+	KernelVersion string `json:"kernelVersion" yaml:"kernelVersion"`
+	// OSImage is PRETTY_NAME of /etc/os-release, e.g. Ubuntu 20.04.4 LTS
+	OSImage         string `json:"osImage" yaml:"osImage"`
+	OperatingSystem string `json:"operatingSystem" yaml:"operatingSystem"`
+	Architecture    string `json:"architecture" yaml:"architecture"`
+	// ContainerRuntimeVersion is like docker://20.10.12
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion" yaml:"containerRuntimeVersion"`
+	CubeletVersion          string `json:"cubeletVersion" yaml:"cubeletVersion"`
+}
+
+type ContainerImage struct {
+	Names     []string `json:"names" yaml:"names"`
+	SizeBytes int64    `json:"sizeBytes" yaml:"sizeBytes"`
+}
+
+type NodeDaemonEndpoints struct {
+	CubeletPort int `json:"cubeletPort" yaml:"cubeletPort"`
+}
+
+// NodeCapacity is given when the node joins, it is used by scheduler
+// until cubelet reports allocatable resources in status
 type NodeCapacity struct {
 	CPUCount int `json:"cpuCount,omitempty" yaml:"cpuCount,omitempty"`
 	Memory   int `json:"memory,omitempty" yaml:"memory,omitempty"`
@@ -51,3 +112,15 @@ type NodeInfo struct {
 	KernelVersion string `json:"kernelVersion,omitempty" yaml:"kernelVersion,omitempty"`
 	DeviceName    string `json:"deviceName,omitempty" yaml:"deviceName,omitempty"`
 }
+
+// GetNodeAllocatable returns cpus, memory in bytes and number of pods that pods on the node
+// can request, zero means not limited
+func GetNodeAllocatable(node *Node) (float64, int64, int) {
+	if node.Status != nil && node.Status.Allocatable != nil {
+		allocatable := node.Status.Allocatable
+		return allocatable.Cpus, allocatable.Memory, allocatable.Pods
+	}
+	// memory capacity in spec is in MiB
+	capacity := node.Spec.Capacity
+	return float64(capacity.CPUCount), int64(capacity.Memory) * 1024 * 1024, capacity.MaxPods
+}
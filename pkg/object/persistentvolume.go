@@ -0,0 +1,254 @@
+package object
+
+const (
+	PersistentVolumeEtcdPrefix      = "/apis/persistentVolume/"
+	PersistentVolumeClaimEtcdPrefix = "/apis/persistentVolumeClaim/"
+	StorageClassEtcdPrefix          = "/apis/storageClass/"
+)
+
+type PersistentVolumeAccessMode string
+
+const (
+	// ReadWriteOnce volumes are mounted read-write by pods of a single node
+	ReadWriteOnce PersistentVolumeAccessMode = "ReadWriteOnce"
+	ReadOnlyMany  PersistentVolumeAccessMode = "ReadOnlyMany"
+	ReadWriteMany PersistentVolumeAccessMode = "ReadWriteMany"
+)
+
+type PersistentVolumeReclaimPolicy string
+
+const (
+	// PersistentVolumeReclaimRetain keeps the volume Released after its claim is deleted,
+	// it must be deleted by hand
+	PersistentVolumeReclaimRetain PersistentVolumeReclaimPolicy = "Retain"
+	// PersistentVolumeReclaimDelete removes the volume along with its data
+	PersistentVolumeReclaimDelete PersistentVolumeReclaimPolicy = "Delete"
+)
+
+type PersistentVolumePhase string
+
+const (
+	VolumeAvailable PersistentVolumePhase = "Available"
+	VolumeBound     PersistentVolumePhase = "Bound"
+	// VolumeReleased means the claim is deleted, the volume is not available for other claims
+	VolumeReleased PersistentVolumePhase = "Released"
+	VolumeFailed   PersistentVolumePhase = "Failed"
+)
+
+type PersistentVolumeClaimPhase string
+
+const (
+	ClaimPending PersistentVolumeClaimPhase = "Pending"
+	ClaimBound   PersistentVolumeClaimPhase = "Bound"
+	// ClaimLost means the bound volume is deleted
+	ClaimLost PersistentVolumeClaimPhase = "Lost"
+)
+
+// provisioners supported by the volume controller
+const (
+	// LocalProvisioner creates a directory under LocalProvisionerBaseDir on the node of the first pod
+	LocalProvisioner = "cubernetes.io/local"
+	// NFSProvisioner creates a directory in the NFS export given by parameters server and path
+	NFSProvisioner = "cubernetes.io/nfs"
+	// NoProvisioner classes only bind volumes created by hand
+	NoProvisioner = "cubernetes.io/no-provisioner"
+)
+
+const LocalProvisionerBaseDir = "/var/lib/cubernetes/local-volumes"
+
+const (
+	// StorageClassParamServer is the NFS server of NFSProvisioner
+	StorageClassParamServer = "server"
+	// StorageClassParamPath is the NFS export of NFSProvisioner
+	StorageClassParamPath = "path"
+)
+
+// annotations of volumes and claims
+const (
+	// AnnProvisionedBy is put on volumes created by a provisioner
+	AnnProvisionedBy = "pv.cubernetes.io/provisioned-by"
+	// AnnSelectedNode is put on claims of WaitForFirstConsumer classes by scheduler,
+	// with the node name of the first pod using it
+	AnnSelectedNode = "volume.cubernetes.io/selected-node"
+)
+
+type VolumeBindingMode string
+
+const (
+	// VolumeBindingImmediate binds claims as soon as they are created,
+	// pods using unbound claims are not scheduled
+	VolumeBindingImmediate VolumeBindingMode = "Immediate"
+	// VolumeBindingWaitForFirstConsumer provisions the volume on the node
+	// chosen for the first pod using the claim
+	VolumeBindingWaitForFirstConsumer VolumeBindingMode = "WaitForFirstConsumer"
+)
+
+// StorageClass tells the volume controller how to provision volumes for claims of the class
+type StorageClass struct {
+	TypeMeta    `json:",inline" yaml:",inline"`
+	ObjectMeta  `json:"metadata" yaml:"metadata"`
+	Provisioner string            `json:"provisioner" yaml:"provisioner"`
+	Parameters  map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// default to Delete
+	ReclaimPolicy PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty" yaml:"reclaimPolicy,omitempty"`
+	// default to Immediate, LocalProvisioner requires WaitForFirstConsumer
+	VolumeBindingMode VolumeBindingMode `json:"volumeBindingMode,omitempty" yaml:"volumeBindingMode,omitempty"`
+}
+
+// PersistentVolume is a piece of storage in the cluster, which outlives pods using it
+type PersistentVolume struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	Spec       PersistentVolumeSpec    `json:"spec" yaml:"spec"`
+	Status     *PersistentVolumeStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+type PersistentVolumeSpec struct {
+	// Capacity in bytes
+	Capacity    int64                        `json:"capacity" yaml:"capacity"`
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes" yaml:"accessModes"`
+	// default to Retain
+	ReclaimPolicy    PersistentVolumeReclaimPolicy `json:"reclaimPolicy,omitempty" yaml:"reclaimPolicy,omitempty"`
+	StorageClassName string                        `json:"storageClassName,omitempty" yaml:"storageClassName,omitempty"`
+	// exactly one of Local and NFS is set
+	Local *LocalVolumeSource `json:"local,omitempty" yaml:"local,omitempty"`
+	NFS   *NFSVolumeSource   `json:"nfs,omitempty" yaml:"nfs,omitempty"`
+	// NodeAffinity limits nodes where the volume can be used, it is required by local volumes
+	NodeAffinity *VolumeNodeAffinity `json:"nodeAffinity,omitempty" yaml:"nodeAffinity,omitempty"`
+	// ClaimRef is the claim bound to the volume, set by the volume controller,
+	// or by users to reserve the volume for a claim
+	ClaimRef *ClaimReference `json:"claimRef,omitempty" yaml:"claimRef,omitempty"`
+}
+
+// LocalVolumeSource is a directory on the node, it must exist unless the volume is provisioned
+type LocalVolumeSource struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+type NFSVolumeSource struct {
+	Server   string `json:"server" yaml:"server"`
+	Path     string `json:"path" yaml:"path"`
+	ReadOnly bool   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// VolumeNodeAffinity matches a node if its name is in NodeNames, or its labels match Selector
+type VolumeNodeAffinity struct {
+	NodeNames []string          `json:"nodeNames,omitempty" yaml:"nodeNames,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type ClaimReference struct {
+	Name string `json:"name" yaml:"name"`
+	UID  string `json:"uid,omitempty" yaml:"uid,omitempty"`
+}
+
+type PersistentVolumeStatus struct {
+	Phase   PersistentVolumePhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Message string                `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// PersistentVolumeClaim requests storage, pods use the bound volume by the name of the claim
+type PersistentVolumeClaim struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	Spec       PersistentVolumeClaimSpec    `json:"spec" yaml:"spec"`
+	Status     *PersistentVolumeClaimStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+type PersistentVolumeClaimSpec struct {
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes" yaml:"accessModes"`
+	// Storage is the minimum capacity in bytes
+	Storage int64 `json:"storage" yaml:"storage"`
+	// volumes of the same class are bound, and the class provisions one if none is available;
+	// empty means only volumes without class are bound
+	StorageClassName string `json:"storageClassName,omitempty" yaml:"storageClassName,omitempty"`
+	// Selector matches labels of volumes to bind
+	Selector map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// VolumeName is the volume bound to the claim, set by the volume controller,
+	// or by users to bind a specific volume
+	VolumeName string `json:"volumeName,omitempty" yaml:"volumeName,omitempty"`
+}
+
+type PersistentVolumeClaimStatus struct {
+	Phase PersistentVolumeClaimPhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	// Capacity of the bound volume
+	Capacity    int64                        `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+	AccessModes []PersistentVolumeAccessMode `json:"accessModes,omitempty" yaml:"accessModes,omitempty"`
+}
+
+// PersistentVolumeClaimVolumeSource mounts the volume bound to the claim in pods
+type PersistentVolumeClaimVolumeSource struct {
+	ClaimName string `json:"claimName" yaml:"claimName"`
+	ReadOnly  bool   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// HasAccessModes tells if modes has all of the wanted ones
+func HasAccessModes(modes []PersistentVolumeAccessMode, wanted []PersistentVolumeAccessMode) bool {
+	for _, w := range wanted {
+		found := false
+		for _, m := range modes {
+			if m == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// IsReadOnlyVolume tells if the volume can only be mounted read-only
+func IsReadOnlyVolume(pv *PersistentVolume) bool {
+	if pv.Spec.NFS != nil && pv.Spec.NFS.ReadOnly {
+		return true
+	}
+	return len(pv.Spec.AccessModes) == 1 && pv.Spec.AccessModes[0] == ReadOnlyMany
+}
+
+// MatchVolumeNodeAffinity tells if the volume can be used on the node
+func MatchVolumeNodeAffinity(affinity *VolumeNodeAffinity, node *Node) bool {
+	if affinity == nil {
+		return true
+	}
+	if len(affinity.NodeNames) != 0 {
+		found := false
+		for _, name := range affinity.NodeNames {
+			if name == node.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(affinity.Selector) == 0 || MatchLabelSelector(affinity.Selector, node.Labels)
+}
+
+// GetPodClaimNames returns names of claims used by volumes of the pod
+func GetPodClaimNames(pod *Pod) []string {
+	names := make([]string, 0)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			names = append(names, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return names
+}
+
+// GetStorageClassReclaimPolicy returns reclaim policy of volumes provisioned by the class
+func GetStorageClassReclaimPolicy(class *StorageClass) PersistentVolumeReclaimPolicy {
+	if class.ReclaimPolicy == "" {
+		return PersistentVolumeReclaimDelete
+	}
+	return class.ReclaimPolicy
+}
+
+func GetVolumeBindingMode(class *StorageClass) VolumeBindingMode {
+	if class.VolumeBindingMode == "" {
+		return VolumeBindingImmediate
+	}
+	return class.VolumeBindingMode
+}
@@ -2,6 +2,7 @@ package object
 
 import (
 	"net"
+	"strings"
 	"time"
 )
 
@@ -19,8 +20,75 @@ type PodSpec struct {
 	Selector   map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
 	Containers []Container       `json:"containers" yaml:"containers"`
 	Volumes    []Volume          `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	// InitContainers run one by one to completion before Containers are started,
+	// those with RestartPolicy Always are sidecars, which keep running along with Containers
+	InitContainers []Container `json:"initContainers,omitempty" yaml:"initContainers,omitempty"`
+	// Tolerations let the pod be scheduled onto (or stay on) tainted nodes
+	Tolerations []Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	// PriorityClassName is resolved to Priority by apiserver when pod is created
+	PriorityClassName string `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	Priority          *int32 `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// default to PreemptLowerPriority, copied from priority class
+	PreemptionPolicy PreemptionPolicy `json:"preemptionPolicy,omitempty" yaml:"preemptionPolicy,omitempty"`
+	// default to Always
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty"`
+	// TerminationGracePeriodSeconds is how long containers have to stop after SIGTERM,
+	// including preStop hooks, before they are killed. Default to 30
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty"`
+	// ImagePullSecrets are secrets of type cubernetes.io/dockerconfigjson,
+	// used to pull images of containers from private registries
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" yaml:"imagePullSecrets,omitempty"`
+	// SecurityContext applies to all containers, unless overridden by the container
+	SecurityContext *PodSecurityContext `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
+	// HostNetwork runs the pod in the network namespace of the node, instead of weave network,
+	// the IP of the pod is the one of the node, and host ports of containers are their container ports
+	HostNetwork bool `json:"hostNetwork,omitempty" yaml:"hostNetwork,omitempty"`
+	// DNSPolicy decides the resolv.conf of containers, default to ClusterFirst,
+	// and DNSConfig is merged into it
+	DNSPolicy DNSPolicy     `json:"dnsPolicy,omitempty" yaml:"dnsPolicy,omitempty"`
+	DNSConfig *PodDNSConfig `json:"dnsConfig,omitempty" yaml:"dnsConfig,omitempty"`
 }
 
+type DNSPolicy string
+
+const (
+	// DNSClusterFirst resolves names by the cluster DNS, searching <ns>.svc.<cluster domain> first.
+	// hostNetwork pods fall back to DNSDefault
+	DNSClusterFirst DNSPolicy = "ClusterFirst"
+	// DNSDefault uses the resolv.conf of the node
+	DNSDefault DNSPolicy = "Default"
+	// DNSNone uses nothing but DNSConfig of the pod
+	DNSNone DNSPolicy = "None"
+)
+
+const (
+	// MaxDNSNameservers and MaxDNSSearches are limits of resolv.conf
+	MaxDNSNameservers = 3
+	MaxDNSSearches    = 6
+)
+
+// PodDNSConfig is added to the resolv.conf made by DNSPolicy, options with the same name are replaced
+type PodDNSConfig struct {
+	Nameservers []string             `json:"nameservers,omitempty" yaml:"nameservers,omitempty"`
+	Searches    []string             `json:"searches,omitempty" yaml:"searches,omitempty"`
+	Options     []PodDNSConfigOption `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+type PodDNSConfigOption struct {
+	Name  string  `json:"name" yaml:"name"`
+	Value *string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+const DefaultTerminationGracePeriodSeconds int64 = 30
+
+type RestartPolicy string
+
+const (
+	RestartPolicyAlways    RestartPolicy = "Always"
+	RestartPolicyOnFailure RestartPolicy = "OnFailure"
+	RestartPolicyNever     RestartPolicy = "Never"
+)
+
 // PodPhase is a label for the condition of a pod at the current time.
 type PodPhase string
 
@@ -50,6 +118,67 @@ const (
 	PodUnknown PodPhase = "Unknown"
 )
 
+// PodReasonUnschedulable is set by scheduler when no node fits the pod
+const PodReasonUnschedulable = "Unschedulable"
+
+// PodReasonEvicted is set by cubelet on pods killed or rejected due to node pressure,
+// the pod is Failed and never restarted
+const PodReasonEvicted = "Evicted"
+
+func IsPodEvicted(pod *Pod) bool {
+	return pod.Status != nil && pod.Status.Phase == PodFailed && pod.Status.Reason == PodReasonEvicted
+}
+
+// IsPodTerminating tells whether deletion of the pod is requested,
+// and its containers are being stopped
+func IsPodTerminating(pod *Pod) bool {
+	return pod.DeletionTimestamp != nil
+}
+
+const (
+	// ConfigSourceAnnotationKey is set on static pods read by cubelet, it is the manifest file
+	ConfigSourceAnnotationKey = "cubernetes.io/config.source"
+	// ConfigHashAnnotationKey is set on static pods, it is the UID of the static pod
+	ConfigHashAnnotationKey = "cubernetes.io/config.hash"
+	// ConfigMirrorAnnotationKey is set on mirror pods, it is the UID of the static pod mirrored
+	ConfigMirrorAnnotationKey = "cubernetes.io/config.mirror"
+)
+
+// IsStaticPod tells whether the pod is read from a manifest file by cubelet, instead of apiserver
+func IsStaticPod(pod *Pod) bool {
+	_, ok := pod.Annotations[ConfigSourceAnnotationKey]
+	return ok && !IsMirrorPod(pod)
+}
+
+// IsMirrorPod tells whether the pod is created by cubelet in apiserver to show a static pod,
+// it is never run itself
+func IsMirrorPod(pod *Pod) bool {
+	_, ok := pod.Annotations[ConfigMirrorAnnotationKey]
+	return ok
+}
+
+// GetPodHostPorts returns ports of containers exposed on the node, including those of init containers.
+// Protocol defaults to TCP, and ports of hostNetwork pods are always exposed
+func GetPodHostPorts(pod *Pod) []ContainerPort {
+	ports := make([]ContainerPort, 0)
+	containers := append([]Container{}, pod.Spec.InitContainers...)
+	for _, c := range append(containers, pod.Spec.Containers...) {
+		for _, port := range c.Ports {
+			if pod.Spec.HostNetwork && port.HostPort == 0 {
+				port.HostPort = port.ContainerPort
+			}
+			if port.HostPort == 0 {
+				continue
+			}
+			if port.Protocol == "" {
+				port.Protocol = string(ProtocolTCP)
+			}
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
 type PodStatus struct {
 	// reserved for later use
 	IP                  net.IP         `json:"IP" yaml:"IP"`
@@ -58,6 +187,149 @@ type PodStatus struct {
 	NodeUID             string         `json:"pod-uid,omitempty" yaml:"pod-uid,omitempty"`
 	ActualResourceUsage *ResourceUsage `json:"actualResourceUsage,omitempty" yaml:"actualResourceUsage,omitempty"`
 	LastUpdateTime      time.Time      `json:"lastUpdateTime" yaml:"lastUpdateTime"`
+	// NominatedNodeUID is set by scheduler when victims on that node
+	// are preempted for this pod, and the pod is waiting for them to go
+	NominatedNodeUID string `json:"nominatedNodeUID,omitempty" yaml:"nominatedNodeUID,omitempty"`
+	// QOSClass is computed from resources of containers, reported by cubelet
+	QOSClass PodQOSClass `json:"qosClass,omitempty" yaml:"qosClass,omitempty"`
+	// Reason and Message explain why the pod is in this phase, e.g. Unschedulable
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Conditions are reported by cubelet
+	Conditions []PodCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// InitContainerStatuses are reported by cubelet, including sidecars, in the order of init containers in spec
+	InitContainerStatuses []ContainerStatus `json:"initContainerStatuses,omitempty" yaml:"initContainerStatuses,omitempty"`
+	// ContainerStatuses are reported by cubelet, in the order of containers in spec
+	ContainerStatuses []ContainerStatus `json:"containerStatuses,omitempty" yaml:"containerStatuses,omitempty"`
+}
+
+// Reasons of ContainerStateWaiting
+const (
+	ContainerReasonContainerCreating = "ContainerCreating"
+	ContainerReasonCrashLoopBackOff  = "CrashLoopBackOff"
+	// PodInitializing is set for containers waiting for init containers
+	ContainerReasonPodInitializing = "PodInitializing"
+	// ErrImagePull is set when pulling image failed, and ImagePullBackOff when waiting to pull again
+	ContainerReasonErrImagePull     = "ErrImagePull"
+	ContainerReasonImagePullBackOff = "ImagePullBackOff"
+	// ErrImageNeverPull is set when image is not present with pull policy Never
+	ContainerReasonErrImageNeverPull = "ErrImageNeverPull"
+)
+
+// Reasons of ContainerStateTerminated
+const (
+	ContainerReasonCompleted = "Completed"
+	ContainerReasonError     = "Error"
+	ContainerReasonOOMKilled = "OOMKilled"
+)
+
+type ContainerStatus struct {
+	Name        string `json:"name" yaml:"name"`
+	ContainerID string `json:"containerID,omitempty" yaml:"containerID,omitempty"`
+	Image       string `json:"image,omitempty" yaml:"image,omitempty"`
+	ImageID     string `json:"imageID,omitempty" yaml:"imageID,omitempty"`
+	// State is the current state, only one of its fields is set
+	State ContainerState `json:"state" yaml:"state"`
+	// LastTerminationState is the state of the container before last restart
+	LastTerminationState ContainerState `json:"lastTerminationState,omitempty" yaml:"lastTerminationState,omitempty"`
+	RestartCount         int32          `json:"restartCount" yaml:"restartCount"`
+	Ready                bool           `json:"ready" yaml:"ready"`
+}
+
+type ContainerState struct {
+	Waiting    *ContainerStateWaiting    `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+	Running    *ContainerStateRunning    `json:"running,omitempty" yaml:"running,omitempty"`
+	Terminated *ContainerStateTerminated `json:"terminated,omitempty" yaml:"terminated,omitempty"`
+}
+
+type ContainerStateWaiting struct {
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type ContainerStateRunning struct {
+	StartedAt time.Time `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+}
+
+type ContainerStateTerminated struct {
+	ExitCode   int32     `json:"exitCode" yaml:"exitCode"`
+	Signal     int32     `json:"signal,omitempty" yaml:"signal,omitempty"`
+	Reason     string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message    string    `json:"message,omitempty" yaml:"message,omitempty"`
+	StartedAt  time.Time `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+	FinishedAt time.Time `json:"finishedAt,omitempty" yaml:"finishedAt,omitempty"`
+}
+
+type PodConditionType string
+
+const (
+	// PodScheduled is set by scheduler, False with reason Unschedulable if no node fits the pod
+	PodScheduled PodConditionType = "PodScheduled"
+	// PodInitialized means all init containers have completed, and sidecars have started
+	PodInitialized PodConditionType = "Initialized"
+	// ContainersReady means all containers are running and their readiness probes succeed
+	ContainersReady PodConditionType = "ContainersReady"
+	// PodReady means the pod can serve requests, and is added to endpoints of services
+	PodReady PodConditionType = "Ready"
+)
+
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+type PodCondition struct {
+	Type   PodConditionType `json:"type" yaml:"type"`
+	Status ConditionStatus  `json:"status" yaml:"status"`
+	// LastTransitionTime is when Status changed last time
+	LastTransitionTime time.Time `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
+	Reason             string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message            string    `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// GetPodCondition returns nil if the condition is not reported
+func GetPodCondition(status *PodStatus, conditionType PodConditionType) *PodCondition {
+	if status == nil {
+		return nil
+	}
+	for idx := range status.Conditions {
+		if status.Conditions[idx].Type == conditionType {
+			return &status.Conditions[idx]
+		}
+	}
+	return nil
+}
+
+// SetPodCondition adds or updates the condition,
+// LastTransitionTime is kept if status is not changed
+func SetPodCondition(status *PodStatus, condition PodCondition) {
+	old := GetPodCondition(status, condition.Type)
+	if old == nil {
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = time.Now()
+		}
+		status.Conditions = append(status.Conditions, condition)
+		return
+	}
+	if old.Status == condition.Status {
+		condition.LastTransitionTime = old.LastTransitionTime
+	} else if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = time.Now()
+	}
+	*old = condition
+}
+
+// IsPodReady tells whether the pod should receive service traffic
+func IsPodReady(pod *Pod) bool {
+	// terminating pods are removed from endpoints first, so that traffic is drained
+	if IsPodTerminating(pod) || pod.Status == nil || pod.Status.IP == nil || pod.Status.Phase != PodRunning {
+		return false
+	}
+	condition := GetPodCondition(pod.Status, PodReady)
+	return condition != nil && condition.Status == ConditionTrue
 }
 
 type ResourceUsage struct {
@@ -67,34 +339,287 @@ type ResourceUsage struct {
 	ActualMemoryUsage int64 `json:"actualMemoryUsage" yaml:"actualMemoryUsage"`
 }
 
+// Container Command overrides ENTRYPOINT of the image, and Args overrides CMD,
+// CMD of the image is dropped if only Command is set
 type Container struct {
 	Name    string   `json:"name" yaml:"name"`
 	Image   string   `json:"image" yaml:"image"`
 	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
 	Args    []string `json:"args,omitempty" yaml:"args,omitempty"`
+	// WorkingDir defaults to the one of the image
+	WorkingDir string `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
 	// use pointer or else omitempty is disabled
 	Resources    *ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
 	VolumeMounts []VolumeMount         `json:"volumeMounts,omitempty" yaml:"volumeMounts,omitempty"`
 	Ports        []ContainerPort       `json:"ports,omitempty" yaml:"ports,omitempty"`
+	// Env overrides variables from EnvFrom with the same name
+	Env     []EnvVar        `json:"env,omitempty" yaml:"env,omitempty"`
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty" yaml:"envFrom,omitempty"`
+	// container is restarted if liveness probe fails
+	LivenessProbe *Probe `json:"livenessProbe,omitempty" yaml:"livenessProbe,omitempty"`
+	// pod receives service traffic only when readiness probes of all containers succeed
+	ReadinessProbe *Probe `json:"readinessProbe,omitempty" yaml:"readinessProbe,omitempty"`
+	// other probes wait until startup probe succeeds,
+	// container is restarted if it doesn't succeed in time
+	StartupProbe *Probe `json:"startupProbe,omitempty" yaml:"startupProbe,omitempty"`
+	// RestartPolicy may only be Always, and only for init containers, which makes it a sidecar
+	RestartPolicy ContainerRestartPolicy `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty"`
+	Lifecycle     *Lifecycle             `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	// default to Always if the image has tag latest or no tag, otherwise IfNotPresent
+	ImagePullPolicy PullPolicy `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
+	// Stdin keeps stdin of the container open, so that it can be attached
+	Stdin bool `json:"stdin,omitempty" yaml:"stdin,omitempty"`
+	TTY   bool `json:"tty,omitempty" yaml:"tty,omitempty"`
+	// SecurityContext overrides fields of PodSpec.SecurityContext
+	SecurityContext *SecurityContext `json:"securityContext,omitempty" yaml:"securityContext,omitempty"`
 }
 
+type PullPolicy string
+
+const (
+	// PullAlways pulls image every time the container is started
+	PullAlways PullPolicy = "Always"
+	// PullIfNotPresent pulls image only if it is not on the node
+	PullIfNotPresent PullPolicy = "IfNotPresent"
+	// PullNever never pulls, the container fails to start if image is not on the node
+	PullNever PullPolicy = "Never"
+)
+
+// DefaultImagePullPolicy is Always for images with tag latest or no tag, otherwise IfNotPresent
+func DefaultImagePullPolicy(image string) PullPolicy {
+	if strings.Contains(image, "@") {
+		// pinned by digest
+		return PullIfNotPresent
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if idx := strings.LastIndex(name, ":"); idx == -1 || name[idx+1:] == "latest" {
+		return PullAlways
+	}
+	return PullIfNotPresent
+}
+
+// Lifecycle hooks are run in the container, or sent to it for HTTP
+type Lifecycle struct {
+	// PostStart runs right after the container is started, the container is killed if it fails
+	PostStart *LifecycleHandler `json:"postStart,omitempty" yaml:"postStart,omitempty"`
+	// PreStop runs before SIGTERM is sent, within the termination grace period
+	PreStop *LifecycleHandler `json:"preStop,omitempty" yaml:"preStop,omitempty"`
+}
+
+// LifecycleHandler sets only one of Exec and HTTPGet
+type LifecycleHandler struct {
+	Exec    *ExecAction    `json:"exec,omitempty" yaml:"exec,omitempty"`
+	HTTPGet *HTTPGetAction `json:"httpGet,omitempty" yaml:"httpGet,omitempty"`
+}
+
+// PodSecurityContext holds security settings shared by containers of the pod
+type PodSecurityContext struct {
+	// RunAsUser defaults to the user of the image
+	RunAsUser *int64 `json:"runAsUser,omitempty" yaml:"runAsUser,omitempty"`
+	// RunAsGroup requires RunAsUser, default to the primary group of the user
+	RunAsGroup *int64 `json:"runAsGroup,omitempty" yaml:"runAsGroup,omitempty"`
+	// SupplementalGroups are added to the processes of every container
+	SupplementalGroups []int64 `json:"supplementalGroups,omitempty" yaml:"supplementalGroups,omitempty"`
+}
+
+// SecurityContext holds security settings of a container
+type SecurityContext struct {
+	RunAsUser  *int64 `json:"runAsUser,omitempty" yaml:"runAsUser,omitempty"`
+	RunAsGroup *int64 `json:"runAsGroup,omitempty" yaml:"runAsGroup,omitempty"`
+	// ReadOnlyRootFilesystem makes the container root filesystem read-only, volumes are still writable
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty" yaml:"readOnlyRootFilesystem,omitempty"`
+	// Privileged gives the container all capabilities and access to host devices
+	Privileged *bool `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+	// AllowPrivilegeEscalation false sets no_new_privs, so that setuid binaries can't gain privileges
+	AllowPrivilegeEscalation *bool         `json:"allowPrivilegeEscalation,omitempty" yaml:"allowPrivilegeEscalation,omitempty"`
+	Capabilities             *Capabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+}
+
+// Capabilities are added to or dropped from the default set of the runtime, like NET_ADMIN or ALL
+type Capabilities struct {
+	Add  []string `json:"add,omitempty" yaml:"add,omitempty"`
+	Drop []string `json:"drop,omitempty" yaml:"drop,omitempty"`
+}
+
+// EffectiveSecurityContext merges the container security context over the pod one, it returns nil if neither is set
+func EffectiveSecurityContext(pod *PodSpec, container *Container) *SecurityContext {
+	if pod.SecurityContext == nil && container.SecurityContext == nil {
+		return nil
+	}
+
+	effective := &SecurityContext{}
+	if container.SecurityContext != nil {
+		*effective = *container.SecurityContext
+	}
+	if pod.SecurityContext != nil {
+		if effective.RunAsUser == nil {
+			effective.RunAsUser = pod.SecurityContext.RunAsUser
+		}
+		if effective.RunAsGroup == nil {
+			effective.RunAsGroup = pod.SecurityContext.RunAsGroup
+		}
+	}
+	return effective
+}
+
+type ContainerRestartPolicy string
+
+const ContainerRestartPolicyAlways ContainerRestartPolicy = "Always"
+
+// IsSidecarContainer tells whether an init container keeps running along with app containers
+func IsSidecarContainer(container *Container) bool {
+	return container.RestartPolicy == ContainerRestartPolicyAlways
+}
+
+// EnvVar sets Value, or the value taken from ValueFrom if set
+type EnvVar struct {
+	Name      string        `json:"name" yaml:"name"`
+	Value     string        `json:"value,omitempty" yaml:"value,omitempty"`
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+}
+
+// EnvVarSource sets only one of its fields
+type EnvVarSource struct {
+	FieldRef        *ObjectFieldSelector `json:"fieldRef,omitempty" yaml:"fieldRef,omitempty"`
+	ConfigMapKeyRef *KeySelector         `json:"configMapKeyRef,omitempty" yaml:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *KeySelector         `json:"secretKeyRef,omitempty" yaml:"secretKeyRef,omitempty"`
+}
+
+// Pod fields supported by ObjectFieldSelector
+const (
+	FieldPathPodName  = "metadata.name"
+	FieldPathPodUID   = "metadata.uid"
+	FieldPathPodIP    = "status.podIP"
+	FieldPathNodeUID  = "status.nodeUID"
+	FieldPathNodeName = "spec.nodeName"
+)
+
+type ObjectFieldSelector struct {
+	FieldPath string `json:"fieldPath" yaml:"fieldPath"`
+}
+
+// KeySelector selects a key of the ConfigMap or Secret with Name
+type KeySelector struct {
+	Name string `json:"name" yaml:"name"`
+	Key  string `json:"key" yaml:"key"`
+	// if true, the variable is not set when the object or key is missing,
+	// otherwise the container fails to start
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// EnvFromSource sets all keys of a ConfigMap or Secret as variables, with Prefix added
+type EnvFromSource struct {
+	Prefix       string                `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	ConfigMapRef *LocalObjectReference `json:"configMapRef,omitempty" yaml:"configMapRef,omitempty"`
+	SecretRef    *LocalObjectReference `json:"secretRef,omitempty" yaml:"secretRef,omitempty"`
+}
+
+// Probe sets only one of Exec, HTTPGet and TCPSocket
+type Probe struct {
+	Exec      *ExecAction      `json:"exec,omitempty" yaml:"exec,omitempty"`
+	HTTPGet   *HTTPGetAction   `json:"httpGet,omitempty" yaml:"httpGet,omitempty"`
+	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty" yaml:"tcpSocket,omitempty"`
+
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty" yaml:"initialDelaySeconds,omitempty"`
+	// default to 1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+	// default to 10
+	PeriodSeconds int32 `json:"periodSeconds,omitempty" yaml:"periodSeconds,omitempty"`
+	// consecutive successes to be taken as succeeded after failure, default to 1
+	SuccessThreshold int32 `json:"successThreshold,omitempty" yaml:"successThreshold,omitempty"`
+	// consecutive failures to be taken as failed, default to 3
+	FailureThreshold int32 `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"`
+}
+
+// ExecAction succeeds if Command exits with 0 in the container
+type ExecAction struct {
+	Command []string `json:"command" yaml:"command"`
+}
+
+// HTTPGetAction succeeds if status code is in [200, 400)
+type HTTPGetAction struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Port int32  `json:"port" yaml:"port"`
+	// default to pod IP
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+	// HTTP or HTTPS, default to HTTP
+	Scheme      string       `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	HTTPHeaders []HTTPHeader `json:"httpHeaders,omitempty" yaml:"httpHeaders,omitempty"`
+}
+
+type HTTPHeader struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// TCPSocketAction succeeds if the port can be connected
+type TCPSocketAction struct {
+	Port int32 `json:"port" yaml:"port"`
+	// default to pod IP
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+}
+
+type LocalObjectReference struct {
+	Name     string `json:"name" yaml:"name"`
+	Optional bool   `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+type TolerationOperator string
+
+const (
+	TolerationOpExists TolerationOperator = "Exists"
+	TolerationOpEqual  TolerationOperator = "Equal"
+)
+
+type Toleration struct {
+	// empty Key with operator Exists matches all taints
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// default to Equal
+	Operator TolerationOperator `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value    string             `json:"value,omitempty" yaml:"value,omitempty"`
+	// empty Effect matches all effects
+	Effect TaintEffect `json:"effect,omitempty" yaml:"effect,omitempty"`
+	// only for NoExecute: how long the pod stays bound after the taint is added,
+	// nil means tolerate forever
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty" yaml:"tolerationSeconds,omitempty"`
+}
+
+// ResourceRequirements limits the container to Cpus and Memory, zero means not limited
 type ResourceRequirements struct {
 	Cpus float64 `json:"cpus,omitempty" yaml:"cpus,omitempty"`
 	// Memory in bytes
 	Memory int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+	// Requests are reserved for the container by scheduler, each defaults to its limit
+	Requests *ResourceList `json:"requests,omitempty" yaml:"requests,omitempty"`
 }
 
+type ResourceList struct {
+	Cpus float64 `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	// Memory in bytes
+	Memory int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+}
+
+// Volume has exactly one source: HostPath, or one of the volume sources managed by cubelet
 type Volume struct {
-	Name string `json:"name" yaml:"name"`
-	// Volume only support HostPath types
-	HostPath string `json:"hostPath" yaml:"hostPath"`
+	Name                  string                             `json:"name" yaml:"name"`
+	HostPath              string                             `json:"hostPath,omitempty" yaml:"hostPath,omitempty"`
+	EmptyDir              *EmptyDirVolumeSource              `json:"emptyDir,omitempty" yaml:"emptyDir,omitempty"`
+	ConfigMap             *ConfigMapVolumeSource             `json:"configMap,omitempty" yaml:"configMap,omitempty"`
+	Secret                *SecretVolumeSource                `json:"secret,omitempty" yaml:"secret,omitempty"`
+	DownwardAPI           *DownwardAPIVolumeSource           `json:"downwardAPI,omitempty" yaml:"downwardAPI,omitempty"`
+	Projected             *ProjectedVolumeSource             `json:"projected,omitempty" yaml:"projected,omitempty"`
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty" yaml:"persistentVolumeClaim,omitempty"`
 }
 
+// VolumeMount of configMap, secret, downwardAPI and projected volumes are always read-only
 type VolumeMount struct {
 	Name      string `json:"name" yaml:"name"`
 	MountPath string `json:"mountPath" yaml:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
 }
 
+// ContainerPort is exposed on the node if HostPort is set, traffic to HostIP:HostPort
+// is forwarded to the pod, an empty HostIP stands for all addresses of the node
 type ContainerPort struct {
 	Name          string `json:"name" yaml:"name"`
 	HostPort      int32  `json:"hostPort" yaml:"hostPort"`
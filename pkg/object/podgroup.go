@@ -0,0 +1,61 @@
+package object
+
+import "time"
+
+const PodGroupEtcdPrefix = "/apis/podGroup/"
+
+// PodGroupLabel is put on pods to join the pod group with the same name
+const PodGroupLabel = "pod-group.cubernetes.io/name"
+
+// DefaultPodGroupScheduleTimeoutSeconds is how long the scheduler reserves
+// capacity for members of a pod group, waiting for the group to be satisfied
+const DefaultPodGroupScheduleTimeoutSeconds = 60
+
+// PodGroup makes pods labeled with PodGroupLabel be scheduled together,
+// none of them is bound to a node before MinMember of them fit
+type PodGroup struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	Spec       PodGroupSpec    `json:"spec" yaml:"spec"`
+	Status     *PodGroupStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+type PodGroupSpec struct {
+	MinMember int32 `json:"minMember" yaml:"minMember"`
+	// default to DefaultPodGroupScheduleTimeoutSeconds
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" yaml:"scheduleTimeoutSeconds,omitempty"`
+}
+
+type PodGroupPhase string
+
+const (
+	// PodGroupPending means less than minMember pods are bound
+	PodGroupPending PodGroupPhase = "Pending"
+	// PodGroupScheduled means at least minMember pods are bound
+	PodGroupScheduled PodGroupPhase = "Scheduled"
+	// PodGroupRunning means at least minMember pods are running
+	PodGroupRunning PodGroupPhase = "Running"
+	// PodGroupFinished means at least minMember pods succeeded
+	PodGroupFinished PodGroupPhase = "Finished"
+)
+
+type PodGroupStatus struct {
+	Phase          PodGroupPhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Scheduled      int32         `json:"scheduled" yaml:"scheduled"`
+	Running        int32         `json:"running" yaml:"running"`
+	Succeeded      int32         `json:"succeeded" yaml:"succeeded"`
+	Failed         int32         `json:"failed" yaml:"failed"`
+	LastUpdateTime time.Time     `json:"lastUpdateTime,omitempty" yaml:"lastUpdateTime,omitempty"`
+}
+
+func (pg *PodGroup) ScheduleTimeout() time.Duration {
+	if pg.Spec.ScheduleTimeoutSeconds != nil && *pg.Spec.ScheduleTimeoutSeconds > 0 {
+		return time.Duration(*pg.Spec.ScheduleTimeoutSeconds) * time.Second
+	}
+	return DefaultPodGroupScheduleTimeoutSeconds * time.Second
+}
+
+// GetPodGroupName returns name of the pod group the pod belongs to, or empty string
+func GetPodGroupName(pod *Pod) string {
+	return pod.Labels[PodGroupLabel]
+}
@@ -0,0 +1,38 @@
+package object
+
+const PriorityClassEtcdPrefix = "/apis/priorityClass/"
+
+const (
+	// DefaultPriority is used by pods without priority class
+	// when there is no global default priority class
+	DefaultPriority int32 = 0
+)
+
+type PreemptionPolicy string
+
+const (
+	PreemptLowerPriority PreemptionPolicy = "PreemptLowerPriority"
+	PreemptNever         PreemptionPolicy = "Never"
+)
+
+// PriorityClass maps a priority class name to the priority value of pods,
+// it is referred by name in PodSpec
+type PriorityClass struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	// higher value means higher priority
+	Value int32 `json:"value" yaml:"value"`
+	// used by pods without priorityClassName, only one class should set this
+	GlobalDefault bool `json:"globalDefault,omitempty" yaml:"globalDefault,omitempty"`
+	// default to PreemptLowerPriority
+	PreemptionPolicy PreemptionPolicy `json:"preemptionPolicy,omitempty" yaml:"preemptionPolicy,omitempty"`
+	Description      string           `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// GetPodPriority returns priority of the pod, resolved by apiserver when created
+func GetPodPriority(pod *Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return DefaultPriority
+}
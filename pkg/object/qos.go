@@ -0,0 +1,117 @@
+package object
+
+import "math"
+
+type PodQOSClass string
+
+const (
+	// PodQOSGuaranteed pods have cpu and memory limits equal to requests in every container,
+	// they are evicted last
+	PodQOSGuaranteed PodQOSClass = "Guaranteed"
+	PodQOSBurstable  PodQOSClass = "Burstable"
+	// PodQOSBestEffort pods have no requests or limits in any container, they are evicted first
+	PodQOSBestEffort PodQOSClass = "BestEffort"
+)
+
+// GetContainerRequests returns cpus and memory (in bytes) requested by the container,
+// each defaults to its limit if not requested
+func GetContainerRequests(container *Container) (float64, int64) {
+	if container.Resources == nil {
+		return 0, 0
+	}
+	cpus, memory := container.Resources.Cpus, container.Resources.Memory
+	if requests := container.Resources.Requests; requests != nil {
+		if requests.Cpus > 0 {
+			cpus = requests.Cpus
+		}
+		if requests.Memory > 0 {
+			memory = requests.Memory
+		}
+	}
+	return cpus, memory
+}
+
+func getContainerLimits(container *Container) (float64, int64) {
+	if container.Resources == nil {
+		return 0, 0
+	}
+	return container.Resources.Cpus, container.Resources.Memory
+}
+
+// GetPodQOS returns the QoS class of the pod by requests and limits of all containers
+func GetPodQOS(pod *Pod) PodQOSClass {
+	containers := append(append([]Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	bestEffort, guaranteed := true, true
+	for idx := range containers {
+		requestCpus, requestMemory := GetContainerRequests(&containers[idx])
+		limitCpus, limitMemory := getContainerLimits(&containers[idx])
+		if requestCpus > 0 || requestMemory > 0 {
+			bestEffort = false
+		}
+		if limitCpus <= 0 || limitMemory <= 0 || requestCpus != limitCpus || requestMemory != limitMemory {
+			guaranteed = false
+		}
+	}
+
+	switch {
+	case bestEffort:
+		return PodQOSBestEffort
+	case guaranteed:
+		return PodQOSGuaranteed
+	default:
+		return PodQOSBurstable
+	}
+}
+
+// GetPodRequests returns cpus and memory (in bytes) requested by all containers of the pod.
+// Init containers run one by one along with sidecars started before them,
+// so the pod requests the most of each step
+func GetPodRequests(pod *Pod) (float64, int64) {
+	return sumPodResources(pod, GetContainerRequests)
+}
+
+// GetPodLimits returns cpus and memory (in bytes) the pod is limited to, summed like GetPodRequests.
+// Zero means not limited, i.e. some container is not limited on the resource
+func GetPodLimits(pod *Pod) (float64, int64) {
+	cpus, memory := sumPodResources(pod, getContainerLimits)
+	containers := append(append([]Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for idx := range containers {
+		c, m := getContainerLimits(&containers[idx])
+		if c <= 0 {
+			cpus = 0
+		}
+		if m <= 0 {
+			memory = 0
+		}
+	}
+	return cpus, memory
+}
+
+func sumPodResources(pod *Pod, resources func(container *Container) (float64, int64)) (float64, int64) {
+	sidecarCpus, sidecarMemory := 0.0, int64(0)
+	initCpus, initMemory := 0.0, int64(0)
+	for idx := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[idx]
+		c, m := resources(container)
+		if IsSidecarContainer(container) {
+			sidecarCpus += c
+			sidecarMemory += m
+			continue
+		}
+		initCpus = math.Max(initCpus, sidecarCpus+c)
+		if sidecarMemory+m > initMemory {
+			initMemory = sidecarMemory + m
+		}
+	}
+
+	cpus, memory := sidecarCpus, sidecarMemory
+	for idx := range pod.Spec.Containers {
+		c, m := resources(&pod.Spec.Containers[idx])
+		cpus += c
+		memory += m
+	}
+	if initMemory > memory {
+		memory = initMemory
+	}
+	return math.Max(cpus, initCpus), memory
+}
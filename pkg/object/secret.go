@@ -0,0 +1,41 @@
+package object
+
+import "encoding/base64"
+
+const SecretEtcdPrefix = "/apis/secret/"
+
+type SecretType string
+
+const (
+	SecretTypeOpaque SecretType = "Opaque"
+	// SecretTypeDockerConfigJson holds registry credentials in key .dockerconfigjson,
+	// in the format of ~/.docker/config.json
+	SecretTypeDockerConfigJson SecretType = "cubernetes.io/dockerconfigjson"
+)
+
+const DockerConfigJsonKey = ".dockerconfigjson"
+
+// Secret is like ConfigMap, but its values are base64 encoded
+type Secret struct {
+	TypeMeta   `json:",inline" yaml:",inline"`
+	ObjectMeta `json:"metadata" yaml:"metadata"`
+	// default to Opaque
+	Type SecretType `json:"type,omitempty" yaml:"type,omitempty"`
+	// values are base64 encoded
+	Data map[string]string `json:"data,omitempty" yaml:"data,omitempty"`
+	// StringData is write-only, apiserver encodes its values into Data
+	StringData map[string]string `json:"stringData,omitempty" yaml:"stringData,omitempty"`
+}
+
+// GetValue returns the decoded value of key
+func (s *Secret) GetValue(key string) (string, bool) {
+	encoded, ok := s.Data[key]
+	if !ok {
+		return "", false
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false
+	}
+	return string(value), true
+}
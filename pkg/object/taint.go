@@ -0,0 +1,96 @@
+package object
+
+const (
+	// TaintNodeMaster is suggested for master nodes to keep workloads off the control plane
+	TaintNodeMaster = "node-role.cubernetes.io/master"
+	// TaintNodeNotReady is never stored in etcd, it is implied by a not ready node
+	TaintNodeNotReady = "node.cubernetes.io/not-ready"
+	// TaintNodeUnschedulable is implied by a cordoned node
+	TaintNodeUnschedulable = "node.cubernetes.io/unschedulable"
+)
+
+// ToleratesTaint checks if the toleration tolerates the taint.
+func (t *Toleration) ToleratesTaint(taint *Taint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+
+	if t.Key != "" && t.Key != taint.Key {
+		return false
+	}
+
+	switch t.Operator {
+	case "", TolerationOpEqual:
+		return t.Value == taint.Value
+	case TolerationOpExists:
+		return true
+	default:
+		return false
+	}
+}
+
+func TolerationsTolerateTaint(tolerations []Toleration, taint *Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindMatchingUntoleratedTaint returns the first taint accepted by inclusionFilter
+// which is not tolerated by tolerations
+func FindMatchingUntoleratedTaint(taints []Taint, tolerations []Toleration,
+	inclusionFilter func(*Taint) bool) (Taint, bool) {
+	for i := range taints {
+		if inclusionFilter != nil && !inclusionFilter(&taints[i]) {
+			continue
+		}
+		if !TolerationsTolerateTaint(tolerations, &taints[i]) {
+			return taints[i], true
+		}
+	}
+	return Taint{}, false
+}
+
+// GetMatchingTolerations returns whether all taints are tolerated,
+// and the tolerations used to tolerate them
+func GetMatchingTolerations(taints []Taint, tolerations []Toleration) (bool, []Toleration) {
+	if len(taints) == 0 {
+		return true, []Toleration{}
+	}
+	if len(tolerations) == 0 {
+		return false, []Toleration{}
+	}
+
+	result := make([]Toleration, 0)
+	for i := range taints {
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				result = append(result, tolerations[j])
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false, []Toleration{}
+		}
+	}
+	return true, result
+}
+
+// EffectiveTaints returns taints stored in spec,
+// plus the ones implied by node conditions
+func (node *Node) EffectiveTaints() []Taint {
+	taints := make([]Taint, 0, len(node.Spec.Taints)+2)
+	taints = append(taints, node.Spec.Taints...)
+
+	if node.Status == nil || !node.Status.Condition.Ready {
+		taints = append(taints, Taint{Key: TaintNodeNotReady, Effect: TaintEffectNoExecute})
+	}
+	if node.Spec.Unschedulable {
+		taints = append(taints, Taint{Key: TaintNodeUnschedulable, Effect: TaintEffectNoSchedule})
+	}
+	return taints
+}
@@ -0,0 +1,47 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSetPodCondition(t *testing.T) {
+	status := &object.PodStatus{}
+	assert.Nil(t, object.GetPodCondition(status, object.PodReady))
+
+	object.SetPodCondition(status, object.PodCondition{Type: object.PodReady, Status: object.ConditionFalse})
+	ready := object.GetPodCondition(status, object.PodReady)
+	assert.NotNil(t, ready)
+	assert.False(t, ready.LastTransitionTime.IsZero())
+	transition := ready.LastTransitionTime
+
+	// transition time is kept while status doesn't change
+	time.Sleep(10 * time.Millisecond)
+	object.SetPodCondition(status, object.PodCondition{Type: object.PodReady, Status: object.ConditionFalse, Message: "probe failed"})
+	ready = object.GetPodCondition(status, object.PodReady)
+	assert.Equal(t, transition, ready.LastTransitionTime)
+	assert.Equal(t, "probe failed", ready.Message)
+
+	object.SetPodCondition(status, object.PodCondition{Type: object.PodReady, Status: object.ConditionTrue})
+	ready = object.GetPodCondition(status, object.PodReady)
+	assert.True(t, ready.LastTransitionTime.After(transition))
+	assert.Equal(t, "", ready.Message)
+
+	object.SetPodCondition(status, object.PodCondition{Type: object.PodScheduled, Status: object.ConditionTrue})
+	assert.Len(t, status.Conditions, 2)
+}
+
+func TestIsPodReady(t *testing.T) {
+	pod := &object.Pod{Status: &object.PodStatus{Phase: object.PodRunning, IP: net.ParseIP("10.32.0.2")}}
+	assert.False(t, object.IsPodReady(pod))
+
+	object.SetPodCondition(pod.Status, object.PodCondition{Type: object.PodReady, Status: object.ConditionTrue})
+	assert.True(t, object.IsPodReady(pod))
+
+	deletionTimestamp := time.Now().Add(time.Minute)
+	pod.DeletionTimestamp = &deletionTimestamp
+	assert.False(t, object.IsPodReady(pod))
+}
@@ -0,0 +1,34 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLogOptionsQuery(t *testing.T) {
+	opts := object.LogOptions{
+		Container:  "nginx",
+		TailLines:  20,
+		Since:      time.Minute * 5,
+		Timestamps: true,
+		Previous:   true,
+	}
+	parsed, err := object.ParseLogOptions(opts.Query())
+	assert.Nil(t, err)
+	assert.Equal(t, opts, parsed)
+
+	parsed, err = object.ParseLogOptions(url.Values{})
+	assert.Nil(t, err)
+	assert.Equal(t, -1, parsed.TailLines)
+	assert.False(t, parsed.Follow)
+
+	_, err = object.ParseLogOptions(url.Values{object.LogQueryTailLines: {"-3"}})
+	assert.NotNil(t, err)
+	_, err = object.ParseLogOptions(url.Values{object.LogQuerySince: {"yesterday"}})
+	assert.NotNil(t, err)
+	_, err = object.ParseLogOptions(url.Values{object.LogQueryFollow: {"true"}, object.LogQueryPrevious: {"true"}})
+	assert.NotNil(t, err)
+}
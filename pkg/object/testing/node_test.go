@@ -0,0 +1,22 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNodeAllocatable(t *testing.T) {
+	node := &object.Node{Spec: object.NodeSpec{Capacity: object.NodeCapacity{CPUCount: 4, Memory: 2048, MaxPods: 20}}}
+	cpus, memory, pods := object.GetNodeAllocatable(node)
+	assert.Equal(t, 4.0, cpus)
+	assert.Equal(t, int64(2048*1024*1024), memory)
+	assert.Equal(t, 20, pods)
+
+	// allocatable reported by cubelet takes precedence
+	node.Status = &object.NodeStatus{Allocatable: &object.NodeResources{Cpus: 3.9, Memory: 1 << 30, Pods: 110}}
+	cpus, memory, pods = object.GetNodeAllocatable(node)
+	assert.Equal(t, 3.9, cpus)
+	assert.Equal(t, int64(1<<30), memory)
+	assert.Equal(t, 110, pods)
+}
@@ -0,0 +1,35 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetPodQOS(t *testing.T) {
+	pod := &object.Pod{Spec: object.PodSpec{Containers: []object.Container{{Name: "a"}, {Name: "b"}}}}
+	assert.Equal(t, object.PodQOSBestEffort, object.GetPodQOS(pod))
+
+	// requests default to limits
+	pod.Spec.Containers[0].Resources = &object.ResourceRequirements{Cpus: 1, Memory: 1024}
+	assert.Equal(t, object.PodQOSBurstable, object.GetPodQOS(pod))
+	pod.Spec.Containers[1].Resources = &object.ResourceRequirements{Cpus: 0.5, Memory: 512}
+	assert.Equal(t, object.PodQOSGuaranteed, object.GetPodQOS(pod))
+
+	pod.Spec.Containers[1].Resources.Requests = &object.ResourceList{Memory: 256}
+	assert.Equal(t, object.PodQOSBurstable, object.GetPodQOS(pod))
+
+	cpus, memory := object.GetPodRequests(pod)
+	assert.Equal(t, 1.5, cpus)
+	assert.Equal(t, int64(1280), memory)
+	cpus, memory = object.GetPodLimits(pod)
+	assert.Equal(t, 1.5, cpus)
+	assert.Equal(t, int64(1536), memory)
+
+	// an init container without limits leaves the pod unlimited
+	pod.Spec.InitContainers = []object.Container{{Name: "init"}}
+	assert.Equal(t, object.PodQOSBurstable, object.GetPodQOS(pod))
+	cpus, memory = object.GetPodLimits(pod)
+	assert.Equal(t, 0.0, cpus)
+	assert.Equal(t, int64(0), memory)
+}
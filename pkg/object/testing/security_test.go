@@ -0,0 +1,28 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEffectiveSecurityContext(t *testing.T) {
+	podUser, podGroup, containerUser := int64(1000), int64(3000), int64(0)
+	readOnly := true
+	spec := &object.PodSpec{}
+	container := &object.Container{}
+	assert.Nil(t, object.EffectiveSecurityContext(spec, container))
+
+	spec.SecurityContext = &object.PodSecurityContext{RunAsUser: &podUser, RunAsGroup: &podGroup}
+	sc := object.EffectiveSecurityContext(spec, container)
+	assert.Equal(t, podUser, *sc.RunAsUser)
+	assert.Equal(t, podGroup, *sc.RunAsGroup)
+
+	// container settings win, and the container spec is not modified
+	container.SecurityContext = &object.SecurityContext{RunAsUser: &containerUser, ReadOnlyRootFilesystem: &readOnly}
+	sc = object.EffectiveSecurityContext(spec, container)
+	assert.Equal(t, containerUser, *sc.RunAsUser)
+	assert.Equal(t, podGroup, *sc.RunAsGroup)
+	assert.True(t, *sc.ReadOnlyRootFilesystem)
+	assert.Nil(t, container.SecurityContext.RunAsGroup)
+}
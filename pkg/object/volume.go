@@ -0,0 +1,284 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type StorageMedium string
+
+const (
+	// StorageMediumDefault uses the disk of the node
+	StorageMediumDefault StorageMedium = ""
+	// StorageMediumMemory backs the volume by tmpfs
+	StorageMediumMemory StorageMedium = "Memory"
+)
+
+// Pod fields supported by downward API volumes besides metadata.name and metadata.uid
+const (
+	FieldPathPodNamespace   = "metadata.namespace"
+	FieldPathPodLabels      = "metadata.labels"
+	FieldPathPodAnnotations = "metadata.annotations"
+)
+
+// DefaultVolumeFileMode is the mode of files in configMap, secret, downwardAPI and projected volumes
+const DefaultVolumeFileMode int32 = 0644
+
+// EmptyDirVolumeSource is a directory created with the pod, and removed with it
+type EmptyDirVolumeSource struct {
+	Medium StorageMedium `json:"medium,omitempty" yaml:"medium,omitempty"`
+	// SizeLimit in bytes, 0 means unlimited; it is the size of tmpfs for Memory medium
+	SizeLimit int64 `json:"sizeLimit,omitempty" yaml:"sizeLimit,omitempty"`
+}
+
+// KeyToPath projects Key of a ConfigMap or Secret to the relative Path in the volume
+type KeyToPath struct {
+	Key  string `json:"key" yaml:"key"`
+	Path string `json:"path" yaml:"path"`
+	// Mode of the file, DefaultMode of the volume is used if nil
+	Mode *int32 `json:"mode,omitempty" yaml:"mode,omitempty"`
+}
+
+// ConfigMapVolumeSource writes each key of the ConfigMap to a file named by the key,
+// or only those in Items; files are updated when the ConfigMap changes
+type ConfigMapVolumeSource struct {
+	Name        string      `json:"name" yaml:"name"`
+	Items       []KeyToPath `json:"items,omitempty" yaml:"items,omitempty"`
+	DefaultMode *int32      `json:"defaultMode,omitempty" yaml:"defaultMode,omitempty"`
+	// if true, the volume is empty when the ConfigMap or a key is missing,
+	// otherwise the pod fails to start
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// SecretVolumeSource is like ConfigMapVolumeSource, files hold the decoded values
+type SecretVolumeSource struct {
+	SecretName  string      `json:"secretName" yaml:"secretName"`
+	Items       []KeyToPath `json:"items,omitempty" yaml:"items,omitempty"`
+	DefaultMode *int32      `json:"defaultMode,omitempty" yaml:"defaultMode,omitempty"`
+	Optional    bool        `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// DownwardAPIVolumeFile writes the pod field to Path, labels and annotations
+// are written one per line as key="value"
+type DownwardAPIVolumeFile struct {
+	Path     string               `json:"path" yaml:"path"`
+	FieldRef *ObjectFieldSelector `json:"fieldRef" yaml:"fieldRef"`
+	Mode     *int32               `json:"mode,omitempty" yaml:"mode,omitempty"`
+}
+
+type DownwardAPIVolumeSource struct {
+	Items       []DownwardAPIVolumeFile `json:"items" yaml:"items"`
+	DefaultMode *int32                  `json:"defaultMode,omitempty" yaml:"defaultMode,omitempty"`
+}
+
+type ConfigMapProjection struct {
+	Name     string      `json:"name" yaml:"name"`
+	Items    []KeyToPath `json:"items,omitempty" yaml:"items,omitempty"`
+	Optional bool        `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+type SecretProjection struct {
+	Name     string      `json:"name" yaml:"name"`
+	Items    []KeyToPath `json:"items,omitempty" yaml:"items,omitempty"`
+	Optional bool        `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+type DownwardAPIProjection struct {
+	Items []DownwardAPIVolumeFile `json:"items" yaml:"items"`
+}
+
+// VolumeProjection has exactly one source
+type VolumeProjection struct {
+	ConfigMap   *ConfigMapProjection   `json:"configMap,omitempty" yaml:"configMap,omitempty"`
+	Secret      *SecretProjection      `json:"secret,omitempty" yaml:"secret,omitempty"`
+	DownwardAPI *DownwardAPIProjection `json:"downwardAPI,omitempty" yaml:"downwardAPI,omitempty"`
+}
+
+// ProjectedVolumeSource merges files of all sources into one directory, paths must not overlap
+type ProjectedVolumeSource struct {
+	Sources     []VolumeProjection `json:"sources" yaml:"sources"`
+	DefaultMode *int32             `json:"defaultMode,omitempty" yaml:"defaultMode,omitempty"`
+}
+
+// IsManaged tells if the directory of the volume is managed by cubelet, i.e. it is not a hostPath
+func (v *Volume) IsManaged() bool {
+	return v.HostPath == ""
+}
+
+// IsReadOnly tells if the volume is always mounted read-only
+func (v *Volume) IsReadOnly() bool {
+	return v.ConfigMap != nil || v.Secret != nil || v.DownwardAPI != nil || v.Projected != nil ||
+		(v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ReadOnly)
+}
+
+// CheckVolume checks that the volume has exactly one valid source
+func CheckVolume(v *Volume) error {
+	if v.Name == "" || v.Name == "." || v.Name == ".." || strings.Contains(v.Name, "/") {
+		return fmt.Errorf("invalid volume name %q", v.Name)
+	}
+
+	sources := 0
+	if v.HostPath != "" {
+		sources++
+		if !filepath.IsAbs(v.HostPath) {
+			return fmt.Errorf("hostPath of volume %s must be absolute", v.Name)
+		}
+	}
+	if v.EmptyDir != nil {
+		sources++
+		if v.EmptyDir.Medium != StorageMediumDefault && v.EmptyDir.Medium != StorageMediumMemory {
+			return fmt.Errorf("unknown medium %q of volume %s", v.EmptyDir.Medium, v.Name)
+		}
+		if v.EmptyDir.SizeLimit < 0 {
+			return fmt.Errorf("sizeLimit of volume %s must be non-negative", v.Name)
+		}
+	}
+	if v.ConfigMap != nil {
+		sources++
+		if v.ConfigMap.Name == "" {
+			return fmt.Errorf("configMap name of volume %s is required", v.Name)
+		}
+		if err := checkKeyToPaths(v.ConfigMap.Items, v.ConfigMap.DefaultMode); err != nil {
+			return fmt.Errorf("volume %s: %v", v.Name, err)
+		}
+	}
+	if v.Secret != nil {
+		sources++
+		if v.Secret.SecretName == "" {
+			return fmt.Errorf("secretName of volume %s is required", v.Name)
+		}
+		if err := checkKeyToPaths(v.Secret.Items, v.Secret.DefaultMode); err != nil {
+			return fmt.Errorf("volume %s: %v", v.Name, err)
+		}
+	}
+	if v.DownwardAPI != nil {
+		sources++
+		if err := checkDownwardAPIFiles(v.DownwardAPI.Items, v.DownwardAPI.DefaultMode); err != nil {
+			return fmt.Errorf("volume %s: %v", v.Name, err)
+		}
+	}
+	if v.Projected != nil {
+		sources++
+		if err := checkProjection(v.Projected); err != nil {
+			return fmt.Errorf("volume %s: %v", v.Name, err)
+		}
+	}
+	if v.PersistentVolumeClaim != nil {
+		sources++
+		if v.PersistentVolumeClaim.ClaimName == "" {
+			return fmt.Errorf("claimName of volume %s is required", v.Name)
+		}
+	}
+
+	if sources != 1 {
+		return fmt.Errorf("volume %s must have exactly one source", v.Name)
+	}
+	return nil
+}
+
+func checkProjection(p *ProjectedVolumeSource) error {
+	if err := checkMode(p.DefaultMode); err != nil {
+		return err
+	}
+	for _, source := range p.Sources {
+		n := 0
+		if source.ConfigMap != nil {
+			n++
+			if source.ConfigMap.Name == "" {
+				return errors.New("configMap name is required")
+			}
+			if err := checkKeyToPaths(source.ConfigMap.Items, nil); err != nil {
+				return err
+			}
+		}
+		if source.Secret != nil {
+			n++
+			if source.Secret.Name == "" {
+				return errors.New("secret name is required")
+			}
+			if err := checkKeyToPaths(source.Secret.Items, nil); err != nil {
+				return err
+			}
+		}
+		if source.DownwardAPI != nil {
+			n++
+			if err := checkDownwardAPIFiles(source.DownwardAPI.Items, nil); err != nil {
+				return err
+			}
+		}
+		if n != 1 {
+			return errors.New("each projected source must have exactly one of configMap, secret and downwardAPI")
+		}
+	}
+	return nil
+}
+
+func checkKeyToPaths(items []KeyToPath, defaultMode *int32) error {
+	if err := checkMode(defaultMode); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Key == "" {
+			return errors.New("key of item is required")
+		}
+		if err := CheckVolumeFilePath(item.Path); err != nil {
+			return err
+		}
+		if err := checkMode(item.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkDownwardAPIFiles(items []DownwardAPIVolumeFile, defaultMode *int32) error {
+	if err := checkMode(defaultMode); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := CheckVolumeFilePath(item.Path); err != nil {
+			return err
+		}
+		if item.FieldRef == nil {
+			return fmt.Errorf("fieldRef of %s is required", item.Path)
+		}
+		switch item.FieldRef.FieldPath {
+		case FieldPathPodName, FieldPathPodUID, FieldPathPodNamespace, FieldPathPodLabels, FieldPathPodAnnotations:
+		default:
+			return fmt.Errorf("field %s is not supported by downward API volumes", item.FieldRef.FieldPath)
+		}
+		if err := checkMode(item.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkMode(mode *int32) error {
+	if mode != nil && (*mode < 0 || *mode > 0777) {
+		return fmt.Errorf("file mode %o is out of range", *mode)
+	}
+	return nil
+}
+
+// CheckVolumeFilePath checks that path is relative and stays inside the volume,
+// paths starting with .. are reserved for data directories of the volume
+func CheckVolumeFilePath(path string) error {
+	if path == "" {
+		return errors.New("path is required")
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path %s must be relative", path)
+	}
+	for _, part := range strings.Split(path, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("path %s must be clean and must not contain '..'", path)
+		}
+	}
+	if strings.HasPrefix(path, "..") {
+		return fmt.Errorf("path %s must not start with '..'", path)
+	}
+	return nil
+}
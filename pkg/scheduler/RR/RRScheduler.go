@@ -77,3 +77,25 @@ func (rr *SchedulerRR) Schedule() (types.ScheduleInfo, error) {
 	n := atomic.AddInt32(&rr.Next, 1)
 	return types.ScheduleInfo{NodeUUID: rr.NameOfNodes[((n - 1) % rr.NumOfNodes)]}, nil
 }
+
+func (rr *SchedulerRR) ScheduleFrom(candidates []string) (types.ScheduleInfo, error) {
+	if rr.NumOfNodes == 0 || len(candidates) == 0 {
+		return types.ScheduleInfo{NodeUUID: ""}, ErrNoNodesToSchedule
+	}
+
+	set := make(map[string]bool, len(candidates))
+	for _, uid := range candidates {
+		set[uid] = true
+	}
+
+	// walk the ring from next position, so that candidates still share load
+	for i := int32(0); i < rr.NumOfNodes; i++ {
+		n := atomic.AddInt32(&rr.Next, 1)
+		uid := rr.NameOfNodes[((n - 1) % rr.NumOfNodes)]
+		if set[uid] {
+			return types.ScheduleInfo{NodeUUID: uid}, nil
+		}
+	}
+
+	return types.ScheduleInfo{NodeUUID: ""}, ErrNoNodesToSchedule
+}
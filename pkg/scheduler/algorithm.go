@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/core"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/types"
+	"log"
+)
+
+// getNodeInfos returns ready nodes, with pods running on them.
+// Pods nominated to a node are also counted, if they are not less important than pod,
+// so that the room made by preemption is not taken by others
+func (sr *ScheduleRuntime) getNodeInfos(pod *object.Pod) ([]*types.NodeInfo, error) {
+	nodes, err := crudobj.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeInfoMap := make(map[string]*types.NodeInfo)
+	nodeInfos := make([]*types.NodeInfo, 0, len(nodes))
+	for idx := range nodes {
+		if nodes[idx].Status == nil || !nodes[idx].Status.Condition.Ready {
+			continue
+		}
+		nodeInfo := &types.NodeInfo{
+			NodeUUID: nodes[idx].UID,
+			Node:     &nodes[idx],
+			Pods:     make([]object.Pod, 0),
+		}
+		nodeInfos = append(nodeInfos, nodeInfo)
+		nodeInfoMap[nodes[idx].UID] = nodeInfo
+	}
+
+	priority := object.GetPodPriority(pod)
+	for _, p := range pods {
+		if p.UID == pod.UID || p.Status == nil ||
+			p.Status.Phase == object.PodSucceeded || p.Status.Phase == object.PodFailed {
+			continue
+		}
+
+		nodeUID := p.Status.NodeUID
+		if nodeUID == "" && p.Status.NominatedNodeUID != "" && object.GetPodPriority(&p) >= priority {
+			nodeUID = p.Status.NominatedNodeUID
+		}
+		if nodeInfo, ok := nodeInfoMap[nodeUID]; ok {
+			nodeInfo.Pods = append(nodeInfo.Pods, p)
+		}
+	}
+
+	// members of pod groups reserved the nodes, though not bound yet
+	for _, p := range sr.gang.reservedPods() {
+		if p.UID == pod.UID {
+			continue
+		}
+		if nodeInfo, ok := nodeInfoMap[p.Status.NominatedNodeUID]; ok {
+			nodeInfo.Pods = append(nodeInfo.Pods, p)
+		}
+	}
+
+	return nodeInfos, nil
+}
+
+// scheduleWithPlugins filters nodes, keeps the ones with the highest score,
+// and lets the scheduler implementation choose one of them.
+// The nominated node is tried first if the pod has preempted others.
+func (sr *ScheduleRuntime) scheduleWithPlugins(pod *object.Pod,
+	filters []plugins.FilterPlugin, scores []plugins.ScorePlugin) (types.ScheduleInfo, error) {
+	nodeInfos, err := sr.getNodeInfos(pod)
+	if err != nil {
+		log.Println("[Error]: when scheduling, get nodes error:", err.Error())
+		return types.ScheduleInfo{NodeUUID: ""}, err
+	}
+
+	if pod.Status != nil && pod.Status.NominatedNodeUID != "" {
+		for _, nodeInfo := range nodeInfos {
+			if nodeInfo.NodeUUID != pod.Status.NominatedNodeUID {
+				continue
+			}
+			if feasible, _ := plugins.RunFilterPlugins(filters, pod, []*types.NodeInfo{nodeInfo}); len(feasible) == 1 {
+				return types.ScheduleInfo{NodeUUID: nodeInfo.NodeUUID}, nil
+			}
+		}
+	}
+
+	feasible, failures := plugins.RunFilterPlugins(filters, pod, nodeInfos)
+	if len(feasible) == 0 {
+		return types.ScheduleInfo{NodeUUID: ""}, &core.FitError{
+			NumAllNodes: len(nodeInfos),
+			Failures:    failures,
+		}
+	}
+
+	candidates := plugins.SelectHighestScore(scores, pod, feasible)
+	return sr.Implement.ScheduleFrom(candidates)
+}
+
+// scheduleWorkload is used by GpuJob and Actor, which have no tolerations,
+// so cordoned nodes and nodes with any NoSchedule / NoExecute taint are excluded
+func (sr *ScheduleRuntime) scheduleWorkload() (types.ScheduleInfo, error) {
+	return sr.scheduleWithPlugins(&object.Pod{}, plugins.WorkloadFilterPlugins(), plugins.DefaultScorePlugins())
+}
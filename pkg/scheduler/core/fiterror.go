@@ -0,0 +1,29 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FitError describes why a pod cannot fit on any node
+type FitError struct {
+	NumAllNodes int
+	// node UID -> reason
+	Failures map[string]string
+}
+
+func (f *FitError) Error() string {
+	reasons := make(map[string]int)
+	for _, reason := range f.Failures {
+		reasons[reason] += 1
+	}
+
+	strs := make([]string, 0, len(reasons))
+	for reason, cnt := range reasons {
+		strs = append(strs, fmt.Sprintf("%d %s", cnt, reason))
+	}
+	sort.Strings(strs)
+
+	return fmt.Sprintf("0/%d nodes are available: %s", f.NumAllNodes, strings.Join(strs, ", "))
+}
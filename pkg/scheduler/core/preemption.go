@@ -0,0 +1,141 @@
+package core
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/types"
+	"sort"
+)
+
+// Victims are the pods to preempt on a node, so that the preemptor fits
+type Victims struct {
+	NodeUID string
+	Pods    []object.Pod
+}
+
+func fits(filters []plugins.FilterPlugin, pod *object.Pod, nodeInfo *types.NodeInfo) bool {
+	for _, filter := range filters {
+		if filter.Filter(pod, nodeInfo) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectVictimsOnNode removes all pods bound to the node with lower priority,
+// checks if the preemptor fits, then adds back as many of them as possible,
+// starting from the most important ones. The rest are the victims.
+func SelectVictimsOnNode(filters []plugins.FilterPlugin, pod *object.Pod,
+	nodeInfo *types.NodeInfo) ([]object.Pod, bool) {
+	priority := object.GetPodPriority(pod)
+
+	potential := make([]object.Pod, 0)
+	remaining := make([]object.Pod, 0, len(nodeInfo.Pods))
+	for _, p := range nodeInfo.Pods {
+		// nominated pods are not running here, and cannot be preempted
+		if p.Status != nil && p.Status.NodeUID == nodeInfo.NodeUUID &&
+			object.GetPodPriority(&p) < priority {
+			potential = append(potential, p)
+		} else {
+			remaining = append(remaining, p)
+		}
+	}
+	if len(potential) == 0 {
+		return nil, false
+	}
+
+	info := &types.NodeInfo{
+		NodeUUID: nodeInfo.NodeUUID,
+		Node:     nodeInfo.Node,
+		Pods:     remaining,
+	}
+	if !fits(filters, pod, info) {
+		return nil, false
+	}
+
+	// higher priority first, older first when priorities are equal
+	sort.SliceStable(potential, func(i, j int) bool {
+		pi, pj := object.GetPodPriority(&potential[i]), object.GetPodPriority(&potential[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return potential[i].Status.StartTime.Before(potential[j].Status.StartTime)
+	})
+
+	victims := make([]object.Pod, 0)
+	for _, p := range potential {
+		info.Pods = append(info.Pods, p)
+		if !fits(filters, pod, info) {
+			info.Pods = info.Pods[:len(info.Pods)-1]
+			victims = append(victims, p)
+		}
+	}
+	return victims, true
+}
+
+func highestPriority(pods []object.Pod) int32 {
+	highest := int32(0)
+	for idx := range pods {
+		if p := object.GetPodPriority(&pods[idx]); idx == 0 || p > highest {
+			highest = p
+		}
+	}
+	return highest
+}
+
+func sumPriority(pods []object.Pod) int64 {
+	sum := int64(0)
+	for idx := range pods {
+		sum += int64(object.GetPodPriority(&pods[idx]))
+	}
+	return sum
+}
+
+// PickOneNodeForPreemption prefers the node whose most important victim
+// has the lowest priority, then the node with the fewest victims,
+// then the node with the lowest sum of victim priorities
+func PickOneNodeForPreemption(candidates []Victims) *Victims {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	best := 0
+	for idx := 1; idx < len(candidates); idx++ {
+		cur, old := candidates[idx].Pods, candidates[best].Pods
+		if h1, h2 := highestPriority(cur), highestPriority(old); h1 != h2 {
+			if h1 < h2 {
+				best = idx
+			}
+			continue
+		}
+		if len(cur) != len(old) {
+			if len(cur) < len(old) {
+				best = idx
+			}
+			continue
+		}
+		if sumPriority(cur) < sumPriority(old) {
+			best = idx
+		}
+	}
+	return &candidates[best]
+}
+
+// Preempt finds a node where the pod fits after preempting some lower priority pods,
+// nil is returned if the pod never preempts, or there is no such node
+func Preempt(filters []plugins.FilterPlugin, pod *object.Pod, nodeInfos []*types.NodeInfo) *Victims {
+	if pod.Spec.PreemptionPolicy == object.PreemptNever {
+		return nil
+	}
+
+	candidates := make([]Victims, 0)
+	for _, nodeInfo := range nodeInfos {
+		if pods, ok := SelectVictimsOnNode(filters, pod, nodeInfo); ok {
+			candidates = append(candidates, Victims{
+				NodeUID: nodeInfo.NodeUUID,
+				Pods:    pods,
+			})
+		}
+	}
+	return PickOneNodeForPreemption(candidates)
+}
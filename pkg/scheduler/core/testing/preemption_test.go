@@ -0,0 +1,88 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/core"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildPod(uid string, nodeUID string, priority int32, cpus float64) object.Pod {
+	return object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: uid},
+		Spec: object.PodSpec{
+			Priority: &priority,
+			Containers: []object.Container{
+				{Name: uid, Resources: &object.ResourceRequirements{Cpus: cpus}},
+			},
+		},
+		Status: &object.PodStatus{NodeUID: nodeUID},
+	}
+}
+
+func buildNodeInfo(uid string, cpus int, pods ...object.Pod) *types.NodeInfo {
+	node := &object.Node{
+		ObjectMeta: object.ObjectMeta{UID: uid},
+		Spec:       object.NodeSpec{Capacity: object.NodeCapacity{CPUCount: cpus}},
+		Status:     &object.NodeStatus{Condition: object.NodeCondition{Ready: true}},
+	}
+	return &types.NodeInfo{NodeUUID: uid, Node: node, Pods: pods}
+}
+
+func TestSelectVictimsOnNode(t *testing.T) {
+	filters := plugins.DefaultFilterPlugins()
+	preemptor := buildPod("preemptor", "", 100, 2)
+
+	nodeInfo := buildNodeInfo("node", 6,
+		buildPod("low", "node", 1, 2),
+		buildPod("mid", "node", 10, 2),
+		buildPod("high", "node", 1000, 2))
+	victims, ok := core.SelectVictimsOnNode(filters, &preemptor, nodeInfo)
+	assert.True(t, ok)
+	// mid is reprieved first, so only low is preempted
+	assert.Equal(t, 1, len(victims))
+	assert.Equal(t, "low", victims[0].UID)
+
+	// pods with higher priority are never preempted
+	nodeInfo = buildNodeInfo("node", 4, buildPod("high", "node", 1000, 4))
+	_, ok = core.SelectVictimsOnNode(filters, &preemptor, nodeInfo)
+	assert.False(t, ok)
+}
+
+func TestPreempt(t *testing.T) {
+	filters := plugins.DefaultFilterPlugins()
+	preemptor := buildPod("preemptor", "", 100, 2)
+
+	nodeInfos := []*types.NodeInfo{
+		buildNodeInfo("two-victims", 2, buildPod("a", "two-victims", 1, 1), buildPod("b", "two-victims", 1, 1)),
+		buildNodeInfo("important-victim", 2, buildPod("c", "important-victim", 50, 2)),
+		buildNodeInfo("one-victim", 2, buildPod("d", "one-victim", 1, 2)),
+	}
+	victims := core.Preempt(filters, &preemptor, nodeInfos)
+	assert.NotNil(t, victims)
+	assert.Equal(t, "one-victim", victims.NodeUID)
+
+	preemptor.Spec.PreemptionPolicy = object.PreemptNever
+	assert.Nil(t, core.Preempt(filters, &preemptor, nodeInfos))
+}
+
+func TestPreemptWithVolumeBinding(t *testing.T) {
+	preemptor := buildPod("preemptor", "", 100, 2)
+	nodeInfos := []*types.NodeInfo{
+		buildNodeInfo("one-victim", 2, buildPod("a", "one-victim", 1, 2)),
+		buildNodeInfo("volume-node", 2, buildPod("b", "volume-node", 1, 1), buildPod("c", "volume-node", 1, 1)),
+	}
+	nodeInfos[1].Node.Name = "volume-node"
+
+	// the local volume of the pod can only be used on volume-node
+	binding := &plugins.VolumeBinding{Affinities: []*object.VolumeNodeAffinity{{NodeNames: []string{"volume-node"}}}}
+	filters := append(plugins.DefaultFilterPlugins(), binding)
+	victims := core.Preempt(filters, &preemptor, nodeInfos)
+	assert.NotNil(t, victims)
+	assert.Equal(t, "volume-node", victims.NodeUID)
+
+	binding.Affinities[0].NodeNames = []string{"other-node"}
+	assert.Nil(t, core.Preempt(filters, &preemptor, nodeInfos))
+}
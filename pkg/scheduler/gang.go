@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/queue"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// gangReservation is a member of pod group which fits a node,
+// but is not bound until the group is satisfied
+type gangReservation struct {
+	info    *queue.QueuedPodInfo
+	cycle   int64
+	nodeUID string
+}
+
+type waitingGroup struct {
+	since    time.Time
+	timeout  time.Duration
+	reserved map[string]*gangReservation
+}
+
+// gangManager keeps reservations of pod groups waiting for enough members
+type gangManager struct {
+	lock sync.Mutex
+	// group name -> waiting group
+	groups map[string]*waitingGroup
+}
+
+func newGangManager() *gangManager {
+	return &gangManager{
+		groups: make(map[string]*waitingGroup),
+	}
+}
+
+// reservedPods returns copies of reserved pods, nominated to the node they reserved,
+// so that scheduler counts their resources
+func (g *gangManager) reservedPods() []object.Pod {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	pods := make([]object.Pod, 0)
+	for _, group := range g.groups {
+		for _, r := range group.reserved {
+			pod := *r.info.Pod
+			status := object.PodStatus{}
+			if pod.Status != nil {
+				status = *pod.Status
+			}
+			status.NodeUID = ""
+			status.NominatedNodeUID = r.nodeUID
+			pod.Status = &status
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// reserve adds the reservation to its group, and returns all reservations of the group
+// if there are enough members with the ones already bound
+func (g *gangManager) reserve(pg *object.PodGroup, r *gangReservation, bound int32) []*gangReservation {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	group, ok := g.groups[pg.Name]
+	if !ok {
+		group = &waitingGroup{
+			since:    time.Now(),
+			timeout:  pg.ScheduleTimeout(),
+			reserved: make(map[string]*gangReservation),
+		}
+		g.groups[pg.Name] = group
+	}
+	group.reserved[r.info.Pod.UID] = r
+
+	if int32(len(group.reserved))+bound < pg.Spec.MinMember {
+		return nil
+	}
+	delete(g.groups, pg.Name)
+	return reservationList(group)
+}
+
+// reject releases all reservations of the group
+func (g *gangManager) reject(groupName string) []*gangReservation {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	group, ok := g.groups[groupName]
+	if !ok {
+		return nil
+	}
+	delete(g.groups, groupName)
+	return reservationList(group)
+}
+
+// forget removes reservation of a deleted pod
+func (g *gangManager) forget(UID string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for name, group := range g.groups {
+		delete(group.reserved, UID)
+		if len(group.reserved) == 0 {
+			delete(g.groups, name)
+		}
+	}
+}
+
+// expired releases reservations of groups waiting longer than their timeout
+func (g *gangManager) expired() map[string][]*gangReservation {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	result := make(map[string][]*gangReservation)
+	for name, group := range g.groups {
+		if time.Since(group.since) > group.timeout {
+			delete(g.groups, name)
+			result[name] = reservationList(group)
+		}
+	}
+	return result
+}
+
+func reservationList(group *waitingGroup) []*gangReservation {
+	list := make([]*gangReservation, 0, len(group.reserved))
+	for _, r := range group.reserved {
+		list = append(list, r)
+	}
+	return list
+}
+
+// getPodGroup returns the group the pod belongs to, with the number of
+// its members not terminated, and the number of members already bound.
+// nil is returned if the pod doesn't belong to a group.
+func (sr *ScheduleRuntime) getPodGroup(pod *object.Pod) (*object.PodGroup, int32, int32, error) {
+	name := object.GetPodGroupName(pod)
+	if name == "" {
+		return nil, 0, 0, nil
+	}
+
+	podGroups, err := crudobj.GetPodGroups()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var pg *object.PodGroup
+	for idx := range podGroups {
+		if podGroups[idx].Name == name {
+			pg = &podGroups[idx]
+			break
+		}
+	}
+	if pg == nil {
+		return nil, 0, 0, fmt.Errorf("pod group %s not found", name)
+	}
+
+	pods, err := crudobj.GetPods()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	total, bound := int32(0), int32(0)
+	for idx := range pods {
+		if object.GetPodGroupName(&pods[idx]) != name || isPodTerminated(&pods[idx]) {
+			continue
+		}
+		total++
+		if !isPodPending(&pods[idx]) {
+			bound++
+		}
+	}
+	return pg, total, bound, nil
+}
+
+// releaseGang puts reserved members back to scheduling queue
+func (sr *ScheduleRuntime) releaseGang(reservations []*gangReservation, message string) {
+	for _, r := range reservations {
+		// the pod may be popped by scheduling loop once it is queued, so status is sent with a copy
+		pod := *r.info.Pod
+		status := *pod.Status
+		pod.Status = &status
+		sr.queue.AddUnschedulable(r.info, r.cycle)
+		sr.sendPodUnschedulable(&pod, message)
+	}
+}
+
+// checkGangTimeout releases groups which cannot be satisfied in time
+func (sr *ScheduleRuntime) checkGangTimeout() {
+	for {
+		time.Sleep(time.Second)
+		for name, reservations := range sr.gang.expired() {
+			log.Printf("[INFO]: pod group %s timed out, releasing %d reservations\n", name, len(reservations))
+			sr.releaseGang(reservations, fmt.Sprintf("pod group %s timed out waiting for enough members", name))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/scheduler/options"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// serveMetrics exposes queue depth and scheduling latency in json
+func (sr *ScheduleRuntime) serveMetrics() {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.Default()
+	router.GET("/metrics", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, sr.metrics.Snapshot(sr.queue.PendingPods()))
+	})
+
+	err := router.Run(":" + strconv.Itoa(options.MetricsPort))
+	if err != nil {
+		log.Println("[Error]: failure when serving scheduler metrics,", err)
+	}
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	ResultScheduled     = "scheduled"
+	ResultUnschedulable = "unschedulable"
+	ResultError         = "error"
+)
+
+// LatencySummary records count, sum and max of durations, in seconds
+type LatencySummary struct {
+	Count      int64   `json:"count"`
+	SumSeconds float64 `json:"sumSeconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+func (s *LatencySummary) observe(d time.Duration) {
+	sec := d.Seconds()
+	s.Count++
+	s.SumSeconds += sec
+	if sec > s.MaxSeconds {
+		s.MaxSeconds = sec
+	}
+}
+
+type Snapshot struct {
+	// PendingPods is the number of pods in each sub-queue
+	PendingPods map[string]int `json:"pendingPods"`
+	// ScheduleAttempts is the number of attempts by result
+	ScheduleAttempts map[string]int64 `json:"scheduleAttempts"`
+	// SchedulingLatency is the time of running scheduling algorithm (and preemption) once
+	SchedulingLatency map[string]*LatencySummary `json:"schedulingLatency"`
+	// E2ESchedulingLatency is the time from a pod first queued until it is bound
+	E2ESchedulingLatency LatencySummary `json:"e2eSchedulingLatency"`
+}
+
+type Recorder struct {
+	lock              sync.Mutex
+	scheduleAttempts  map[string]int64
+	schedulingLatency map[string]*LatencySummary
+	e2eLatency        LatencySummary
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		scheduleAttempts:  make(map[string]int64),
+		schedulingLatency: make(map[string]*LatencySummary),
+	}
+}
+
+// ObserveAttempt records an attempt with result, which took duration
+func (r *Recorder) ObserveAttempt(result string, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.scheduleAttempts[result]++
+	summary, ok := r.schedulingLatency[result]
+	if !ok {
+		summary = &LatencySummary{}
+		r.schedulingLatency[result] = summary
+	}
+	summary.observe(duration)
+}
+
+func (r *Recorder) ObserveE2E(duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.e2eLatency.observe(duration)
+}
+
+func (r *Recorder) Snapshot(pendingPods map[string]int) Snapshot {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	snapshot := Snapshot{
+		PendingPods:          pendingPods,
+		ScheduleAttempts:     make(map[string]int64),
+		SchedulingLatency:    make(map[string]*LatencySummary),
+		E2ESchedulingLatency: r.e2eLatency,
+	}
+	for result, cnt := range r.scheduleAttempts {
+		snapshot.ScheduleAttempts[result] = cnt
+	}
+	for result, summary := range r.schedulingLatency {
+		s := *summary
+		snapshot.SchedulingLatency[result] = &s
+	}
+	return snapshot
+}
@@ -0,0 +1,17 @@
+package options
+
+import "time"
+
+const (
+	// MetricsPort serves scheduling queue and latency metrics
+	MetricsPort = 6820
+
+	// PodInitialBackoffDuration is the backoff of a pod after its first failed attempt,
+	// it doubles after each failure until PodMaxBackoffDuration
+	PodInitialBackoffDuration = time.Second
+	PodMaxBackoffDuration     = time.Second * 10
+
+	// PodMaxInUnschedulableDuration is how long a pod may stay unschedulable
+	// without any cluster event before it is retried anyway
+	PodMaxInUnschedulableDuration = time.Minute
+)
@@ -0,0 +1,48 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"errors"
+	"strings"
+)
+
+var ErrNodePortsConflict = errors.New("node(s) didn't have free ports for the requested pod ports")
+
+// NodePorts filters out nodes where host ports of the pod are taken by other pods
+type NodePorts struct{}
+
+func (p *NodePorts) Name() string {
+	return "NodePorts"
+}
+
+func (p *NodePorts) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	wanted := object.GetPodHostPorts(pod)
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	for idx := range nodeInfo.Pods {
+		for _, used := range object.GetPodHostPorts(&nodeInfo.Pods[idx]) {
+			for _, port := range wanted {
+				if hostPortsConflict(&port, &used) {
+					return ErrNodePortsConflict
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hostPortsConflict tells whether two ports are bound to the same address,
+// an empty or unspecified host IP takes the port on all addresses
+func hostPortsConflict(a, b *object.ContainerPort) bool {
+	if a.HostPort != b.HostPort || !strings.EqualFold(a.Protocol, b.Protocol) {
+		return false
+	}
+	return isWildcardIP(a.HostIP) || isWildcardIP(b.HostIP) || a.HostIP == b.HostIP
+}
+
+func isWildcardIP(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
@@ -0,0 +1,32 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"errors"
+)
+
+var ErrNodeUnderMemoryPressure = errors.New("node(s) had memory pressure")
+var ErrNodeUnderDiskPressure = errors.New("node(s) had disk pressure")
+
+// NodePressure filters out nodes evicting pods: nodes under disk pressure take no pods,
+// nodes under memory pressure take no BestEffort pods
+type NodePressure struct{}
+
+func (p *NodePressure) Name() string {
+	return "NodePressure"
+}
+
+func (p *NodePressure) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if nodeInfo.Node == nil || nodeInfo.Node.Status == nil {
+		return nil
+	}
+	condition := nodeInfo.Node.Status.Condition
+	if condition.DiskPressure || condition.OutOfDisk {
+		return ErrNodeUnderDiskPressure
+	}
+	if condition.MemoryPressure && object.GetPodQOS(pod) == object.PodQOSBestEffort {
+		return ErrNodeUnderMemoryPressure
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"errors"
+)
+
+var ErrInsufficientCPU = errors.New("Insufficient cpu")
+var ErrInsufficientMemory = errors.New("Insufficient memory")
+var ErrTooManyPods = errors.New("Too many pods")
+
+// NodeResourcesFit checks if the sum of requests of pods on the node,
+// plus the pod to schedule, exceeds allocatable resources of the node.
+// Zero means not limited.
+type NodeResourcesFit struct{}
+
+func (p *NodeResourcesFit) Name() string {
+	return "NodeResourcesFit"
+}
+
+func (p *NodeResourcesFit) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if nodeInfo.Node == nil {
+		return nil
+	}
+	allocatableCpus, allocatableMemory, maxPods := object.GetNodeAllocatable(nodeInfo.Node)
+
+	if maxPods > 0 && len(nodeInfo.Pods)+1 > maxPods {
+		return ErrTooManyPods
+	}
+
+	cpus, memory := object.GetPodRequests(pod)
+	for idx := range nodeInfo.Pods {
+		c, m := object.GetPodRequests(&nodeInfo.Pods[idx])
+		cpus += c
+		memory += m
+	}
+
+	if allocatableCpus > 0 && cpus > allocatableCpus {
+		return ErrInsufficientCPU
+	}
+	if allocatableMemory > 0 && memory > allocatableMemory {
+		return ErrInsufficientMemory
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"errors"
+)
+
+var ErrNodeSelectorNotMatch = errors.New("node(s) didn't match pod's node selector")
+var ErrNodeUnschedulable = errors.New("node(s) were unschedulable")
+
+type NodeSelector struct{}
+
+func (p *NodeSelector) Name() string {
+	return "NodeSelector"
+}
+
+func (p *NodeSelector) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if len(pod.Spec.Selector) == 0 {
+		return nil
+	}
+	if nodeInfo.Node == nil || !object.MatchLabelSelector(pod.Spec.Selector, nodeInfo.Node.Labels) {
+		return ErrNodeSelectorNotMatch
+	}
+	return nil
+}
+
+// NodeUnschedulable filters out cordoned nodes,
+// unless the pod tolerates the unschedulable taint
+type NodeUnschedulable struct{}
+
+func (p *NodeUnschedulable) Name() string {
+	return "NodeUnschedulable"
+}
+
+func (p *NodeUnschedulable) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if nodeInfo.Node == nil || !nodeInfo.Node.Spec.Unschedulable {
+		return nil
+	}
+
+	taint := object.Taint{Key: object.TaintNodeUnschedulable, Effect: object.TaintEffectNoSchedule}
+	if object.TolerationsTolerateTaint(pod.Spec.Tolerations, &taint) {
+		return nil
+	}
+	return ErrNodeUnschedulable
+}
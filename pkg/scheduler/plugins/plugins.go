@@ -0,0 +1,92 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+)
+
+// FilterPlugin rules out nodes that cannot run the pod,
+// a non-nil error tells why the node doesn't fit
+type FilterPlugin interface {
+	Name() string
+	Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error
+}
+
+// ScorePlugin ranks feasible nodes, higher is better
+type ScorePlugin interface {
+	Name() string
+	Score(pod *object.Pod, nodeInfo *types.NodeInfo) int
+}
+
+// DefaultFilterPlugins is the filter chain used by scheduler
+func DefaultFilterPlugins() []FilterPlugin {
+	return []FilterPlugin{
+		&NodeUnschedulable{},
+		&NodePressure{},
+		&NodeSelector{},
+		&TaintToleration{},
+		&NodePorts{},
+		&NodeResourcesFit{},
+	}
+}
+
+// WorkloadFilterPlugins is the filter chain used for GpuJob and Actor,
+// they declare no resources or tolerations, so any NoSchedule / NoExecute taint keeps them off
+func WorkloadFilterPlugins() []FilterPlugin {
+	return []FilterPlugin{
+		&NodeUnschedulable{},
+		&TaintToleration{},
+	}
+}
+
+func DefaultScorePlugins() []ScorePlugin {
+	return []ScorePlugin{
+		&TaintToleration{},
+	}
+}
+
+// RunFilterPlugins returns UID of nodes passing all filters,
+// and the first failure reason of the others
+func RunFilterPlugins(filters []FilterPlugin, pod *object.Pod,
+	nodeInfos []*types.NodeInfo) ([]*types.NodeInfo, map[string]string) {
+	feasible := make([]*types.NodeInfo, 0, len(nodeInfos))
+	failures := make(map[string]string)
+
+	for _, nodeInfo := range nodeInfos {
+		fit := true
+		for _, filter := range filters {
+			if err := filter.Filter(pod, nodeInfo); err != nil {
+				failures[nodeInfo.NodeUUID] = filter.Name() + ": " + err.Error()
+				fit = false
+				break
+			}
+		}
+		if fit {
+			feasible = append(feasible, nodeInfo)
+		}
+	}
+
+	return feasible, failures
+}
+
+// SelectHighestScore returns UID of all nodes sharing the highest total score
+func SelectHighestScore(scores []ScorePlugin, pod *object.Pod, nodeInfos []*types.NodeInfo) []string {
+	best := make([]string, 0)
+	bestScore := 0
+
+	for idx, nodeInfo := range nodeInfos {
+		score := 0
+		for _, plugin := range scores {
+			score += plugin.Score(pod, nodeInfo)
+		}
+
+		if idx == 0 || score > bestScore {
+			bestScore = score
+			best = []string{nodeInfo.NodeUUID}
+		} else if score == bestScore {
+			best = append(best, nodeInfo.NodeUUID)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,47 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"fmt"
+)
+
+// TaintToleration filters out nodes with NoSchedule / NoExecute taints
+// the pod doesn't tolerate, and prefers nodes with less untolerated
+// PreferNoSchedule taints
+type TaintToleration struct{}
+
+func (p *TaintToleration) Name() string {
+	return "TaintToleration"
+}
+
+func (p *TaintToleration) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if nodeInfo.Node == nil {
+		return nil
+	}
+
+	taint, found := object.FindMatchingUntoleratedTaint(nodeInfo.Node.Spec.Taints, pod.Spec.Tolerations,
+		func(t *object.Taint) bool {
+			return t.Effect == object.TaintEffectNoSchedule || t.Effect == object.TaintEffectNoExecute
+		})
+	if found {
+		return fmt.Errorf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value)
+	}
+	return nil
+}
+
+func (p *TaintToleration) Score(pod *object.Pod, nodeInfo *types.NodeInfo) int {
+	if nodeInfo.Node == nil {
+		return 0
+	}
+
+	score := 0
+	for idx := range nodeInfo.Node.Spec.Taints {
+		taint := &nodeInfo.Node.Spec.Taints[idx]
+		if taint.Effect == object.TaintEffectPreferNoSchedule &&
+			!object.TolerationsTolerateTaint(pod.Spec.Tolerations, taint) {
+			score -= 1
+		}
+	}
+	return score
+}
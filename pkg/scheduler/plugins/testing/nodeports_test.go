@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/plugins"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildPortPod(hostNetwork bool, ports ...object.ContainerPort) object.Pod {
+	return object.Pod{
+		Spec: object.PodSpec{
+			Containers:  []object.Container{{Name: "c", Ports: ports}},
+			HostNetwork: hostNetwork,
+		},
+	}
+}
+
+func TestNodePorts(t *testing.T) {
+	filter := &plugins.NodePorts{}
+	node := buildNodeInfo("node", false)
+	node.Pods = []object.Pod{
+		buildPortPod(false, object.ContainerPort{ContainerPort: 80, HostPort: 8080, HostIP: "10.0.0.1"}),
+		buildPortPod(true, object.ContainerPort{ContainerPort: 53, Protocol: "UDP"}),
+	}
+
+	pod := buildPortPod(false, object.ContainerPort{ContainerPort: 80})
+	assert.NoError(t, filter.Filter(&pod, node))
+
+	pod = buildPortPod(false, object.ContainerPort{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"})
+	assert.ErrorIs(t, filter.Filter(&pod, node), plugins.ErrNodePortsConflict)
+
+	pod = buildPortPod(false, object.ContainerPort{ContainerPort: 80, HostPort: 8080, HostIP: "10.0.0.2"})
+	assert.NoError(t, filter.Filter(&pod, node))
+
+	pod = buildPortPod(false, object.ContainerPort{ContainerPort: 80, HostPort: 8080, Protocol: "UDP"})
+	assert.NoError(t, filter.Filter(&pod, node))
+
+	// ports of hostNetwork pods are taken on the node
+	pod = buildPortPod(false, object.ContainerPort{ContainerPort: 5353, HostPort: 53, Protocol: "UDP"})
+	assert.ErrorIs(t, filter.Filter(&pod, node), plugins.ErrNodePortsConflict)
+	pod = buildPortPod(true, object.ContainerPort{ContainerPort: 8080})
+	assert.ErrorIs(t, filter.Filter(&pod, node), plugins.ErrNodePortsConflict)
+}
@@ -0,0 +1,78 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildNodeInfo(uid string, unschedulable bool, taints ...object.Taint) *types.NodeInfo {
+	node := &object.Node{
+		ObjectMeta: object.ObjectMeta{UID: uid},
+		Spec: object.NodeSpec{
+			Unschedulable: unschedulable,
+			Taints:        taints,
+		},
+		Status: &object.NodeStatus{Condition: object.NodeCondition{Ready: true}},
+	}
+	return &types.NodeInfo{NodeUUID: uid, Node: node}
+}
+
+func TestTaintToleration(t *testing.T) {
+	master := object.Taint{Key: object.TaintNodeMaster, Effect: object.TaintEffectNoSchedule}
+	prefer := object.Taint{Key: "disk", Value: "hdd", Effect: object.TaintEffectPreferNoSchedule}
+	nodes := []*types.NodeInfo{
+		buildNodeInfo("master", false, master),
+		buildNodeInfo("hdd", false, prefer),
+		buildNodeInfo("ssd", false),
+		buildNodeInfo("cordoned", true),
+	}
+
+	pod := &object.Pod{}
+	feasible, failures := plugins.RunFilterPlugins(plugins.DefaultFilterPlugins(), pod, nodes)
+	assert.Equal(t, 2, len(feasible))
+	assert.Contains(t, failures, "master")
+	assert.Contains(t, failures, "cordoned")
+
+	best := plugins.SelectHighestScore(plugins.DefaultScorePlugins(), pod, feasible)
+	assert.Equal(t, []string{"ssd"}, best)
+
+	pod.Spec.Tolerations = []object.Toleration{
+		{Key: object.TaintNodeMaster, Operator: object.TolerationOpExists},
+		{Key: "disk", Value: "hdd", Effect: object.TaintEffectPreferNoSchedule},
+	}
+	feasible, _ = plugins.RunFilterPlugins(plugins.DefaultFilterPlugins(), pod, nodes)
+	assert.Equal(t, 3, len(feasible))
+	best = plugins.SelectHighestScore(plugins.DefaultScorePlugins(), pod, feasible)
+	assert.Equal(t, 3, len(best))
+}
+
+func TestWorkloadFilters(t *testing.T) {
+	master := object.Taint{Key: object.TaintNodeMaster, Effect: object.TaintEffectNoSchedule}
+	notReady := object.Taint{Key: object.TaintNodeNotReady, Effect: object.TaintEffectNoExecute}
+	nodes := []*types.NodeInfo{
+		buildNodeInfo("master", false, master),
+		buildNodeInfo("notReady", false, notReady),
+		buildNodeInfo("cordoned", true),
+		buildNodeInfo("worker", false),
+	}
+
+	feasible, failures := plugins.RunFilterPlugins(plugins.WorkloadFilterPlugins(), &object.Pod{}, nodes)
+	assert.Equal(t, 1, len(feasible))
+	assert.Equal(t, "worker", feasible[0].NodeUUID)
+	assert.Contains(t, failures, "master")
+	assert.Contains(t, failures, "notReady")
+	assert.Contains(t, failures, "cordoned")
+}
+
+func TestToleratesTaint(t *testing.T) {
+	taint := object.Taint{Key: "k", Value: "v", Effect: object.TaintEffectNoExecute}
+
+	assert.True(t, (&object.Toleration{Key: "k", Value: "v"}).ToleratesTaint(&taint))
+	assert.True(t, (&object.Toleration{Operator: object.TolerationOpExists}).ToleratesTaint(&taint))
+	assert.False(t, (&object.Toleration{Key: "k", Value: "x"}).ToleratesTaint(&taint))
+	assert.False(t, (&object.Toleration{Key: "k", Operator: object.TolerationOpExists,
+		Effect: object.TaintEffectNoSchedule}).ToleratesTaint(&taint))
+}
@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/types"
+	"errors"
+	"fmt"
+)
+
+var ErrVolumeNodeConflict = errors.New("node(s) had volume node affinity conflict")
+
+// VolumeBinding filters out nodes where persistent volumes of the pod can't be used,
+// it is built for each pod from the volumes bound to its claims
+type VolumeBinding struct {
+	Affinities []*object.VolumeNodeAffinity
+}
+
+func (p *VolumeBinding) Name() string {
+	return "VolumeBinding"
+}
+
+func (p *VolumeBinding) Filter(pod *object.Pod, nodeInfo *types.NodeInfo) error {
+	if len(p.Affinities) == 0 {
+		return nil
+	}
+	if nodeInfo.Node == nil {
+		return ErrVolumeNodeConflict
+	}
+	for _, affinity := range p.Affinities {
+		if !object.MatchVolumeNodeAffinity(affinity, nodeInfo.Node) {
+			return ErrVolumeNodeConflict
+		}
+	}
+	return nil
+}
+
+// NewVolumeBinding returns the filter of nodes where volumes of the pod can be used, nil if the pod
+// has no claims. A message is returned if the pod can't be scheduled until its claims are bound
+func NewVolumeBinding(pod *object.Pod, pvcs []object.PersistentVolumeClaim,
+	pvs []object.PersistentVolume, classes []object.StorageClass) (*VolumeBinding, string) {
+	names := object.GetPodClaimNames(pod)
+	if len(names) == 0 {
+		return nil, ""
+	}
+
+	binding := &VolumeBinding{}
+	for _, name := range names {
+		var pvc *object.PersistentVolumeClaim
+		for idx := range pvcs {
+			if pvcs[idx].Name == name {
+				pvc = &pvcs[idx]
+				break
+			}
+		}
+		if pvc == nil {
+			return nil, fmt.Sprintf("persistentvolumeclaim %s not found", name)
+		}
+
+		if pvc.Spec.VolumeName != "" && pvc.Status != nil && pvc.Status.Phase == object.ClaimBound {
+			var pv *object.PersistentVolume
+			for idx := range pvs {
+				if pvs[idx].Name == pvc.Spec.VolumeName {
+					pv = &pvs[idx]
+					break
+				}
+			}
+			if pv == nil {
+				return nil, fmt.Sprintf("volume %s of claim %s not found", pvc.Spec.VolumeName, name)
+			}
+			binding.Affinities = append(binding.Affinities, pv.Spec.NodeAffinity)
+			continue
+		}
+
+		var class *object.StorageClass
+		for idx := range classes {
+			if classes[idx].Name == pvc.Spec.StorageClassName {
+				class = &classes[idx]
+				break
+			}
+		}
+		if class == nil || object.GetVolumeBindingMode(class) != object.VolumeBindingWaitForFirstConsumer {
+			return nil, fmt.Sprintf("pod has unbound immediate persistentvolumeclaim %s", name)
+		}
+		// the volume is being provisioned on the node chosen for another pod
+		if node := pvc.Annotations[object.AnnSelectedNode]; node != "" {
+			binding.Affinities = append(binding.Affinities, &object.VolumeNodeAffinity{NodeNames: []string{node}})
+		}
+	}
+	return binding, ""
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/core"
+	"Cubernetes/pkg/scheduler/plugins"
+	"log"
+)
+
+// preempt tries to make room for the pod by deleting lower priority pods on one node,
+// the cubelet on that node stops the victims gracefully when they are deleted.
+// The pod is nominated to the node, and will be scheduled there when the watch event comes back.
+// filters are the ones the pod is scheduled with, including its volume binding
+func (sr *ScheduleRuntime) preempt(pod *object.Pod, filters []plugins.FilterPlugin) bool {
+	nodeInfos, err := sr.getNodeInfos(pod)
+	if err != nil {
+		log.Println("[Error]: when preempting, get nodes error:", err.Error())
+		return false
+	}
+
+	victims := core.Preempt(filters, pod, nodeInfos)
+	if victims == nil {
+		return false
+	}
+
+	for _, victim := range victims.Pods {
+		log.Printf("[INFO]: preempting pod %s on node %s for pod %s\n", victim.UID, victims.NodeUID, pod.UID)
+		if err = crudobj.DeletePod(victim.UID); err != nil {
+			log.Printf("[Error]: fail to preempt pod %s: %v\n", victim.UID, err)
+			return false
+		}
+	}
+
+	pod.Status.NominatedNodeUID = victims.NodeUID
+	pod.Status.Reason = ""
+	pod.Status.Message = ""
+	if _, err = crudobj.UpdatePod(*pod); err != nil {
+		log.Printf("[Error]: fail to nominate pod %s to node %s: %v\n", pod.UID, victims.NodeUID, err)
+		return false
+	}
+	log.Printf("[INFO]: pod %s nominated to node %s\n", pod.UID, victims.NodeUID)
+	return true
+}
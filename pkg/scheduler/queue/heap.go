@@ -0,0 +1,81 @@
+package queue
+
+import "container/heap"
+
+type lessFunc func(a, b *QueuedPodInfo) bool
+
+// podHeap is a heap of pods which can also be looked up by pod UID
+type podHeap struct {
+	items []*QueuedPodInfo
+	index map[string]int
+	less  lessFunc
+}
+
+func newPodHeap(less lessFunc) *podHeap {
+	return &podHeap{
+		items: make([]*QueuedPodInfo, 0),
+		index: make(map[string]int),
+		less:  less,
+	}
+}
+
+func (h *podHeap) Len() int { return len(h.items) }
+
+func (h *podHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+
+func (h *podHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].Pod.UID] = i
+	h.index[h.items[j].Pod.UID] = j
+}
+
+func (h *podHeap) Push(x interface{}) {
+	info := x.(*QueuedPodInfo)
+	h.index[info.Pod.UID] = len(h.items)
+	h.items = append(h.items, info)
+}
+
+func (h *podHeap) Pop() interface{} {
+	n := len(h.items)
+	info := h.items[n-1]
+	h.items = h.items[:n-1]
+	delete(h.index, info.Pod.UID)
+	return info
+}
+
+// AddOrUpdate puts the pod into heap, replacing the old one with the same UID
+func (h *podHeap) AddOrUpdate(info *QueuedPodInfo) {
+	if idx, ok := h.index[info.Pod.UID]; ok {
+		h.items[idx] = info
+		heap.Fix(h, idx)
+		return
+	}
+	heap.Push(h, info)
+}
+
+func (h *podHeap) Get(UID string) (*QueuedPodInfo, bool) {
+	if idx, ok := h.index[UID]; ok {
+		return h.items[idx], true
+	}
+	return nil, false
+}
+
+func (h *podHeap) Delete(UID string) {
+	if idx, ok := h.index[UID]; ok {
+		heap.Remove(h, idx)
+	}
+}
+
+func (h *podHeap) Peek() *QueuedPodInfo {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.items[0]
+}
+
+func (h *podHeap) PopPod() *QueuedPodInfo {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return heap.Pop(h).(*QueuedPodInfo)
+}
@@ -0,0 +1,326 @@
+package queue
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/options"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SchedulingQueue holds pods waiting to be scheduled.
+// Pods to try next are in the active queue, ordered by priority.
+// Pods failed recently wait in the backoff queue until their backoff expires.
+// Pods that no node fits wait in the unschedulable pool until a cluster event
+// may make them schedulable, or until they have waited for too long.
+type SchedulingQueue interface {
+	// Add puts a new pending pod into active queue, or updates the queued one.
+	// Unschedulable members of the pod group of a new pod are moved to retry.
+	Add(pod *object.Pod)
+	// Delete removes the pod from all sub-queues
+	Delete(UID string)
+	// Pop blocks until there is a pod in active queue, nil is returned if the queue is closed
+	Pop() *QueuedPodInfo
+	// SchedulingCycle returns the number of pods popped so far
+	SchedulingCycle() int64
+	// AddUnschedulable puts a popped pod back after a failed attempt.
+	// If a cluster event happened after the pod was popped, it goes to backoff queue directly.
+	AddUnschedulable(info *QueuedPodInfo, podSchedulingCycle int64)
+	// AddBackoff puts a popped pod back into backoff queue, e.g. when apiserver is not reachable
+	AddBackoff(info *QueuedPodInfo)
+	// MoveAllToActiveOrBackoff is called on cluster events,
+	// unschedulable pods are retried when their backoff expires
+	MoveAllToActiveOrBackoff(event ClusterEvent)
+	// PendingPods returns number of pods in each sub-queue
+	PendingPods() map[string]int
+	// Run flushes backoff queue and unschedulable pool periodically until Close
+	Run()
+	Close()
+}
+
+func NewSchedulingQueue() SchedulingQueue {
+	q := &priorityQueue{
+		podInitialBackoff:     options.PodInitialBackoffDuration,
+		podMaxBackoff:         options.PodMaxBackoffDuration,
+		podMaxInUnschedulable: options.PodMaxInUnschedulableDuration,
+		unschedulable:         make(map[string]*QueuedPodInfo),
+		stop:                  make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.lock)
+	q.active = newPodHeap(activeLess)
+	q.backoff = newPodHeap(func(a, b *QueuedPodInfo) bool {
+		return q.backoffExpiry(a).Before(q.backoffExpiry(b))
+	})
+	return q
+}
+
+// activeLess puts pods with higher priority first, then pods added earlier
+func activeLess(a, b *QueuedPodInfo) bool {
+	pa, pb := object.GetPodPriority(a.Pod), object.GetPodPriority(b.Pod)
+	if pa != pb {
+		return pa > pb
+	}
+	return a.Timestamp.Before(b.Timestamp)
+}
+
+type priorityQueue struct {
+	podInitialBackoff     time.Duration
+	podMaxBackoff         time.Duration
+	podMaxInUnschedulable time.Duration
+
+	lock sync.Mutex
+	cond *sync.Cond
+
+	active        *podHeap
+	backoff       *podHeap
+	unschedulable map[string]*QueuedPodInfo
+
+	schedulingCycle  int64
+	moveRequestCycle int64
+
+	closed bool
+	stop   chan struct{}
+}
+
+func (q *priorityQueue) backoffDuration(info *QueuedPodInfo) time.Duration {
+	duration := q.podInitialBackoff
+	for i := 1; i < info.Attempts; i++ {
+		duration *= 2
+		if duration > q.podMaxBackoff {
+			return q.podMaxBackoff
+		}
+	}
+	return duration
+}
+
+func (q *priorityQueue) backoffExpiry(info *QueuedPodInfo) time.Time {
+	return info.Timestamp.Add(q.backoffDuration(info))
+}
+
+func (q *priorityQueue) isBackingOff(info *QueuedPodInfo) bool {
+	return info.Attempts > 0 && time.Now().Before(q.backoffExpiry(info))
+}
+
+// isPodUpdated ignores status change, which is mostly written by scheduler itself
+func isPodUpdated(old *object.Pod, new *object.Pod) bool {
+	return !reflect.DeepEqual(old.ObjectMeta, new.ObjectMeta) || !reflect.DeepEqual(old.Spec, new.Spec)
+}
+
+func (q *priorityQueue) Add(pod *object.Pod) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if info, ok := q.active.Get(pod.UID); ok {
+		info.Pod = pod
+		q.active.AddOrUpdate(info)
+		return
+	}
+	if info, ok := q.backoff.Get(pod.UID); ok {
+		info.Pod = pod
+		q.backoff.AddOrUpdate(info)
+		return
+	}
+	if info, ok := q.unschedulable[pod.UID]; ok {
+		updated := isPodUpdated(info.Pod, pod)
+		info.Pod = pod
+		if !updated {
+			return
+		}
+		// the pod may fit now
+		delete(q.unschedulable, pod.UID)
+		if q.isBackingOff(info) {
+			q.backoff.AddOrUpdate(info)
+		} else {
+			q.active.AddOrUpdate(info)
+			q.cond.Broadcast()
+		}
+		return
+	}
+
+	now := time.Now()
+	q.active.AddOrUpdate(&QueuedPodInfo{
+		Pod:                     pod,
+		Timestamp:               now,
+		InitialAttemptTimestamp: now,
+	})
+	q.cond.Broadcast()
+
+	// members of the pod group are parked until the group has enough members,
+	// a new member may complete it
+	if group := object.GetPodGroupName(pod); group != "" {
+		q.moveAllLocked(PodGroupMemberAdd, func(info *QueuedPodInfo) bool {
+			return object.GetPodGroupName(info.Pod) == group
+		})
+		q.moveRequestCycle = q.schedulingCycle
+	}
+}
+
+func (q *priorityQueue) Delete(UID string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.active.Delete(UID)
+	q.backoff.Delete(UID)
+	delete(q.unschedulable, UID)
+}
+
+func (q *priorityQueue) Pop() *QueuedPodInfo {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.active.Len() == 0 {
+		if q.closed {
+			return nil
+		}
+		q.cond.Wait()
+	}
+	q.schedulingCycle++
+	return q.active.PopPod()
+}
+
+func (q *priorityQueue) SchedulingCycle() int64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.schedulingCycle
+}
+
+func (q *priorityQueue) AddUnschedulable(info *QueuedPodInfo, podSchedulingCycle int64) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.contains(info.Pod.UID) {
+		return
+	}
+
+	info.Attempts++
+	info.Timestamp = time.Now()
+	if q.moveRequestCycle >= podSchedulingCycle {
+		q.backoff.AddOrUpdate(info)
+	} else {
+		q.unschedulable[info.Pod.UID] = info
+	}
+}
+
+func (q *priorityQueue) AddBackoff(info *QueuedPodInfo) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.contains(info.Pod.UID) {
+		return
+	}
+
+	info.Attempts++
+	info.Timestamp = time.Now()
+	q.backoff.AddOrUpdate(info)
+}
+
+func (q *priorityQueue) contains(UID string) bool {
+	if _, ok := q.active.Get(UID); ok {
+		return true
+	}
+	if _, ok := q.backoff.Get(UID); ok {
+		return true
+	}
+	_, ok := q.unschedulable[UID]
+	return ok
+}
+
+func (q *priorityQueue) MoveAllToActiveOrBackoff(event ClusterEvent) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.moveAllLocked(event, func(*QueuedPodInfo) bool { return true })
+	q.moveRequestCycle = q.schedulingCycle
+}
+
+func (q *priorityQueue) moveAllLocked(event ClusterEvent, match func(*QueuedPodInfo) bool) {
+	moved := 0
+	for UID, info := range q.unschedulable {
+		if !match(info) {
+			continue
+		}
+		delete(q.unschedulable, UID)
+		if q.isBackingOff(info) {
+			q.backoff.AddOrUpdate(info)
+		} else {
+			q.active.AddOrUpdate(info)
+		}
+		moved++
+	}
+
+	if moved > 0 {
+		log.Printf("[INFO]: %d unschedulable pods moved to retry on event %s\n", moved, event)
+		q.cond.Broadcast()
+	}
+}
+
+func (q *priorityQueue) flushBackoffCompleted() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	moved := false
+	for {
+		info := q.backoff.Peek()
+		if info == nil || q.isBackingOff(info) {
+			break
+		}
+		q.backoff.PopPod()
+		q.active.AddOrUpdate(info)
+		moved = true
+	}
+	if moved {
+		q.cond.Broadcast()
+	}
+}
+
+func (q *priorityQueue) flushUnschedulableLeftover() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := time.Now()
+	q.moveAllLocked(UnschedulableTimeout, func(info *QueuedPodInfo) bool {
+		return now.Sub(info.Timestamp) > q.podMaxInUnschedulable
+	})
+}
+
+func (q *priorityQueue) PendingPods() map[string]int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return map[string]int{
+		ActiveQ:        q.active.Len(),
+		BackoffQ:       q.backoff.Len(),
+		UnschedulableQ: len(q.unschedulable),
+	}
+}
+
+func (q *priorityQueue) Run() {
+	backoffTicker := time.NewTicker(time.Second)
+	defer backoffTicker.Stop()
+	unschedulableTicker := time.NewTicker(time.Second * 30)
+	defer unschedulableTicker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-backoffTicker.C:
+			q.flushBackoffCompleted()
+		case <-unschedulableTicker.C:
+			q.flushUnschedulableLeftover()
+		}
+	}
+}
+
+func (q *priorityQueue) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.stop)
+	q.cond.Broadcast()
+}
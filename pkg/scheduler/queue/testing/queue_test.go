@@ -0,0 +1,97 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/queue"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func buildPod(uid string, priority int32) *object.Pod {
+	return &object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: uid, Name: uid},
+		Spec:       object.PodSpec{Priority: &priority},
+	}
+}
+
+func TestPopByPriority(t *testing.T) {
+	q := queue.NewSchedulingQueue()
+	defer q.Close()
+
+	q.Add(buildPod("low", 1))
+	q.Add(buildPod("high", 100))
+	q.Add(buildPod("mid", 10))
+
+	assert.Equal(t, "high", q.Pop().Pod.UID)
+	assert.Equal(t, "mid", q.Pop().Pod.UID)
+	assert.Equal(t, "low", q.Pop().Pod.UID)
+}
+
+func TestUnschedulableRequeue(t *testing.T) {
+	q := queue.NewSchedulingQueue()
+	go q.Run()
+	defer q.Close()
+
+	q.Add(buildPod("pod", 0))
+	info := q.Pop()
+	q.AddUnschedulable(info, q.SchedulingCycle())
+	assert.Equal(t, 1, q.PendingPods()[queue.UnschedulableQ])
+
+	// status-only update keeps the pod unschedulable
+	updated := *info.Pod
+	updated.Status = &object.PodStatus{Reason: object.PodReasonUnschedulable}
+	q.Add(&updated)
+	assert.Equal(t, 1, q.PendingPods()[queue.UnschedulableQ])
+
+	// a node is added: the pod backs off, then becomes active again
+	q.MoveAllToActiveOrBackoff(queue.NodeAdd)
+	assert.Equal(t, 1, q.PendingPods()[queue.BackoffQ])
+
+	popped := make(chan *queue.QueuedPodInfo)
+	go func() { popped <- q.Pop() }()
+	select {
+	case info = <-popped:
+		assert.Equal(t, "pod", info.Pod.UID)
+		assert.Equal(t, 1, info.Attempts)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pod is not requeued after backoff")
+	}
+}
+
+func TestEventDuringScheduling(t *testing.T) {
+	q := queue.NewSchedulingQueue()
+	defer q.Close()
+
+	q.Add(buildPod("pod", 0))
+	info := q.Pop()
+	cycle := q.SchedulingCycle()
+	// the event may make the pod schedulable, so it should not wait in unschedulable pool
+	q.MoveAllToActiveOrBackoff(queue.AssignedPodDelete)
+	q.AddUnschedulable(info, cycle)
+	assert.Equal(t, 0, q.PendingPods()[queue.UnschedulableQ])
+	assert.Equal(t, 1, q.PendingPods()[queue.BackoffQ])
+}
+
+func TestPodGroupMemberAdd(t *testing.T) {
+	q := queue.NewSchedulingQueue()
+	defer q.Close()
+
+	member := buildPod("member-0", 0)
+	member.Labels = map[string]string{object.PodGroupLabel: "group"}
+	q.Add(member)
+	q.Add(buildPod("other", 0))
+	for i := 0; i < 2; i++ {
+		info := q.Pop()
+		q.AddUnschedulable(info, q.SchedulingCycle())
+	}
+	assert.Equal(t, 2, q.PendingPods()[queue.UnschedulableQ])
+
+	// the new member may complete the group, so only its sibling is moved to retry
+	sibling := buildPod("member-1", 0)
+	sibling.Labels = map[string]string{object.PodGroupLabel: "group"}
+	q.Add(sibling)
+	assert.Equal(t, 1, q.PendingPods()[queue.UnschedulableQ])
+	assert.Equal(t, 1, q.PendingPods()[queue.BackoffQ])
+	assert.Equal(t, 1, q.PendingPods()[queue.ActiveQ])
+}
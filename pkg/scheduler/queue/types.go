@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"Cubernetes/pkg/object"
+	"time"
+)
+
+// QueuedPodInfo is a pod waiting to be scheduled, with its scheduling history
+type QueuedPodInfo struct {
+	Pod *object.Pod
+	// Timestamp is when the pod was added to the queue (again)
+	Timestamp time.Time
+	// InitialAttemptTimestamp is when the pod was added to the queue for the first time,
+	// used to compute end-to-end scheduling latency
+	InitialAttemptTimestamp time.Time
+	// Attempts is the number of failed scheduling attempts
+	Attempts int
+}
+
+// ClusterEvent may make unschedulable pods schedulable
+type ClusterEvent string
+
+const (
+	NodeAdd              ClusterEvent = "NodeAdd"
+	NodeUpdate           ClusterEvent = "NodeUpdate"
+	AssignedPodDelete    ClusterEvent = "AssignedPodDelete"
+	AssignedPodComplete  ClusterEvent = "AssignedPodComplete"
+	PodGroupMemberAdd    ClusterEvent = "PodGroupMemberAdd"
+	PvcAddOrUpdate       ClusterEvent = "PvcAddOrUpdate"
+	PvAddOrUpdate        ClusterEvent = "PvAddOrUpdate"
+	UnschedulableTimeout ClusterEvent = "UnschedulableTimeout"
+)
+
+const (
+	ActiveQ        = "active"
+	BackoffQ       = "backoff"
+	UnschedulableQ = "unschedulable"
+)
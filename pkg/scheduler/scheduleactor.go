@@ -16,7 +16,7 @@ func (sr *ScheduleRuntime) ScheduleActor(Actor *object.Actor) {
 	}
 
 	if Actor.Status.Phase == object.ActorCreated && Actor.Status.NodeUID == "" {
-		ActorInfo, err := sr.Implement.Schedule()
+		ActorInfo, err := sr.scheduleWorkload()
 		if err != nil {
 			log.Println("[Error]: when scheduling, error:", err.Error())
 			return
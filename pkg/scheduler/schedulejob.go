@@ -12,7 +12,7 @@ import (
 func (sr *ScheduleRuntime) ScheduleJob(job *object.GpuJob) {
 	// only support job has checked files and never be scheduled
 	if job.Status.NodeUID == "" && job.Status.Phase == object.JobCreated {
-		podInfo, err := sr.Implement.Schedule()
+		podInfo, err := sr.scheduleWorkload()
 		if err != nil {
 			log.Println("[Error]: when scheduling, error:", err.Error())
 		}
@@ -4,57 +4,182 @@ import (
 	"Cubernetes/pkg/apiserver/crudobj"
 	"Cubernetes/pkg/apiserver/watchobj"
 	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/core"
+	"Cubernetes/pkg/scheduler/metrics"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/queue"
 	"Cubernetes/pkg/scheduler/types"
+	"fmt"
 	"log"
 	"time"
 )
 
-func (sr *ScheduleRuntime) SchedulePod(pod *object.Pod) {
-	if pod.Status == nil || pod.Status.NodeUID == "" {
-		if pod.Status == nil {
-			pod.Status = &object.PodStatus{
-				ActualResourceUsage: &object.ResourceUsage{},
-			}
+func isPodPending(pod *object.Pod) bool {
+	return pod.Status == nil || pod.Status.NodeUID == ""
+}
+
+func isPodTerminated(pod *object.Pod) bool {
+	return pod.Status != nil &&
+		(pod.Status.Phase == object.PodSucceeded || pod.Status.Phase == object.PodFailed)
+}
+
+// scheduleLoop pops pods from scheduling queue one by one until the queue is closed
+func (sr *ScheduleRuntime) scheduleLoop() {
+	for {
+		info := sr.queue.Pop()
+		if info == nil {
+			log.Println("[INFO]: scheduling queue closed")
+			return
 		}
+		sr.scheduleOne(info, sr.queue.SchedulingCycle())
+	}
+}
 
-		podInfo := types.ScheduleInfo{NodeUUID: ""}
-		var err error
-		// Patch: easiest advanced scheduler implementation
-		if len(pod.Spec.Selector) != 0 {
-			nodes, err := crudobj.GetNodes()
-			if err != nil {
-				log.Println("[Error]: when scheduling, get nodes error:", err.Error())
-				return
-			}
+func (sr *ScheduleRuntime) scheduleOne(info *queue.QueuedPodInfo, podSchedulingCycle int64) {
+	pod := info.Pod
+	if pod.Status == nil {
+		pod.Status = &object.PodStatus{
+			ActualResourceUsage: &object.ResourceUsage{},
+		}
+	}
 
-			for _, node := range nodes {
-				if object.MatchLabelSelector(pod.Spec.Selector, node.Labels) {
-					podInfo = types.ScheduleInfo{NodeUUID: node.UID}
-					break
-				}
-			}
-		} else {
-			podInfo, err = sr.Implement.Schedule()
-			if err != nil {
-				log.Println("[Error]: when scheduling, error:", err.Error())
-				return
-			}
+	start := time.Now()
+	pg, total, bound, err := sr.getPodGroup(pod)
+	if err != nil {
+		log.Println("[Error]: when getting pod group, error:", err.Error())
+		sr.metrics.ObserveAttempt(metrics.ResultError, time.Since(start))
+		sr.queue.AddBackoff(info)
+		return
+	}
+	if pg != nil && total < pg.Spec.MinMember {
+		sr.metrics.ObserveAttempt(metrics.ResultUnschedulable, time.Since(start))
+		sr.queue.AddUnschedulable(info, podSchedulingCycle)
+		sr.sendPodUnschedulable(pod, fmt.Sprintf("pod group %s has %d/%d members",
+			pg.Name, total, pg.Spec.MinMember))
+		return
+	}
+
+	volumeBinding, message, err := sr.getVolumeBinding(pod)
+	if err != nil {
+		log.Println("[Error]: when getting volumes of pod, error:", err.Error())
+		sr.metrics.ObserveAttempt(metrics.ResultError, time.Since(start))
+		sr.queue.AddBackoff(info)
+		return
+	}
+	if message != "" {
+		sr.metrics.ObserveAttempt(metrics.ResultUnschedulable, time.Since(start))
+		// claim and volume events move the pod to retry
+		sr.queue.AddUnschedulable(info, podSchedulingCycle)
+		sr.sendPodUnschedulable(pod, message)
+		return
+	}
+	filters := sr.filters
+	if volumeBinding != nil {
+		filters = append(append([]plugins.FilterPlugin{}, sr.filters...), volumeBinding)
+	}
+
+	podInfo, err := sr.scheduleWithPlugins(pod, filters, sr.scores)
+	if err != nil {
+		fitErr, ok := err.(*core.FitError)
+		if !ok {
+			log.Println("[Error]: when scheduling, error:", err.Error())
+			sr.metrics.ObserveAttempt(metrics.ResultError, time.Since(start))
+			sr.queue.AddBackoff(info)
+			return
 		}
 
-		if podInfo.NodeUUID == "" {
-			log.Println("[Warn]: No node to schedule this node")
+		log.Printf("[INFO]: pod %s is unschedulable: %v\n", pod.UID, fitErr.Error())
+		preempted := false
+		if pg != nil {
+			// one member doesn't fit, so the group gives up all reservations,
+			// and members never preempt others
+			sr.releaseGang(sr.gang.reject(pg.Name), fmt.Sprintf("pod group %s rejected: %s", pg.Name, fitErr.Error()))
+		} else {
+			preempted = sr.preempt(pod, filters)
 		}
+		sr.metrics.ObserveAttempt(metrics.ResultUnschedulable, time.Since(start))
+		// put back before updating status, so the status update event
+		// is taken as a status-only change, and the pod stays unschedulable.
+		// If victims are preempted, their delete events will move the pod to retry.
+		sr.queue.AddUnschedulable(info, podSchedulingCycle)
+		if !preempted {
+			sr.sendPodUnschedulable(pod, fitErr.Error())
+		}
+		return
+	}
 
-		err = sr.SendPodScheduleInfoBack(pod, &podInfo)
-		if err != nil {
-			log.Println("[Error]: when sending scheduler result,", err.Error())
+	if pg != nil && bound < pg.Spec.MinMember {
+		reservations := sr.gang.reserve(pg, &gangReservation{
+			info:    info,
+			cycle:   podSchedulingCycle,
+			nodeUID: podInfo.NodeUUID,
+		}, bound)
+		sr.metrics.ObserveAttempt(metrics.ResultScheduled, time.Since(start))
+		if reservations == nil {
+			log.Printf("[INFO]: pod %s reserved node %s, waiting for pod group %s\n",
+				pod.UID, podInfo.NodeUUID, pg.Name)
+			return
+		}
+		log.Printf("[INFO]: pod group %s satisfied, binding %d pods\n", pg.Name, len(reservations))
+		for _, r := range reservations {
+			sr.bind(r.info, r.nodeUID)
 		}
+		return
+	}
+
+	sr.metrics.ObserveAttempt(metrics.ResultScheduled, time.Since(start))
+	sr.bind(info, podInfo.NodeUUID)
+}
+
+func (sr *ScheduleRuntime) bind(info *queue.QueuedPodInfo, nodeUID string) {
+	if err := sr.selectNodeForClaims(info.Pod, nodeUID); err != nil {
+		log.Println("[Error]: when selecting node for claims,", err.Error())
+		sr.queue.AddBackoff(info)
+		return
+	}
+	err := sr.SendPodScheduleInfoBack(info.Pod, &types.ScheduleInfo{NodeUUID: nodeUID})
+	if err != nil {
+		log.Println("[Error]: when sending scheduler result,", err.Error())
+		sr.queue.AddBackoff(info)
+		return
+	}
+	sr.metrics.ObserveE2E(time.Since(info.InitialAttemptTimestamp))
+}
+
+// sendPodUnschedulable records why the pod is not scheduled,
+// nothing is sent if the reason is not changed
+func (sr *ScheduleRuntime) sendPodUnschedulable(pod *object.Pod, message string) {
+	if pod.Status.Reason == object.PodReasonUnschedulable && pod.Status.Message == message {
+		return
+	}
+
+	if pod.Status.Phase == "" {
+		pod.Status.Phase = object.PodCreated
+	}
+	pod.Status.Reason = object.PodReasonUnschedulable
+	pod.Status.Message = message
+	object.SetPodCondition(pod.Status, object.PodCondition{
+		Type:    object.PodScheduled,
+		Status:  object.ConditionFalse,
+		Reason:  object.PodReasonUnschedulable,
+		Message: message,
+	})
+	_, err := crudobj.UpdatePodStatus(pod.UID, *pod.Status)
+	if err != nil {
+		log.Println("[Error]: when sending unschedulable reason,", err.Error())
 	}
 }
 
 func (sr *ScheduleRuntime) SendPodScheduleInfoBack(podToSchedule *object.Pod, info *types.ScheduleInfo) error {
 	podToSchedule.Status.NodeUID = info.NodeUUID
 	podToSchedule.Status.Phase = object.PodBound
+	podToSchedule.Status.NominatedNodeUID = ""
+	podToSchedule.Status.Reason = ""
+	podToSchedule.Status.Message = ""
+	object.SetPodCondition(podToSchedule.Status, object.PodCondition{
+		Type:   object.PodScheduled,
+		Status: object.ConditionTrue,
+	})
 
 	_, err := crudobj.UpdatePod(*podToSchedule)
 	if err != nil {
@@ -73,14 +198,39 @@ func (sr *ScheduleRuntime) WatchPod() {
 	}
 }
 
+// onPodEvent keeps pending pods in scheduling queue,
+// and retries unschedulable pods when an assigned pod is gone
+func (sr *ScheduleRuntime) onPodEvent(pod *object.Pod, eType watchobj.EventType) {
+	switch eType {
+	case watchobj.EVENT_PUT:
+		if isPodPending(pod) {
+			sr.queue.Add(pod)
+			return
+		}
+		sr.queue.Delete(pod.UID)
+		if isPodTerminated(pod) {
+			sr.queue.MoveAllToActiveOrBackoff(queue.AssignedPodComplete)
+		}
+	case watchobj.EVENT_DELETE:
+		// only UID is known, the deleted pod may have been assigned
+		sr.queue.Delete(pod.UID)
+		sr.gang.forget(pod.UID)
+		sr.queue.MoveAllToActiveOrBackoff(queue.AssignedPodDelete)
+	default:
+		log.Panic("[Fatal]: Unsupported types in watching pod.")
+	}
+}
+
 func (sr *ScheduleRuntime) tryWatchPod() {
 	if allPods, err := crudobj.GetPods(); err != nil {
 		log.Printf("[INFO]: fail to get all pods from apiserver: %v\n", err)
 		log.Printf("[INFO]: will retry after %d seconds...\n", WatchRetryIntervalSec)
 		return
 	} else {
-		for _, pod := range allPods {
-			sr.SchedulePod(&pod)
+		for idx := range allPods {
+			if isPodPending(&allPods[idx]) {
+				sr.queue.Add(&allPods[idx])
+			}
 		}
 	}
 
@@ -98,14 +248,8 @@ func (sr *ScheduleRuntime) tryWatchPod() {
 				log.Printf("[INFO]: lost connection with APIServer, retry after %d seconds...\n", WatchRetryIntervalSec)
 				return
 			} else {
-				switch podEvent.EType {
-				case watchobj.EVENT_PUT:
-					sr.SchedulePod(&podEvent.Pod)
-				case watchobj.EVENT_DELETE:
-					log.Println("[Info]: Delete Pod, do nothing")
-				default:
-					log.Panic("[Fatal]: Unsupported types in watching pod.")
-				}
+				pod := podEvent.Pod
+				sr.onPodEvent(&pod, podEvent.EType)
 			}
 		default:
 			time.Sleep(time.Second)
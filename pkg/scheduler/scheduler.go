@@ -1,7 +1,11 @@
 package scheduler
 
 import (
+	"Cubernetes/pkg/object"
 	"Cubernetes/pkg/scheduler/RR"
+	"Cubernetes/pkg/scheduler/metrics"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/queue"
 	"Cubernetes/pkg/scheduler/types"
 	"log"
 	"sync"
@@ -12,6 +16,15 @@ const WatchRetryIntervalSec = 10
 
 type ScheduleRuntime struct {
 	Implement types.Scheduler
+
+	filters []plugins.FilterPlugin
+	scores  []plugins.ScorePlugin
+
+	queue   queue.SchedulingQueue
+	metrics *metrics.Recorder
+	gang    *gangManager
+	// nodes seen by node watcher, to tell what changed in node events
+	nodeCache map[string]object.Node
 }
 
 func NewScheduler() *ScheduleRuntime {
@@ -28,6 +41,12 @@ func NewScheduler() *ScheduleRuntime {
 
 	return &ScheduleRuntime{
 		Implement: &scheduler,
+		filters:   plugins.DefaultFilterPlugins(),
+		scores:    plugins.DefaultScorePlugins(),
+		queue:     queue.NewSchedulingQueue(),
+		metrics:   metrics.NewRecorder(),
+		gang:      newGangManager(),
+		nodeCache: make(map[string]object.Node),
 	}
 }
 
@@ -35,7 +54,27 @@ func (sr *ScheduleRuntime) Run() {
 	log.Println("[INFO]: Init Scheduler with current nodes, it may take 2 seconds...")
 
 	wg := sync.WaitGroup{}
-	wg.Add(4)
+	wg.Add(9)
+
+	go func() {
+		defer wg.Done()
+		sr.queue.Run()
+	}()
+
+	go func() {
+		defer wg.Done()
+		sr.scheduleLoop()
+	}()
+
+	go func() {
+		defer wg.Done()
+		sr.serveMetrics()
+	}()
+
+	go func() {
+		defer wg.Done()
+		sr.checkGangTimeout()
+	}()
 
 	go func() {
 		defer wg.Done()
@@ -50,6 +89,11 @@ func (sr *ScheduleRuntime) Run() {
 		sr.WatchPod()
 	}()
 
+	go func() {
+		defer wg.Done()
+		sr.WatchVolume()
+	}()
+
 	go func() {
 		defer wg.Done()
 		sr.WatchJob()
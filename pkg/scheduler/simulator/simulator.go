@@ -0,0 +1,259 @@
+package simulator
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/core"
+	"Cubernetes/pkg/scheduler/plugins"
+	"Cubernetes/pkg/scheduler/types"
+	"fmt"
+	"sort"
+)
+
+// Snapshot is the cluster state to simulate on,
+// it can be taken from apiserver or loaded from a file
+type Snapshot struct {
+	Nodes     []object.Node     `json:"nodes" yaml:"nodes"`
+	Pods      []object.Pod      `json:"pods" yaml:"pods"`
+	PodGroups []object.PodGroup `json:"podGroups,omitempty" yaml:"podGroups,omitempty"`
+	// claims, volumes and classes tell where persistent volumes of pods can be used
+	PersistentVolumeClaims []object.PersistentVolumeClaim `json:"persistentVolumeClaims,omitempty" yaml:"persistentVolumeClaims,omitempty"`
+	PersistentVolumes      []object.PersistentVolume      `json:"persistentVolumes,omitempty" yaml:"persistentVolumes,omitempty"`
+	StorageClasses         []object.StorageClass          `json:"storageClasses,omitempty" yaml:"storageClasses,omitempty"`
+}
+
+// Placement is the simulated result of one pod
+type Placement struct {
+	PodUID  string `json:"podUID" yaml:"podUID"`
+	PodName string `json:"podName" yaml:"podName"`
+	// empty if the pod is unschedulable
+	NodeUID string `json:"nodeUID,omitempty" yaml:"nodeUID,omitempty"`
+	// UID of pods preempted to make room for this pod
+	Victims []string `json:"victims,omitempty" yaml:"victims,omitempty"`
+	// node UID -> why the pod doesn't fit the node
+	Failures map[string]string `json:"failures,omitempty" yaml:"failures,omitempty"`
+	Message  string            `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type Simulator struct {
+	filters []plugins.FilterPlugin
+	scores  []plugins.ScorePlugin
+}
+
+// NewSimulator uses the same plugins as scheduler
+func NewSimulator() *Simulator {
+	return &Simulator{
+		filters: plugins.DefaultFilterPlugins(),
+		scores:  plugins.DefaultScorePlugins(),
+	}
+}
+
+func isPending(pod *object.Pod) bool {
+	return pod.Status == nil || pod.Status.NodeUID == ""
+}
+
+func isTerminated(pod *object.Pod) bool {
+	return pod.Status != nil &&
+		(pod.Status.Phase == object.PodSucceeded || pod.Status.Phase == object.PodFailed)
+}
+
+// AddNodes adds hypothetical nodes, which are taken as ready
+func (s *Snapshot) AddNodes(nodes ...object.Node) {
+	for _, node := range nodes {
+		if node.UID == "" {
+			node.UID = node.Name
+		}
+		if node.Status == nil {
+			node.Status = &object.NodeStatus{}
+		}
+		node.Status.Condition.Ready = true
+		s.Nodes = append(s.Nodes, node)
+	}
+}
+
+// RemoveNodes removes nodes by UID or name, pods on them become pending
+func (s *Snapshot) RemoveNodes(names ...string) {
+	removed := make(map[string]bool)
+	nodes := make([]object.Node, 0, len(s.Nodes))
+	for _, node := range s.Nodes {
+		match := false
+		for _, name := range names {
+			if node.UID == name || node.Name == name {
+				match = true
+			}
+		}
+		if match {
+			removed[node.UID] = true
+		} else {
+			nodes = append(nodes, node)
+		}
+	}
+	s.Nodes = nodes
+
+	for idx := range s.Pods {
+		if status := s.Pods[idx].Status; status != nil && removed[status.NodeUID] {
+			newStatus := *status
+			newStatus.NodeUID = ""
+			s.Pods[idx].Status = &newStatus
+		}
+	}
+}
+
+// UnbindAll makes all pods not terminated pending, to simulate scheduling from scratch
+func (s *Snapshot) UnbindAll() {
+	for idx := range s.Pods {
+		if status := s.Pods[idx].Status; status != nil && !isTerminated(&s.Pods[idx]) {
+			newStatus := *status
+			newStatus.NodeUID = ""
+			s.Pods[idx].Status = &newStatus
+		}
+	}
+}
+
+// Simulate places pending pods of the snapshot one by one in scheduling queue order,
+// pods placed are counted when placing the following ones. Nothing is bound.
+func (sim *Simulator) Simulate(snapshot *Snapshot) []Placement {
+	nodeInfos := make([]*types.NodeInfo, 0, len(snapshot.Nodes))
+	nodeInfoMap := make(map[string]*types.NodeInfo)
+	for idx := range snapshot.Nodes {
+		node := &snapshot.Nodes[idx]
+		if node.Status == nil || !node.Status.Condition.Ready {
+			continue
+		}
+		nodeInfo := &types.NodeInfo{NodeUUID: node.UID, Node: node, Pods: make([]object.Pod, 0)}
+		nodeInfos = append(nodeInfos, nodeInfo)
+		nodeInfoMap[node.UID] = nodeInfo
+	}
+
+	pending := make([]object.Pod, 0)
+	for _, pod := range snapshot.Pods {
+		if isTerminated(&pod) {
+			continue
+		}
+		if isPending(&pod) {
+			pending = append(pending, pod)
+		} else if nodeInfo, ok := nodeInfoMap[pod.Status.NodeUID]; ok {
+			nodeInfo.Pods = append(nodeInfo.Pods, pod)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return object.GetPodPriority(&pending[i]) > object.GetPodPriority(&pending[j])
+	})
+
+	placements := make([]Placement, 0, len(pending))
+	for idx := range pending {
+		pod := &pending[idx]
+		placement := sim.placeOne(snapshot, pod, nodeInfos, nodeInfoMap)
+		if placement.NodeUID != "" {
+			placed := *pod
+			status := object.PodStatus{}
+			if pod.Status != nil {
+				status = *pod.Status
+			}
+			status.NodeUID = placement.NodeUID
+			placed.Status = &status
+			nodeInfoMap[placement.NodeUID].Pods = append(nodeInfoMap[placement.NodeUID].Pods, placed)
+		}
+		placements = append(placements, placement)
+	}
+
+	sim.checkPodGroups(snapshot, placements)
+	return placements
+}
+
+// placeOne filters and preempts like the scheduler does for a single pod
+func (sim *Simulator) placeOne(snapshot *Snapshot, pod *object.Pod, nodeInfos []*types.NodeInfo,
+	nodeInfoMap map[string]*types.NodeInfo) Placement {
+	placement := Placement{PodUID: pod.UID, PodName: pod.Name}
+
+	volumeBinding, message := plugins.NewVolumeBinding(pod, snapshot.PersistentVolumeClaims,
+		snapshot.PersistentVolumes, snapshot.StorageClasses)
+	if message != "" {
+		placement.Message = message
+		return placement
+	}
+	filters := sim.filters
+	if volumeBinding != nil {
+		filters = append(append([]plugins.FilterPlugin{}, sim.filters...), volumeBinding)
+	}
+
+	feasible, failures := plugins.RunFilterPlugins(filters, pod, nodeInfos)
+	if len(failures) != 0 {
+		placement.Failures = failures
+	}
+	if len(feasible) != 0 {
+		// scheduler picks one of the best nodes in turn, the first one is taken here
+		candidates := plugins.SelectHighestScore(sim.scores, pod, feasible)
+		placement.NodeUID = candidates[0]
+		return placement
+	}
+
+	fitErr := &core.FitError{NumAllNodes: len(nodeInfos), Failures: failures}
+	placement.Message = fitErr.Error()
+
+	// members of pod groups never preempt others
+	if object.GetPodGroupName(pod) != "" {
+		return placement
+	}
+	victims := core.Preempt(filters, pod, nodeInfos)
+	if victims == nil {
+		return placement
+	}
+
+	nodeInfo := nodeInfoMap[victims.NodeUID]
+	preempted := make(map[string]bool)
+	for _, victim := range victims.Pods {
+		preempted[victim.UID] = true
+		placement.Victims = append(placement.Victims, victim.UID)
+	}
+	remaining := make([]object.Pod, 0, len(nodeInfo.Pods))
+	for _, p := range nodeInfo.Pods {
+		if !preempted[p.UID] {
+			remaining = append(remaining, p)
+		}
+	}
+	nodeInfo.Pods = remaining
+	placement.NodeUID = victims.NodeUID
+	return placement
+}
+
+// checkPodGroups takes back placements of pod groups without enough members placed,
+// since the scheduler binds none of them in that case
+func (sim *Simulator) checkPodGroups(snapshot *Snapshot, placements []Placement) {
+	if len(snapshot.PodGroups) == 0 {
+		return
+	}
+
+	groupOf := make(map[string]string)
+	bound := make(map[string]int32)
+	for idx := range snapshot.Pods {
+		pod := &snapshot.Pods[idx]
+		name := object.GetPodGroupName(pod)
+		if name == "" || isTerminated(pod) {
+			continue
+		}
+		groupOf[pod.UID] = name
+		if !isPending(pod) {
+			bound[name]++
+		}
+	}
+	for _, placement := range placements {
+		if name, ok := groupOf[placement.PodUID]; ok && placement.NodeUID != "" {
+			bound[name]++
+		}
+	}
+
+	for _, pg := range snapshot.PodGroups {
+		if bound[pg.Name] >= pg.Spec.MinMember {
+			continue
+		}
+		for idx := range placements {
+			if groupOf[placements[idx].PodUID] != pg.Name || placements[idx].NodeUID == "" {
+				continue
+			}
+			placements[idx].NodeUID = ""
+			placements[idx].Victims = nil
+			placements[idx].Message = fmt.Sprintf("pod group %s has %d/%d members placed",
+				pg.Name, bound[pg.Name], pg.Spec.MinMember)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package testing
+
+import (
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/simulator"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildNode(uid string, cpus int, labels map[string]string) object.Node {
+	return object.Node{
+		ObjectMeta: object.ObjectMeta{UID: uid, Name: uid, Labels: labels},
+		Spec:       object.NodeSpec{Capacity: object.NodeCapacity{CPUCount: cpus}},
+		Status:     &object.NodeStatus{Condition: object.NodeCondition{Ready: true}},
+	}
+}
+
+func buildPod(uid string, nodeUID string, cpus float64, selector map[string]string) object.Pod {
+	return object.Pod{
+		ObjectMeta: object.ObjectMeta{UID: uid, Name: uid},
+		Spec: object.PodSpec{
+			Selector: selector,
+			Containers: []object.Container{
+				{Name: uid, Resources: &object.ResourceRequirements{Cpus: cpus}},
+			},
+		},
+		Status: &object.PodStatus{NodeUID: nodeUID},
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	snapshot := &simulator.Snapshot{
+		Nodes: []object.Node{
+			buildNode("small", 1, nil),
+			buildNode("gpu", 4, map[string]string{"gpu": "true"}),
+		},
+		Pods: []object.Pod{
+			buildPod("running", "gpu", 2, nil),
+			buildPod("big", "", 2, nil),
+			buildPod("gpu-job", "", 2, map[string]string{"gpu": "true"}),
+		},
+	}
+
+	placements := simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, 2, len(placements))
+	// big takes the rest of gpu node, so gpu-job doesn't fit anywhere
+	assert.Equal(t, "gpu", placements[0].NodeUID)
+	assert.Equal(t, "", placements[1].NodeUID)
+	assert.Contains(t, placements[1].Failures["small"], "NodeSelector")
+	assert.Contains(t, placements[1].Failures["gpu"], "NodeResourcesFit")
+
+	// with another gpu node added, both pods fit
+	snapshot.AddNodes(buildNode("gpu2", 4, map[string]string{"gpu": "true"}))
+	placements = simulator.NewSimulator().Simulate(snapshot)
+	assert.NotEqual(t, "", placements[0].NodeUID)
+	assert.NotEqual(t, "", placements[1].NodeUID)
+
+	// without gpu node, running pod becomes pending too
+	snapshot.RemoveNodes("gpu", "gpu2")
+	placements = simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, 3, len(placements))
+	for _, p := range placements {
+		assert.Equal(t, "", p.NodeUID)
+	}
+}
+
+func TestSimulatePreemption(t *testing.T) {
+	var priority int32 = 100
+	member := buildPod("member", "", 2, nil)
+	member.Spec.Priority = &priority
+	member.Labels = map[string]string{object.PodGroupLabel: "train"}
+	snapshot := &simulator.Snapshot{
+		Nodes:     []object.Node{buildNode("node", 2, nil)},
+		Pods:      []object.Pod{buildPod("low", "node", 2, nil), member},
+		PodGroups: []object.PodGroup{{ObjectMeta: object.ObjectMeta{Name: "train"}, Spec: object.PodGroupSpec{MinMember: 1}}},
+	}
+
+	// members of pod groups never preempt
+	placements := simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, 1, len(placements))
+	assert.Equal(t, "", placements[0].NodeUID)
+	assert.Empty(t, placements[0].Victims)
+
+	snapshot.Pods[1].Labels = nil
+	placements = simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, "node", placements[0].NodeUID)
+	assert.Equal(t, []string{"low"}, placements[0].Victims)
+}
+
+func TestSimulateVolumeBinding(t *testing.T) {
+	pod := buildPod("db", "", 1, nil)
+	pod.Spec.Volumes = []object.Volume{{
+		Name:                  "data",
+		PersistentVolumeClaim: &object.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+	}}
+	pvc := object.PersistentVolumeClaim{
+		ObjectMeta: object.ObjectMeta{Name: "data"},
+		Spec:       object.PersistentVolumeClaimSpec{VolumeName: "local-b"},
+		Status:     &object.PersistentVolumeClaimStatus{Phase: object.ClaimBound},
+	}
+	pv := object.PersistentVolume{
+		ObjectMeta: object.ObjectMeta{Name: "local-b"},
+		Spec: object.PersistentVolumeSpec{
+			NodeAffinity: &object.VolumeNodeAffinity{NodeNames: []string{"b"}},
+		},
+	}
+	snapshot := &simulator.Snapshot{
+		Nodes:                  []object.Node{buildNode("a", 4, nil), buildNode("b", 4, nil)},
+		Pods:                   []object.Pod{pod},
+		PersistentVolumeClaims: []object.PersistentVolumeClaim{pvc},
+		PersistentVolumes:      []object.PersistentVolume{pv},
+	}
+
+	placements := simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, "b", placements[0].NodeUID)
+	assert.Contains(t, placements[0].Failures["a"], "VolumeBinding")
+
+	// pods with unbound immediate claims wait for the volume controller
+	snapshot.PersistentVolumeClaims[0].Spec.VolumeName = ""
+	snapshot.PersistentVolumeClaims[0].Status = nil
+	placements = simulator.NewSimulator().Simulate(snapshot)
+	assert.Equal(t, "", placements[0].NodeUID)
+	assert.Contains(t, placements[0].Message, "unbound immediate persistentvolumeclaim data")
+}
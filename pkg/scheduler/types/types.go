@@ -1,7 +1,12 @@
 package types
 
+import "Cubernetes/pkg/object"
+
 type NodeInfo struct {
 	NodeUUID string
+	// Node and Pods are only filled when running scheduling plugins
+	Node *object.Node
+	Pods []object.Pod
 }
 
 type ScheduleInfo struct {
@@ -16,4 +21,7 @@ type Scheduler interface {
 	RemoveNode(Info *NodeInfo) error
 
 	Schedule() (ScheduleInfo, error)
+
+	// ScheduleFrom works like Schedule, but only picks one of the candidates
+	ScheduleFrom(candidates []string) (ScheduleInfo, error)
 }
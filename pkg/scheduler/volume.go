@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/apiserver/crudobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/plugins"
+	"log"
+)
+
+// getVolumeBinding returns the filter of nodes where volumes of the pod can be used, nil if the pod
+// has no claims. A message is returned if the pod can't be scheduled until its claims are bound
+func (sr *ScheduleRuntime) getVolumeBinding(pod *object.Pod) (*plugins.VolumeBinding, string, error) {
+	if len(object.GetPodClaimNames(pod)) == 0 {
+		return nil, "", nil
+	}
+
+	pvcs, err := crudobj.GetPersistentVolumeClaims()
+	if err != nil {
+		return nil, "", err
+	}
+	pvs, err := crudobj.GetPersistentVolumes()
+	if err != nil {
+		return nil, "", err
+	}
+	classes, err := crudobj.GetStorageClasses()
+	if err != nil {
+		return nil, "", err
+	}
+
+	binding, message := plugins.NewVolumeBinding(pod, pvcs, pvs, classes)
+	return binding, message, nil
+}
+
+// selectNodeForClaims puts the node on unbound claims of WaitForFirstConsumer classes,
+// so that the volume controller provisions their volumes for the node
+func (sr *ScheduleRuntime) selectNodeForClaims(pod *object.Pod, nodeUID string) error {
+	names := object.GetPodClaimNames(pod)
+	if len(names) == 0 {
+		return nil
+	}
+
+	pvcs, err := crudobj.GetPersistentVolumeClaims()
+	if err != nil {
+		return err
+	}
+	var node *object.Node
+	for _, name := range names {
+		pvc := findClaim(pvcs, name)
+		if pvc == nil || pvc.Spec.VolumeName != "" || pvc.Annotations[object.AnnSelectedNode] != "" {
+			continue
+		}
+
+		if node == nil {
+			n, err := crudobj.GetNode(nodeUID)
+			if err != nil {
+				return err
+			}
+			node = &n
+		}
+		if pvc.Annotations == nil {
+			pvc.Annotations = make(map[string]string)
+		}
+		pvc.Annotations[object.AnnSelectedNode] = node.Name
+		if _, err = crudobj.UpdatePersistentVolumeClaim(*pvc); err != nil {
+			return err
+		}
+		log.Printf("[INFO]: node %s selected for claim %s of pod %s\n", node.Name, pvc.Name, pod.UID)
+	}
+	return nil
+}
+
+func findClaim(pvcs []object.PersistentVolumeClaim, name string) *object.PersistentVolumeClaim {
+	for idx := range pvcs {
+		if pvcs[idx].Name == name {
+			return &pvcs[idx]
+		}
+	}
+	return nil
+}
@@ -3,11 +3,31 @@ package scheduler
 import (
 	"Cubernetes/pkg/apiserver/crudobj"
 	"Cubernetes/pkg/apiserver/watchobj"
+	"Cubernetes/pkg/object"
+	"Cubernetes/pkg/scheduler/queue"
 	"Cubernetes/pkg/scheduler/types"
 	"log"
+	"reflect"
 	"time"
 )
 
+// onNodeUpdate retries unschedulable pods when a node is added,
+// or its labels, taints, capacity, allocatable resources or conditions changed
+func (sr *ScheduleRuntime) onNodeUpdate(node *object.Node) {
+	oldNode, exist := sr.nodeCache[node.UID]
+	sr.nodeCache[node.UID] = *node
+
+	if !exist {
+		sr.queue.MoveAllToActiveOrBackoff(queue.NodeAdd)
+		return
+	}
+	if oldNode.Status == nil || oldNode.Status.Condition != node.Status.Condition ||
+		!reflect.DeepEqual(oldNode.Status.Allocatable, node.Status.Allocatable) ||
+		!reflect.DeepEqual(oldNode.Labels, node.Labels) || !reflect.DeepEqual(oldNode.Spec, node.Spec) {
+		sr.queue.MoveAllToActiveOrBackoff(queue.NodeUpdate)
+	}
+}
+
 func (sr *ScheduleRuntime) WatchNode() {
 	for {
 		sr.tryWatchNode()
@@ -24,7 +44,9 @@ func (sr *ScheduleRuntime) tryWatchNode() {
 	} else {
 		for _, node := range allNodes {
 			_ = sr.Implement.AddNode(&types.NodeInfo{NodeUUID: node.UID})
+			sr.nodeCache[node.UID] = node
 		}
+		sr.queue.MoveAllToActiveOrBackoff(queue.NodeAdd)
 	}
 
 	ch, handler, err := watchobj.WatchNodes()
@@ -45,6 +67,7 @@ func (sr *ScheduleRuntime) tryWatchNode() {
 					if nodeEvent.Node.Status == nil {
 						continue
 					}
+					sr.onNodeUpdate(&nodeEvent.Node)
 					if !nodeEvent.Node.Status.Condition.Ready {
 						log.Println("[INFO]: Scheduler may removed a node: ", nodeEvent.Node.UID)
 						err := sr.Implement.RemoveNode(&types.NodeInfo{NodeUUID: nodeEvent.Node.UID})
@@ -60,6 +83,7 @@ func (sr *ScheduleRuntime) tryWatchNode() {
 						}
 					}
 				} else if nodeEvent.EType == watchobj.EVENT_DELETE {
+					delete(sr.nodeCache, nodeEvent.Node.UID)
 					log.Println("[INFO]: Scheduler may removed a node: ", nodeEvent.Node.UID)
 					err := sr.Implement.RemoveNode(&types.NodeInfo{NodeUUID: nodeEvent.Node.UID})
 					if err != nil {
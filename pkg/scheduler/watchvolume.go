@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"Cubernetes/pkg/apiserver/watchobj"
+	"Cubernetes/pkg/scheduler/queue"
+	"log"
+	"time"
+)
+
+// WatchVolume retries unschedulable pods when claims or volumes are added or changed,
+// e.g. pods waiting for an immediate claim to be bound by the volume controller
+func (sr *ScheduleRuntime) WatchVolume() {
+	for {
+		sr.tryWatchVolume()
+		time.Sleep(WatchRetryIntervalSec * time.Second)
+	}
+}
+
+func (sr *ScheduleRuntime) tryWatchVolume() {
+	pvcCh, pvcCancel, err := watchobj.WatchPersistentVolumeClaims()
+	if err != nil {
+		log.Printf("[Error]: Error occurs when watching persistent volume claims: %v", err)
+		return
+	}
+	defer pvcCancel()
+
+	pvCh, pvCancel, err := watchobj.WatchPersistentVolumes()
+	if err != nil {
+		log.Printf("[Error]: Error occurs when watching persistent volumes: %v", err)
+		return
+	}
+	defer pvCancel()
+
+	// claims may be bound while the watch is down
+	sr.queue.MoveAllToActiveOrBackoff(queue.PvcAddOrUpdate)
+
+	for {
+		select {
+		case pvcEvent, ok := <-pvcCh:
+			if !ok {
+				log.Printf("[INFO]: lost connection with APIServer, retry after %d seconds...\n", WatchRetryIntervalSec)
+				return
+			}
+			if pvcEvent.EType == watchobj.EVENT_PUT {
+				sr.queue.MoveAllToActiveOrBackoff(queue.PvcAddOrUpdate)
+			}
+		case pvEvent, ok := <-pvCh:
+			if !ok {
+				log.Printf("[INFO]: lost connection with APIServer, retry after %d seconds...\n", WatchRetryIntervalSec)
+				return
+			}
+			if pvEvent.EType == watchobj.EVENT_PUT {
+				sr.queue.MoveAllToActiveOrBackoff(queue.PvAddOrUpdate)
+			}
+		default:
+			time.Sleep(time.Second)
+		}
+	}
+}
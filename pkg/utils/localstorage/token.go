@@ -0,0 +1,68 @@
+package localstorage
+
+import (
+	cubeconfig "Cubernetes/config"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const cubeletTokenBytes = 32
+
+// GenerateCubeletToken returns a new random token for a cluster
+func GenerateCubeletToken() (string, error) {
+	buf := make([]byte, cubeletTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SaveCubeletToken writes the cluster token to CubeletTokenFile, readable by root only
+func SaveCubeletToken(token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("empty cubelet token")
+	}
+
+	err := os.MkdirAll(filepath.Dir(cubeconfig.CubeletTokenFile), 0700)
+	if err != nil {
+		log.Println("[FATAL] fail to make cubelet token dir, err: ", err)
+		return err
+	}
+
+	err = ioutil.WriteFile(cubeconfig.CubeletTokenFile, []byte(token+"\n"), 0600)
+	if err != nil {
+		log.Println("[FATAL] fail to write cubelet token file, err: ", err)
+	}
+	return err
+}
+
+// LoadCubeletToken returns the token shared by apiserver and cubelets,
+// an error is returned if the node has not been given one
+func LoadCubeletToken() (string, error) {
+	content, err := ioutil.ReadFile(cubeconfig.CubeletTokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", errors.New("cubelet token file " + cubeconfig.CubeletTokenFile + " is empty")
+	}
+	return token, nil
+}
+
+func ClearCubeletToken() error {
+	err := os.Remove(cubeconfig.CubeletTokenFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Println("[FATAL] fail to clear cubelet token, err: ", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package mount
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// MountNFS mounts server:path on target by mount(8), which needs nfs-common on the host
+func MountNFS(server, path, target string, readOnly bool) error {
+	source := server + ":" + path
+	args := []string{"-t", "nfs"}
+	if readOnly {
+		args = append(args, "-o", "ro")
+	}
+	args = append(args, source, target)
+
+	output, err := exec.Command("mount", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fail to mount %s on %s: %v, %s", source, target, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func Unmount(target string) error {
+	output, err := exec.Command("umount", target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fail to unmount %s: %v, %s", target, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// WithNFSExport mounts the export on a temporary directory, and runs fn with it
+func WithNFSExport(server, path string, fn func(dir string) error) error {
+	dir, err := os.MkdirTemp("", "cubernetes-nfs-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(dir) }()
+
+	if err = MountNFS(server, path, dir, false); err != nil {
+		return err
+	}
+	fnErr := fn(dir)
+	if err = Unmount(dir); err != nil {
+		return err
+	}
+	return fnErr
+}
@@ -0,0 +1,72 @@
+package remotecommand
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+type StreamOptions struct {
+	// Stdin is nil if stdin is not attached
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Stderr defaults to Stdout, a remote tty never writes it
+	Stderr io.Writer
+	// Resize sends the size of the client terminal whenever it changes, may be nil
+	Resize <-chan TerminalSize
+}
+
+// Stream serves an exec or attach stream until the remote process exits, and returns its exit status
+func Stream(conn io.ReadWriteCloser, opts StreamOptions) (*Status, error) {
+	defer func() { _ = conn.Close() }()
+	if opts.Stderr == nil {
+		opts.Stderr = opts.Stdout
+	}
+
+	lock := &sync.Mutex{}
+	if opts.Stdin != nil {
+		go func() {
+			stdin := NewFrameWriter(lock, conn, ChannelStdin)
+			if _, err := io.Copy(stdin, opts.Stdin); err == nil {
+				_ = stdin.Close()
+			}
+		}()
+	}
+	if opts.Resize != nil {
+		go func() {
+			resize := NewFrameWriter(lock, conn, ChannelResize)
+			for size := range opts.Resize {
+				if resize.WriteJSON(size) != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		channel, payload, err := ReadFrame(conn)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errors.New("stream closed before the remote process exits")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch channel {
+		case ChannelStdout:
+			_, err = opts.Stdout.Write(payload)
+		case ChannelStderr:
+			_, err = opts.Stderr.Write(payload)
+		case ChannelStatus:
+			status := &Status{}
+			if err = json.Unmarshal(payload, status); err != nil {
+				return nil, err
+			}
+			return status, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
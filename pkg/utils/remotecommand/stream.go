@@ -0,0 +1,121 @@
+package remotecommand
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// exec and attach streams are sequences of frames over an upgraded connection,
+// each frame is [channel byte][payload length uint32][payload]
+const (
+	ChannelStdin byte = iota
+	ChannelStdout
+	ChannelStderr
+	// ChannelStatus carries a json Status once the remote process exits
+	ChannelStatus
+	// ChannelResize carries a json TerminalSize whenever the client terminal is resized
+	ChannelResize
+)
+
+const frameHeaderSize = 5
+const maxFrameSize = 32 * 1024
+
+type TerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+type Status struct {
+	ExitCode int    `json:"exitCode"`
+	Message  string `json:"message,omitempty"`
+}
+
+// WriteFrame is not safe for concurrent use, use FrameWriter if frames are written by many goroutines
+func WriteFrame(w io.Writer, channel byte, payload []byte) error {
+	if len(payload) > maxFrameSize {
+		return errors.New("frame too large")
+	}
+
+	buf := make([]byte, frameHeaderSize+len(payload))
+	buf[0] = channel
+	binary.BigEndian.PutUint32(buf[1:frameHeaderSize], uint32(len(payload)))
+	copy(buf[frameHeaderSize:], payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+func ReadFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, errors.New("frame too large")
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func WriteJSONFrame(w io.Writer, channel byte, obj any) error {
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return WriteFrame(w, channel, buf)
+}
+
+// FrameWriter wraps writes into frames of channel, writers sharing a lock can write the same connection
+type FrameWriter struct {
+	lock    *sync.Mutex
+	writer  io.Writer
+	channel byte
+}
+
+func NewFrameWriter(lock *sync.Mutex, writer io.Writer, channel byte) *FrameWriter {
+	return &FrameWriter{
+		lock:    lock,
+		writer:  writer,
+		channel: channel,
+	}
+}
+
+func (w *FrameWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	written := 0
+	for written < len(p) {
+		end := written + maxFrameSize
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := WriteFrame(w.writer, w.channel, p[written:end]); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+// Close sends an empty frame, which means EOF of the channel
+func (w *FrameWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return WriteFrame(w.writer, w.channel, nil)
+}
+
+func (w *FrameWriter) WriteJSON(obj any) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return WriteJSONFrame(w.writer, w.channel, obj)
+}
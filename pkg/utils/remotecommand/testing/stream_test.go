@@ -0,0 +1,59 @@
+package testing
+
+import (
+	"Cubernetes/pkg/utils/remotecommand"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFrameWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := remotecommand.NewFrameWriter(&sync.Mutex{}, buf, remotecommand.ChannelStderr)
+
+	payload := strings.Repeat("x", 40*1024)
+	n, err := writer.Write([]byte(payload))
+	assert.Nil(t, err)
+	assert.Equal(t, len(payload), n)
+	assert.Nil(t, writer.Close())
+
+	// large writes are split into frames
+	var received []byte
+	for {
+		channel, data, err := remotecommand.ReadFrame(buf)
+		assert.Nil(t, err)
+		assert.Equal(t, remotecommand.ChannelStderr, channel)
+		if len(data) == 0 {
+			break
+		}
+		received = append(received, data...)
+	}
+	assert.Equal(t, payload, string(received))
+}
+
+func TestStream(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		channel, data, err := remotecommand.ReadFrame(server)
+		assert.Nil(t, err)
+		assert.Equal(t, remotecommand.ChannelStdin, channel)
+		// echo stdin back
+		_ = remotecommand.WriteFrame(server, remotecommand.ChannelStdout, data)
+		_ = remotecommand.WriteFrame(server, remotecommand.ChannelStderr, []byte("oops"))
+		_ = remotecommand.WriteJSONFrame(server, remotecommand.ChannelStatus, remotecommand.Status{ExitCode: 3})
+	}()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	status, err := remotecommand.Stream(client, remotecommand.StreamOptions{
+		Stdin:  strings.NewReader("hello"),
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, status.ExitCode)
+	assert.Equal(t, "hello", stdout.String())
+	assert.Equal(t, "oops", stderr.String())
+}
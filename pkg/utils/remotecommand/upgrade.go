@@ -0,0 +1,119 @@
+package remotecommand
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// StreamProtocol is the Upgrade header of requests for exec, attach and port-forward,
+// the connection becomes a raw stream after 101 Switching Protocols, as docker attach does
+const StreamProtocol = "cubernetes-stream"
+
+type UpgradeError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *UpgradeError) Error() string {
+	return e.Message
+}
+
+func IsUpgradeRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), StreamProtocol)
+}
+
+// Upgrade switches the connection of the request to a raw stream, errors must be
+// replied as normal http responses before calling it
+func Upgrade(w http.ResponseWriter, r *http.Request) (net.Conn, error) {
+	if !IsUpgradeRequest(r) {
+		return nil, errors.New("not an upgrade request")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection can't be hijacked")
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, err = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: " + StreamProtocol + "\r\n\r\n"))
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	// the client may send data right after the request, which is buffered already
+	return &bufferedConn{Conn: conn, reader: buf.Reader}, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// Dial sends an upgrade request to url, and returns the raw stream if the server switches protocols,
+// otherwise an UpgradeError with the response of the server
+func Dial(url string, header http.Header) (io.ReadWriteCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", StreamProtocol)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, &UpgradeError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	stream, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, errors.New("upgraded response is not writable")
+	}
+	return stream, nil
+}
+
+// Pipe copies data between a and b in both directions, both are closed once either side ends
+func Pipe(a, b io.ReadWriteCloser) {
+	var once sync.Once
+	closeBoth := func() {
+		_ = a.Close()
+		_ = b.Close()
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(a, b)
+		once.Do(closeBoth)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(b, a)
+		once.Do(closeBoth)
+	}()
+	wg.Wait()
+}
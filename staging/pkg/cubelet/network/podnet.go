@@ -45,10 +45,11 @@ func ReleaseNetwork(cni gocni.CNI, podStatus *container.PodStatus) error {
 }
 
 // ConstructPodPortMapping creates a PodPortMapping from the ports specified in the pod's
-// containers.
+// containers, including init containers, which may be sidecars.
 func ConstructPodPortMapping(pod *object.Pod, podIP net.IP) *PodPortMapping {
 	portMappings := make([]*PortMapping, 0)
-	for _, c := range pod.Spec.Containers {
+	containers := append([]object.Container{}, pod.Spec.InitContainers...)
+	for _, c := range append(containers, pod.Spec.Containers...) {
 		for _, port := range c.Ports {
 			portMappings = append(portMappings, &PortMapping{
 				Name:          port.Name,